@@ -1,8 +1,12 @@
 package dsc
 
 import (
+	"context"
 	"database/sql"
+	"encoding/json"
+	"io"
 	"reflect"
+	"sync"
 	"time"
 )
 
@@ -23,6 +27,15 @@ type ColumnValueProvider interface {
 	ColumnValues() ([]interface{}, error)
 }
 
+//DocumentScanner represents a document store scanner handing over current document without struct mapping
+type DocumentScanner interface {
+	//Document returns decoded current document
+	Document() (map[string]interface{}, error)
+
+	//RawDocument returns current document JSON
+	RawDocument() (json.RawMessage, error)
+}
+
 //RecordMapper represents a datastore record mapper, it is responsible for mapping data record into application abstraction.
 type RecordMapper interface {
 	//Maps data record by passing to the scanner references to the application abstraction
@@ -34,6 +47,8 @@ type ParametrizedSQL struct {
 	SQL    string        //Sql
 	Values []interface{} //binding parameter values
 	Type   int
+	//Columns binding parameter columns, set by DmlBuilder, so that values of omitted columns are not accessed by position
+	Columns []string
 }
 
 //DmlProvider represents dml generator, which is responsible for providing parametrized sql, it takes operation type:
@@ -48,6 +63,13 @@ type DmlProvider interface {
 	KeyGetter
 }
 
+//AuditedDmlProvider represents dml generator populating table descriptor audit columns with actor provided by Config.ActorProvider
+type AuditedDmlProvider interface {
+	DmlProvider
+
+	GetAudited(operationType int, instance interface{}, actor string) *ParametrizedSQL
+}
+
 //Manager represents datastore manager.
 type Manager interface {
 	Config() *Config
@@ -55,7 +77,7 @@ type Manager interface {
 	//ConnectionProvider returns connection provider
 	ConnectionProvider() ConnectionProvider
 
-	//Execute executes provided sql, with the arguments, '?' is used as placeholder for and arguments
+	//Execute executes provided sql, with the arguments, '?' is used as placeholder for and arguments, QueryOption (i.e. WithQueryTimeout) can be passed along arguments
 	Execute(sql string, parameters ...interface{}) (sql.Result, error)
 
 	//ExecuteAll executes all provided sql
@@ -73,7 +95,7 @@ type Manager interface {
 	//ReadSingleOnConnection fetches a single record of data on connection, it takes connection, pointer to the result, sql query, binding parameters, record to application instance mapper
 	ReadSingleOnConnection(connection Connection, resultPointer interface{}, query string, parameters []interface{}, mapper RecordMapper) (success bool, err error)
 
-	//ReadAll reads all records, it takes pointer to the result slice , sql query, binding parameters, record to application instance mapper
+	//ReadAll reads all records, it takes pointer to the result slice , sql query, binding parameters (QueryOption can be passed along parameters), record to application instance mapper
 	ReadAll(resultSlicePointer interface{}, query string, parameters []interface{}, mapper RecordMapper) error
 
 	//ReadAllOnConnection reads all records, it takes connection, pointer to the result slice , sql query, binding parameters, record to application instance mapper
@@ -265,6 +287,42 @@ type ConnectionProvider interface {
 	Close() error
 }
 
+//ReloadableConnectionProvider represents connection provider rebuilding its pool with changed config, it is implemented by providers embedding AbstractConnectionProvider
+type ReloadableConnectionProvider interface {
+	//Reload applies passed in config (or config reloaded from its URL when nil) and gracefully rebuilds the connection pool
+	Reload(config *Config) error
+
+	//Watch reloads provider in background whenever config source or expanded DSN changes, until context is done
+	Watch(ctx context.Context, interval time.Duration) error
+}
+
+//HookedConnectionProvider represents connection provider running connection lifecycle hooks
+type HookedConnectionProvider interface {
+	//OnAcquire registers a hook run for each connection returned by Get, hook error vetoes the connection
+	OnAcquire(hook ConnectionHook)
+
+	//OnRelease registers a hook run before connection is returned to the pool, hook error closes the connection instead
+	OnRelease(hook ConnectionHook)
+
+	//OnClose registers a hook run before pooled connection is closed
+	OnClose(hook ConnectionHook)
+}
+
+//ShutdownConnectionProvider represents connection provider draining checked out connections before closing them
+type ShutdownConnectionProvider interface {
+	//Shutdown stops handing out connections, waits for checked out connections to be returned (bounded by context) and closes them
+	Shutdown(ctx context.Context) error
+}
+
+//PoolStatsProvider represents connection provider reporting connection pool stats
+type PoolStatsProvider interface {
+	//Stats returns connection pool snapshot
+	Stats() PoolStats
+}
+
+//ConnectionHook represents a connection lifecycle hook, i.e. per session setup, checkout metrics or connection validation
+type ConnectionHook func(connection Connection) error
+
 //ManagerFactory represents a manager factory.
 type ManagerFactory interface {
 	//Creates manager, takes config pointer.
@@ -292,3 +350,198 @@ type KeyGetter interface {
 	//Key returns key/id for the the application domain instance.
 	Key(instance interface{}) []interface{}
 }
+
+//ScriptExecutor represents manager executing multi statement SQL scripts, it is implemented by managers embedding AbstractManager
+type ScriptExecutor interface {
+	//ExecuteScript executes multi statement SQL script (comments, DELIMITER directive, postgres dollar quoted bodies), failed statement error reports its script line
+	ExecuteScript(script string) ([]sql.Result, error)
+
+	//ExecuteScriptOnConnection executes multi statement SQL script on passed in connection
+	ExecuteScriptOnConnection(connection Connection, script string) ([]sql.Result, error)
+
+	//ExecuteScriptFromURL executes multi statement SQL script (i.e. migration file) from URL
+	ExecuteScriptFromURL(URL string) ([]sql.Result, error)
+}
+
+//OptionsExecutor represents manager executing statements with execute options
+type OptionsExecutor interface {
+	//ExecuteAllWithOptions executes all sql in one transaction or in committed batches, result reports per statement rows affected and the first failing statement
+	ExecuteAllWithOptions(sqls []string, options *ExecuteOptions) (*ExecuteAllResult, error)
+}
+
+//OptionsDeleter represents manager deleting records with delete options
+type OptionsDeleter interface {
+	//DeleteAllWithOptions deletes or soft deletes all record for passed in slice pointer from table, optionally verifying expected deleted count
+	DeleteAllWithOptions(slicePointer interface{}, table string, options *DeleteOptions) (deleted int, err error)
+}
+
+//NativeManager represents manager executing backend specific query documents
+type NativeManager interface {
+	//ExecuteNative executes backend specific query document (i.e. mongo command, aerospike statement) with the native client obtained with connection Unwrap
+	ExecuteNative(query interface{}) (sql.Result, error)
+
+	//ExecuteNativeOnConnection executes backend specific query document on passed in connection
+	ExecuteNativeOnConnection(connection Connection, query interface{}) (sql.Result, error)
+
+	//ReadAllNative reads all records for backend specific query document (i.e. mongo aggregation pipeline), each record is mapped to result slice pointer with record mapper
+	ReadAllNative(resultSlicePointer interface{}, query interface{}, mapper RecordMapper) error
+
+	//ReadAllNativeWithHandlerOnConnection reads data for backend specific query document on connection, for each row reading handler will be called, to continue reading next row, it needs to return true
+	ReadAllNativeWithHandlerOnConnection(connection Connection, query interface{}, readingHandler func(scanner Scanner) (toContinue bool, err error)) error
+}
+
+//DocumentReader represents manager streaming backend specific query documents without struct mapping
+type DocumentReader interface {
+	//ReadAllDocuments streams backend specific query documents decoded as map, to continue reading next document handler needs to return true
+	ReadAllDocuments(query interface{}, handler func(document map[string]interface{}) (toContinue bool, err error)) error
+
+	//ReadAllRawDocuments streams backend specific query documents as JSON, to continue reading next document handler needs to return true
+	ReadAllRawDocuments(query interface{}, handler func(document json.RawMessage) (toContinue bool, err error)) error
+}
+
+//TxRunner represents manager running function in a retried transaction
+type TxRunner interface {
+	//RunInTx executes function in a transaction, re-executing it with backoff on dialect specific deadlock or serialization failure
+	RunInTx(fn func(connection Connection) error, options *TxOptions) error
+}
+
+//StatsProvider represents manager reporting runtime stats
+type StatsProvider interface {
+	//Stats returns runtime snapshot with connection pool, per operation and cache counters
+	Stats() Stats
+}
+
+//HealthChecker represents manager validating datastore connectivity
+type HealthChecker interface {
+	//Ping validates datastore connectivity with dialect specific validation query
+	Ping(ctx context.Context) error
+
+	//HealthCheck pings datastore and reports its status with connection pool snapshot
+	HealthCheck(ctx context.Context) *HealthStatus
+}
+
+//Subscriber represents manager subscribing to datastore push notifications
+type Subscriber interface {
+	//Subscribe subscribes to datastore push notification channels (postgres LISTEN/NOTIFY), subscription reconnects when connection is lost or pool recycled
+	Subscribe(channels ...string) (*Subscription, error)
+}
+
+//ChangeStreamer represents manager opening change data capture streams
+type ChangeStreamer interface {
+	//ChangeStream opens change data capture stream delivering insert, update and delete events with resume tokens (postgres wal2json logical replication slot)
+	ChangeStream(options *ChangeStreamOptions) (ChangeStream, error)
+}
+
+//PaginatorProvider represents manager providing keyset paginator
+type PaginatorProvider interface {
+	//Paginator returns keyset paginator walking table by key columns with opaque cursor tokens
+	Paginator(table string, pageSize int) *Paginator
+}
+
+//ParallelReader represents manager reading query partitions concurrently
+type ParallelReader interface {
+	//ReadAllParallel splits query into partitions read concurrently on separate connections, results are appended in partition order
+	ReadAllParallel(resultSlicePointer interface{}, query string, parameters []interface{}, options *PartitionOptions, mapper RecordMapper) error
+
+	//ReadAllParallelWithHandler splits query into partitions read concurrently on separate connections, handler calls are serialized
+	ReadAllParallelWithHandler(query string, parameters []interface{}, options *PartitionOptions, readingHandler func(scanner Scanner) (toContinue bool, err error)) error
+}
+
+//Exporter represents manager streaming query rows with export format
+type Exporter interface {
+	//Export streams query rows into writer with export format (csv, ndjson or registered one), it returns number of exported rows
+	Export(writer io.Writer, query string, parameters []interface{}, options *ExportOptions) (int, error)
+
+	//ExportToURL streams query rows into storage URL with export format inferred from URL extension if not specified, it returns number of exported rows
+	ExportToURL(URL string, query string, parameters []interface{}, options *ExportOptions) (int, error)
+}
+
+//PoolReader represents manager reading rows into pooled records
+type PoolReader interface {
+	//ReadAllPooled reads all rows into struct pointers borrowed from the pool, handler needs to call release once the record is processed, to continue reading next row it needs to return true
+	ReadAllPooled(pool *sync.Pool, query string, parameters []interface{}, handler func(record interface{}, release func()) (toContinue bool, err error)) error
+
+	//ReadAllPooledOnConnection reads all rows on connection into struct pointers borrowed from the pool, handler needs to call release once the record is processed
+	ReadAllPooledOnConnection(connection Connection, pool *sync.Pool, query string, parameters []interface{}, handler func(record interface{}, release func()) (toContinue bool, err error)) error
+}
+
+//SessionProvider represents manager pinning connection sessions
+type SessionProvider interface {
+	//Session checks out and pins a single connection, session scoped state (temporary tables, SET variables, advisory locks) survives between session calls until the session is released
+	Session(ctx context.Context) (*Session, error)
+}
+
+//SessionSettingsDialect represents dialect applying session settings
+type SessionSettingsDialect interface {
+	//SessionSettingSQL returns SQL applying session setting on a connection, or error if setting is not allowed by the dialect
+	SessionSettingSQL(name string, value interface{}) (string, error)
+}
+
+//TLSDialect represents dialect translating TLS options into driver DSN
+type TLSDialect interface {
+	//ApplyTLS returns DSN with driver specific TLS options, it may also register tls.Config with the driver
+	ApplyTLS(dsn string, options *TLSConfig) (string, error)
+}
+
+//ExplainDialect represents dialect explaining query plans
+type ExplainDialect interface {
+	//ExplainPlan returns normalized query plan with index usage and cost estimates, raw datastore plan is kept in QueryPlan.Raw
+	ExplainPlan(manager Manager, SQL string, parameters []interface{}) (*QueryPlan, error)
+}
+
+//CapabilitiesDialect represents dialect declaring datastore features, dialects not implementing it are described by CanHandleTransaction and CanPersistBatch
+type CapabilitiesDialect interface {
+	//Capabilities returns declared datastore features (transactions, upsert, returning, batch limits, placeholder style)
+	Capabilities() Capabilities
+}
+
+//PartitionDialect represents dialect managing table partitions
+type PartitionDialect interface {
+	//GetPartitions returns table partitions (postgres declarative partitions, mysql partitions)
+	GetPartitions(manager Manager, table string) ([]*TablePartition, error)
+
+	//CreatePartition creates range or list partition of partitioned table
+	CreatePartition(manager Manager, table string, partition *TablePartition) error
+
+	//DropPartition drops table partition with its data
+	DropPartition(manager Manager, table, partition string) error
+}
+
+//ViewDialect represents dialect reading views and refreshing materialized views
+type ViewDialect interface {
+	//GetViews returns views and materialized views for passed in datastore
+	GetViews(manager Manager, datastore string) ([]*View, error)
+
+	//GetViewDefinition returns view defining SELECT statement
+	GetViewDefinition(manager Manager, datastore, view string) (string, error)
+
+	//RefreshMaterializedView refreshes materialized view data
+	RefreshMaterializedView(manager Manager, view string) error
+}
+
+//IndexDialect represents dialect reading table indexes and foreign keys
+type IndexDialect interface {
+	//GetIndexes returns table indexes with their columns and uniqueness
+	GetIndexes(manager Manager, datastore, table string) ([]*TableIndex, error)
+
+	//GetForeignKeys returns table foreign keys with referenced table, columns and referential actions
+	GetForeignKeys(manager Manager, datastore, table string) ([]*ForeignKey, error)
+}
+
+//IdentifierDialect represents dialect quoting identifiers
+type IdentifierDialect interface {
+	//QuoteIdentifier returns table or column name quoted with dialect quoting style if it is a reserved word or has special characters, identifier quoted in other dialect style is requoted
+	QuoteIdentifier(identifier string) string
+}
+
+//ManagerLister represents manager registry listing registered managers
+type ManagerLister interface {
+	//Names returns registered manager names
+	Names() []string
+}
+
+//RegistryHealthChecker represents manager registry checking registered managers
+type RegistryHealthChecker interface {
+	//HealthCheck checks all registered managers, report is healthy when all managers are healthy
+	HealthCheck(ctx context.Context) *HealthReport
+}
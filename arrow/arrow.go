@@ -0,0 +1,224 @@
+// Package arrow reads query results as Apache Arrow record batches and registers arrow IPC stream export format:
+//
+//	import _ "github.com/viant/dsc/arrow"
+package arrow
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"time"
+
+	"github.com/apache/arrow/go/v17/arrow"
+	"github.com/apache/arrow/go/v17/arrow/array"
+	"github.com/apache/arrow/go/v17/arrow/ipc"
+	"github.com/apache/arrow/go/v17/arrow/memory"
+	"github.com/viant/dsc"
+	"github.com/viant/toolbox"
+)
+
+const defaultBatchSize = 1024
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// dataType returns arrow data type for export column, unknown types are read as strings
+func dataType(column *dsc.ExportColumn) arrow.DataType {
+	if column.Binary {
+		return arrow.BinaryTypes.Binary
+	}
+	valueType := column.ValueType()
+	if valueType == nil {
+		return arrow.BinaryTypes.String
+	}
+	if valueType == timeType {
+		return arrow.FixedWidthTypes.Timestamp_us
+	}
+	switch valueType.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return arrow.PrimitiveTypes.Int64
+	case reflect.Float32, reflect.Float64:
+		return arrow.PrimitiveTypes.Float64
+	case reflect.Bool:
+		return arrow.FixedWidthTypes.Boolean
+	}
+	return arrow.BinaryTypes.String
+}
+
+// NewSchema returns arrow schema for export columns, all fields are nullable
+func NewSchema(columns []*dsc.ExportColumn) *arrow.Schema {
+	var fields = make([]arrow.Field, len(columns))
+	for i, column := range columns {
+		fields[i] = arrow.Field{Name: column.Name, Type: dataType(column), Nullable: true}
+	}
+	return arrow.NewSchema(fields, nil)
+}
+
+// recordWriter represents export writer building record batches, each completed batch is passed to emit and released afterwards
+type recordWriter struct {
+	columns   []*dsc.ExportColumn
+	builder   *array.RecordBuilder
+	batchSize int
+	rows      int
+	emit      func(record arrow.Record) error
+	close     func() error
+}
+
+// append appends formatted value to field builder
+func (w *recordWriter) append(i int, value interface{}) error {
+	field := w.builder.Field(i)
+	if value == nil {
+		field.AppendNull()
+		return nil
+	}
+	var err error
+	switch builder := field.(type) {
+	case *array.Int64Builder:
+		var intValue int
+		if intValue, err = toolbox.ToInt(value); err == nil {
+			builder.Append(int64(intValue))
+		}
+	case *array.Float64Builder:
+		var floatValue float64
+		if floatValue, err = toolbox.ToFloat(value); err == nil {
+			builder.Append(floatValue)
+		}
+	case *array.BooleanBuilder:
+		builder.Append(toolbox.AsBoolean(value))
+	case *array.TimestampBuilder:
+		var timeValue *time.Time
+		if timeValue, err = toolbox.ToTime(value, ""); err == nil {
+			builder.Append(arrow.Timestamp(timeValue.UnixMicro()))
+		}
+	case *array.BinaryBuilder:
+		if bytes, ok := value.([]byte); ok {
+			builder.Append(bytes)
+		} else {
+			builder.Append([]byte(toolbox.AsString(value)))
+		}
+	case *array.StringBuilder:
+		builder.Append(toolbox.AsString(value))
+	default:
+		return fmt.Errorf("unsupported arrow builder %T", field)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to convert %v value %v due to %v", w.columns[i].Name, value, err)
+	}
+	return nil
+}
+
+func (w *recordWriter) Write(values []interface{}) error {
+	for i, value := range values {
+		if err := w.append(i, value); err != nil {
+			return err
+		}
+	}
+	w.rows++
+	if w.rows >= w.batchSize {
+		return w.flush()
+	}
+	return nil
+}
+
+func (w *recordWriter) flush() error {
+	if w.rows == 0 {
+		return nil
+	}
+	record := w.builder.NewRecord()
+	defer record.Release()
+	w.rows = 0
+	return w.emit(record)
+}
+
+func (w *recordWriter) Close() error {
+	defer w.builder.Release()
+	err := w.flush()
+	if errors.Is(err, dsc.ErrStopExport) {
+		err = nil
+	}
+	if w.close != nil {
+		if closeErr := w.close(); err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+func newRecordWriter(allocator memory.Allocator, columns []*dsc.ExportColumn, batchSize int) *recordWriter {
+	if allocator == nil {
+		allocator = memory.DefaultAllocator
+	}
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+	return &recordWriter{columns: columns, builder: array.NewRecordBuilder(allocator, NewSchema(columns)), batchSize: batchSize}
+}
+
+// newIPCWriter creates export writer writing arrow IPC stream
+func newIPCWriter(output io.Writer, columns []*dsc.ExportColumn, options *dsc.ExportOptions) (dsc.ExportWriter, error) {
+	result := newRecordWriter(nil, columns, defaultBatchSize)
+	writer := ipc.NewWriter(output, ipc.WithSchema(result.builder.Schema()))
+	result.emit = writer.Write
+	result.close = writer.Close
+	return result, nil
+}
+
+// Reader reads query results as arrow record batches
+type Reader struct {
+	Manager dsc.Manager
+	//BatchSize maximum number of rows in a record batch, default 1024
+	BatchSize int
+	//Allocator arrow memory allocator, default memory.DefaultAllocator
+	Allocator memory.Allocator
+}
+
+// ReadRecords reads query results as record batches, record is released once handler returns, handler needs to call Retain to keep it,
+// to continue reading next batch it needs to return true
+func (r *Reader) ReadRecords(query string, parameters []interface{}, handler func(record arrow.Record) (toContinue bool, err error)) error {
+	exporter, ok := r.Manager.(dsc.Exporter)
+	if !ok {
+		return fmt.Errorf("failed to read records due to unsupported manager %T", r.Manager)
+	}
+	_, err := exporter.Export(nil, query, parameters, &dsc.ExportOptions{
+		Format: dsc.ExportFormatArrow,
+		Provider: func(writer io.Writer, columns []*dsc.ExportColumn, options *dsc.ExportOptions) (dsc.ExportWriter, error) {
+			result := newRecordWriter(r.Allocator, columns, r.BatchSize)
+			result.emit = func(record arrow.Record) error {
+				toContinue, err := handler(record)
+				if err == nil && !toContinue {
+					return dsc.ErrStopExport
+				}
+				return err
+			}
+			return result, nil
+		},
+	})
+	return err
+}
+
+// ReadAll reads all query results as record batches, caller needs to release returned records
+func (r *Reader) ReadAll(query string, parameters []interface{}) ([]arrow.Record, error) {
+	var result = make([]arrow.Record, 0)
+	err := r.ReadRecords(query, parameters, func(record arrow.Record) (bool, error) {
+		record.Retain()
+		result = append(result, record)
+		return true, nil
+	})
+	if err != nil {
+		for _, record := range result {
+			record.Release()
+		}
+		return nil, err
+	}
+	return result, nil
+}
+
+// NewReader creates arrow record batch reader for manager
+func NewReader(manager dsc.Manager) *Reader {
+	return &Reader{Manager: manager, BatchSize: defaultBatchSize}
+}
+
+func init() {
+	dsc.RegisterExportFormat(dsc.ExportFormatArrow, newIPCWriter)
+}
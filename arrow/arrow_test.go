@@ -0,0 +1,80 @@
+package arrow_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/apache/arrow/go/v17/arrow"
+	"github.com/apache/arrow/go/v17/arrow/array"
+	"github.com/apache/arrow/go/v17/arrow/ipc"
+	"github.com/apache/arrow/go/v17/arrow/memory"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/dsc"
+	dscarrow "github.com/viant/dsc/arrow"
+)
+
+func TestReader(t *testing.T) {
+	manager, err := dsc.NewManagerFactory().Create(dsc.NewConfig("sqlite3", "[url]", "url:../test/arrow.db"))
+	if !assert.Nil(t, err) {
+		return
+	}
+	for _, SQL := range []string{"DROP TABLE IF EXISTS accounts", "CREATE TABLE accounts(id INTEGER PRIMARY KEY, name TEXT, balance REAL)"} {
+		_, err = manager.Execute(SQL)
+		assert.Nil(t, err)
+	}
+	for i := 1; i <= 5; i++ {
+		var balance interface{}
+		if i%2 == 1 {
+			balance = float64(i) * 1.5
+		}
+		_, err = manager.Execute("INSERT INTO accounts(id, name, balance) VALUES(?, ?, ?)", i, "name", balance)
+		assert.Nil(t, err)
+	}
+	allocator := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer allocator.AssertSize(t, 0)
+
+	reader := dscarrow.NewReader(manager)
+	reader.BatchSize = 2
+	reader.Allocator = allocator
+	records, err := reader.ReadAll("SELECT id, name, balance FROM accounts ORDER BY id", nil)
+	if !assert.Nil(t, err) {
+		return
+	}
+	if assert.Equal(t, 3, len(records)) {
+		assert.EqualValues(t, 2, records[0].NumRows())
+		assert.EqualValues(t, 1, records[2].NumRows())
+		assert.Equal(t, arrow.PrimitiveTypes.Int64, records[0].Schema().Field(0).Type)
+		ids := records[1].Column(0).(*array.Int64)
+		assert.EqualValues(t, 3, ids.Value(0))
+		balances := records[0].Column(2).(*array.Float64)
+		assert.EqualValues(t, 1.5, balances.Value(0))
+		assert.True(t, balances.IsNull(1))
+	}
+	for _, record := range records {
+		record.Release()
+	}
+
+	batches := 0
+	err = reader.ReadRecords("SELECT id FROM accounts", nil, func(record arrow.Record) (bool, error) {
+		batches++
+		return false, nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, batches)
+
+	buffer := new(bytes.Buffer)
+	count, err := manager.(dsc.Exporter).Export(buffer, "SELECT id, name FROM accounts", nil, &dsc.ExportOptions{Format: dsc.ExportFormatArrow})
+	if assert.Nil(t, err) {
+		assert.Equal(t, 5, count)
+		ipcReader, err := ipc.NewReader(buffer)
+		if assert.Nil(t, err) {
+			defer ipcReader.Release()
+			rows := 0
+			for ipcReader.Next() {
+				rows += int(ipcReader.Record().NumRows())
+			}
+			assert.Equal(t, 5, rows)
+		}
+	}
+}
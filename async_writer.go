@@ -0,0 +1,194 @@
+package dsc
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+const defaultAsyncFlushInterval = time.Second
+
+var errAsyncWriterClosed = errors.New("async writer was closed")
+
+// AsyncWriterOptions represents async writer options
+type AsyncWriterOptions struct {
+	//BatchSize number of buffered records triggering flush, also max number of records persisted at once, default batchSize config parameter
+	BatchSize int
+	//FlushInterval interval of periodic flush, default 1s
+	FlushInterval time.Duration
+	//Provider optional dml provider
+	Provider DmlProvider
+	//OnError optional callback called with records that failed to persist in background flush
+	OnError func(records []interface{}, err error)
+	//MaxPending max number of buffered records, Write fails once it would be exceeded, zero means unlimited
+	MaxPending int
+}
+
+// AsyncWriter buffers records and persists them into a table with Manager.PersistAll on a background goroutine,
+// buffer is flushed when it reaches batch size or every flush interval, records are persisted in write order
+type AsyncWriter struct {
+	manager       Manager
+	table         string
+	options       AsyncWriterOptions
+	mutex         sync.Mutex
+	buffer        []interface{}
+	recordType    reflect.Type
+	closed        bool
+	full          chan bool
+	flushRequests chan chan error
+	done          chan bool
+	finished      chan error
+	closeOnce     sync.Once
+	closeErr      error
+}
+
+// Write buffers records, all records have to be of the same type (struct, struct pointer or map)
+func (w *AsyncWriter) Write(records ...interface{}) error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if w.closed {
+		return errAsyncWriterClosed
+	}
+	for _, record := range records {
+		if record == nil {
+			return fmt.Errorf("failed to write into %v: record was nil", w.table)
+		}
+		recordType := reflect.TypeOf(record)
+		if w.recordType == nil {
+			w.recordType = recordType
+		} else if w.recordType != recordType {
+			return fmt.Errorf("failed to write into %v: expected %v record, but had %v", w.table, w.recordType, recordType)
+		}
+	}
+	if w.options.MaxPending > 0 && len(w.buffer)+len(records) > w.options.MaxPending {
+		select {
+		case w.full <- true:
+		default:
+		}
+		return fmt.Errorf("failed to write %v record(s) into %v due to pending records limit: %v", len(records), w.table, w.options.MaxPending)
+	}
+	w.buffer = append(w.buffer, records...)
+	if len(w.buffer) >= w.options.BatchSize {
+		select {
+		case w.full <- true:
+		default:
+		}
+	}
+	return nil
+}
+
+// Flush persists all buffered records and waits for completion, it returns the first persist error
+func (w *AsyncWriter) Flush() error {
+	response := make(chan error, 1)
+	select {
+	case w.flushRequests <- response:
+		return <-response
+	case <-w.done:
+		return errAsyncWriterClosed
+	}
+}
+
+// Close stops accepting records, flushes buffered ones and stops background goroutine, it returns the final flush error
+func (w *AsyncWriter) Close() error {
+	w.closeOnce.Do(func() {
+		w.mutex.Lock()
+		w.closed = true
+		w.mutex.Unlock()
+		close(w.done)
+		w.closeErr = <-w.finished
+	})
+	return w.closeErr
+}
+
+// Pending returns number of buffered records
+func (w *AsyncWriter) Pending() int {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return len(w.buffer)
+}
+
+func (w *AsyncWriter) run() {
+	ticker := time.NewTicker(w.options.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.done:
+			w.finished <- w.flush()
+			return
+		case response := <-w.flushRequests:
+			response <- w.flush()
+		case <-w.full:
+			_ = w.flush()
+		case <-ticker.C:
+			_ = w.flush()
+		}
+	}
+}
+
+// flush persists buffered records in batches, failed batches are reported with OnError callback
+func (w *AsyncWriter) flush() error {
+	w.mutex.Lock()
+	records := w.buffer
+	w.buffer = nil
+	recordType := w.recordType
+	w.mutex.Unlock()
+	var result error
+	for len(records) > 0 {
+		size := w.options.BatchSize
+		if size > len(records) {
+			size = len(records)
+		}
+		if err := w.persist(recordType, records[:size]); err != nil {
+			if result == nil {
+				result = err
+			}
+			if w.options.OnError != nil {
+				w.options.OnError(records[:size], err)
+			} else {
+				Logf("%v", err)
+			}
+		}
+		records = records[size:]
+	}
+	return result
+}
+
+func (w *AsyncWriter) persist(recordType reflect.Type, records []interface{}) error {
+	slice := reflect.MakeSlice(reflect.SliceOf(recordType), 0, len(records))
+	for _, record := range records {
+		slice = reflect.Append(slice, reflect.ValueOf(record))
+	}
+	slicePointer := reflect.New(slice.Type())
+	slicePointer.Elem().Set(slice)
+	if _, _, err := w.manager.PersistAll(slicePointer.Interface(), w.table, w.options.Provider); err != nil {
+		return fmt.Errorf("failed to persist %v record(s) into %v due to %v", len(records), w.table, err)
+	}
+	return nil
+}
+
+// NewAsyncWriter creates a writer buffering records for the table and starts its background flush goroutine, writer has to be closed
+func NewAsyncWriter(manager Manager, table string, options *AsyncWriterOptions) *AsyncWriter {
+	var writerOptions = AsyncWriterOptions{}
+	if options != nil {
+		writerOptions = *options
+	}
+	if writerOptions.BatchSize <= 0 {
+		writerOptions.BatchSize = manager.Config().GetInt(BatchSizeKey, defaultBatchSize)
+	}
+	if writerOptions.FlushInterval <= 0 {
+		writerOptions.FlushInterval = defaultAsyncFlushInterval
+	}
+	result := &AsyncWriter{
+		manager:       manager,
+		table:         table,
+		options:       writerOptions,
+		full:          make(chan bool, 1),
+		flushRequests: make(chan chan error),
+		done:          make(chan bool),
+		finished:      make(chan error, 1),
+	}
+	go result.run()
+	return result
+}
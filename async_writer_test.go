@@ -0,0 +1,77 @@
+package dsc_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/dsc"
+	"github.com/viant/toolbox"
+)
+
+type Event struct {
+	Id   int    `column:"id" primaryKey:"true"`
+	Name string `column:"name"`
+}
+
+func countRows(t *testing.T, manager dsc.Manager, table string) int {
+	var result = make([]interface{}, 0)
+	success, err := manager.ReadSingle(&result, "SELECT COUNT(*) FROM "+table, nil, nil)
+	if !assert.Nil(t, err) || !assert.True(t, success) {
+		return -1
+	}
+	return toolbox.AsInt(result[0])
+}
+
+func TestAsyncWriter(t *testing.T) {
+	manager := GetManager(t)
+	for _, SQL := range []string{
+		"DROP TABLE IF EXISTS events",
+		"CREATE TABLE events(id INTEGER PRIMARY KEY, name VARCHAR(255))",
+	} {
+		_, err := manager.Execute(SQL)
+		if !assert.Nil(t, err) {
+			return
+		}
+	}
+	writer := dsc.NewAsyncWriter(manager, "events", &dsc.AsyncWriterOptions{BatchSize: 3, FlushInterval: 20 * time.Millisecond})
+	for i := 1; i <= 7; i++ {
+		assert.Nil(t, writer.Write(&Event{Id: i, Name: "event"}))
+	}
+	assert.NotNil(t, writer.Write(Event{Id: 8}), "records have to be of the same type")
+	assert.Nil(t, writer.Flush())
+	assert.Equal(t, 0, writer.Pending())
+	assert.Equal(t, 7, countRows(t, manager, "events"))
+
+	//periodic flush
+	assert.Nil(t, writer.Write(&Event{Id: 8, Name: "late"}))
+	for i := 0; i < 100 && countRows(t, manager, "events") < 8; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, 8, countRows(t, manager, "events"))
+
+	assert.Nil(t, writer.Write(&Event{Id: 9, Name: "last"}))
+	assert.Nil(t, writer.Close())
+	assert.Nil(t, writer.Close())
+	assert.Equal(t, 9, countRows(t, manager, "events"), "close should flush buffered records")
+	assert.NotNil(t, writer.Write(&Event{Id: 10}))
+	assert.NotNil(t, writer.Flush())
+
+	var failed []interface{}
+	writer = dsc.NewAsyncWriter(manager, "missing_events", &dsc.AsyncWriterOptions{OnError: func(records []interface{}, err error) {
+		failed = append(failed, records...)
+	}})
+	assert.Nil(t, writer.Write(&Event{Id: 1}, &Event{Id: 2}))
+	assert.NotNil(t, writer.Flush())
+	assert.Equal(t, 2, len(failed))
+	assert.Nil(t, writer.Close())
+
+	//pending records limit
+	writer = dsc.NewAsyncWriter(manager, "events", &dsc.AsyncWriterOptions{BatchSize: 10, FlushInterval: time.Hour, MaxPending: 2})
+	assert.Nil(t, writer.Write(&Event{Id: 10}, &Event{Id: 11}))
+	assert.NotNil(t, writer.Write(&Event{Id: 12}))
+	assert.Nil(t, writer.Flush())
+	assert.Nil(t, writer.Write(&Event{Id: 12}))
+	assert.Nil(t, writer.Close())
+	assert.Equal(t, 12, countRows(t, manager, "events"))
+}
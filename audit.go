@@ -0,0 +1,121 @@
+package dsc
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/viant/toolbox"
+)
+
+const (
+	// AuditCreatedAt audit tag value of column storing insert time
+	AuditCreatedAt = "createdAt"
+	// AuditCreatedBy audit tag value of column storing actor that inserted row
+	AuditCreatedBy = "createdBy"
+	// AuditUpdatedAt audit tag value of column storing last insert or update time
+	AuditUpdatedAt = "updatedAt"
+	// AuditUpdatedBy audit tag value of column storing actor that last inserted or updated row
+	AuditUpdatedBy = "updatedBy"
+)
+
+// AuditColumns represents audit columns populated by DmlBuilder, created columns are set on insert only, updated columns on both insert and update
+type AuditColumns struct {
+	CreatedAt string
+	CreatedBy string
+	UpdatedAt string
+	UpdatedBy string
+}
+
+// created returns true if column is created audit column
+func (c *AuditColumns) created(column string) bool {
+	return column != "" && (strings.EqualFold(column, c.CreatedAt) || strings.EqualFold(column, c.CreatedBy))
+}
+
+// columns returns declared audit columns
+func (c *AuditColumns) columns() []string {
+	var result = make([]string, 0)
+	for _, column := range []string{c.CreatedAt, c.CreatedBy, c.UpdatedAt, c.UpdatedBy} {
+		if column != "" {
+			result = append(result, column)
+		}
+	}
+	return result
+}
+
+// ActorProvider represents a provider of actor (i.e. user name) stored in created/updated by audit columns
+type ActorProvider interface {
+	Actor() string
+}
+
+// ActorProviderFunc represents function adapter for ActorProvider
+type ActorProviderFunc func() string
+
+// Actor calls the function
+func (f ActorProviderFunc) Actor() string {
+	return f()
+}
+
+// clock returns current UTC time written to audit and soft delete columns, so that stored times do not depend on process time zone
+var clock = func() time.Time {
+	return time.Now().UTC()
+}
+
+// auditingValueProvider returns value provider setting audit columns for sqlType, by columns are only set when actor is not empty
+func auditingValueProvider(audit *AuditColumns, sqlType int, actor string, valueProvider func(column string) interface{}) func(column string) interface{} {
+	now := clock()
+	return func(column string) interface{} {
+		switch {
+		case sqlType == SQLTypeInsert && strings.EqualFold(column, audit.CreatedAt),
+			strings.EqualFold(column, audit.UpdatedAt):
+			return now
+		case actor != "" && sqlType == SQLTypeInsert && strings.EqualFold(column, audit.CreatedBy),
+			actor != "" && strings.EqualFold(column, audit.UpdatedBy):
+			return actor
+		}
+		return valueProvider(column)
+	}
+}
+
+// newAuditColumns returns audit columns for fields with `audit` tag or nil if none was tagged
+func newAuditColumns(targetType reflect.Type) (*AuditColumns, error) {
+	var result *AuditColumns
+	mapping := toolbox.BuildTagMapping(targetType, "column", "transient", true, true, []string{"column", "audit"})
+	for _, fieldMapping := range mapping {
+		kind, ok := fieldMapping["audit"]
+		if !ok {
+			continue
+		}
+		column, ok := fieldMapping["column"]
+		if !ok {
+			column = fieldMapping["fieldName"]
+		}
+		if result == nil {
+			result = &AuditColumns{}
+		}
+		switch kind {
+		case AuditCreatedAt:
+			result.CreatedAt = column
+		case AuditCreatedBy:
+			result.CreatedBy = column
+		case AuditUpdatedAt:
+			result.UpdatedAt = column
+		case AuditUpdatedBy:
+			result.UpdatedBy = column
+		default:
+			return nil, fmt.Errorf("unsupported audit %v of column %v", kind, column)
+		}
+	}
+	return result, nil
+}
+
+// hasColumn returns true if columns contain column, comparison is case insensitive
+func hasColumn(columns []string, column string) bool {
+	for _, candidate := range columns {
+		if strings.EqualFold(candidate, column) {
+			return true
+		}
+	}
+	return false
+}
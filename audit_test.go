@@ -0,0 +1,101 @@
+package dsc_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/dsc"
+	"github.com/viant/toolbox"
+)
+
+type Document struct {
+	Id        int        `autoincrement:"true"`
+	Title     string     `column:"title"`
+	CreatedAt *time.Time `column:"created_at" audit:"createdAt"`
+	CreatedBy string     `column:"created_by" audit:"createdBy"`
+	UpdatedAt *time.Time `column:"updated_at" audit:"updatedAt"`
+	UpdatedBy string     `column:"updated_by" audit:"updatedBy"`
+}
+
+func TestNewDmlBuilder_Audit(t *testing.T) {
+	descriptor := &dsc.TableDescriptor{
+		Table:     "documents",
+		PkColumns: []string{"id"},
+		Columns:   []string{"id", "title"},
+		Audit:     &dsc.AuditColumns{CreatedAt: "created_at", UpdatedBy: "updated_by"},
+	}
+	builder := dsc.NewDmlBuilder(descriptor)
+	assert.Equal(t, "INSERT INTO documents(title,created_at,updated_by,id) VALUES(?,?,?,?)", builder.InsertSQL)
+	assert.Equal(t, "UPDATE documents SET  title = ?, updated_by = ? WHERE  id = ?", builder.UpdateSQL)
+
+	var record = map[string]interface{}{"id": 1, "title": "Draft", "updated_by": "nobody"}
+	insert := builder.GetAuditedParametrizedSQL(dsc.SQLTypeInsert, "alice", func(column string) interface{} {
+		return record[column]
+	})
+	if assert.Equal(t, 4, len(insert.Values)) {
+		createdAt, isTime := insert.Values[1].(time.Time)
+		assert.True(t, isTime)
+		assert.Equal(t, time.UTC, createdAt.Location())
+		assert.Equal(t, "alice", insert.Values[2])
+	}
+	update := builder.GetParametrizedSQL(dsc.SQLTypeUpdate, func(column string) interface{} {
+		return record[column]
+	})
+	assert.EqualValues(t, []interface{}{"Draft", "nobody", 1}, update.Values)
+
+	_, err := dsc.NewTableDescriptor("documents", struct {
+		Id      int
+		Removed bool `audit:"deletedAt"`
+	}{})
+	assert.NotNil(t, err)
+}
+
+func TestManager_PersistAllAudit(t *testing.T) {
+	manager := GetManager(t)
+	for _, SQL := range []string{
+		"DROP TABLE IF EXISTS documents",
+		"CREATE TABLE documents(id INTEGER PRIMARY KEY AUTOINCREMENT, title VARCHAR(255), created_at TIMESTAMP, created_by VARCHAR(64), updated_at TIMESTAMP, updated_by VARCHAR(64))",
+	} {
+		_, err := manager.Execute(SQL)
+		if !assert.Nil(t, err) {
+			return
+		}
+	}
+	var actor = "alice"
+	manager.Config().ActorProvider = dsc.ActorProviderFunc(func() string {
+		return actor
+	})
+	defer func() {
+		manager.Config().ActorProvider = nil
+	}()
+
+	var documents = []*Document{{Title: "Draft"}}
+	inserted, _, err := manager.PersistAll(&documents, "documents", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, inserted)
+
+	var stored = make([]map[string]interface{}, 0)
+	err = manager.ReadAll(&stored, "SELECT id, title, created_at, created_by, updated_at, updated_by FROM documents", nil, nil)
+	if !assert.Nil(t, err) || !assert.Equal(t, 1, len(stored)) {
+		return
+	}
+	assert.Equal(t, "alice", toolbox.AsString(stored[0]["created_by"]))
+	assert.Equal(t, "alice", toolbox.AsString(stored[0]["updated_by"]))
+	assert.NotNil(t, stored[0]["created_at"])
+	assert.NotNil(t, stored[0]["updated_at"])
+
+	actor = "bob"
+	documents[0].Title = "Final"
+	documents[0].CreatedBy = "mallory"
+	_, updated, err := manager.PersistAll(&documents, "documents", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, updated)
+	stored = make([]map[string]interface{}, 0)
+	err = manager.ReadAll(&stored, "SELECT id, title, created_by, updated_by FROM documents", nil, nil)
+	if assert.Nil(t, err) && assert.Equal(t, 1, len(stored)) {
+		assert.Equal(t, "Final", toolbox.AsString(stored[0]["title"]))
+		assert.Equal(t, "alice", toolbox.AsString(stored[0]["created_by"]))
+		assert.Equal(t, "bob", toolbox.AsString(stored[0]["updated_by"]))
+	}
+}
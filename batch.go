@@ -2,6 +2,7 @@ package dsc
 
 import (
 	"compress/gzip"
+	"database/sql"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -13,17 +14,20 @@ type batch struct {
 	tempDir        string
 	tempFile       string
 	size           int
+	maxParameters  int
 	sql            string
+	insertSQL      string //insertSQL first batched row SQL, rows with different columns (i.e. omitted zero values) are batched separately
 	writer         *gzip.Writer
 	values         []interface{}
 	placeholders   string
 	columns        string
 	dataIndexes    []int
-	firstSeq       int64
 	bulkInsertType string
 	manager        *AbstractManager
 	sqlProvider    func(item interface{}) *ParametrizedSQL
 	updateId       func(index int, seq int64)
+	keyStrategy    GeneratedKeyStrategy
+	lastRowSeq     bool
 	connection     Connection
 	table          string
 }
@@ -60,14 +64,28 @@ func (b *batch) flush() (int, error) {
 	if err != nil {
 		return 0, err
 	}
-	for _, i := range dataIndexes {
-		b.firstSeq++
-		b.updateId(i, b.firstSeq)
+	//multi row insert LastInsertId returns the first generated key, or the last one for lastRowInsertIDDialect
+	if seq, _ := result.LastInsertId(); seq > 0 {
+		firstSeq := seq
+		if b.lastRowSeq {
+			firstSeq = seq - int64(len(dataIndexes)) + 1
+		}
+		for j, i := range dataIndexes {
+			b.updateId(i, firstSeq+int64(j))
+		}
 	}
-	b.firstSeq = 0
 	return int(affected), nil
 }
 
+// lastRowInsertIDDialect represents dialect which multi row insert LastInsertId returns the last generated key (i.e. sqlite), by default it is the first one (i.e. mysql)
+type lastRowInsertIDDialect interface {
+	lastRowInsertID() bool
+}
+
+func (d sqlLiteDialect) lastRowInsertID() bool {
+	return true
+}
+
 func (b *batch) expandedValues(parametrizedSQL *ParametrizedSQL) string {
 	recordLine := b.manager.ExpandSQL(b.placeholders, parametrizedSQL.Values)
 	if breakCount := strings.Count(recordLine, "\n"); breakCount > 0 {
@@ -78,6 +96,7 @@ func (b *batch) expandedValues(parametrizedSQL *ParametrizedSQL) string {
 
 func (b *batch) transformFirst(parametrizedSQL *ParametrizedSQL) error {
 	b.sql = parametrizedSQL.SQL
+	b.insertSQL = parametrizedSQL.SQL
 	b.values = parametrizedSQL.Values
 	fragment := " VALUES"
 	valuesIndex := strings.Index(parametrizedSQL.SQL, fragment)
@@ -146,12 +165,12 @@ func (b *batch) transformNext(parametrizedSQL *ParametrizedSQL) error {
 
 func (b *batch) persist(index int, item interface{}) error {
 	parametrizedSQL := b.sqlProvider(item)
-	if len(parametrizedSQL.Values) == 1 && parametrizedSQL.Type == SQLTypeUpdate {
+	if parametrizedSQL.Type == SQLTypeUpdate && (len(parametrizedSQL.Values) == 1 || parametrizedSQL.SQL == "") {
 		//nothing to udpate, one parameter is ID=? without values to update
 		return nil
 	}
 	if parametrizedSQL.Type == SQLTypeInsert && b.size > 0 {
-		if len(b.dataIndexes) > b.size {
+		if len(b.dataIndexes) > b.size || (b.maxParameters > 0 && len(b.sql) > 0 && len(b.values)+len(parametrizedSQL.Values) > b.maxParameters) || (len(b.sql) > 0 && parametrizedSQL.SQL != b.insertSQL) {
 			if _, err := b.flush(); err != nil {
 				return err
 			}
@@ -162,7 +181,13 @@ func (b *batch) persist(index int, item interface{}) error {
 		}
 		return b.transformNext(parametrizedSQL)
 	}
-	result, err := b.manager.ExecuteOnConnection(b.connection, parametrizedSQL.SQL, parametrizedSQL.Values)
+	var result sql.Result
+	var err error
+	if parametrizedSQL.Type == SQLTypeInsert {
+		result, err = b.keyStrategy.Insert(b.manager.Manager, b.connection, b.table, parametrizedSQL.SQL, parametrizedSQL.Values)
+	} else {
+		result, err = b.manager.ExecuteOnConnection(b.connection, parametrizedSQL.SQL, parametrizedSQL.Values)
+	}
 	if err != nil {
 		return err
 	}
@@ -172,9 +197,6 @@ func (b *batch) persist(index int, item interface{}) error {
 	}
 	b.processed += int(affected)
 	seq, _ := result.LastInsertId()
-	if b.size > 0 && b.firstSeq == 0 {
-		b.firstSeq = seq
-	}
 	b.updateId(index, seq)
 	return nil
 }
@@ -183,7 +205,16 @@ func newBatch(table string, connection Connection, manager *AbstractManager, sql
 	dialect := GetDatastoreDialect(manager.Config().DriverName)
 	var batchSize = manager.Config().GetInt(BatchSizeKey, defaultBatchSize)
 	Logf("batch size: %v\n", batchSize)
-	canUseBatch := dialect != nil && dialect.CanPersistBatch() && batchSize > 1
+	keyStrategy := GetGeneratedKeyStrategy(manager.Config().DriverName)
+	capabilities := dialectCapabilities(dialect)
+	if capabilities.MaxBatchSize > 0 && batchSize > capabilities.MaxBatchSize {
+		batchSize = capabilities.MaxBatchSize
+	}
+	canUseBatch := capabilities.BatchInsert && batchSize > 1
+	if keyStrategy != LastInsertIDKeyStrategy && manager.tableDescriptorRegistry.Has(table) && manager.tableDescriptorRegistry.Get(table).Autoincrement {
+		//custom strategy retrieves generated key per statement
+		canUseBatch = false
+	}
 	if !canUseBatch {
 		batchSize = 0
 	}
@@ -191,11 +222,18 @@ func newBatch(table string, connection Connection, manager *AbstractManager, sql
 	if dialect != nil {
 		insertType = dialect.BulkInsertType()
 	}
+	lastRowInsertID := false
+	if actual, ok := dialect.(lastRowInsertIDDialect); ok {
+		lastRowInsertID = actual.lastRowInsertID()
+	}
 	return &batch{
 		connection:     connection,
 		updateId:       updateId,
+		keyStrategy:    keyStrategy,
+		lastRowSeq:     lastRowInsertID,
 		sqlProvider:    sqlProvider,
 		size:           batchSize,
+		maxParameters:  capabilities.MaxParameters,
 		values:         []interface{}{},
 		dataIndexes:    []int{},
 		bulkInsertType: insertType,
@@ -0,0 +1,70 @@
+package dsc
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/toolbox"
+)
+
+// batchSQLLiteDialect represents sqlite dialect with multi row insert enabled
+type batchSQLLiteDialect struct {
+	*sqlLiteDialect
+}
+
+func (d batchSQLLiteDialect) CanPersistBatch() bool {
+	return true
+}
+
+func (d batchSQLLiteDialect) Capabilities() Capabilities {
+	result := d.sqlLiteDialect.Capabilities()
+	result.BatchInsert = true
+	return result
+}
+
+type batchKeyItem struct {
+	Id   int `autoincrement:"true"`
+	Name string
+}
+
+func TestBatch_MultiRowInsertKeys(t *testing.T) {
+	if !toolbox.HasSliceAnyElements(sql.Drivers(), "sqlite3batch") {
+		sql.Register("sqlite3batch", &sqlite3.SQLiteDriver{})
+	}
+	RegisterDatastoreDialect("sqlite3batch", batchSQLLiteDialect{newSQLLiteDialect()})
+	var statements = 0
+	config := NewConfig("sqlite3batch", "[url]", "url:./test/batch_keys.db,batchSize:10")
+	config.QueryLogger = QueryLoggerFunc(func(event *QueryEvent) {
+		statements++
+	})
+	manager, err := NewManagerFactory().Create(config)
+	if !assert.Nil(t, err) {
+		return
+	}
+	for _, SQL := range []string{
+		"DROP TABLE IF EXISTS batch_items",
+		"CREATE TABLE batch_items(Id INTEGER PRIMARY KEY AUTOINCREMENT, Name TEXT)",
+		"INSERT INTO batch_items(Name) VALUES('existing')",
+	} {
+		if _, err = manager.Execute(SQL); !assert.Nil(t, err) {
+			return
+		}
+	}
+	statements = 0
+	items := []*batchKeyItem{{Name: "a"}, {Name: "b"}, {Name: "c"}}
+	_, _, err = manager.PersistAll(&items, "batch_items", nil)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, 2, statements, "rows should be inserted with one statement after existing keys lookup")
+	for _, item := range items {
+		var read = batchKeyItem{}
+		success, err := manager.ReadSingle(&read, "SELECT Id, Name FROM batch_items WHERE Id = ?", []interface{}{item.Id}, nil)
+		assert.Nil(t, err)
+		assert.True(t, success, item.Name)
+		assert.EqualValues(t, item.Name, read.Name)
+	}
+	assert.EqualValues(t, []int{2, 3, 4}, []int{items[0].Id, items[1].Id, items[2].Id})
+}
@@ -0,0 +1,324 @@
+package dsc
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/viant/toolbox"
+)
+
+// BlobChunkSizeKey represents a config parameter with size in bytes of chunk read or written by blob streams (1MB by default)
+const BlobChunkSizeKey = "blobChunkSize"
+
+const defaultBlobChunkSize = 1024 * 1024
+
+// blobDialect represents a dialect specific binary column chunk expressions
+type blobDialect interface {
+	// blobChunkExpression returns expression reading chunk of column, the first parameter is 1-based offset, the second chunk size
+	blobChunkExpression(column string) string
+	// blobAppendExpression returns expression appending parameter to column
+	blobAppendExpression(column string) string
+}
+
+func (d sqlLiteDialect) blobChunkExpression(column string) string {
+	return fmt.Sprintf("SUBSTR(%v, ?, ?)", column)
+}
+
+func (d sqlLiteDialect) blobAppendExpression(column string) string {
+	return fmt.Sprintf("CAST(%v || ? AS BLOB)", column)
+}
+
+func (d mySQLDialect) blobChunkExpression(column string) string {
+	return fmt.Sprintf("SUBSTRING(%v, ?, ?)", column)
+}
+
+func (d mySQLDialect) blobAppendExpression(column string) string {
+	return fmt.Sprintf("CONCAT(%v, ?)", column)
+}
+
+func (d pgDialect) blobChunkExpression(column string) string {
+	return fmt.Sprintf("SUBSTRING(%v FROM ? FOR ?)", column)
+}
+
+func (d pgDialect) blobAppendExpression(column string) string {
+	return fmt.Sprintf("%v || ?", column)
+}
+
+func (d msSQLDialect) blobChunkExpression(column string) string {
+	return fmt.Sprintf("SUBSTRING(%v, ?, ?)", column)
+}
+
+func (d msSQLDialect) blobAppendExpression(column string) string {
+	return fmt.Sprintf("%v + ?", column)
+}
+
+func blobChunkSize(manager Manager) int {
+	if size := manager.Config().GetInt(BlobChunkSizeKey, defaultBlobChunkSize); size > 0 {
+		return size
+	}
+	return defaultBlobChunkSize
+}
+
+func getBlobDialect(manager Manager) (blobDialect, error) {
+	dialect, ok := GetDatastoreDialect(manager.Config().DriverName).(blobDialect)
+	if !ok {
+		return nil, fmt.Errorf("blob streams are not supported by %v", manager.Config().DriverName)
+	}
+	return dialect, nil
+}
+
+// blobKeyCriteria returns table primary key criteria
+func blobKeyCriteria(manager Manager, table string, key []interface{}) (string, error) {
+	descriptor := manager.TableDescriptorRegistry().Get(table)
+	if len(descriptor.PkColumns) == 0 {
+		return "", fmt.Errorf("failed to lookup %v primary key", table)
+	}
+	if len(descriptor.PkColumns) != len(key) {
+		return "", fmt.Errorf("invalid %v key %v, expected values for %v", table, key, descriptor.PkColumns)
+	}
+	var criteria = make([]string, len(descriptor.PkColumns))
+	for i, column := range descriptor.PkColumns {
+		criteria[i] = column + " = ?"
+	}
+	return strings.Join(criteria, " AND "), nil
+}
+
+// chunkReader represents a reader fetching data in chunks, it releases connection on close
+type chunkReader struct {
+	connection Connection
+	chunkSize  int
+	fetch      func(connection Connection, offset int64, size int) ([]byte, bool, error)
+	offset     int64
+	buffer     []byte
+	eof        bool
+}
+
+// Read reads data, next chunk is fetched when buffered chunk was consumed
+func (r *chunkReader) Read(data []byte) (int, error) {
+	for len(r.buffer) == 0 {
+		if r.eof {
+			return 0, io.EOF
+		}
+		chunk, found, err := r.fetch(r.connection, r.offset, r.chunkSize)
+		if err != nil {
+			return 0, err
+		}
+		if !found {
+			return 0, fmt.Errorf("failed to read blob: row was not found")
+		}
+		r.offset += int64(len(chunk))
+		r.buffer = chunk
+		r.eof = len(chunk) < r.chunkSize
+	}
+	read := copy(data, r.buffer)
+	r.buffer = r.buffer[read:]
+	return read, nil
+}
+
+// Close releases connection
+func (r *chunkReader) Close() error {
+	return r.connection.Close()
+}
+
+// readChunk reads single []byte value returned by SQL
+func readChunk(manager Manager, connection Connection, SQL string, parameters []interface{}) ([]byte, bool, error) {
+	var result []byte
+	var found bool
+	err := manager.ReadAllOnWithHandlerOnConnection(connection, SQL, parameters, func(scanner Scanner) (bool, error) {
+		found = true
+		return false, scanner.Scan(&result)
+	})
+	return result, found, err
+}
+
+// OpenBlobReader returns reader of binary column of table row identified by primary key values, column is read in chunks (see blobChunkSize config parameter)
+// so that value is never fully materialized in memory, reader has to be closed to release connection
+func OpenBlobReader(manager Manager, table, column string, key ...interface{}) (io.ReadCloser, error) {
+	dialect, err := getBlobDialect(manager)
+	if err != nil {
+		return nil, err
+	}
+	criteria, err := blobKeyCriteria(manager, table, key)
+	if err != nil {
+		return nil, err
+	}
+	connection, err := manager.ConnectionProvider().Get()
+	if err != nil {
+		return nil, err
+	}
+	SQL := fmt.Sprintf("SELECT %v FROM %v WHERE %v", dialect.blobChunkExpression(column), table, criteria)
+	return &chunkReader{
+		connection: connection,
+		chunkSize:  blobChunkSize(manager),
+		fetch: func(connection Connection, offset int64, size int) ([]byte, bool, error) {
+			return readChunk(manager, connection, SQL, append([]interface{}{offset + 1, size}, key...))
+		},
+	}, nil
+}
+
+// chunkWriter represents a writer storing data in chunks within transaction, it commits transaction on close,
+// when write fails, transaction is rolled back and the error is returned by subsequent calls
+type chunkWriter struct {
+	connection Connection
+	chunkSize  int
+	store      func(connection Connection, chunk []byte, first bool) error
+	buffer     []byte
+	written    bool
+	err        error
+}
+
+// Write buffers data, full chunks are stored
+func (w *chunkWriter) Write(data []byte) (int, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+	w.buffer = append(w.buffer, data...)
+	for len(w.buffer) >= w.chunkSize {
+		if err := w.flush(w.buffer[:w.chunkSize]); err != nil {
+			return 0, err
+		}
+		w.buffer = w.buffer[w.chunkSize:]
+	}
+	return len(data), nil
+}
+
+func (w *chunkWriter) flush(chunk []byte) error {
+	if err := w.store(w.connection, chunk, !w.written); err != nil {
+		w.abort(err)
+		return w.err
+	}
+	w.written = true
+	return nil
+}
+
+func (w *chunkWriter) abort(err error) {
+	w.err = fmt.Errorf("failed to write blob due to %v", err)
+	_ = w.connection.Rollback()
+	_ = w.connection.Close()
+}
+
+// Close stores remaining data and commits transaction
+func (w *chunkWriter) Close() error {
+	if w.err != nil {
+		return w.err
+	}
+	if len(w.buffer) > 0 || !w.written {
+		if err := w.flush(append([]byte{}, w.buffer...)); err != nil {
+			return err
+		}
+	}
+	w.err = fmt.Errorf("blob writer was closed")
+	defer w.connection.Close()
+	return w.connection.Commit()
+}
+
+func newChunkWriter(manager Manager, store func(connection Connection, chunk []byte, first bool) error) (*chunkWriter, error) {
+	connection, err := manager.ConnectionProvider().Get()
+	if err != nil {
+		return nil, err
+	}
+	if err = connection.Begin(); err != nil {
+		_ = connection.Close()
+		return nil, fmt.Errorf("failed to start transaction due to %v", err)
+	}
+	return &chunkWriter{connection: connection, chunkSize: blobChunkSize(manager), store: store}, nil
+}
+
+// OpenBlobWriter returns writer replacing binary column value of existing table row identified by primary key values,
+// data is written in chunks (see blobChunkSize config parameter) within one transaction committed on close
+func OpenBlobWriter(manager Manager, table, column string, key ...interface{}) (io.WriteCloser, error) {
+	dialect, err := getBlobDialect(manager)
+	if err != nil {
+		return nil, err
+	}
+	criteria, err := blobKeyCriteria(manager, table, key)
+	if err != nil {
+		return nil, err
+	}
+	setSQL := fmt.Sprintf(updateSQLTemplate, table, column+" = ?", criteria)
+	appendSQL := fmt.Sprintf(updateSQLTemplate, table, column+" = "+dialect.blobAppendExpression(column), criteria)
+	return newChunkWriter(manager, func(connection Connection, chunk []byte, first bool) error {
+		SQL := appendSQL
+		if first {
+			SQL = setSQL
+		}
+		result, err := manager.ExecuteOnConnection(connection, SQL, append([]interface{}{chunk}, key...))
+		if err != nil {
+			return err
+		}
+		if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+			return fmt.Errorf("%v row %v was not found", table, key)
+		}
+		return nil
+	})
+}
+
+func checkLargeObjects(manager Manager) error {
+	if dialect := GetDatastoreDialect(manager.Config().DriverName); dialect == nil || !dialectCapabilities(dialect).LargeObjects {
+		return fmt.Errorf("large objects are not supported by %v", manager.Config().DriverName)
+	}
+	return nil
+}
+
+// CreateLargeObject creates postgres large object with reader content, it returns large object oid
+func CreateLargeObject(manager Manager, reader io.Reader) (uint32, error) {
+	if err := checkLargeObjects(manager); err != nil {
+		return 0, err
+	}
+	var oid uint32
+	var offset int64
+	writer, err := newChunkWriter(manager, func(connection Connection, chunk []byte, first bool) error {
+		if first {
+			var result = make([]interface{}, 0)
+			if _, err := manager.ReadSingleOnConnection(connection, &result, "SELECT lo_create(0)", nil, nil); err != nil {
+				return err
+			}
+			if len(result) == 0 {
+				return fmt.Errorf("lo_create returned no oid")
+			}
+			oid = uint32(toolbox.AsInt(result[0]))
+		}
+		_, _, err := readChunk(manager, connection, "SELECT lo_put(?, ?, ?)", []interface{}{oid, offset, chunk})
+		offset += int64(len(chunk))
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	if _, err = io.Copy(writer, reader); err != nil {
+		if writer.err == nil {
+			writer.abort(err)
+		}
+		return 0, err
+	}
+	return oid, writer.Close()
+}
+
+// OpenLargeObjectReader returns reader of postgres large object content, reader has to be closed to release connection
+func OpenLargeObjectReader(manager Manager, oid uint32) (io.ReadCloser, error) {
+	if err := checkLargeObjects(manager); err != nil {
+		return nil, err
+	}
+	connection, err := manager.ConnectionProvider().Get()
+	if err != nil {
+		return nil, err
+	}
+	return &chunkReader{
+		connection: connection,
+		chunkSize:  blobChunkSize(manager),
+		fetch: func(connection Connection, offset int64, size int) ([]byte, bool, error) {
+			return readChunk(manager, connection, "SELECT lo_get(?, ?, ?)", []interface{}{oid, offset, size})
+		},
+	}, nil
+}
+
+// DeleteLargeObject removes postgres large object
+func DeleteLargeObject(manager Manager, oid uint32) error {
+	if err := checkLargeObjects(manager); err != nil {
+		return err
+	}
+	var result = make([]interface{}, 0)
+	_, err := manager.ReadSingle(&result, "SELECT lo_unlink(?)", []interface{}{oid}, nil)
+	return err
+}
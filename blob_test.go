@@ -0,0 +1,77 @@
+package dsc_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/dsc"
+)
+
+func TestBlobStreams(t *testing.T) {
+	manager := GetManager(t)
+	for _, SQL := range []string{
+		"DROP TABLE IF EXISTS attachments",
+		"CREATE TABLE attachments(id INTEGER PRIMARY KEY, name VARCHAR(255), content BLOB)",
+		"INSERT INTO attachments(id, name) VALUES(1, 'report.bin')",
+	} {
+		_, err := manager.Execute(SQL)
+		if !assert.Nil(t, err) {
+			return
+		}
+	}
+	manager.Config().Parameters[dsc.BlobChunkSizeKey] = 7
+	defer delete(manager.Config().Parameters, dsc.BlobChunkSizeKey)
+
+	var payload = make([]byte, 100)
+	for i := range payload {
+		payload[i] = byte(i * 7 % 256)
+	}
+	writer, err := dsc.OpenBlobWriter(manager, "attachments", "content", 1)
+	if !assert.Nil(t, err) {
+		return
+	}
+	_, err = io.Copy(writer, bytes.NewReader(payload))
+	assert.Nil(t, err)
+	assert.Nil(t, writer.Close())
+
+	reader, err := dsc.OpenBlobReader(manager, "attachments", "content", 1)
+	if assert.Nil(t, err) {
+		actual, err := io.ReadAll(reader)
+		assert.Nil(t, err)
+		assert.Equal(t, payload, actual)
+		assert.Nil(t, reader.Close())
+	}
+
+	//empty payload replaces content
+	writer, err = dsc.OpenBlobWriter(manager, "attachments", "content", 1)
+	if assert.Nil(t, err) {
+		assert.Nil(t, writer.Close())
+	}
+	reader, err = dsc.OpenBlobReader(manager, "attachments", "content", 1)
+	if assert.Nil(t, err) {
+		actual, err := io.ReadAll(reader)
+		assert.Nil(t, err)
+		assert.Equal(t, 0, len(actual))
+		assert.Nil(t, reader.Close())
+	}
+
+	writer, err = dsc.OpenBlobWriter(manager, "attachments", "content", 2)
+	if assert.Nil(t, err) {
+		_, err = writer.Write(payload)
+		assert.NotNil(t, err, "row was not found")
+		assert.NotNil(t, writer.Close())
+	}
+	reader, err = dsc.OpenBlobReader(manager, "attachments", "content", 2)
+	if assert.Nil(t, err) {
+		_, err = io.ReadAll(reader)
+		assert.NotNil(t, err)
+		assert.Nil(t, reader.Close())
+	}
+	_, err = dsc.OpenBlobReader(manager, "attachments", "content", 1, 2)
+	assert.NotNil(t, err)
+
+	_, err = dsc.CreateLargeObject(manager, bytes.NewReader(payload))
+	assert.NotNil(t, err, "large objects are postgres specific")
+}
@@ -0,0 +1,333 @@
+package dsc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/viant/toolbox"
+)
+
+const (
+	//ChangeTypeInsert represents inserted record change event
+	ChangeTypeInsert = "insert"
+	//ChangeTypeUpdate represents updated (or replaced) record change event
+	ChangeTypeUpdate = "update"
+	//ChangeTypeDelete represents deleted record change event
+	ChangeTypeDelete = "delete"
+)
+
+const (
+	defaultChangeStreamPollInterval = time.Second
+	defaultChangeStreamSlot         = "dsc_change_stream"
+)
+
+// ChangeEvent represents change data capture event
+type ChangeEvent struct {
+	//Type change type: insert, update or delete
+	Type string
+	//Table changed table or collection
+	Table string
+	//Key changed record key values (primary key or document key)
+	Key map[string]interface{}
+	//Record changed record image, for deletes it holds old record image reported by datastore (at least key values)
+	Record map[string]interface{}
+	//Token resume token, stream opened with this token as ChangeStreamOptions.ResumeToken continues after this event
+	Token string
+}
+
+// Map maps event record into result pointer with record mapper, if mapper is nil, a default one is created for result type
+func (e *ChangeEvent) Map(resultPointer interface{}, mapper RecordMapper) error {
+	toolbox.AssertKind(resultPointer, reflect.Ptr, "resultPointer")
+	elementType := reflect.TypeOf(resultPointer).Elem()
+	mapper = NewRecordMapperIfNeeded(mapper, elementType)
+	var columns = make([]string, 0, len(e.Record))
+	for column := range e.Record {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+	scanner := &FileScanner{columns: columns, converter: *toolbox.NewColumnConverter(toolbox.DefaultDateLayout), Values: e.Record}
+	mapped, err := mapper.Map(scanner)
+	if err != nil {
+		return fmt.Errorf("failed to map %v %v event with %T due to %v", e.Table, e.Type, mapper, err)
+	}
+	if mapped == nil {
+		return nil
+	}
+	value := reflect.ValueOf(mapped)
+	if value.Kind() == reflect.Ptr && elementType.Kind() != reflect.Ptr {
+		value = value.Elem()
+	}
+	reflect.ValueOf(resultPointer).Elem().Set(value)
+	return nil
+}
+
+// ChangeStreamOptions represents change stream options
+type ChangeStreamOptions struct {
+	//Tables tables or collections to capture, all if empty
+	Tables []string
+	//ResumeToken token of the last processed event, stream continues with subsequent events
+	ResumeToken string
+	//Slot postgres logical replication slot using wal2json output plugin, default dsc_change_stream
+	Slot string
+	//CreateSlot creates replication slot if it does not exist
+	CreateSlot bool
+	//PollInterval interval of polling for new changes when datastore does not push them, default 1s
+	PollInterval time.Duration
+}
+
+// ChangeStream represents datastore change feed with at-least-once delivery, events that were not committed are delivered again
+// once stream is reopened, consumers need to handle duplicates
+type ChangeStream interface {
+	//Read reads change events, it waits for new changes until handler returns false, an error occurs or stream is closed
+	Read(handler func(event *ChangeEvent) (toContinue bool, err error)) error
+	//Commit acknowledges events up to token (inclusive), for datastores without server side consumer position it is a no-op,
+	//consumers need to persist token and pass it with ChangeStreamOptions.ResumeToken
+	Commit(token string) error
+	//Close closes stream, pending Read returns
+	Close() error
+}
+
+// changeStreamDialect represents dialect exposing change feed
+type changeStreamDialect interface {
+	newChangeStream(manager Manager, options *ChangeStreamOptions) (ChangeStream, error)
+}
+
+// ChangeStream opens change data capture stream, it is supported by postgres with wal2json logical replication slot
+func (m *AbstractManager) ChangeStream(options *ChangeStreamOptions) (ChangeStream, error) {
+	if options == nil {
+		options = &ChangeStreamOptions{}
+	}
+	dialect, ok := GetDatastoreDialect(m.Manager.Config().DriverName).(changeStreamDialect)
+	if !ok {
+		return nil, fmt.Errorf("failed to open change stream: change data capture is not supported by %v", m.Manager.Config().DriverName)
+	}
+	return dialect.newChangeStream(m.Manager, options)
+}
+
+// parseLSN parses postgres log sequence number i.e. 16/B374D848
+func parseLSN(lsn string) (uint64, error) {
+	fragments := strings.Split(lsn, "/")
+	if len(fragments) != 2 {
+		return 0, fmt.Errorf("invalid lsn: %v", lsn)
+	}
+	high, err := strconv.ParseUint(fragments[0], 16, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid lsn: %v", lsn)
+	}
+	low, err := strconv.ParseUint(fragments[1], 16, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid lsn: %v", lsn)
+	}
+	return high<<32 | low, nil
+}
+
+type wal2JSONColumn struct {
+	Name  string      `json:"name"`
+	Value interface{} `json:"value"`
+}
+
+// wal2JSONChange represents wal2json format-version 2 change
+type wal2JSONChange struct {
+	Action   string           `json:"action"`
+	Schema   string           `json:"schema"`
+	Table    string           `json:"table"`
+	Columns  []wal2JSONColumn `json:"columns"`
+	Identity []wal2JSONColumn `json:"identity"`
+	PK       []wal2JSONColumn `json:"pk"`
+}
+
+// newWal2JSONChangeEvent converts wal2json format-version 2 change into change event, it returns nil for non DML actions (begin, commit, truncate, message)
+func newWal2JSONChangeEvent(lsn string, data []byte) (*ChangeEvent, error) {
+	var change = &wal2JSONChange{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(change); err != nil {
+		return nil, fmt.Errorf("failed to decode wal2json change %s due to %v", data, err)
+	}
+	var event = &ChangeEvent{Table: change.Table, Token: lsn, Key: make(map[string]interface{}), Record: make(map[string]interface{})}
+	if change.Schema != "" && change.Schema != "public" {
+		event.Table = change.Schema + "." + change.Table
+	}
+	var image = change.Columns
+	switch change.Action {
+	case "I":
+		event.Type = ChangeTypeInsert
+	case "U":
+		event.Type = ChangeTypeUpdate
+	case "D":
+		event.Type = ChangeTypeDelete
+		image = change.Identity
+	default:
+		return nil, nil
+	}
+	for _, column := range image {
+		event.Record[column.Name] = column.Value
+	}
+	if event.Type == ChangeTypeDelete {
+		event.Key = event.Record
+		return event, nil
+	}
+	for _, column := range change.PK {
+		event.Key[column.Name] = event.Record[column.Name]
+	}
+	return event, nil
+}
+
+// pgChangeStream represents postgres logical replication slot change stream, changes are peeked and slot is advanced with Commit
+type pgChangeStream struct {
+	manager   Manager
+	options   *ChangeStreamOptions
+	mutex     *sync.Mutex
+	committed uint64
+	position  uint64
+	closed    chan bool
+	once      *sync.Once
+}
+
+func (s *pgChangeStream) peekSQL() string {
+	var SQL = "SELECT lsn::text, data FROM pg_logical_slot_peek_changes($1, NULL, NULL, 'format-version', '2', 'include-transaction', 'false', 'include-pk', 'true'"
+	if len(s.options.Tables) > 0 {
+		SQL += ", 'add-tables', $2"
+	}
+	return SQL + ")"
+}
+
+// poll reads changes past current position, it returns false once handler stops reading
+func (s *pgChangeStream) poll(handler func(event *ChangeEvent) (toContinue bool, err error)) (bool, error) {
+	var parameters = []interface{}{s.options.Slot}
+	if len(s.options.Tables) > 0 {
+		var tables = make([]string, len(s.options.Tables))
+		for i, table := range s.options.Tables {
+			if !strings.Contains(table, ".") {
+				table = "*." + table
+			}
+			tables[i] = table
+		}
+		parameters = append(parameters, strings.Join(tables, ","))
+	}
+	var toContinue = true
+	err := s.manager.ReadAllWithHandler(s.peekSQL(), parameters, func(scanner Scanner) (bool, error) {
+		var lsn, data string
+		if err := scanner.Scan(&lsn, &data); err != nil {
+			return false, err
+		}
+		position, err := parseLSN(lsn)
+		if err != nil {
+			return false, err
+		}
+		if position <= s.position {
+			return true, nil
+		}
+		event, err := newWal2JSONChangeEvent(lsn, []byte(data))
+		if err != nil || event == nil {
+			return err == nil, err
+		}
+		if toContinue, err = handler(event); err != nil {
+			return false, err
+		}
+		s.position = position
+		return toContinue, nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to read changes from %v slot due to %v", s.options.Slot, err)
+	}
+	return toContinue, nil
+}
+
+func (s *pgChangeStream) Read(handler func(event *ChangeEvent) (toContinue bool, err error)) error {
+	for {
+		select {
+		case <-s.closed:
+			return nil
+		default:
+		}
+		toContinue, err := s.poll(handler)
+		if err != nil || !toContinue {
+			return err
+		}
+		select {
+		case <-s.closed:
+			return nil
+		case <-time.After(s.options.PollInterval):
+		}
+	}
+}
+
+func (s *pgChangeStream) Commit(token string) error {
+	position, err := parseLSN(token)
+	if err != nil {
+		return err
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if position <= s.committed {
+		return nil
+	}
+	if _, err = s.manager.Execute("SELECT pg_replication_slot_advance($1, $2::pg_lsn)", s.options.Slot, token); err != nil {
+		return fmt.Errorf("failed to advance %v slot to %v due to %v", s.options.Slot, token, err)
+	}
+	s.committed = position
+	return nil
+}
+
+func (s *pgChangeStream) Close() error {
+	s.once.Do(func() {
+		close(s.closed)
+	})
+	return nil
+}
+
+// newChangeStream opens change stream on wal2json logical replication slot, slot keeps consumer position, resume token advances it
+func (d pgDialect) newChangeStream(manager Manager, options *ChangeStreamOptions) (ChangeStream, error) {
+	var streamOptions = *options
+	if streamOptions.Slot == "" {
+		streamOptions.Slot = defaultChangeStreamSlot
+	}
+	if streamOptions.PollInterval <= 0 {
+		streamOptions.PollInterval = defaultChangeStreamPollInterval
+	}
+	var confirmed string
+	var found bool
+	readLSN := func(SQL string) error {
+		return manager.ReadAllWithHandler(SQL, []interface{}{streamOptions.Slot}, func(scanner Scanner) (bool, error) {
+			found = true
+			return false, scanner.Scan(&confirmed)
+		})
+	}
+	if err := readLSN("SELECT COALESCE(confirmed_flush_lsn::text, '') FROM pg_replication_slots WHERE slot_name = $1"); err != nil {
+		return nil, fmt.Errorf("failed to read %v slot due to %v", streamOptions.Slot, err)
+	}
+	if !found {
+		if !streamOptions.CreateSlot {
+			return nil, fmt.Errorf("failed to open change stream: replication slot %v does not exist", streamOptions.Slot)
+		}
+		if err := readLSN("SELECT lsn::text FROM pg_create_logical_replication_slot($1, 'wal2json')"); err != nil {
+			return nil, fmt.Errorf("failed to create %v slot due to %v", streamOptions.Slot, err)
+		}
+	}
+	var result = &pgChangeStream{manager: manager, options: &streamOptions, mutex: &sync.Mutex{}, closed: make(chan bool), once: &sync.Once{}}
+	if confirmed != "" {
+		position, err := parseLSN(confirmed)
+		if err != nil {
+			return nil, err
+		}
+		result.committed = position
+		result.position = position
+	}
+	if streamOptions.ResumeToken != "" {
+		if err := result.Commit(streamOptions.ResumeToken); err != nil {
+			return nil, err
+		}
+		if result.committed > result.position {
+			result.position = result.committed
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,91 @@
+package dsc
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLSN(t *testing.T) {
+	position, err := parseLSN("16/B374D848")
+	assert.Nil(t, err)
+	assert.EqualValues(t, uint64(0x16)<<32|0xB374D848, position)
+	next, _ := parseLSN("17/0")
+	assert.True(t, next > position)
+	_, err = parseLSN("B374D848")
+	assert.NotNil(t, err)
+}
+
+func TestNewWal2JSONChangeEvent(t *testing.T) {
+	type User struct {
+		ID       int    `column:"id"`
+		Username string `column:"username"`
+	}
+	var useCases = []struct {
+		description string
+		data        string
+		expectType  string
+		expectTable string
+		expectKey   map[string]interface{}
+		expectUser  *User
+	}{
+		{
+			description: "insert",
+			data:        `{"action":"I","schema":"public","table":"users","columns":[{"name":"id","type":"integer","value":1},{"name":"username","type":"text","value":"Bob"}],"pk":[{"name":"id","type":"integer"}]}`,
+			expectType:  ChangeTypeInsert,
+			expectTable: "users",
+			expectKey:   map[string]interface{}{"id": json.Number("1")},
+			expectUser:  &User{ID: 1, Username: "Bob"},
+		},
+		{
+			description: "update in schema",
+			data:        `{"action":"U","schema":"app","table":"users","columns":[{"name":"id","type":"integer","value":2},{"name":"username","type":"text","value":"Ann"}],"identity":[{"name":"id","type":"integer","value":2}],"pk":[{"name":"id","type":"integer"}]}`,
+			expectType:  ChangeTypeUpdate,
+			expectTable: "app.users",
+			expectKey:   map[string]interface{}{"id": json.Number("2")},
+			expectUser:  &User{ID: 2, Username: "Ann"},
+		},
+		{
+			description: "delete",
+			data:        `{"action":"D","schema":"public","table":"users","identity":[{"name":"id","type":"integer","value":3}]}`,
+			expectType:  ChangeTypeDelete,
+			expectTable: "users",
+			expectKey:   map[string]interface{}{"id": json.Number("3")},
+			expectUser:  &User{ID: 3},
+		},
+		{
+			description: "commit",
+			data:        `{"action":"C"}`,
+		},
+	}
+	for _, useCase := range useCases {
+		event, err := newWal2JSONChangeEvent("0/16B3748", []byte(useCase.data))
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		if useCase.expectType == "" {
+			assert.Nil(t, event, useCase.description)
+			continue
+		}
+		assert.Equal(t, useCase.expectType, event.Type, useCase.description)
+		assert.Equal(t, useCase.expectTable, event.Table, useCase.description)
+		assert.Equal(t, "0/16B3748", event.Token, useCase.description)
+		assert.EqualValues(t, useCase.expectKey, event.Key, useCase.description)
+		var user = User{}
+		if assert.Nil(t, event.Map(&user, nil), useCase.description) {
+			assert.EqualValues(t, useCase.expectUser, &user, useCase.description)
+		}
+	}
+	_, err := newWal2JSONChangeEvent("0/16B3748", []byte("{"))
+	assert.NotNil(t, err)
+}
+
+func TestManager_ChangeStream(t *testing.T) {
+	manager, err := NewManagerFactory().Create(NewConfig("sqlite3", "[url]", "url:./test/change.db"))
+	if !assert.Nil(t, err) {
+		return
+	}
+	_, err = manager.(ChangeStreamer).ChangeStream(nil)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,316 @@
+package dsc
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/viant/toolbox"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+var valuerType = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+var sqlScannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+
+// isCompositeType returns true for slices (except []byte), maps and structs (except time.Time) mapped to array or JSON column
+func isCompositeType(aType reflect.Type) bool {
+	if aType.Kind() == reflect.Ptr {
+		aType = aType.Elem()
+	}
+	switch aType.Kind() {
+	case reflect.Slice:
+		return aType.Elem().Kind() != reflect.Uint8
+	case reflect.Map:
+		return true
+	case reflect.Struct:
+		return aType != timeType
+	}
+	return false
+}
+
+// compositeValue represents slice bound as array literal, or map/struct bound as JSON
+type compositeValue struct {
+	value interface{}
+}
+
+// Value returns array literal or JSON text
+func (v *compositeValue) Value() (driver.Value, error) {
+	value := reflect.ValueOf(v.value)
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return nil, nil
+		}
+		value = value.Elem()
+	}
+	if value.Kind() == reflect.Slice {
+		if value.IsNil() {
+			return nil, nil
+		}
+		return encodeArray(value), nil
+	}
+	if value.Kind() == reflect.Map && value.IsNil() {
+		return nil, nil
+	}
+	encoded, err := json.Marshal(value.Interface())
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %T as JSON due to %v", v.value, err)
+	}
+	return string(encoded), nil
+}
+
+// compositeParameters wraps slice, map and struct parameters with compositeValue if dialect supports composite types
+func compositeParameters(dialect DatastoreDialect, parameters []interface{}) []interface{} {
+	if dialect == nil || !dialectCapabilities(dialect).CompositeTypes {
+		return parameters
+	}
+	var result []interface{}
+	for i, parameter := range parameters {
+		if parameter == nil {
+			continue
+		}
+		parameterType := reflect.TypeOf(parameter)
+		if parameterType.Implements(valuerType) || !isCompositeType(parameterType) {
+			continue
+		}
+		if result == nil {
+			result = append([]interface{}{}, parameters...)
+		}
+		result[i] = &compositeValue{value: parameter}
+	}
+	if result == nil {
+		return parameters
+	}
+	return result
+}
+
+// encodeArray encodes slice as postgres array literal, i.e. {"a","b"} or {{1,2},{3,4}}
+func encodeArray(value reflect.Value) string {
+	var elements = make([]string, value.Len())
+	for i := 0; i < value.Len(); i++ {
+		item := value.Index(i)
+		for item.Kind() == reflect.Ptr || item.Kind() == reflect.Interface {
+			if item.IsNil() {
+				break
+			}
+			item = item.Elem()
+		}
+		switch item.Kind() {
+		case reflect.Ptr, reflect.Interface:
+			elements[i] = "NULL"
+		case reflect.Slice:
+			if item.Type().Elem().Kind() == reflect.Uint8 {
+				elements[i] = quoteArrayElement(string(item.Bytes()))
+			} else {
+				elements[i] = encodeArray(item)
+			}
+		case reflect.Bool:
+			elements[i] = strconv.FormatBool(item.Bool())
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+			reflect.Float32, reflect.Float64:
+			elements[i] = toolbox.AsString(item.Interface())
+		default:
+			if timeValue, ok := item.Interface().(time.Time); ok {
+				elements[i] = quoteArrayElement(timeValue.Format(time.RFC3339Nano))
+				continue
+			}
+			elements[i] = quoteArrayElement(toolbox.AsString(item.Interface()))
+		}
+	}
+	return "{" + strings.Join(elements, ",") + "}"
+}
+
+func quoteArrayElement(text string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(text) + `"`
+}
+
+// arrayParser represents postgres array literal parser
+type arrayParser struct {
+	text     string
+	position int
+}
+
+// parse parses array, elements are string, nil for NULL or []interface{} for nested array
+func (p *arrayParser) parse() ([]interface{}, error) {
+	if p.position >= len(p.text) || p.text[p.position] != '{' {
+		return nil, fmt.Errorf("failed to parse array %v: expected '{' at %v", p.text, p.position)
+	}
+	p.position++
+	var result = make([]interface{}, 0)
+	if p.position < len(p.text) && p.text[p.position] == '}' {
+		p.position++
+		return result, nil
+	}
+	for p.position < len(p.text) {
+		var element interface{}
+		switch p.text[p.position] {
+		case '{':
+			nested, err := p.parse()
+			if err != nil {
+				return nil, err
+			}
+			element = nested
+		case '"':
+			p.position++
+			var quoted = make([]byte, 0)
+			for p.position < len(p.text) && p.text[p.position] != '"' {
+				if p.text[p.position] == '\\' && p.position+1 < len(p.text) {
+					p.position++
+				}
+				quoted = append(quoted, p.text[p.position])
+				p.position++
+			}
+			p.position++
+			element = string(quoted)
+		default:
+			start := p.position
+			for p.position < len(p.text) && p.text[p.position] != ',' && p.text[p.position] != '}' {
+				p.position++
+			}
+			text := strings.TrimSpace(p.text[start:p.position])
+			if strings.EqualFold(text, "NULL") {
+				element = nil
+			} else {
+				element = text
+			}
+		}
+		result = append(result, element)
+		if p.position >= len(p.text) {
+			break
+		}
+		switch p.text[p.position] {
+		case ',':
+			p.position++
+		case '}':
+			p.position++
+			return result, nil
+		default:
+			return nil, fmt.Errorf("failed to parse array %v: unexpected '%c' at %v", p.text, p.text[p.position], p.position)
+		}
+	}
+	return nil, fmt.Errorf("failed to parse array %v: missing '}'", p.text)
+}
+
+// compositeScanner represents a scanner of array or JSON column into slice, map or struct field
+type compositeScanner struct {
+	target reflect.Value
+}
+
+// Scan decodes postgres array literal or JSON into target
+func (s *compositeScanner) Scan(src interface{}) error {
+	var text string
+	switch actual := src.(type) {
+	case nil:
+		s.target.Set(reflect.Zero(s.target.Type()))
+		return nil
+	case []byte:
+		text = string(actual)
+	case string:
+		text = actual
+	default:
+		return fmt.Errorf("failed to scan %T into %v", src, s.target.Type())
+	}
+	text = strings.TrimSpace(text)
+	targetType := s.target.Type()
+	if targetType.Kind() == reflect.Ptr {
+		targetType = targetType.Elem()
+	}
+	if targetType.Kind() == reflect.Slice && strings.HasPrefix(text, "{") {
+		parser := &arrayParser{text: text}
+		elements, err := parser.parse()
+		if err != nil {
+			return err
+		}
+		value := reflect.New(targetType).Elem()
+		if err = assignArray(value, elements); err != nil {
+			return err
+		}
+		return s.assign(value)
+	}
+	value := reflect.New(targetType)
+	if err := json.Unmarshal([]byte(text), value.Interface()); err != nil {
+		return fmt.Errorf("failed to decode JSON into %v due to %v", targetType, err)
+	}
+	return s.assign(value.Elem())
+}
+
+func (s *compositeScanner) assign(value reflect.Value) error {
+	if s.target.Kind() == reflect.Ptr {
+		pointer := reflect.New(value.Type())
+		pointer.Elem().Set(value)
+		s.target.Set(pointer)
+		return nil
+	}
+	s.target.Set(value)
+	return nil
+}
+
+// assignArray assigns parsed array elements to target slice
+func assignArray(target reflect.Value, elements []interface{}) error {
+	target.Set(reflect.MakeSlice(target.Type(), len(elements), len(elements)))
+	for i, element := range elements {
+		item := target.Index(i)
+		switch actual := element.(type) {
+		case nil:
+			continue
+		case []interface{}:
+			if item.Kind() != reflect.Slice {
+				return fmt.Errorf("failed to assign nested array to %v", item.Type())
+			}
+			if err := assignArray(item, actual); err != nil {
+				return err
+			}
+		case string:
+			if item.Kind() == reflect.Bool {
+				item.SetBool(actual == "t" || strings.EqualFold(actual, "true"))
+				continue
+			}
+			if err := toolbox.DefaultConverter.AssignConverted(item.Addr().Interface(), actual); err != nil {
+				return fmt.Errorf("failed to assign array element %v due to %v", actual, err)
+			}
+		}
+	}
+	return nil
+}
+
+// compositeTypesScanner represents a scanner decoding array and JSON columns into slice, map or struct destinations
+type compositeTypesScanner struct {
+	Scanner
+	composite map[reflect.Type]bool
+}
+
+// Scan scans composite destinations with compositeScanner, other destinations are passed as is
+func (s *compositeTypesScanner) Scan(destinations ...interface{}) error {
+	var scanned []interface{}
+	for i, destination := range destinations {
+		if destination == nil {
+			continue
+		}
+		destinationType := reflect.TypeOf(destination)
+		composite, ok := s.composite[destinationType]
+		if !ok {
+			composite = destinationType.Kind() == reflect.Ptr && isCompositeType(destinationType.Elem()) && !destinationType.Implements(sqlScannerType)
+			s.composite[destinationType] = composite
+		}
+		if !composite {
+			continue
+		}
+		if scanned == nil {
+			scanned = append([]interface{}{}, destinations...)
+		}
+		scanned[i] = &compositeScanner{target: reflect.ValueOf(destination).Elem()}
+	}
+	if scanned == nil {
+		return s.Scanner.Scan(destinations...)
+	}
+	return s.Scanner.Scan(scanned...)
+}
+
+func newCompositeTypesScanner() *compositeTypesScanner {
+	return &compositeTypesScanner{composite: make(map[reflect.Type]bool)}
+}
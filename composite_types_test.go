@@ -0,0 +1,85 @@
+package dsc
+
+import (
+	"database/sql/driver"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type profile struct {
+	Theme string `json:"theme"`
+	Size  int    `json:"size"`
+}
+
+type account struct {
+	Id       int               `column:"id"`
+	Tags     []string          `column:"tags"`
+	Scores   [][]int           `column:"scores"`
+	Settings map[string]string `column:"settings"`
+	Profile  *profile          `column:"profile"`
+}
+
+func TestCompositeParameters(t *testing.T) {
+	parameters := []interface{}{1, []string{`a"b`, "c,d"}, [][]int{{1, 2}, {3, 4}}, map[string]string{"k": "v"}, &profile{Theme: "dark"}, []byte("raw")}
+	assert.Equal(t, parameters, compositeParameters(GetDatastoreDialect("sqlite3"), parameters))
+
+	bound := compositeParameters(GetDatastoreDialect("postgres"), parameters)
+	assert.Equal(t, 1, bound[0])
+	assert.Equal(t, []byte("raw"), bound[5])
+	var expected = []driver.Value{`{"a\"b","c,d"}`, "{{1,2},{3,4}}", `{"k":"v"}`, `{"theme":"dark","size":0}`}
+	for i, value := range expected {
+		valuer, ok := bound[i+1].(driver.Valuer)
+		if assert.True(t, ok) {
+			actual, err := valuer.Value()
+			assert.Nil(t, err)
+			assert.Equal(t, value, actual)
+		}
+	}
+	var nilTags []string
+	actual, err := (&compositeValue{value: nilTags}).Value()
+	assert.Nil(t, err)
+	assert.Nil(t, actual)
+}
+
+func TestArrayParser_Parse(t *testing.T) {
+	parser := &arrayParser{text: `{"a\"b",NULL,plain,{1,2},{}}`}
+	elements, err := parser.parse()
+	assert.Nil(t, err)
+	assert.EqualValues(t, []interface{}{`a"b`, nil, "plain", []interface{}{"1", "2"}, []interface{}{}}, elements)
+
+	for _, invalid := range []string{"a,b", "{a,b", `{"a"x}`} {
+		_, err = (&arrayParser{text: invalid}).parse()
+		assert.NotNil(t, err, invalid)
+	}
+}
+
+func TestCompositeTypesScanner(t *testing.T) {
+	config := NewConfig("sqlite3", "[url]", "url:./test/foo.db")
+	manager, err := NewManagerFactory().Create(config)
+	if !assert.Nil(t, err) {
+		return
+	}
+	for _, SQL := range []string{
+		"DROP TABLE IF EXISTS accounts",
+		"CREATE TABLE accounts(id INTEGER PRIMARY KEY, tags TEXT, scores TEXT, settings TEXT, profile TEXT)",
+		`INSERT INTO accounts(id, tags, scores, settings, profile) VALUES(1, '{"x y",z}', '{{1,2},{3,4}}', '{"k":"v"}', '{"theme":"dark","size":12}')`,
+		`INSERT INTO accounts(id, tags, scores, settings, profile) VALUES(2, '["json"]', NULL, NULL, NULL)`,
+	} {
+		_, err := manager.Execute(SQL)
+		if !assert.Nil(t, err) {
+			return
+		}
+	}
+	var accounts = make([]*account, 0)
+	err = manager.ReadAll(&accounts, "SELECT id, tags, scores, settings, profile FROM accounts ORDER BY id", nil, nil)
+	if assert.Nil(t, err) && assert.Equal(t, 2, len(accounts)) {
+		assert.EqualValues(t, []string{"x y", "z"}, accounts[0].Tags)
+		assert.EqualValues(t, [][]int{{1, 2}, {3, 4}}, accounts[0].Scores)
+		assert.EqualValues(t, map[string]string{"k": "v"}, accounts[0].Settings)
+		assert.EqualValues(t, &profile{Theme: "dark", Size: 12}, accounts[0].Profile)
+		assert.EqualValues(t, []string{"json"}, accounts[1].Tags)
+		assert.Nil(t, accounts[1].Scores)
+		assert.Nil(t, accounts[1].Profile)
+	}
+}
@@ -0,0 +1,222 @@
+package dsc
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/viant/toolbox"
+)
+
+// ConcurrencyConflictError represents optimistic concurrency conflict, it is returned when passed in token does not match current row version.
+type ConcurrencyConflictError struct {
+	Table string
+	Key   []interface{}
+	//Token represents token supplied by the caller (If-Match)
+	Token string
+	//CurrentToken represents current row token, it is empty if the row does not exist
+	CurrentToken string
+}
+
+// Error returns error message
+func (e *ConcurrencyConflictError) Error() string {
+	if e.CurrentToken == "" {
+		return fmt.Sprintf("concurrency conflict on %v%v: row does not exist", e.Table, e.Key)
+	}
+	return fmt.Sprintf("concurrency conflict on %v%v: token %v does not match %v", e.Table, e.Key, e.Token, e.CurrentToken)
+}
+
+// IsConcurrencyConflict returns true if error is (or wraps) ConcurrencyConflictError
+func IsConcurrencyConflict(err error) bool {
+	var conflict *ConcurrencyConflictError
+	return errors.As(err, &conflict)
+}
+
+// ConcurrencyGuard derives opaque concurrency tokens (ETag) from a version or checksum column and guards updates with If-Match semantics.
+type ConcurrencyGuard struct {
+	manager       Manager
+	table         string
+	versionColumn string
+}
+
+// NewConcurrencyToken returns opaque quoted token for table row key and version
+func NewConcurrencyToken(table string, key []interface{}, version interface{}) string {
+	hash := sha256.New()
+	hash.Write([]byte(table))
+	for _, value := range key {
+		hash.Write([]byte{0})
+		hash.Write([]byte(toolbox.AsString(value)))
+	}
+	hash.Write([]byte{0})
+	hash.Write([]byte(toolbox.AsString(version)))
+	return `"` + base64.RawURLEncoding.EncodeToString(hash.Sum(nil)[:18]) + `"`
+}
+
+// matchesToken returns true if If-Match header value matches token, it supports '*', weak tokens and coma separated token list
+func matchesToken(ifMatch, token string) bool {
+	for _, candidate := range strings.Split(ifMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" {
+			return true
+		}
+		candidate = strings.TrimPrefix(candidate, "W/")
+		if strings.Trim(candidate, `"`) == strings.Trim(token, `"`) {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *ConcurrencyGuard) metaProvider(instance interface{}) (*metaDmlProvider, error) {
+	targetType := toolbox.DiscoverTypeByKind(instance, reflect.Struct)
+	provider, err := newMetaDmlProvider(g.table, targetType, identifierQuoter(g.manager.Config()))
+	if err != nil {
+		return nil, err
+	}
+	result := provider.(*metaDmlProvider)
+	if len(result.pkColumns()) == 0 {
+		return nil, fmt.Errorf("failed to guard %v: primary key was not defined", g.table)
+	}
+	return result, nil
+}
+
+// Token returns current token of passed in instance, derived from its version column value
+func (g *ConcurrencyGuard) Token(instance interface{}) (string, error) {
+	provider, err := g.metaProvider(instance)
+	if err != nil {
+		return "", err
+	}
+	version := provider.readValues(instance, []string{g.versionColumn})
+	return NewConcurrencyToken(g.table, provider.Key(instance), version[0]), nil
+}
+
+func (g *ConcurrencyGuard) readVersion(connection Connection, pkColumns []string, key []interface{}) (interface{}, bool, error) {
+	quote := identifierQuoter(g.manager.Config())
+	SQL := fmt.Sprintf(querySQLTemplate, quoteIdentifier(quote, g.versionColumn), quoteIdentifier(quote, g.table), buildAssignValueSQL(quoteIdentifiers(quote, pkColumns), " AND "))
+	var record = make([]interface{}, 0)
+	success, err := g.manager.ReadSingleOnConnection(connection, &record, SQL, key, nil)
+	if err != nil || !success || len(record) == 0 {
+		return nil, false, err
+	}
+	return record[0], true, nil
+}
+
+// CurrentToken returns token of the row identified by passed in key, or false if row does not exist
+func (g *ConcurrencyGuard) CurrentToken(instance interface{}) (string, bool, error) {
+	provider, err := g.metaProvider(instance)
+	if err != nil {
+		return "", false, err
+	}
+	connection, err := g.manager.ConnectionProvider().Get()
+	if err != nil {
+		return "", false, err
+	}
+	defer connection.Close()
+	key := provider.Key(instance)
+	version, found, err := g.readVersion(connection, provider.pkColumns(), key)
+	if err != nil || !found {
+		return "", found, err
+	}
+	return NewConcurrencyToken(g.table, key, version), true, nil
+}
+
+// nextVersion returns incremented numeric version, or instance version for checksum (non numeric) columns
+func nextVersion(current, instanceVersion interface{}) interface{} {
+	if toolbox.IsInt(current) || toolbox.IsFloat(current) {
+		return toolbox.AsInt(current) + 1
+	}
+	if text, ok := current.([]byte); ok {
+		current = string(text)
+	}
+	if text, ok := current.(string); ok && toolbox.CanConvertToInt(text) {
+		return toolbox.AsInt(text) + 1
+	}
+	return instanceVersion
+}
+
+// Update updates instance if ifMatch token matches current row token, numeric version column is incremented (and set back on instance pointer),
+// checksum column is expected to be already recomputed on the instance. It returns new row token or ConcurrencyConflictError.
+func (g *ConcurrencyGuard) Update(instance interface{}, ifMatch string) (string, error) {
+	provider, err := g.metaProvider(instance)
+	if err != nil {
+		return "", err
+	}
+	connection, err := g.manager.ConnectionProvider().Get()
+	if err != nil {
+		return "", err
+	}
+	defer connection.Close()
+	if err = connection.Begin(); err != nil {
+		return "", err
+	}
+	token, err := g.update(connection, provider, instance, ifMatch)
+	if err != nil {
+		_ = connection.Rollback()
+		return "", err
+	}
+	return token, connection.Commit()
+}
+
+func (g *ConcurrencyGuard) update(connection Connection, provider *metaDmlProvider, instance interface{}, ifMatch string) (string, error) {
+	key := provider.Key(instance)
+	current, found, err := g.readVersion(connection, provider.pkColumns(), key)
+	if err != nil {
+		return "", err
+	}
+	if !found {
+		return "", &ConcurrencyConflictError{Table: g.table, Key: key, Token: ifMatch}
+	}
+	currentToken := NewConcurrencyToken(g.table, key, current)
+	if !matchesToken(ifMatch, currentToken) {
+		return "", &ConcurrencyConflictError{Table: g.table, Key: key, Token: ifMatch, CurrentToken: currentToken}
+	}
+	parametrizedSQL := provider.Get(SQLTypeUpdate, instance)
+	version := nextVersion(current, provider.readValues(instance, []string{g.versionColumn})[0])
+	var versionUpdated = false
+	for i, column := range parametrizedSQL.Columns {
+		if strings.EqualFold(strings.Trim(column, "`"), g.versionColumn) {
+			parametrizedSQL.Values[i] = version
+			versionUpdated = true
+		}
+	}
+	if !versionUpdated {
+		return "", fmt.Errorf("failed to update %v: version column %v was omitted from update", g.table, g.versionColumn)
+	}
+	SQL := parametrizedSQL.SQL + " AND " + quoteIdentifier(identifierQuoter(g.manager.Config()), g.versionColumn) + " = ?"
+	result, err := g.manager.ExecuteOnConnection(connection, SQL, append(parametrizedSQL.Values, current))
+	if err != nil {
+		return "", err
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return "", &ConcurrencyConflictError{Table: g.table, Key: key, Token: ifMatch, CurrentToken: currentToken}
+	}
+	g.setVersion(provider, instance, version)
+	return NewConcurrencyToken(g.table, key, version), nil
+}
+
+func (g *ConcurrencyGuard) setVersion(provider *metaDmlProvider, instance interface{}, version interface{}) {
+	value := reflect.ValueOf(instance)
+	if value.Kind() != reflect.Ptr {
+		return
+	}
+	fieldName, ok := provider.columnToFieldNameMap[strings.ToLower(g.versionColumn)]["fieldName"]
+	if !ok {
+		return
+	}
+	field := value.Elem().FieldByName(fieldName)
+	if field.IsValid() && field.CanSet() {
+		_ = toolbox.DefaultConverter.AssignConverted(field.Addr().Interface(), version)
+	}
+}
+
+// NewConcurrencyGuard creates a concurrency guard for passed in table and version (or checksum) column
+func NewConcurrencyGuard(manager Manager, table, versionColumn string) *ConcurrencyGuard {
+	return &ConcurrencyGuard{
+		manager:       manager,
+		table:         table,
+		versionColumn: versionColumn,
+	}
+}
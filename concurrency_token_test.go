@@ -0,0 +1,91 @@
+package dsc_test
+
+import (
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/dsc"
+)
+
+type versionedArticle struct {
+	Id      int `primaryKey:"true"`
+	Title   string
+	Version int
+}
+
+type omittingArticle struct {
+	Id      int    `primaryKey:"true"`
+	Title   string `zeroValue:"omit"`
+	Version int
+}
+
+func TestConcurrencyGuard_Update(t *testing.T) {
+	config := dsc.NewConfig("sqlite3", "[url]", "url:./test/concurrency.db")
+	manager, err := dsc.NewManagerFactory().Create(config)
+	if !assert.Nil(t, err) {
+		return
+	}
+	for _, SQL := range []string{
+		"DROP TABLE IF EXISTS articles",
+		"CREATE TABLE articles(id INTEGER PRIMARY KEY, title TEXT, version INTEGER)",
+		"INSERT INTO articles(id, title, version) VALUES(1, 'draft', 1)",
+	} {
+		_, err = manager.Execute(SQL)
+		if !assert.Nil(t, err) {
+			return
+		}
+	}
+	guard := dsc.NewConcurrencyGuard(manager, "articles", "version")
+	article := &versionedArticle{Id: 1, Title: "draft", Version: 1}
+	token, err := guard.Token(article)
+	assert.Nil(t, err)
+	currentToken, found, err := guard.CurrentToken(article)
+	assert.Nil(t, err)
+	assert.True(t, found)
+	assert.EqualValues(t, token, currentToken)
+
+	article.Title = "published"
+	newToken, err := guard.Update(article, token)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.NotEqual(t, token, newToken)
+	assert.EqualValues(t, 2, article.Version)
+
+	stale := &versionedArticle{Id: 1, Title: "stale", Version: 1}
+	_, err = guard.Update(stale, token)
+	assert.True(t, dsc.IsConcurrencyConflict(err))
+
+	article.Title = "weak match"
+	_, err = guard.Update(article, "W/"+newToken)
+	assert.Nil(t, err)
+
+	missing := &versionedArticle{Id: 10, Title: "none", Version: 1}
+	_, err = guard.Update(missing, "*")
+	assert.True(t, dsc.IsConcurrencyConflict(err))
+
+	var records = make([]versionedArticle, 0)
+	err = manager.ReadAll(&records, "SELECT id, title, version FROM articles", nil, nil)
+	assert.Nil(t, err)
+	if assert.Equal(t, 1, len(records)) {
+		assert.EqualValues(t, "weak match", records[0].Title)
+		assert.EqualValues(t, 3, records[0].Version)
+	}
+
+	//omitted zero value title shifts version parameter position
+	omitting := &omittingArticle{Id: 1, Version: 3}
+	token, err = guard.Token(omitting)
+	assert.Nil(t, err)
+	_, err = guard.Update(omitting, token)
+	if !assert.Nil(t, err) {
+		return
+	}
+	records = make([]versionedArticle, 0)
+	err = manager.ReadAll(&records, "SELECT id, title, version FROM articles", nil, nil)
+	assert.Nil(t, err)
+	if assert.Equal(t, 1, len(records)) {
+		assert.EqualValues(t, "weak match", records[0].Title, "omitted column should keep current value")
+		assert.EqualValues(t, 4, records[0].Version)
+	}
+}
@@ -25,9 +25,23 @@ type Config struct {
 	PoolSize    int
 	MaxPoolSize int
 	//@deprecated use DSN instead
-	Descriptor          string `json:"-"`
-	DSN                 string
-	InitSQL             []string
+	Descriptor string `json:"-"`
+	DSN        string
+	InitSQL    []string
+	//SessionSettings represents driver specific session settings (i.e. statement_timeout, sql_mode, NLS_DATE_FORMAT) applied by the dialect on each new connection
+	SessionSettings map[string]interface{}
+	//TLS represents TLS/mTLS options translated by the dialect into driver specific DSN parameters
+	TLS *TLSConfig
+	//Cache represents read-through result cache TTLs used by NewCachedManager
+	Cache *CacheConfig
+	//TypeMappings overrides dialect datastore to go type mapping for read values, i.e. TIMESTAMP: string, BIGINT UNSIGNED: uint64, NUMBER(1): bool
+	TypeMappings map[string]string
+	//QueryLogger receives executed statements details, see slowQueryThresholdMs and redactParameters parameters
+	QueryLogger QueryLogger `json:"-"`
+	//DryRun records executed statements into transcript instead of sending them to the datastore
+	DryRun *Transcript `json:"-"`
+	//ActorProvider provides actor stored by persist operations in created/updated by audit columns
+	ActorProvider       ActorProvider `json:"-"`
 	Parameters          map[string]interface{}
 	Credentials         string
 	MaxRequestPerSecond int
@@ -35,9 +49,13 @@ type Config struct {
 	username            string
 	password            string
 	dsnDescriptor       string
+	secrets             *secretCache
 	lock                *sync.Mutex
 	race                uint32
 	initRun             bool
+	profile             string
+	loader              func(URL, profile string) (*Config, error)
+	sources             []string
 	CredConfig          *cred.Generic `json:"-"`
 }
 
@@ -51,14 +69,33 @@ func (c *Config) Get(name string) string {
 	return ""
 }
 
-// DsnDescriptor return dsn expanded descriptor or error
+// DsnDescriptor return dsn expanded descriptor or error, [secret:key] references are resolved with registered secret providers.
 func (c *Config) DsnDescriptor() (string, error) {
 	if c.dsnDescriptor == "" {
 		if err := c.Init(); err != nil {
 			return "", err
 		}
 	}
-	return c.dsnDescriptor, nil
+	if !strings.Contains(c.dsnDescriptor, secretMacroPrefix) {
+		return c.dsnDescriptor, nil
+	}
+	return c.secretCache().expand(context.Background(), c, c.dsnDescriptor)
+}
+
+// secretCache returns secret cache, it is created by Init or on first use by config created without Init
+func (c *Config) secretCache() *secretCache {
+	c.initLock()
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.secrets == nil {
+		c.secrets = newSecretCache()
+	}
+	return c.secrets
+}
+
+// RefreshSecrets discards cached secret values, so that the next connection resolves rotated secrets.
+func (c *Config) RefreshSecrets() {
+	c.secretCache().reset()
 }
 
 // Get returns value for passed in parameter name or panic - please use Config.Has to check if value is present.
@@ -73,11 +110,15 @@ func (c *Config) GetMap(name string) map[string]interface{} {
 	return nil
 }
 
-// GetInt returns value for passed in parameter name or defaultValue
+// GetInt returns value for passed in parameter name or defaultValue, value can use size unit suffix (i.e. 64KB, 64MiB) (see ParseSize),
+// invalid value of dsc size parameter fails Init, other value that is not a size is converted with toolbox.AsInt
 func (c *Config) GetInt(name string, defaultValue int) int {
 	c.lock.Lock()
 	defer c.lock.Unlock()
 	if result, ok := c.Parameters[name]; ok {
+		if value, err := ParseSize(result); err == nil {
+			return value
+		}
 		return toolbox.AsInt(result)
 	}
 	return defaultValue
@@ -93,12 +134,18 @@ func (c *Config) GetFloat(name string, defaultValue float64) float64 {
 	return defaultValue
 }
 
-// GetDuration returns value for passed in parameter name or defaultValue
+// GetDuration returns value for passed in parameter name or defaultValue, number is multiplied by multiplier, value can use unit suffix (i.e. 500ms, 2m),
+// invalid value of dsc duration or *Ms parameter fails Init, other invalid value returns defaultValue (see ParseDuration)
 func (c *Config) GetDuration(name string, multiplier time.Duration, defaultValue time.Duration) time.Duration {
 	c.lock.Lock()
 	defer c.lock.Unlock()
 	if result, ok := c.Parameters[name]; ok {
-		return time.Duration(toolbox.AsInt(result)) * multiplier
+		value, err := ParseDuration(result, multiplier)
+		if err != nil {
+			Logf("invalid %v config parameter: %v", name, err)
+			return defaultValue
+		}
+		return value
 	}
 	return defaultValue
 }
@@ -183,7 +230,8 @@ func (c *Config) ApplyCredentials(config *cred.Generic) error {
 	return nil
 }
 
-// Init makes parameter map from encoded parameters if presents, expands descriptor with parameter value using [param_name] matching pattern.
+// Init makes parameter map from encoded parameters if presents, expands ${ENV_VAR} and ${param} (or ${name:-default}) placeholders in descriptor and parameters,
+// and expands descriptor with parameter value using [param_name] matching pattern. It returns error if any ${...} placeholder was not resolved.
 func (c *Config) Init() error {
 	defer func() { c.initRun = true }()
 	if c.cred == "" {
@@ -208,7 +256,17 @@ func (c *Config) Init() error {
 	if c.Descriptor == "" {
 		c.Descriptor = c.DSN
 	}
-	c.dsnDescriptor = c.Descriptor
+	if c.secrets == nil {
+		c.secrets = newSecretCache()
+	}
+	descriptor, err := expandTemplates(c.Descriptor, c.Parameters)
+	if err != nil {
+		return err
+	}
+	if err = validateUnitParameters(c.Parameters); err != nil {
+		return err
+	}
+	c.dsnDescriptor = descriptor
 
 	c.dsnDescriptor = strings.Replace(c.dsnDescriptor, "[username]", c.username, 1)
 	c.dsnDescriptor = strings.Replace(c.dsnDescriptor, "[password]", c.password, 1)
@@ -223,6 +281,29 @@ func (c *Config) Init() error {
 	return nil
 }
 
+// reloaded returns initialised copy of passed in config replacing this config, settings that are not serializable are kept from this config if not set,
+// this config is not modified, so that it can be still read by in-flight operations
+func (c *Config) reloaded(source *Config) (*Config, error) {
+	result := source.Clone()
+	if err := result.Init(); err != nil {
+		return nil, err
+	}
+	if result.QueryLogger == nil { //logger is not serializable, config reloaded from URL keeps the current one
+		result.QueryLogger = c.QueryLogger
+	}
+	if result.DryRun == nil {
+		result.DryRun = c.DryRun
+	}
+	if result.ActorProvider == nil {
+		result.ActorProvider = c.ActorProvider
+	}
+	result.CredConfig = source.CredConfig
+	if result.MaxPoolSize == 0 {
+		result.MaxPoolSize = 1
+	}
+	return result, nil
+}
+
 // Clone clones config
 func (c *Config) Clone() *Config {
 	cred := c.cred
@@ -233,6 +314,13 @@ func (c *Config) Clone() *Config {
 		DriverName:          c.DriverName,
 		URL:                 c.URL,
 		InitSQL:             c.InitSQL,
+		SessionSettings:     c.SessionSettings,
+		TLS:                 c.TLS,
+		Cache:               c.Cache,
+		TypeMappings:        c.TypeMappings,
+		QueryLogger:         c.QueryLogger,
+		DryRun:              c.DryRun,
+		ActorProvider:       c.ActorProvider,
 		Descriptor:          c.Descriptor,
 		Driver:              c.Driver,
 		DSN:                 c.DSN,
@@ -243,9 +331,13 @@ func (c *Config) Clone() *Config {
 		username:            c.username,
 		password:            c.password,
 		dsnDescriptor:       c.dsnDescriptor,
+		secrets:             newSecretCache(),
 		lock:                &sync.Mutex{},
 		Credentials:         cred,
 		cred:                c.cred,
+		profile:             c.profile,
+		loader:              c.loader,
+		sources:             c.sources,
 	}
 	if len(c.Parameters) > 0 {
 		for k, v := range c.Parameters {
@@ -281,7 +373,27 @@ func NewConfigWithParameters(driverName string, descriptor string, credential st
 	return result, err
 }
 
-// NewConfigFromUrl returns new config from url
+// reload loads config again from its URL with the loader and profile it was created with
+func (c *Config) reload() (*Config, error) {
+	if c.loader != nil {
+		return c.loader(c.URL, c.profile)
+	}
+	return NewConfigFromURL(c.URL)
+}
+
+// sourceURLs returns URLs of all documents config was loaded from, including merged includes
+func (c *Config) sourceURLs() []string {
+	if len(c.sources) > 0 {
+		return c.sources
+	}
+	if c.URL == "" {
+		return nil
+	}
+	return []string{c.URL}
+}
+
+// NewConfigFromUrl returns new config from url, the url is retained as config source (see ConnectionProvider.Watch),
+// use NewConfigFromURLWithProfile to load config with includes, profiles, defaults and validation
 func NewConfigFromURL(URL string) (*Config, error) {
 	result := &Config{}
 	var resource = url.NewResource(URL)
@@ -290,5 +402,8 @@ func NewConfigFromURL(URL string) (*Config, error) {
 	if err == nil {
 		err = result.Init()
 	}
+	if result.URL == "" {
+		result.URL = URL
+	}
 	return result, err
 }
@@ -0,0 +1,228 @@
+package dsc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/viant/toolbox"
+	"github.com/viant/toolbox/url"
+	"gopkg.in/yaml.v2"
+)
+
+// ConfigProfileEnvKey represents environment variable selecting config profile
+const ConfigProfileEnvKey = "DSC_PROFILE"
+
+const (
+	configIncludeKey  = "Include"
+	configProfilesKey = "Profiles"
+)
+
+// normalizeConfigValue converts YAML decoded maps into map[string]interface{}
+func normalizeConfigValue(value interface{}) interface{} {
+	switch actual := value.(type) {
+	case map[interface{}]interface{}:
+		var result = make(map[string]interface{}, len(actual))
+		for k, v := range actual {
+			result[toolbox.AsString(k)] = normalizeConfigValue(v)
+		}
+		return result
+	case map[string]interface{}:
+		for k, v := range actual {
+			actual[k] = normalizeConfigValue(v)
+		}
+		return actual
+	case []interface{}:
+		for i, v := range actual {
+			actual[i] = normalizeConfigValue(v)
+		}
+		return actual
+	}
+	return value
+}
+
+// mergeConfigMaps deep merges overlay into base, overlay maps are merged recursively, other overlay values replace base values
+func mergeConfigMaps(base, overlay map[string]interface{}) map[string]interface{} {
+	for key, value := range overlay {
+		baseKey := key
+		for candidate := range base {
+			if strings.EqualFold(candidate, key) {
+				baseKey = candidate
+				break
+			}
+		}
+		overlayMap, isOverlayMap := value.(map[string]interface{})
+		baseMap, isBaseMap := base[baseKey].(map[string]interface{})
+		if isOverlayMap && isBaseMap {
+			base[baseKey] = mergeConfigMaps(baseMap, overlayMap)
+			continue
+		}
+		delete(base, baseKey)
+		base[key] = value
+	}
+	return base
+}
+
+// takeConfigKey removes and returns case insensitive key value
+func takeConfigKey(document map[string]interface{}, key string) (interface{}, bool) {
+	for candidate, value := range document {
+		if strings.EqualFold(candidate, key) {
+			delete(document, candidate)
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+// resolveConfigURL resolves relative include URL against including document URL
+func resolveConfigURL(baseURL, include string) string {
+	if strings.Contains(include, "://") || path.IsAbs(include) {
+		return include
+	}
+	if index := strings.LastIndex(baseURL, "/"); index != -1 {
+		return baseURL[:index+1] + include
+	}
+	return include
+}
+
+// loadConfigDocument loads JSON or YAML config document with its includes, included documents are merged in order before the including document,
+// URLs of loaded documents are appended to sources when it is not nil
+func loadConfigDocument(URL string, loading map[string]bool, sources *[]string) (map[string]interface{}, error) {
+	if loading[URL] {
+		return nil, fmt.Errorf("failed to load config %v due to cyclic include", URL)
+	}
+	loading[URL] = true
+	defer delete(loading, URL)
+	content, err := url.NewResource(URL).Download()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config %v due to %v", URL, err)
+	}
+	if sources != nil {
+		*sources = append(*sources, URL)
+	}
+	var document = make(map[string]interface{})
+	switch strings.ToLower(path.Ext(URL)) {
+	case ".yaml", ".yml":
+		var decoded interface{}
+		if err = yaml.Unmarshal(content, &decoded); err == nil {
+			if normalized, ok := normalizeConfigValue(decoded).(map[string]interface{}); ok {
+				document = normalized
+			} else if decoded != nil {
+				err = fmt.Errorf("expected map but had %T", decoded)
+			}
+		}
+	default:
+		decoder := json.NewDecoder(bytes.NewReader(content))
+		decoder.UseNumber()
+		err = decoder.Decode(&document)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode config %v due to %v", URL, err)
+	}
+	includes, ok := takeConfigKey(document, configIncludeKey)
+	if !ok {
+		return document, nil
+	}
+	var result = make(map[string]interface{})
+	for _, include := range toolbox.AsSlice(wrapConfigInclude(includes)) {
+		included, err := loadConfigDocument(resolveConfigURL(URL, toolbox.AsString(include)), loading, sources)
+		if err != nil {
+			return nil, err
+		}
+		result = mergeConfigMaps(result, included)
+	}
+	return mergeConfigMaps(result, document), nil
+}
+
+func wrapConfigInclude(includes interface{}) interface{} {
+	if toolbox.IsSlice(includes) {
+		return includes
+	}
+	return []interface{}{includes}
+}
+
+// applyConfigProfile merges selected profile overlay, it returns error if config defines profiles but not the selected one
+func applyConfigProfile(document map[string]interface{}, profile string) (map[string]interface{}, error) {
+	profiles, ok := takeConfigKey(document, configProfilesKey)
+	if !ok || profile == "" {
+		return document, nil
+	}
+	profileMap, ok := profiles.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected %v map but had %T", configProfilesKey, profiles)
+	}
+	overlay, ok := profileMap[profile]
+	if !ok {
+		return nil, fmt.Errorf("profile %v was not defined", profile)
+	}
+	overlayMap, ok := overlay.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected profile %v map but had %T", profile, overlay)
+	}
+	return mergeConfigMaps(document, overlayMap), nil
+}
+
+// applyDefaults sets pool defaults
+func (c *Config) applyDefaults() {
+	if c.PoolSize == 0 {
+		c.PoolSize = 1
+	}
+	if c.MaxPoolSize == 0 {
+		c.MaxPoolSize = 2
+	}
+	if c.MaxPoolSize < c.PoolSize {
+		c.MaxPoolSize = c.PoolSize
+	}
+}
+
+// validate checks that required settings are present and consistent
+func (c *Config) validate() error {
+	if c.DriverName == "" {
+		return fmt.Errorf("invalid config %v: driver was empty", c.URL)
+	}
+	if c.PoolSize < 0 || c.MaxPoolSize < 0 || c.MaxRequestPerSecond < 0 {
+		return fmt.Errorf("invalid config %v: pool sizes and max request per second can not be negative", c.URL)
+	}
+	return nil
+}
+
+// NewConfigFromURLWithProfile returns new config from JSON or YAML url, includes are merged first, then the document and finally the selected profile overlay.
+// Config document can use 'include' (url or list of urls, relative to the document) and 'profiles' (profile name to config overlay) keys;
+// when profile is empty, DSC_PROFILE environment variable is used. Defaults are applied and the resulting config is validated.
+// Config keeps the profile and loaded documents, so that provider Reload and Watch load it the same way.
+func NewConfigFromURLWithProfile(URL, profile string) (*Config, error) {
+	if profile == "" {
+		profile = os.Getenv(ConfigProfileEnvKey)
+	}
+	var sources []string
+	document, err := loadConfigDocument(URL, make(map[string]bool), &sources)
+	if err != nil {
+		return nil, err
+	}
+	if document, err = applyConfigProfile(document, profile); err != nil {
+		return nil, fmt.Errorf("failed to load config %v due to %v", URL, err)
+	}
+	encoded, err := json.Marshal(document)
+	if err != nil {
+		return nil, err
+	}
+	result := &Config{}
+	if err = json.Unmarshal(encoded, result); err != nil {
+		return nil, fmt.Errorf("failed to decode config %v due to %v", URL, err)
+	}
+	result.initLock()
+	if err = result.Init(); err != nil {
+		return nil, err
+	}
+	if result.URL == "" {
+		result.URL = URL
+	}
+	result.profile = profile
+	result.loader = NewConfigFromURLWithProfile
+	result.sources = sources
+	result.applyDefaults()
+	return result, result.validate()
+}
@@ -0,0 +1,131 @@
+package dsc
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/viant/toolbox"
+)
+
+// templateExpander expands ${name} and ${name:-default} placeholders with config parameters or environment variables,
+// parameters take precedence over environment variables
+type templateExpander struct {
+	parameters map[string]interface{}
+	lookupEnv  func(key string) (string, bool)
+	resolved   map[string]string
+	resolving  map[string]bool
+	unresolved map[string]bool
+}
+
+func (e *templateExpander) parameter(name string) (string, bool, error) {
+	if value, ok := e.resolved[name]; ok {
+		return value, true, nil
+	}
+	value, ok := e.parameters[name]
+	if !ok {
+		return "", false, nil
+	}
+	text, ok := value.(string)
+	if !ok {
+		return toolbox.AsString(value), true, nil
+	}
+	if e.resolving[name] {
+		return "", false, fmt.Errorf("failed to expand parameter %v due to cyclic reference", name)
+	}
+	e.resolving[name] = true
+	defer delete(e.resolving, name)
+	expanded, err := e.expand(text)
+	if err != nil {
+		return "", false, err
+	}
+	e.resolved[name] = expanded
+	return expanded, true, nil
+}
+
+func (e *templateExpander) lookup(expression string) (string, error) {
+	name, defaultValue, hasDefault := expression, "", false
+	if index := strings.Index(expression, ":-"); index != -1 {
+		name, defaultValue, hasDefault = expression[:index], expression[index+2:], true
+	}
+	value, ok, err := e.parameter(name)
+	if err != nil || ok {
+		return value, err
+	}
+	if value, ok := e.lookupEnv(name); ok {
+		return value, nil
+	}
+	if hasDefault {
+		return e.expand(defaultValue)
+	}
+	e.unresolved[name] = true
+	return "${" + expression + "}", nil
+}
+
+func (e *templateExpander) expand(text string) (string, error) {
+	if !strings.Contains(text, "${") {
+		return text, nil
+	}
+	var result = strings.Builder{}
+	for {
+		index := strings.Index(text, "${")
+		if index == -1 {
+			result.WriteString(text)
+			return result.String(), nil
+		}
+		result.WriteString(text[:index])
+		end := strings.Index(text[index:], "}")
+		if end == -1 {
+			return "", fmt.Errorf("failed to expand %v: missing closing brace", text[index:])
+		}
+		value, err := e.lookup(text[index+2 : index+end])
+		if err != nil {
+			return "", err
+		}
+		result.WriteString(value)
+		text = text[index+end+1:]
+	}
+}
+
+func (e *templateExpander) validate() error {
+	if len(e.unresolved) == 0 {
+		return nil
+	}
+	var names = make([]string, 0, len(e.unresolved))
+	for name := range e.unresolved {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return fmt.Errorf("failed to expand config: unresolved placeholders: %v", strings.Join(names, ", "))
+}
+
+func newTemplateExpander(parameters map[string]interface{}) *templateExpander {
+	return &templateExpander{
+		parameters: parameters,
+		lookupEnv:  os.LookupEnv,
+		resolved:   make(map[string]string),
+		resolving:  make(map[string]bool),
+		unresolved: make(map[string]bool),
+	}
+}
+
+// expandTemplates expands ${...} placeholders in string parameters (in place) and returns expanded descriptor
+func expandTemplates(descriptor string, parameters map[string]interface{}) (string, error) {
+	expander := newTemplateExpander(parameters)
+	for key, value := range parameters {
+		if _, ok := value.(string); !ok {
+			continue
+		}
+		expanded, _, err := expander.parameter(key)
+		if err != nil {
+			return "", err
+		}
+		parameters[key] = expanded
+	}
+	descriptor, err := expander.expand(descriptor)
+	if err != nil {
+		return "", err
+	}
+	return descriptor, expander.validate()
+}
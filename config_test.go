@@ -4,7 +4,10 @@ import (
 	"fmt"
 	"github.com/stretchr/testify/assert"
 	"github.com/viant/dsc"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestConfigHasDateLayout(t *testing.T) {
@@ -47,3 +50,124 @@ func TestGetPanic(t *testing.T) {
 	config.Get("abc")
 
 }
+
+func TestConfig_InitTemplateExpansion(t *testing.T) {
+	os.Setenv("DSC_TEST_DB_HOST", "db.prod")
+	defer os.Unsetenv("DSC_TEST_DB_HOST")
+	config, err := dsc.NewConfigWithParameters("mysql", "${user}:[password]@tcp(${address})/${dbname:-mydb}", "", map[string]interface{}{
+		"user":    "app",
+		"address": "${DSC_TEST_DB_HOST}:${port}",
+		"port":    3306,
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	dsn, err := config.DsnDescriptor()
+	assert.Nil(t, err)
+	assert.EqualValues(t, "app:@tcp(db.prod:3306)/mydb", dsn)
+	assert.EqualValues(t, "db.prod:3306", config.Get("address"))
+
+	_, err = dsc.NewConfigWithParameters("mysql", "${user}@tcp(${DSC_TEST_UNDEFINED_HOST})/${DSC_TEST_UNDEFINED_DB}", "", map[string]interface{}{"user": "app"})
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "DSC_TEST_UNDEFINED_DB, DSC_TEST_UNDEFINED_HOST")
+	}
+	_, err = dsc.NewConfigWithParameters("mysql", "${a}", "", map[string]interface{}{"a": "${b}", "b": "${a}"})
+	assert.NotNil(t, err)
+}
+
+func TestNewConfigFromURLWithProfile(t *testing.T) {
+	dir := t.TempDir()
+	_ = os.WriteFile(filepath.Join(dir, "common.yaml"), []byte(`
+driver: sqlite3
+dsn: "[url]"
+maxPoolSize: 4
+parameters:
+  url: ./test/common.db
+  batchSize: 100
+`), 0644)
+	_ = os.WriteFile(filepath.Join(dir, "app.yaml"), []byte(`
+include: common.yaml
+parameters:
+  dateFormat: yyyy-MM-dd
+profiles:
+  prod:
+    poolSize: 3
+    parameters:
+      url: ./test/prod.db
+`), 0644)
+	_ = os.WriteFile(filepath.Join(dir, "cycle.json"), []byte(`{"Include":["cycle.json"]}`), 0644)
+
+	config, err := dsc.NewConfigFromURLWithProfile(filepath.Join(dir, "app.yaml"), "")
+	if assert.Nil(t, err) {
+		assert.EqualValues(t, "sqlite3", config.DriverName)
+		assert.EqualValues(t, "./test/common.db", config.Get("url"))
+		assert.EqualValues(t, 100, config.GetInt("batchSize", 0))
+		assert.EqualValues(t, "yyyy-MM-dd", config.Get("dateFormat"))
+		assert.EqualValues(t, 1, config.PoolSize, "default should be applied")
+		assert.EqualValues(t, 4, config.MaxPoolSize)
+	}
+	config, err = dsc.NewConfigFromURLWithProfile(filepath.Join(dir, "app.yaml"), "prod")
+	if assert.Nil(t, err) {
+		assert.EqualValues(t, "./test/prod.db", config.Get("url"))
+		assert.EqualValues(t, 100, config.GetInt("batchSize", 0), "profile should be merged with defaults")
+		assert.EqualValues(t, 3, config.PoolSize)
+	}
+	os.Setenv(dsc.ConfigProfileEnvKey, "staging")
+	_, err = dsc.NewConfigFromURLWithProfile(filepath.Join(dir, "app.yaml"), "")
+	assert.NotNil(t, err, "undefined profile should fail")
+	os.Unsetenv(dsc.ConfigProfileEnvKey)
+
+	_, err = dsc.NewConfigFromURLWithProfile(filepath.Join(dir, "cycle.json"), "")
+	assert.NotNil(t, err)
+	_, err = dsc.NewConfigFromURLWithProfile(filepath.Join(dir, "common.yaml"), "")
+	assert.Nil(t, err)
+
+	_ = os.WriteFile(filepath.Join(dir, "plain.json"), []byte(`{"Parameters":{"url":"./test/plain.db"}}`), 0644)
+	config, err = dsc.NewConfigFromURL(filepath.Join(dir, "plain.json"))
+	if assert.Nil(t, err, "plain url config should not be validated") {
+		assert.EqualValues(t, "./test/plain.db", config.Get("url"))
+		assert.EqualValues(t, 0, config.PoolSize, "defaults should not be applied")
+	}
+	_, err = dsc.NewConfigFromURLWithProfile(filepath.Join(dir, "plain.json"), "")
+	assert.NotNil(t, err, "driver should be required")
+}
+
+func TestConfig_UnitValues(t *testing.T) {
+	config, err := dsc.NewConfigWithParameters("sqlite3", "[url]", "", map[string]interface{}{
+		"url":               "./test/foo.db",
+		"connMaxLifetimeMs": "2m",
+		"queryTimeoutMs":    1500,
+		"batchSize":         "2KiB",
+		"maxIdleConns":      "4",
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, 2*time.Minute, config.GetDuration("connMaxLifetimeMs", time.Millisecond, 0))
+	assert.Equal(t, 1500*time.Millisecond, config.GetDuration("queryTimeoutMs", time.Millisecond, 0))
+	assert.Equal(t, 2048, config.GetInt("batchSize", 0))
+	assert.Equal(t, 4, config.GetInt("maxIdleConns", 0))
+
+	_, err = dsc.NewConfigWithParameters("sqlite3", "[url]", "", map[string]interface{}{"url": "./test/foo.db", "queryTimeoutMs": "2 minutes"})
+	assert.NotNil(t, err)
+	_, err = dsc.NewConfigWithParameters("sqlite3", "[url]", "", map[string]interface{}{"url": "./test/foo.db", "batchSize": "64XB"})
+	assert.NotNil(t, err)
+	_, err = dsc.NewConfigWithParameters("sqlite3", "[url]", "", map[string]interface{}{"url": "./test/foo.db", dsc.BlobChunkSizeKey: "1 chunk"})
+	assert.NotNil(t, err)
+	_, err = dsc.NewConfigWithParameters("sqlite3", "[url]", "", map[string]interface{}{"url": "./test/foo.db", dsc.ValidationIdleMsKey: "soon"})
+	assert.NotNil(t, err)
+	config, err = dsc.NewConfigWithParameters("sqlite3", "[url]", "", map[string]interface{}{"url": "./test/foo.db", "retentionMs": "forever", "shards": "3.0"})
+	assert.Nil(t, err, "non dsc parameters should not be validated")
+	assert.Equal(t, 3, config.GetInt("shards", 0))
+
+	for value, expected := range map[string]int{"64MiB": 64 << 20, "1KB": 1000, "1.5KiB": 1536, "10B": 10} {
+		actual, err := dsc.ParseSize(value)
+		assert.Nil(t, err, value)
+		assert.Equal(t, expected, actual, value)
+	}
+	_, err = dsc.ParseSize("1.5B")
+	assert.NotNil(t, err)
+	duration, err := dsc.ParseDuration("500ms", time.Second)
+	assert.Nil(t, err)
+	assert.Equal(t, 500*time.Millisecond, duration)
+}
@@ -0,0 +1,110 @@
+package dsc
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// durationParameters represents config parameters validated as durations, other parameters are not validated
+var durationParameters = []string{connMaxLifetimeMsKey, connMaxIdleTimeMsKey, PoolAcquireTimeoutMsKey, QueryTimeoutMsKey, shutdownTimeoutMsKey, txRetryBackoffMsKey, slowQueryThresholdMsKey, secretRefreshMsKey, ValidationIdleMsKey}
+
+// sizeParameters represents config parameters validated as integers with optional size unit
+var sizeParameters = []string{BatchSizeKey, BlobChunkSizeKey, maxIdleConnsKey, maxOpenConnsKey, PoolMaxOpenKey, txMaxRetriesKey}
+
+// sizeUnits represents supported size suffixes, decimal (KB) and binary (KiB) units
+var sizeUnits = map[string]float64{
+	"B":   1,
+	"KB":  1000,
+	"MB":  1000 * 1000,
+	"GB":  1000 * 1000 * 1000,
+	"TB":  1000 * 1000 * 1000 * 1000,
+	"KIB": 1 << 10,
+	"MIB": 1 << 20,
+	"GIB": 1 << 30,
+	"TIB": 1 << 40,
+}
+
+// ParseDuration parses config duration value, numbers are multiplied by multiplier (i.e. time.Millisecond for *Ms parameters),
+// text with unit suffix uses time.ParseDuration format, i.e. "500ms", "2m", "1h30m"
+func ParseDuration(value interface{}, multiplier time.Duration) (time.Duration, error) {
+	switch actual := value.(type) {
+	case time.Duration:
+		return actual, nil
+	case int:
+		return time.Duration(actual) * multiplier, nil
+	case int64:
+		return time.Duration(actual) * multiplier, nil
+	case float64:
+		return time.Duration(actual * float64(multiplier)), nil
+	case string:
+		text := strings.TrimSpace(actual)
+		if number, err := strconv.ParseFloat(text, 64); err == nil {
+			return time.Duration(number * float64(multiplier)), nil
+		}
+		result, err := time.ParseDuration(text)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q, expected number or value with unit suffix (ns, us, ms, s, m, h)", actual)
+		}
+		return result, nil
+	}
+	return ParseDuration(fmt.Sprintf("%v", value), multiplier)
+}
+
+// ParseSize parses config integer value with optional size unit suffix, i.e. 512, "64KB", "64MiB"
+func ParseSize(value interface{}) (int, error) {
+	switch actual := value.(type) {
+	case int:
+		return actual, nil
+	case int64:
+		return int(actual), nil
+	case float64:
+		if actual != math.Trunc(actual) {
+			return 0, fmt.Errorf("invalid size %v, expected integer", actual)
+		}
+		return int(actual), nil
+	case string:
+		text := strings.TrimSpace(actual)
+		if result, err := strconv.Atoi(text); err == nil {
+			return result, nil
+		}
+		index := strings.IndexFunc(text, func(r rune) bool {
+			return (r < '0' || r > '9') && r != '.'
+		})
+		if index <= 0 {
+			return 0, fmt.Errorf("invalid size %q, expected integer with optional unit suffix (B, KB, MB, GB, TB, KiB, MiB, GiB, TiB)", actual)
+		}
+		unit, ok := sizeUnits[strings.ToUpper(strings.TrimSpace(text[index:]))]
+		number, err := strconv.ParseFloat(text[:index], 64)
+		if !ok || err != nil {
+			return 0, fmt.Errorf("invalid size %q, expected integer with optional unit suffix (B, KB, MB, GB, TB, KiB, MiB, GiB, TiB)", actual)
+		}
+		result := number * unit
+		if result != math.Trunc(result) || result > math.MaxInt32 {
+			return 0, fmt.Errorf("invalid size %q, expected integer up to %v", actual, math.MaxInt32)
+		}
+		return int(result), nil
+	}
+	return ParseSize(fmt.Sprintf("%v", value))
+}
+
+// validateUnitParameters returns error for dsc duration and size parameters that can not be parsed
+func validateUnitParameters(parameters map[string]interface{}) error {
+	for _, key := range durationParameters {
+		if value, ok := parameters[key]; ok {
+			if _, err := ParseDuration(value, time.Millisecond); err != nil {
+				return fmt.Errorf("invalid %v config parameter: %v", key, err)
+			}
+		}
+	}
+	for _, key := range sizeParameters {
+		if value, ok := parameters[key]; ok {
+			if _, err := ParseSize(value); err != nil {
+				return fmt.Errorf("invalid %v config parameter: %v", key, err)
+			}
+		}
+	}
+	return nil
+}
@@ -1,16 +1,60 @@
 package dsc
 
 import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
 	"log"
+	"sync"
+	"sync/atomic"
 	"time"
+
+	"github.com/viant/toolbox/url"
+)
+
+const (
+	shutdownTimeoutMsKey     = "shutdownTimeoutMs"
+	defaultShutdownTimeoutMs = 30000
 )
 
+var errProviderShutdown = errors.New("connection provider was shut down")
+
 //AbstractConnection represents an abstract connection
 type AbstractConnection struct {
 	Connection
 	lastUsed       *time.Time
 	config         *Config
 	connectionPool chan Connection
+	provider       ConnectionProvider
+	checkedOut     bool
+}
+
+//abstractConnectionHolder represents a connection embedding AbstractConnection
+type abstractConnectionHolder interface {
+	abstractConnection() *AbstractConnection
+}
+
+func (ac *AbstractConnection) abstractConnection() *AbstractConnection {
+	return ac
+}
+
+//connectionHooks represents a provider running connection lifecycle hooks
+type connectionHooks interface {
+	release(connection Connection) error
+	closeConnection(connection Connection) error
+	checkIn()
+	isShutdown() bool
+	countRecycled()
+	handOff(connection Connection) bool
+}
+
+//closeNow closes connection running provider close hooks
+func (ac *AbstractConnection) closeNow() error {
+	if hooks, ok := ac.provider.(connectionHooks); ok {
+		return hooks.closeConnection(ac.Connection)
+	}
+	return ac.Connection.CloseNow()
 }
 
 //Config returns a datastore config
@@ -33,10 +77,36 @@ func (ac *AbstractConnection) SetLastUsed(ts *time.Time) {
 	ac.lastUsed = ts
 }
 
-//Close closes connection if pool is full or send it back to the pool
+//Close closes connection if pool is full, was recycled by provider reload or release hook failed, otherwise it hands it over to waiting Get call or sends it back to the pool
 func (ac *AbstractConnection) Close() error {
 	channel := ac.Connection.ConnectionPool()
 	config := ac.config
+	hooks, hasHooks := ac.provider.(connectionHooks)
+	if hasHooks && ac.checkedOut {
+		ac.checkedOut = false
+		defer hooks.checkIn()
+	}
+	if ac.provider != nil && channel != ac.provider.ConnectionPool() {
+		if hasHooks {
+			hooks.countRecycled()
+		}
+		return ac.closeNow()
+	}
+	if hasHooks {
+		if hooks.isShutdown() {
+			return ac.closeNow()
+		}
+		if err := hooks.release(ac.Connection); err != nil {
+			Logf("release hook failed, closing connection: %v", err)
+			hooks.countRecycled()
+			return ac.closeNow()
+		}
+		var ts = time.Now()
+		ac.Connection.SetLastUsed(&ts)
+		if hooks.handOff(ac.Connection) {
+			return nil
+		}
+	}
 	if len(ac.Connection.ConnectionPool()) < config.MaxPoolSize {
 		var connection = ac.Connection
 		channel <- connection
@@ -44,7 +114,7 @@ func (ac *AbstractConnection) Close() error {
 		connection.SetLastUsed(&ts)
 
 	} else {
-		return ac.Connection.CloseNow()
+		return ac.closeNow()
 	}
 	return nil
 }
@@ -68,18 +138,107 @@ type AbstractConnectionProvider struct {
 	ConnectionProvider
 	config         *Config
 	connectionPool chan Connection
+	mutex          sync.RWMutex
+	acquireHooks   []ConnectionHook
+	releaseHooks   []ConnectionHook
+	closeHooks     []ConnectionHook
+	shutdown       bool
+	checkedOut     int
+	open           int
+	waiters        []chan Connection
+	counters       poolCounters
 }
 
-//Config returns a datastore config,
+//Config returns a datastore config, reloaded config replaces it as a whole (see Reload)
 func (cp *AbstractConnectionProvider) Config() *Config {
+	cp.mutex.RLock()
+	defer cp.mutex.RUnlock()
 	return cp.config
 }
 
 //ConnectionPool returns a ConnectionPool
 func (cp *AbstractConnectionProvider) ConnectionPool() chan Connection {
+	cp.mutex.RLock()
+	defer cp.mutex.RUnlock()
 	return cp.connectionPool
 }
 
+//OnAcquire registers a hook called with every connection returned by Get (pooled or freshly created), hook error vetoes the connection: it is closed and another one is acquired
+func (cp *AbstractConnectionProvider) OnAcquire(hook ConnectionHook) {
+	cp.mutex.Lock()
+	defer cp.mutex.Unlock()
+	cp.acquireHooks = append(cp.acquireHooks, hook)
+}
+
+//OnRelease registers a hook called when connection is being returned to the pool, hook error closes the connection instead
+func (cp *AbstractConnectionProvider) OnRelease(hook ConnectionHook) {
+	cp.mutex.Lock()
+	defer cp.mutex.Unlock()
+	cp.releaseHooks = append(cp.releaseHooks, hook)
+}
+
+//OnClose registers a hook called before pooled connection is closed, hook error is logged
+func (cp *AbstractConnectionProvider) OnClose(hook ConnectionHook) {
+	cp.mutex.Lock()
+	defer cp.mutex.Unlock()
+	cp.closeHooks = append(cp.closeHooks, hook)
+}
+
+func (cp *AbstractConnectionProvider) runHooks(hooks []ConnectionHook, connection Connection) error {
+	for _, hook := range hooks {
+		if err := hook(connection); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (cp *AbstractConnectionProvider) hooks() (acquire, release, close []ConnectionHook) {
+	cp.mutex.RLock()
+	defer cp.mutex.RUnlock()
+	return cp.acquireHooks, cp.releaseHooks, cp.closeHooks
+}
+
+func (cp *AbstractConnectionProvider) acquire(connection Connection) error {
+	acquire, _, _ := cp.hooks()
+	return cp.runHooks(acquire, connection)
+}
+
+func (cp *AbstractConnectionProvider) release(connection Connection) error {
+	_, release, _ := cp.hooks()
+	if err := cp.runHooks(release, connection); err != nil {
+		return err
+	}
+	if releaser, ok := connection.(nativeReleaser); ok {
+		releaser.releaseNative()
+	}
+	if validator, ok := cp.ConnectionProvider.(returnValidator); ok {
+		if err := validator.validateOnReturn(connection); err != nil {
+			return err
+		}
+	}
+	if trimmer := cp.idleTrimmer(); trimmer != nil {
+		return trimmer.Trim(connection)
+	}
+	return nil
+}
+
+//closeConnection closes connection and releases its pool slot
+func (cp *AbstractConnectionProvider) closeConnection(connection Connection) error {
+	defer cp.releaseSlot()
+	return cp.closeDriverConnection(connection)
+}
+
+//closeDriverConnection runs close hooks and closes connection
+func (cp *AbstractConnectionProvider) closeDriverConnection(connection Connection) error {
+	_, _, closeHooks := cp.hooks()
+	if err := cp.runHooks(closeHooks, connection); err != nil {
+		Logf("close hook failed: %v", err)
+	}
+	atomic.AddInt64(&cp.counters.closed, 1)
+	return connection.CloseNow()
+}
+
 //SpawnConnectionIfNeeded creates a new connection if connection pool has not reached size controlled by Config.PoolSize
 func (cp *AbstractConnectionProvider) SpawnConnectionIfNeeded() {
 	config := cp.ConnectionProvider.Config()
@@ -88,9 +247,11 @@ func (cp *AbstractConnectionProvider) SpawnConnectionIfNeeded() {
 	}
 	connectionPool := cp.ConnectionProvider.ConnectionPool()
 	for i := len(connectionPool); i < config.PoolSize; i++ {
-		connection, err := cp.ConnectionProvider.NewConnection()
+		connection, err := cp.newConnection()
 		if err != nil {
-			log.Printf("failed to create connection %v\n", err)
+			if err != errPoolFull {
+				log.Printf("failed to create connection %v\n", err)
+			}
 			break
 		}
 
@@ -105,14 +266,14 @@ func (cp *AbstractConnectionProvider) SpawnConnectionIfNeeded() {
 
 //Close closes a datastore connection or returns it to the pool (Config.PoolSize and Config.MaxPoolSize).
 func (cp *AbstractConnectionProvider) Close() error {
-
-	poolsize := len(cp.connectionPool)
+	connectionPool := cp.ConnectionPool()
+	poolsize := len(connectionPool)
 	for i := 0; i < poolsize; i++ {
 		var connection Connection
 		select {
 		case <-time.After(1 * time.Second):
-		case connection = <-cp.connectionPool:
-			err := connection.CloseNow()
+		case connection = <-connectionPool:
+			err := cp.closeConnection(connection)
 			if err != nil {
 				return err
 			}
@@ -120,34 +281,237 @@ func (cp *AbstractConnectionProvider) Close() error {
 	}
 
 	// 防止池子中再回收进新连接
-	if len(cp.connectionPool) > 0 {
+	if len(connectionPool) > 0 {
 		cp.Close()
 	}
 
 	return nil
 }
 
-//Get returns a new datastore connection or error.
+func (cp *AbstractConnectionProvider) checkOut() error {
+	cp.mutex.Lock()
+	defer cp.mutex.Unlock()
+	if cp.shutdown {
+		return errProviderShutdown
+	}
+	cp.checkedOut++
+	return nil
+}
+
+func (cp *AbstractConnectionProvider) checkIn() {
+	cp.mutex.Lock()
+	defer cp.mutex.Unlock()
+	cp.checkedOut--
+}
+
+func (cp *AbstractConnectionProvider) isShutdown() bool {
+	cp.mutex.RLock()
+	defer cp.mutex.RUnlock()
+	return cp.shutdown
+}
+
+func (cp *AbstractConnectionProvider) inUse() int {
+	cp.mutex.RLock()
+	defer cp.mutex.RUnlock()
+	return cp.checkedOut
+}
+
+//Shutdown stops handing out connections, waits until checked out connections are returned (bounded by context) and closes all pooled connections
+func (cp *AbstractConnectionProvider) Shutdown(ctx context.Context) error {
+	cp.mutex.Lock()
+	cp.shutdown = true
+	cp.mutex.Unlock()
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+	var err error
+	for err == nil && cp.inUse() > 0 {
+		select {
+		case <-ctx.Done():
+			err = fmt.Errorf("failed to drain connection pool: %v connection(s) still in use due to %v", cp.inUse(), ctx.Err())
+		case <-ticker.C:
+		}
+	}
+	if closeErr := cp.ConnectionProvider.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+//Get returns a new datastore connection or error, acquire hooks are run for both pooled and freshly created connection.
+//With poolMaxOpen config parameter, Get waits in FIFO order for released connection once the limit was reached, and returns ErrPoolExhausted after poolAcquireTimeoutMs.
 func (cp *AbstractConnectionProvider) Get() (Connection, error) {
+	if cp.isShutdown() {
+		return nil, errProviderShutdown
+	}
 	cp.ConnectionProvider.SpawnConnectionIfNeeded()
 	connectionPool := cp.ConnectionProvider.ConnectionPool()
+	for vetoed := 0; ; vetoed++ {
+		var result Connection
+		var fresh bool
+		if cp.maxOpen() > 0 {
+			var err error
+			if result, fresh, err = cp.acquireBounded(connectionPool); err != nil {
+				return nil, err
+			}
+		} else if vetoed <= cap(connectionPool) {
+			select {
+			case result = <-connectionPool:
+			default:
+				started := time.Now()
+				select {
+				case <-time.After(100 * time.Millisecond):
+					{
+						Logf("unable to acquire connection from pool, creating new connection ...")
+					}
+				case result = <-connectionPool:
+				}
+				cp.counters.wait(time.Now().Sub(started))
+			}
+		}
+		if result == nil {
+			fresh = true
+			var err error
+			result, err = cp.newConnection()
+			if err != nil {
+				return nil, err
+			}
+		}
+		err := cp.adopt(result, !fresh)
+		if err == nil {
+			return result, nil
+		}
+		cp.countRecycled()
+		if closeErr := cp.closeConnection(result); closeErr != nil {
+			Logf("failed to close vetoed connection %v", closeErr)
+		}
+		if err == errProviderShutdown {
+			return nil, err
+		}
+		if fresh {
+			return nil, fmt.Errorf("failed to acquire connection due to %v", err)
+		}
+	}
+}
 
-	var result Connection
-	select {
-	case <-time.After(100 * time.Millisecond):
-		{
-			Logf("unable to acquire connection from pool, creating new connection ...")
+//adopt restores trimmed idle state of pooled connection, runs acquire hooks and checks out connection to the caller
+func (cp *AbstractConnectionProvider) adopt(connection Connection, pooled bool) error {
+	if pooled {
+		if trimmer := cp.idleTrimmer(); trimmer != nil {
+			if err := trimmer.Restore(connection); err != nil {
+				return err
+			}
 		}
-	case result = <-connectionPool:
 	}
-	if result == nil {
-		var err error
-		result, err = cp.ConnectionProvider.NewConnection()
+	if err := cp.acquire(connection); err != nil {
+		return err
+	}
+	holder, ok := connection.(abstractConnectionHolder)
+	if !ok {
+		return nil
+	}
+	if err := cp.checkOut(); err != nil {
+		return err
+	}
+	abstractConnection := holder.abstractConnection()
+	abstractConnection.provider = cp.ConnectionProvider
+	abstractConnection.checkedOut = true
+	return nil
+}
+
+//Reload replaces config with passed in config (or config reloaded from Config.URL when nil) and recycles the pool: idle connections are closed,
+//new Get calls receive fresh connections, in-flight connections are closed instead of being returned to the pool.
+//Replaced config is not modified, so that concurrent readers are not affected, Config (or manager Config) returns the reloaded one.
+func (cp *AbstractConnectionProvider) Reload(config *Config) error {
+	current := cp.ConnectionProvider.Config()
+	if config == nil {
+		config = current
+		if current.URL != "" {
+			var err error
+			if config, err = current.reload(); err != nil {
+				return fmt.Errorf("failed to reload config from %v due to %v", current.URL, err)
+			}
+		}
+	}
+	reloaded, err := current.reloaded(config)
+	if err != nil {
+		return fmt.Errorf("failed to reload config due to %v", err)
+	}
+	cp.mutex.Lock()
+	previous := cp.connectionPool
+	cp.config = reloaded
+	cp.connectionPool = make(chan Connection, reloaded.MaxPoolSize)
+	cp.mutex.Unlock()
+	for {
+		select {
+		case connection := <-previous:
+			cp.countRecycled()
+			if err := cp.closeConnection(connection); err != nil {
+				Logf("failed to close recycled connection %v", err)
+			}
+		default:
+			return nil
+		}
+	}
+}
+
+//reloadable returns provider implementing reload, or abstract provider if it does not implement ReloadableConnectionProvider
+func (cp *AbstractConnectionProvider) reloadable() ReloadableConnectionProvider {
+	if provider, ok := cp.ConnectionProvider.(ReloadableConnectionProvider); ok {
+		return provider
+	}
+	return cp
+}
+
+//configFingerprint returns hash of config source content, including included documents, and expanded DSN
+func (cp *AbstractConnectionProvider) configFingerprint() (string, error) {
+	config := cp.ConnectionProvider.Config()
+	hash := sha256.New()
+	for _, URL := range config.sourceURLs() {
+		content, err := url.NewResource(URL).DownloadText()
 		if err != nil {
-			return nil, err
+			return "", err
 		}
+		hash.Write([]byte(URL + "\x00" + content + "\x00"))
+	}
+	dsn, err := config.DsnDescriptor()
+	if err != nil {
+		return "", err
+	}
+	hash.Write([]byte(dsn))
+	return fmt.Sprintf("%x", hash.Sum(nil)), nil
+}
+
+//Watch checks config source (Config.URL) and expanded DSN (i.e. rotated secrets) every interval in background and reloads provider when they change, it stops when context is done.
+func (cp *AbstractConnectionProvider) Watch(ctx context.Context, interval time.Duration) error {
+	fingerprint, err := cp.configFingerprint()
+	if err != nil {
+		return err
 	}
-	return result, nil
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			current, err := cp.configFingerprint()
+			if err != nil {
+				Logf("failed to check config changes %v", err)
+				continue
+			}
+			if current == fingerprint {
+				continue
+			}
+			if err = cp.reloadable().Reload(nil); err != nil {
+				Logf("%v", err)
+				continue
+			}
+			fingerprint = current
+		}
+	}()
+	return nil
 }
 
 //NewAbstractConnectionProvider create a new AbstractConnectionProvider
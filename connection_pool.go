@@ -0,0 +1,188 @@
+package dsc
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	// PoolMaxOpenKey represents a config parameter with hard limit of connections opened by provider (in use and idle), 0 (default) means unlimited
+	PoolMaxOpenKey = "poolMaxOpen"
+	// PoolAcquireTimeoutMsKey represents a config parameter with time Get waits for a connection once PoolMaxOpenKey limit was reached (30s by default)
+	PoolAcquireTimeoutMsKey     = "poolAcquireTimeoutMs"
+	defaultPoolAcquireTimeoutMs = 30000
+)
+
+var errPoolFull = errors.New("connection pool is full")
+
+// maxOpen returns hard limit of open connections
+func (cp *AbstractConnectionProvider) maxOpen() int {
+	config := cp.ConnectionProvider.Config()
+	if config == nil || len(config.Parameters) == 0 {
+		return 0
+	}
+	return config.GetInt(PoolMaxOpenKey, 0)
+}
+
+// reserveSlot counts a connection to be opened against the hard limit, when limit was reached and enqueue is set, it returns waiter channel
+// receiving either released connection or nil that hands over slot of closed connection
+func (cp *AbstractConnectionProvider) reserveSlot(enqueue bool) (bool, chan Connection) {
+	maxOpen := cp.maxOpen()
+	cp.mutex.Lock()
+	defer cp.mutex.Unlock()
+	if maxOpen <= 0 || cp.open < maxOpen {
+		cp.open++
+		return true, nil
+	}
+	if !enqueue {
+		return false, nil
+	}
+	waiter := make(chan Connection, 1)
+	cp.waiters = append(cp.waiters, waiter)
+	return false, waiter
+}
+
+// releaseSlot hands over slot of closed connection to the longest waiting Get call or frees it
+func (cp *AbstractConnectionProvider) releaseSlot() {
+	cp.mutex.Lock()
+	defer cp.mutex.Unlock()
+	if len(cp.waiters) > 0 {
+		cp.nextWaiter() <- nil
+		return
+	}
+	cp.open--
+}
+
+// handOff passes released connection to the longest waiting Get call, it returns false if nobody waits
+func (cp *AbstractConnectionProvider) handOff(connection Connection) bool {
+	cp.mutex.Lock()
+	defer cp.mutex.Unlock()
+	if len(cp.waiters) == 0 {
+		return false
+	}
+	cp.nextWaiter() <- connection
+	return true
+}
+
+// nextWaiter removes and returns the head of waiters queue, caller has to hold the mutex
+func (cp *AbstractConnectionProvider) nextWaiter() chan Connection {
+	waiter := cp.waiters[0]
+	cp.waiters[0] = nil
+	cp.waiters = cp.waiters[1:]
+	return waiter
+}
+
+// cancelWait removes waiter from the queue, if waiter was already served it returns handed over connection (nil for a slot) and true
+func (cp *AbstractConnectionProvider) cancelWait(waiter chan Connection) (Connection, bool) {
+	cp.mutex.Lock()
+	for i, candidate := range cp.waiters {
+		if candidate == waiter {
+			cp.waiters = append(cp.waiters[:i], cp.waiters[i+1:]...)
+			cp.mutex.Unlock()
+			return nil, false
+		}
+	}
+	cp.mutex.Unlock()
+	return <-waiter, true
+}
+
+// giveBack returns served but unused connection or slot to other waiters or to the pool
+func (cp *AbstractConnectionProvider) giveBack(connection Connection) {
+	if connection == nil {
+		cp.releaseSlot()
+		return
+	}
+	if cp.handOff(connection) {
+		return
+	}
+	select {
+	case cp.ConnectionProvider.ConnectionPool() <- connection:
+	default:
+		if err := cp.closeConnection(connection); err != nil {
+			Logf("failed to close returned connection %v", err)
+		}
+	}
+}
+
+// waiting returns number of Get calls waiting for connection
+func (cp *AbstractConnectionProvider) waiting() int {
+	cp.mutex.RLock()
+	defer cp.mutex.RUnlock()
+	return len(cp.waiters)
+}
+
+// acquireBounded returns pooled or new connection, once PoolMaxOpenKey limit was reached, callers wait in FIFO order
+// for released connection up to PoolAcquireTimeoutMsKey, fresh flag is set for newly created connection
+func (cp *AbstractConnectionProvider) acquireBounded(connectionPool chan Connection) (Connection, bool, error) {
+	select {
+	case result := <-connectionPool:
+		return result, false, nil
+	default:
+	}
+	reserved, waiter := cp.reserveSlot(true)
+	if reserved {
+		return cp.openReserved()
+	}
+	started := time.Now()
+	defer func() {
+		cp.counters.wait(time.Now().Sub(started))
+	}()
+	timeout := cp.ConnectionProvider.Config().GetDuration(PoolAcquireTimeoutMsKey, time.Millisecond, defaultPoolAcquireTimeoutMs*time.Millisecond)
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	var result Connection
+	select {
+	case result = <-waiter:
+	case result = <-connectionPool:
+		if served, ok := cp.cancelWait(waiter); ok {
+			cp.giveBack(served)
+		}
+		return result, false, nil
+	case <-timer.C:
+		served, ok := cp.cancelWait(waiter)
+		if !ok {
+			return nil, false, &Error{Kinds: []error{ErrPoolExhausted}, Err: fmt.Errorf("failed to acquire connection within %v: all %v connection(s) are in use", timeout, cp.maxOpen())}
+		}
+		result = served
+	}
+	if result != nil {
+		return result, false, nil
+	}
+	if cp.isShutdown() {
+		cp.releaseSlot()
+		return nil, false, errProviderShutdown
+	}
+	return cp.openReserved()
+}
+
+// openReserved creates connection in reserved slot, slot is released when connection could not be created
+func (cp *AbstractConnectionProvider) openReserved() (Connection, bool, error) {
+	result, err := cp.createConnection()
+	if err != nil {
+		cp.releaseSlot()
+		return nil, false, err
+	}
+	return result, true, nil
+}
+
+// renew closes checked out connection and creates its replacement in the same slot
+func (cp *AbstractConnectionProvider) renew(connection Connection) (Connection, error) {
+	if holder, ok := connection.(abstractConnectionHolder); ok && holder.abstractConnection().checkedOut {
+		holder.abstractConnection().checkedOut = false
+		cp.checkIn()
+	}
+	cp.countRecycled()
+	if err := cp.closeDriverConnection(connection); err != nil {
+		Logf("failed to close discarded connection %v", err)
+	}
+	result, _, err := cp.openReserved()
+	if err != nil {
+		return nil, err
+	}
+	if err = cp.adopt(result, false); err != nil {
+		_ = cp.closeConnection(result)
+		return nil, fmt.Errorf("failed to acquire connection due to %v", err)
+	}
+	return result, nil
+}
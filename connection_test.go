@@ -1,9 +1,16 @@
 package dsc_test
 
 import (
+	"context"
+	"database/sql"
 	"errors"
+	"fmt"
+	"github.com/mattn/go-sqlite3"
 	"github.com/stretchr/testify/assert"
 	"github.com/viant/dsc"
+	"io"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 )
@@ -111,3 +118,403 @@ func newTestConnectionProvider(config *dsc.Config) *testConnectionProvider {
 	testConnectionProvider.AbstractConnectionProvider = super
 	return connectionProvider
 }
+
+func TestConnectionProvider_Reload(t *testing.T) {
+	config := dsc.NewConfig("sqlite3", "[url]", "url:./test/reload1.db")
+	manager, err := dsc.NewManagerFactory().Create(config)
+	if !assert.Nil(t, err) {
+		return
+	}
+	provider := manager.ConnectionProvider()
+	inFlight, err := provider.Get()
+	if !assert.Nil(t, err) {
+		return
+	}
+	err = provider.(dsc.ReloadableConnectionProvider).Reload(dsc.NewConfig("sqlite3", "[url]", "url:./test/reload2.db"))
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.EqualValues(t, "./test/reload2.db", manager.Config().Get("url"), "reloaded config should replace manager config")
+	assert.EqualValues(t, "./test/reload1.db", config.Get("url"), "replaced config should not be modified")
+	_, err = manager.Execute("CREATE TABLE IF NOT EXISTS reloaded(id INTEGER)")
+	assert.Nil(t, err)
+	_, err = os.Stat("./test/reload2.db")
+	assert.Nil(t, err, "new connections should use reloaded config")
+
+	assert.Nil(t, inFlight.Close())
+	db := inFlight.Unwrap((*sql.DB)(nil)).(*sql.DB)
+	assert.NotNil(t, db.Ping(), "in-flight connection should be closed once returned")
+	for i := 0; i < len(provider.ConnectionPool()); i++ {
+		connection := <-provider.ConnectionPool()
+		assert.NotEqual(t, inFlight, connection)
+		provider.ConnectionPool() <- connection
+	}
+}
+
+func TestConnectionProvider_ReloadWithConcurrentReader(t *testing.T) {
+	manager, err := dsc.NewManagerFactory().Create(dsc.NewConfig("sqlite3", "[url]", "url:./test/reload1.db"))
+	if !assert.Nil(t, err) {
+		return
+	}
+	done := make(chan bool)
+	read := make(chan bool)
+	go func() {
+		defer close(read)
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			for j := 0; j < 100; j++ {
+				config := manager.Config()
+				_ = config.DriverName + config.Descriptor
+				_ = len(config.Parameters) + config.MaxPoolSize
+			}
+			if _, err := manager.Execute("SELECT 1"); err != nil {
+				assert.Nil(t, err)
+				return
+			}
+		}
+	}()
+	for i := 0; i < 200; i++ {
+		assert.Nil(t, manager.ConnectionProvider().(dsc.ReloadableConnectionProvider).Reload(dsc.NewConfig("sqlite3", "[url]", fmt.Sprintf("url:./test/reload%v.db", 1+i%2))))
+	}
+	close(done)
+	<-read
+}
+
+func TestConnectionProvider_Watch(t *testing.T) {
+	configURL := filepath.Join(t.TempDir(), "config.json")
+	writeConfig := func(dbname string) {
+		_ = os.WriteFile(configURL, []byte(`{"Driver":"sqlite3","DSN":"[url]","Parameters":{"url":"./test/`+dbname+`.db"}}`), 0644)
+	}
+	writeConfig("watch1")
+	manager, err := dsc.NewManagerFactory().CreateFromURL(configURL)
+	if !assert.Nil(t, err) {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	assert.Nil(t, manager.ConnectionProvider().(dsc.ReloadableConnectionProvider).Watch(ctx, 10*time.Millisecond))
+	writeConfig("watch2")
+	for i := 0; i < 100 && manager.Config().Get("url") != "./test/watch2.db"; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.EqualValues(t, "./test/watch2.db", manager.Config().Get("url"))
+}
+
+func TestConnectionProvider_Hooks(t *testing.T) {
+	config := dsc.NewConfig("sqlite3", "[url]", "url:./test/hooks.db")
+	manager, err := dsc.NewManagerFactory().Create(config)
+	if !assert.Nil(t, err) {
+		return
+	}
+	provider := manager.ConnectionProvider()
+	hooks := provider.(dsc.HookedConnectionProvider)
+	var acquired, released, closed, vetoed int
+	hooks.OnAcquire(func(connection dsc.Connection) error {
+		acquired++
+		if vetoed == 0 {
+			vetoed++
+			return errors.New("broken connection")
+		}
+		return nil
+	})
+	hooks.OnRelease(func(connection dsc.Connection) error {
+		released++
+		return nil
+	})
+	hooks.OnClose(func(connection dsc.Connection) error {
+		closed++
+		return nil
+	})
+	connection, err := provider.Get()
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, 2, acquired, "vetoed connection should be replaced")
+	assert.Equal(t, 1, closed, "vetoed connection should be closed")
+	assert.Nil(t, connection.Close())
+	assert.Equal(t, 1, released)
+
+	_, err = manager.Execute("CREATE TABLE IF NOT EXISTS hooks(id INTEGER)")
+	assert.Nil(t, err)
+	assert.Equal(t, 3, acquired, "manager operations should run acquire hooks")
+	assert.Equal(t, 2, released)
+
+	closed = 0
+	assert.Nil(t, provider.Close())
+	assert.True(t, closed > 0, "closing provider should run close hooks")
+
+	hooks.OnAcquire(func(connection dsc.Connection) error {
+		return errors.New("vetoed")
+	})
+	_, err = provider.Get()
+	assert.NotNil(t, err, "vetoing fresh connection should fail")
+}
+
+func TestConnectionProvider_Shutdown(t *testing.T) {
+	{
+		config := dsc.NewConfig("sqlite3", "[url]", "url:./test/shutdown.db,shutdownTimeoutMs:2000")
+		manager, err := dsc.NewManagerFactory().Create(config)
+		if !assert.Nil(t, err) {
+			return
+		}
+		provider := manager.ConnectionProvider()
+		inFlight, err := provider.Get()
+		if !assert.Nil(t, err) {
+			return
+		}
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			_ = inFlight.Close()
+		}()
+		started := time.Now()
+		assert.Nil(t, manager.(io.Closer).Close())
+		assert.True(t, time.Since(started) >= 50*time.Millisecond, "shutdown should wait for in-flight connection")
+		db := inFlight.Unwrap((*sql.DB)(nil)).(*sql.DB)
+		assert.NotNil(t, db.Ping(), "returned connection should be closed")
+		assert.Equal(t, 0, len(provider.ConnectionPool()))
+		_, err = provider.Get()
+		assert.NotNil(t, err, "shut down provider should not hand out connections")
+		_, err = manager.Execute("SELECT 1")
+		assert.NotNil(t, err)
+	}
+	{
+		config := dsc.NewConfig("sqlite3", "[url]", "url:./test/shutdown.db")
+		manager, err := dsc.NewManagerFactory().Create(config)
+		if !assert.Nil(t, err) {
+			return
+		}
+		_, err = manager.ConnectionProvider().Get()
+		if !assert.Nil(t, err) {
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		assert.NotNil(t, manager.ConnectionProvider().(dsc.ShutdownConnectionProvider).Shutdown(ctx), "leaked connection should fail shutdown once context is done")
+	}
+}
+
+func TestConnectionProvider_Validation(t *testing.T) {
+	var breakConnection = func(provider dsc.ConnectionProvider) *sql.DB {
+		connection, err := provider.Get()
+		if !assert.Nil(t, err) {
+			return nil
+		}
+		db := connection.Unwrap((*sql.DB)(nil)).(*sql.DB)
+		_ = db.Close()
+		assert.Nil(t, connection.Close())
+		return db
+	}
+	{
+		config := dsc.NewConfig("sqlite3", "[url]", "url:./test/validation.db,validationIdleMs:0,validationMode:query")
+		manager, err := dsc.NewManagerFactory().Create(config)
+		if !assert.Nil(t, err) {
+			return
+		}
+		broken := breakConnection(manager.ConnectionProvider())
+		assert.Equal(t, 1, len(manager.ConnectionProvider().ConnectionPool()))
+		connection, err := manager.ConnectionProvider().Get()
+		if assert.Nil(t, err) {
+			db := connection.Unwrap((*sql.DB)(nil)).(*sql.DB)
+			assert.True(t, db != broken, "connection failing validation should be renewed")
+			assert.Nil(t, db.Ping())
+			_ = connection.Close()
+		}
+	}
+	{
+		config := dsc.NewConfig("sqlite3", "[url]", "url:./test/validation.db,validationIdleMs:0,validationMode:none")
+		manager, err := dsc.NewManagerFactory().Create(config)
+		if !assert.Nil(t, err) {
+			return
+		}
+		broken := breakConnection(manager.ConnectionProvider())
+		connection, err := manager.ConnectionProvider().Get()
+		if assert.Nil(t, err) {
+			assert.True(t, connection.Unwrap((*sql.DB)(nil)).(*sql.DB) == broken, "connection should not be validated")
+		}
+	}
+	{
+		config := dsc.NewConfig("sqlite3", "[url]", "url:./test/validation.db,validateOnReturn:true")
+		manager, err := dsc.NewManagerFactory().Create(config)
+		if !assert.Nil(t, err) {
+			return
+		}
+		breakConnection(manager.ConnectionProvider())
+		assert.Equal(t, 0, len(manager.ConnectionProvider().ConnectionPool()), "connection failing validation on return should be closed")
+	}
+	{
+		config := dsc.NewConfig("sqlite3", "[url]", "url:./test/validation.db,validationMode:select")
+		manager, err := dsc.NewManagerFactory().Create(config)
+		if !assert.Nil(t, err) {
+			return
+		}
+		_, err = manager.ConnectionProvider().Get()
+		assert.NotNil(t, err)
+	}
+}
+
+func TestConnectionProvider_PoolSettings(t *testing.T) {
+	config := dsc.NewConfig("sqlite3", "[url]", "url:./test/foo.db,maxOpenConns:3,maxIdleConns:1,connMaxLifetimeMs:1m,connMaxIdleTimeMs:500")
+	manager, err := dsc.NewManagerFactory().Create(config)
+	if !assert.Nil(t, err) {
+		return
+	}
+	connection, err := manager.ConnectionProvider().Get()
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer connection.Close()
+	db := connection.Unwrap((*sql.DB)(nil)).(*sql.DB)
+	assert.Equal(t, 3, db.Stats().MaxOpenConnections, "pool settings should be applied when connection is created")
+
+	for i := 0; i < 5; i++ {
+		_, err = manager.ExecuteOnConnection(connection, "SELECT 1", nil)
+		assert.Nil(t, err)
+	}
+	assert.True(t, db.Stats().Idle <= 1)
+}
+
+func TestConnectionProvider_PoolLimit(t *testing.T) {
+	config := dsc.NewConfig("sqlite3", "[url]", "url:./test/foo.db,poolMaxOpen:2,poolAcquireTimeoutMs:50")
+	config.MaxPoolSize = 2
+	manager, err := dsc.NewManagerFactory().Create(config)
+	if !assert.Nil(t, err) {
+		return
+	}
+	provider := manager.ConnectionProvider()
+	var held []dsc.Connection
+	for i := 0; i < 2; i++ {
+		connection, err := provider.Get()
+		if !assert.Nil(t, err) {
+			return
+		}
+		held = append(held, connection)
+	}
+	started := time.Now()
+	_, err = provider.Get()
+	assert.True(t, dsc.IsPoolExhausted(err), "pool limit was reached")
+	assert.True(t, time.Since(started) >= 50*time.Millisecond, "Get should wait for acquire timeout")
+	assert.EqualValues(t, 2, provider.(dsc.PoolStatsProvider).Stats().Open)
+
+	//waiting Get calls are served in FIFO order
+	config.Parameters[dsc.PoolAcquireTimeoutMsKey] = 2000
+	var served = make(chan int, 2)
+	for i := 0; i < 2; i++ {
+		waiting := provider.(dsc.PoolStatsProvider).Stats().Waiting
+		go func(id int) {
+			connection, err := provider.Get()
+			if assert.Nil(t, err) {
+				served <- id
+				time.Sleep(20 * time.Millisecond)
+				_ = connection.Close()
+			}
+		}(i)
+		for provider.(dsc.PoolStatsProvider).Stats().Waiting == waiting {
+			time.Sleep(time.Millisecond)
+		}
+	}
+	assert.Nil(t, held[0].Close())
+	assert.Equal(t, 0, <-served)
+	assert.Equal(t, 1, <-served)
+	assert.Nil(t, held[1].Close())
+
+	//slot of closed connection is handed over to waiting Get call
+	for i := 0; i < 2; i++ {
+		if held[i], err = provider.Get(); !assert.Nil(t, err) {
+			return
+		}
+	}
+	var acquired = make(chan dsc.Connection, 1)
+	go func() {
+		connection, err := provider.Get()
+		assert.Nil(t, err)
+		acquired <- connection
+	}()
+	for provider.(dsc.PoolStatsProvider).Stats().Waiting == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	assert.Nil(t, provider.(dsc.ReloadableConnectionProvider).Reload(config))
+	assert.Nil(t, held[0].Close(), "recycled connection should be closed")
+	connection := <-acquired
+	if assert.NotNil(t, connection) {
+		assert.NotEqual(t, held[0], connection)
+		assert.Nil(t, connection.Close())
+	}
+	assert.Nil(t, held[1].Close())
+	assert.True(t, provider.(dsc.PoolStatsProvider).Stats().Open <= 2)
+}
+
+type nativeHandle struct {
+	released bool
+}
+
+func TestConnectionProvider_NativeUnwrap(t *testing.T) {
+	sql.Register("sqlite3native", &sqlite3.SQLiteDriver{})
+	dsc.RegisterDatastoreDialect("sqlite3native", dsc.GetDatastoreDialect("sqlite3"))
+	var unwrapped = 0
+	dsc.RegisterNativeUnwrapper("sqlite3native", func(connection dsc.Connection, target interface{}) (interface{}, func(), error) {
+		switch target.(type) {
+		case *nativeHandle:
+			unwrapped++
+			handle := &nativeHandle{}
+			return handle, func() { handle.released = true }, nil
+		case *string:
+			return nil, nil, errors.New("test error")
+		}
+		return nil, nil, nil
+	})
+	config := dsc.NewConfig("sqlite3native", "[url]", "url:./test/native.db")
+	manager, err := dsc.NewManagerFactory().Create(config)
+	if !assert.Nil(t, err) {
+		return
+	}
+	connection, err := manager.ConnectionProvider().Get()
+	if !assert.Nil(t, err) {
+		return
+	}
+	handle, ok := connection.Unwrap((*nativeHandle)(nil)).(*nativeHandle)
+	if !assert.True(t, ok) {
+		return
+	}
+	assert.True(t, handle == connection.Unwrap((*nativeHandle)(nil)), "native handle should be cached")
+	assert.Equal(t, 1, unwrapped)
+	assert.Nil(t, connection.Unwrap((*string)(nil)))
+	assert.Panics(t, func() { connection.Unwrap((*int)(nil)) })
+	assert.False(t, handle.released)
+	assert.Nil(t, connection.Close())
+	assert.True(t, handle.released, "native handle should be released with connection")
+}
+
+func TestConnectionProvider_WatchIncludedProfile(t *testing.T) {
+	dir := t.TempDir()
+	writeCommon := func(batchSize int) {
+		_ = os.WriteFile(filepath.Join(dir, "common.json"), []byte(fmt.Sprintf(`{"Driver":"sqlite3","DSN":"[url]","Parameters":{"url":"./test/watch1.db","batchSize":%v}}`, batchSize)), 0644)
+	}
+	writeCommon(100)
+	_ = os.WriteFile(filepath.Join(dir, "app.json"), []byte(`{"Include":"common.json","Profiles":{"prod":{"Parameters":{"url":"./test/watch3.db"}}}}`), 0644)
+	config, err := dsc.NewConfigFromURLWithProfile(filepath.Join(dir, "app.json"), "prod")
+	if !assert.Nil(t, err) {
+		return
+	}
+	manager, err := dsc.NewManagerFactory().Create(config)
+	if !assert.Nil(t, err) {
+		return
+	}
+	provider := manager.ConnectionProvider().(dsc.ReloadableConnectionProvider)
+	assert.Nil(t, provider.Reload(nil))
+	assert.EqualValues(t, "./test/watch3.db", manager.Config().Get("url"), "reload should apply profile overlay")
+	assert.EqualValues(t, 1, manager.Config().PoolSize, "reload should apply defaults")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	assert.Nil(t, provider.Watch(ctx, 10*time.Millisecond))
+	writeCommon(200)
+	for i := 0; i < 100 && manager.Config().GetInt("batchSize", 0) != 200; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.EqualValues(t, 200, manager.Config().GetInt("batchSize", 0), "included document change should trigger reload")
+	assert.EqualValues(t, "./test/watch3.db", manager.Config().Get("url"))
+}
@@ -0,0 +1,95 @@
+package dsc
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	// ValidationIdleMsKey represents config parameter with idle time after which pooled connection is validated when acquired (60s by default), 0 validates every pooled connection
+	ValidationIdleMsKey     = "validationIdleMs"
+	defaultValidationIdleMs = 60000
+	// ValidationModeKey represents config parameter with connection validation mode: ping (default), query or none
+	ValidationModeKey = "validationMode"
+	// ValidateOnReturnKey represents config parameter enabling validation of connection returned to the pool, connection failing validation is closed
+	ValidateOnReturnKey = "validateOnReturn"
+)
+
+const (
+	//ValidationModePing validates connection with driver ping
+	ValidationModePing = "ping"
+	//ValidationModeQuery validates connection with dialect validation query, i.e. SELECT 1 FROM DUAL for oracle
+	ValidationModeQuery = "query"
+	//ValidationModeNone disables connection validation
+	ValidationModeNone = "none"
+)
+
+// connectionValidation represents connection validation settings
+type connectionValidation struct {
+	mode     string
+	idle     time.Duration
+	onReturn bool
+}
+
+// isRequired returns true if connection idle since last used needs to be validated on acquire, fresh connections are not validated
+func (v *connectionValidation) isRequired(lastUsed *time.Time) bool {
+	if v.mode == ValidationModeNone || lastUsed == nil {
+		return false
+	}
+	return time.Now().Sub(*lastUsed) >= v.idle
+}
+
+// newConnectionValidation returns connection validation settings from config
+func newConnectionValidation(config *Config) (*connectionValidation, error) {
+	var result = &connectionValidation{mode: ValidationModePing, idle: defaultValidationIdleMs * time.Millisecond}
+	if config == nil {
+		return result, nil
+	}
+	config.initLock()
+	result.mode = config.GetString(ValidationModeKey, ValidationModePing)
+	switch result.mode {
+	case ValidationModePing, ValidationModeQuery, ValidationModeNone:
+	default:
+		return nil, fmt.Errorf("invalid %v: %v, supported: %v, %v, %v", ValidationModeKey, result.mode, ValidationModePing, ValidationModeQuery, ValidationModeNone)
+	}
+	result.idle = config.GetDuration(ValidationIdleMsKey, time.Millisecond, defaultValidationIdleMs*time.Millisecond)
+	result.onReturn = config.GetBoolean(ValidateOnReturnKey, false)
+	return result, nil
+}
+
+// validate validates connection with passed in mode
+func (c *sqlConnection) validate(mode string) error {
+	switch mode {
+	case ValidationModeNone:
+		return nil
+	case ValidationModeQuery:
+		return c.ping(context.Background(), validationQuery(GetDatastoreDialect(c.config.DriverName)))
+	}
+	db, err := asSQLDb(c.db)
+	if err != nil {
+		return err
+	}
+	return db.Ping()
+}
+
+// returnValidator represents connection provider validating connections returned to the pool
+type returnValidator interface {
+	validateOnReturn(connection Connection) error
+}
+
+// validateOnReturn validates connection returned to the pool when enabled with validateOnReturn config parameter, connections with active transaction are not validated
+func (c *sqlConnectionProvider) validateOnReturn(connection Connection) error {
+	validation, err := newConnectionValidation(c.ConnectionProvider.Config())
+	if err != nil || !validation.onReturn {
+		return err
+	}
+	sqlConnection, ok := connection.(*sqlConnection)
+	if !ok || sqlConnection.tx != nil {
+		return nil
+	}
+	if err = sqlConnection.validate(validation.mode); err != nil {
+		return fmt.Errorf("failed to validate returned connection due to %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,230 @@
+package dsc
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CopyProgress represents copy progress
+type CopyProgress struct {
+	//Read number of records read from the source
+	Read int
+	//Written number of records inserted or updated in the destination
+	Written int
+	//Batches number of persisted batches
+	Batches int
+	//Elapsed time since copy started
+	Elapsed time.Duration
+}
+
+// Copier streams records from a source manager query into a destination manager table, destination rows are inserted or updated by primary key
+type Copier struct {
+	Source      Manager
+	Destination Manager
+	//ColumnMapping maps source to destination column, column mapped to empty name is skipped, unmapped ones are copied as is
+	ColumnMapping map[string]string
+	//PkColumns destination primary key columns, if empty table descriptor or dialect key is used, without key records are only inserted
+	PkColumns []string
+	//BatchSize number of records persisted at once, default batchSize destination config parameter
+	BatchSize int
+	//Workers number of parallel writers, default 1
+	Workers int
+	//Progress optional callback called after each persisted batch, calls are serialized across workers
+	Progress func(progress CopyProgress)
+}
+
+// copyState represents shared copy state
+type copyState struct {
+	mutex    *sync.Mutex
+	started  time.Time
+	progress CopyProgress
+	err      error
+}
+
+func (s *copyState) failed() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.err != nil
+}
+
+func (s *copyState) fail(err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.err == nil {
+		s.err = err
+	}
+}
+
+func (c *Copier) record(columns []string, values []interface{}) map[string]interface{} {
+	var result = make(map[string]interface{}, len(columns))
+	for i, column := range columns {
+		if mapped, ok := c.ColumnMapping[column]; ok {
+			if mapped == "" {
+				continue
+			}
+			column = mapped
+		}
+		result[column] = values[i]
+	}
+	return result
+}
+
+func (c *Copier) destinationColumns(columns []string) []string {
+	var result = make([]string, 0, len(columns))
+	for _, column := range columns {
+		if mapped, ok := c.ColumnMapping[column]; ok {
+			if mapped == "" {
+				continue
+			}
+			column = mapped
+		}
+		result = append(result, column)
+	}
+	return result
+}
+
+func (c *Copier) pkColumns(table string) []string {
+	if len(c.PkColumns) > 0 {
+		return c.PkColumns
+	}
+	registry := c.Destination.TableDescriptorRegistry()
+	if registry.Has(table) {
+		return registry.Get(table).PkColumns
+	}
+	dialect := GetDatastoreDialect(c.Destination.Config().DriverName)
+	if dialect == nil {
+		return nil
+	}
+	datastore, err := dialect.GetCurrentDatastore(c.Destination)
+	if err != nil {
+		return nil
+	}
+	var result = make([]string, 0)
+	for _, column := range strings.Split(dialect.GetKeyName(c.Destination, datastore, table), ",") {
+		if column = strings.TrimSpace(column); column != "" {
+			result = append(result, column)
+		}
+	}
+	return result
+}
+
+// persist inserts or updates batch, or only inserts it if destination table has no primary key
+func (c *Copier) persist(table string, provider DmlProvider, hasKey bool, batch []map[string]interface{}) (int, error) {
+	if hasKey {
+		inserted, updated, err := c.Destination.PersistAll(&batch, table, provider)
+		return inserted + updated, err
+	}
+	connection, err := c.Destination.ConnectionProvider().Get()
+	if err != nil {
+		return 0, err
+	}
+	defer connection.Close()
+	if err = connection.Begin(); err != nil {
+		return 0, fmt.Errorf("failed to start transaction on %v due to %v", c.Destination.Config().Descriptor, err)
+	}
+	inserted, err := c.Destination.PersistData(connection, batch, table, nil, func(item interface{}) *ParametrizedSQL {
+		return provider.Get(SQLTypeInsert, item)
+	})
+	if err == nil {
+		if err = connection.Commit(); err != nil {
+			return 0, fmt.Errorf("failed to commit on %v due to %v", c.Destination.Config().Descriptor, err)
+		}
+		return inserted, nil
+	}
+	if rollbackErr := connection.Rollback(); rollbackErr != nil {
+		return 0, fmt.Errorf("failed to rollback on %v due to %v, %v", c.Destination.Config().Descriptor, err, rollbackErr)
+	}
+	return 0, err
+}
+
+// Copy streams query result into destination table, it returns final progress or the first read/write error
+func (c *Copier) Copy(query string, parameters []interface{}, table string) (*CopyProgress, error) {
+	if c.Source == nil || c.Destination == nil {
+		return nil, fmt.Errorf("failed to copy into %v: source and destination managers are required", table)
+	}
+	batchSize := c.BatchSize
+	if batchSize <= 0 {
+		batchSize = c.Destination.Config().GetInt(BatchSizeKey, defaultBatchSize)
+	}
+	workers := c.Workers
+	if workers <= 0 {
+		workers = 1
+	}
+	state := &copyState{mutex: &sync.Mutex{}, started: time.Now()}
+	batches := make(chan []map[string]interface{}, workers)
+	var provider DmlProvider
+	var hasKey bool
+	waitGroup := &sync.WaitGroup{}
+	startWorkers := func(columns []string) {
+		pkColumns := c.pkColumns(table)
+		hasKey = len(pkColumns) > 0
+		descriptor := &TableDescriptor{Table: table, PkColumns: pkColumns, Columns: columns}
+		if registry := c.Destination.TableDescriptorRegistry(); !registry.Has(table) {
+			_ = registry.Register(descriptor)
+		}
+		provider = newMapDmlProvider(descriptor, identifierQuoter(c.Destination.Config()))
+		for i := 0; i < workers; i++ {
+			waitGroup.Add(1)
+			go func() {
+				defer waitGroup.Done()
+				for batch := range batches {
+					if state.failed() {
+						continue
+					}
+					written, err := c.persist(table, provider, hasKey, batch)
+					if err != nil {
+						state.fail(fmt.Errorf("failed to copy into %v due to %w", table, err))
+						continue
+					}
+					state.mutex.Lock()
+					state.progress.Written += written
+					state.progress.Batches++
+					state.progress.Elapsed = time.Now().Sub(state.started)
+					if c.Progress != nil {
+						c.Progress(state.progress)
+					}
+					state.mutex.Unlock()
+				}
+			}()
+		}
+	}
+	var batch []map[string]interface{}
+	readErr := c.Source.ReadAllWithHandler(query, parameters, func(scanner Scanner) (bool, error) {
+		if state.failed() {
+			return false, nil
+		}
+		values, columns, err := ScanRow(scanner)
+		if err != nil {
+			return false, err
+		}
+		if provider == nil {
+			startWorkers(c.destinationColumns(columns))
+		}
+		batch = append(batch, c.record(columns, values))
+		state.mutex.Lock()
+		state.progress.Read++
+		state.mutex.Unlock()
+		if len(batch) >= batchSize {
+			batches <- batch
+			batch = nil
+		}
+		return true, nil
+	})
+	if len(batch) > 0 && readErr == nil {
+		batches <- batch
+	}
+	close(batches)
+	waitGroup.Wait()
+	state.progress.Elapsed = time.Now().Sub(state.started)
+	if readErr != nil {
+		return &state.progress, fmt.Errorf("failed to read copy source due to %w", readErr)
+	}
+	return &state.progress, state.err
+}
+
+// NewCopier creates a copier streaming records from source to destination manager
+func NewCopier(source, destination Manager) *Copier {
+	return &Copier{Source: source, Destination: destination, Workers: 1}
+}
@@ -0,0 +1,59 @@
+package dsc_test
+
+import (
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/dsc"
+)
+
+func TestCopier_Copy(t *testing.T) {
+	source := GetManager(t)
+	for _, SQL := range []string{
+		"INSERT INTO users(username, active, salary) VALUES('Bob', 0, 1000)",
+		"INSERT INTO users(username, active, salary) VALUES('Kiki', 1, 2000)",
+	} {
+		_, err := source.Execute(SQL)
+		assert.Nil(t, err)
+	}
+	destination, err := dsc.NewManagerFactory().Create(dsc.NewConfig("sqlite3", "[url]", "url:file:./test/copy.db?_busy_timeout=5000&_txlock=immediate"))
+	if !assert.Nil(t, err) {
+		return
+	}
+	for _, SQL := range []string{"DROP TABLE IF EXISTS accounts", "CREATE TABLE accounts(id INTEGER PRIMARY KEY, name TEXT, balance DECIMAL(7,2))",
+		"INSERT INTO accounts(id, name, balance) VALUES(1, 'old', 0)"} {
+		_, err = destination.Execute(SQL)
+		assert.Nil(t, err)
+	}
+	var batches = 0
+	copier := dsc.NewCopier(source, destination)
+	copier.ColumnMapping = map[string]string{"username": "name", "salary": "balance", "active": ""}
+	copier.PkColumns = []string{"id"}
+	copier.BatchSize = 2
+	copier.Workers = 2
+	copier.Progress = func(progress dsc.CopyProgress) {
+		batches++
+	}
+	progress, err := copier.Copy("SELECT id, username, active, salary FROM users ORDER BY id", nil, "accounts")
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, 3, progress.Read)
+	assert.Equal(t, 3, progress.Written)
+	assert.Equal(t, 2, progress.Batches)
+	assert.Equal(t, 2, batches)
+
+	var names = make([]string, 0)
+	err = destination.ReadAllWithHandler("SELECT name FROM accounts ORDER BY id", nil, func(scanner dsc.Scanner) (bool, error) {
+		var name string
+		err := scanner.Scan(&name)
+		names = append(names, name)
+		return true, err
+	})
+	assert.Nil(t, err)
+	assert.EqualValues(t, []string{"Edi", "Bob", "Kiki"}, names, "existing row should be updated")
+
+	_, err = copier.Copy("SELECT id, username FROM missing_table", nil, "accounts")
+	assert.NotNil(t, err)
+}
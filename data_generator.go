@@ -0,0 +1,357 @@
+package dsc
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/viant/toolbox"
+)
+
+var generatorFirstNames = []string{"James", "Mary", "John", "Patricia", "Robert", "Jennifer", "Michael", "Linda", "William", "Elizabeth", "David", "Barbara", "Richard", "Susan", "Joseph", "Jessica"}
+var generatorLastNames = []string{"Smith", "Johnson", "Williams", "Brown", "Jones", "Garcia", "Miller", "Davis", "Rodriguez", "Martinez", "Wilson", "Anderson", "Taylor", "Thomas", "Moore", "Jackson"}
+var generatorCities = []string{"New York", "London", "Paris", "Berlin", "Madrid", "Rome", "Warsaw", "Tokyo", "Sydney", "Toronto", "Chicago", "Austin"}
+var generatorCountries = []string{"US", "GB", "FR", "DE", "ES", "IT", "PL", "JP", "AU", "CA"}
+var generatorWords = []string{"lorem", "ipsum", "dolor", "sit", "amet", "consectetur", "adipiscing", "elit", "sed", "do", "eiusmod", "tempor", "incididunt", "labore", "magna", "aliqua"}
+
+// generatorColumn represents column constraints used by data generator
+type generatorColumn struct {
+	name     string
+	dataType string
+	length   int
+	nullable bool
+	unique   bool
+	skip     bool
+}
+
+// DataGenerator generates deterministic (seeded) fake records matching table column types and constraints:
+// string lengths, nullability, unique and primary key columns, and foreign key references to existing rows.
+// Generated records can be persisted with Manager.PersistAll.
+type DataGenerator struct {
+	rand *rand.Rand
+	//Epoch start of generated time values range
+	Epoch time.Time
+	//TimeRange range of generated time values, zero or negative range generates Epoch only
+	TimeRange time.Duration
+	//NullRate probability of generating nil for nullable column mapped to pointer field
+	NullRate   float64
+	unique     map[string]bool
+	values     map[string][]interface{}
+	references map[string]*generatorReference
+	sequences  map[string]int64
+}
+
+type generatorReference struct {
+	table  string
+	column string
+}
+
+// Seed resets generator random number generator with passed in seed
+func (g *DataGenerator) Seed(seed int64) {
+	g.rand = rand.New(rand.NewSource(seed))
+	g.sequences = make(map[string]int64)
+}
+
+// Unique flags columns as unique (primary key columns are unique by default)
+func (g *DataGenerator) Unique(columns ...string) *DataGenerator {
+	for _, column := range columns {
+		g.unique[strings.ToLower(column)] = true
+	}
+	return g
+}
+
+// Values sets values a column is generated from
+func (g *DataGenerator) Values(column string, values ...interface{}) *DataGenerator {
+	g.values[strings.ToLower(column)] = values
+	return g
+}
+
+// Reference sets foreign key column reference, column values are picked from existing referenced table column values
+func (g *DataGenerator) Reference(column, table, referencedColumn string) *DataGenerator {
+	g.references[strings.ToLower(column)] = &generatorReference{table: table, column: referencedColumn}
+	return g
+}
+
+func (g *DataGenerator) loadReferences(manager Manager) error {
+	for column, reference := range g.references {
+		if _, ok := g.values[column]; ok {
+			continue
+		}
+		var records = make([][]interface{}, 0)
+		SQL := fmt.Sprintf("SELECT %v FROM %v ORDER BY %v", reference.column, reference.table, reference.column)
+		if err := manager.ReadAll(&records, SQL, nil, nil); err != nil {
+			return fmt.Errorf("failed to load %v reference values due to %v", column, err)
+		}
+		if len(records) == 0 {
+			return fmt.Errorf("failed to generate %v: referenced table %v was empty", column, reference.table)
+		}
+		var values = make([]interface{}, len(records))
+		for i, record := range records {
+			values[i] = record[0]
+		}
+		g.values[column] = values
+	}
+	return nil
+}
+
+// columnLength returns column length or length declared in data type i.e. VARCHAR(32)
+func columnLength(column Column) int {
+	if length, ok := column.Length(); ok && length > 0 && length < 1<<20 {
+		return int(length)
+	}
+	dataType := column.DatabaseTypeName()
+	if begin := strings.Index(dataType, "("); begin != -1 {
+		if end := strings.Index(dataType[begin:], ")"); end != -1 {
+			size := strings.Split(dataType[begin+1:begin+end], ",")[0]
+			return toolbox.AsInt(strings.TrimSpace(size))
+		}
+	}
+	return 0
+}
+
+func (g *DataGenerator) columns(manager Manager, table string) (map[string]*generatorColumn, error) {
+	dialect := GetDatastoreDialect(manager.Config().DriverName)
+	datastore, err := dialect.GetCurrentDatastore(manager)
+	if err != nil {
+		return nil, err
+	}
+	columns, err := dialect.GetColumns(manager, datastore, table)
+	if err != nil {
+		return nil, err
+	}
+	descriptor := manager.TableDescriptorRegistry().Get(table)
+	var result = make(map[string]*generatorColumn)
+	for _, column := range columns {
+		key := strings.ToLower(column.Name())
+		nullable, _ := column.Nullable()
+		result[key] = &generatorColumn{
+			name:     column.Name(),
+			dataType: strings.ToUpper(column.DatabaseTypeName()),
+			length:   columnLength(column),
+			nullable: nullable,
+			unique:   g.unique[key],
+		}
+	}
+	for _, pkColumn := range descriptor.PkColumns {
+		if column, ok := result[strings.ToLower(pkColumn)]; ok {
+			column.unique = true
+			column.skip = descriptor.Autoincrement
+		}
+	}
+	for key, column := range result {
+		if column.unique && !column.skip {
+			if err = g.initSequence(manager, table, key, column); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return result, nil
+}
+
+// initSequence starts unique column sequence after existing rows, so that generated values do not collide with them
+func (g *DataGenerator) initSequence(manager Manager, table, key string, column *generatorColumn) error {
+	if _, ok := g.sequences[key]; ok {
+		return nil
+	}
+	expression := "COUNT(*)"
+	if strings.Contains(column.dataType, "INT") {
+		expression = "MAX(" + column.name + ")"
+	}
+	var record = make([]interface{}, 0)
+	if _, err := manager.ReadSingle(&record, fmt.Sprintf("SELECT %v FROM %v", expression, table), nil, nil); err != nil {
+		return err
+	}
+	if len(record) > 0 && record[0] != nil {
+		g.sequences[key] = int64(toolbox.AsInt(record[0]))
+	} else {
+		g.sequences[key] = 0
+	}
+	return nil
+}
+
+// Generate generates count records into passed in struct slice pointer, using manager table column metadata
+func (g *DataGenerator) Generate(manager Manager, table string, slicePointer interface{}, count int) error {
+	toolbox.AssertPointerKind(slicePointer, reflect.Slice, "slicePointer")
+	if err := g.loadReferences(manager); err != nil {
+		return err
+	}
+	columns, err := g.columns(manager, table)
+	if err != nil {
+		return err
+	}
+	slice := reflect.ValueOf(slicePointer).Elem()
+	componentType := slice.Type().Elem()
+	structType := componentType
+	if structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+	if structType.Kind() != reflect.Struct {
+		return fmt.Errorf("failed to generate %v: expected struct slice but had %v", table, slice.Type())
+	}
+	var fieldColumns = make(map[string]string)
+	for _, mapping := range toolbox.NewFieldSettingByKey(reflect.New(structType).Interface(), "column") {
+		name, ok := mapping["column"]
+		if !ok {
+			name = mapping["fieldName"]
+		}
+		if column, ok := columns[strings.ToLower(name)]; ok {
+			if _, ok := mapping["autoincrement"]; ok {
+				column.skip = true
+			}
+			if _, ok := mapping["primaryKey"]; ok && !column.unique {
+				column.unique = true
+				if err = g.initSequence(manager, table, strings.ToLower(name), column); err != nil {
+					return err
+				}
+			}
+		}
+		fieldColumns[mapping["fieldName"]] = name
+	}
+	var fields = make([]*generatorColumn, structType.NumField())
+	for i := 0; i < structType.NumField(); i++ {
+		name, ok := fieldColumns[structType.Field(i).Name]
+		if !ok {
+			continue
+		}
+		if column, ok := columns[strings.ToLower(name)]; ok && !column.skip {
+			fields[i] = column
+		}
+	}
+	for n := 0; n < count; n++ {
+		record := reflect.New(structType)
+		for i, column := range fields {
+			if column == nil {
+				continue
+			}
+			if err := g.setField(record.Elem().Field(i), column); err != nil {
+				return fmt.Errorf("failed to generate %v.%v due to %v", table, column.name, err)
+			}
+		}
+		if componentType.Kind() == reflect.Ptr {
+			slice.Set(reflect.Append(slice, record))
+		} else {
+			slice.Set(reflect.Append(slice, record.Elem()))
+		}
+	}
+	return nil
+}
+
+func (g *DataGenerator) setField(field reflect.Value, column *generatorColumn) error {
+	if field.Kind() == reflect.Ptr {
+		if column.nullable && !column.unique && g.rand.Float64() < g.NullRate {
+			return nil
+		}
+		field.Set(reflect.New(field.Type().Elem()))
+		field = field.Elem()
+	}
+	value := g.value(field.Type(), column)
+	return toolbox.DefaultConverter.AssignConverted(field.Addr().Interface(), value)
+}
+
+func (g *DataGenerator) nextSequence(column string) int64 {
+	g.sequences[column]++
+	return g.sequences[column]
+}
+
+func (g *DataGenerator) value(fieldType reflect.Type, column *generatorColumn) interface{} {
+	key := strings.ToLower(column.name)
+	if values, ok := g.values[key]; ok && len(values) > 0 {
+		if column.unique {
+			return values[int(g.nextSequence(key)-1)%len(values)]
+		}
+		return values[g.rand.Intn(len(values))]
+	}
+	if fieldType == reflect.TypeOf(time.Time{}) {
+		if g.TimeRange <= 0 {
+			return g.Epoch.Truncate(time.Second)
+		}
+		return g.Epoch.Add(time.Duration(g.rand.Int63n(int64(g.TimeRange)))).Truncate(time.Second)
+	}
+	switch fieldType.Kind() {
+	case reflect.Bool:
+		return g.rand.Intn(2) == 1
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if column.unique {
+			return g.nextSequence(key)
+		}
+		upper := int64(1000)
+		if fieldType.Bits() == 8 {
+			upper = 100
+		}
+		return g.rand.Int63n(upper)
+	case reflect.Float32, reflect.Float64:
+		return float64(g.rand.Int63n(1000000)) / 100
+	case reflect.String:
+		return g.text(column)
+	}
+	return nil
+}
+
+func (g *DataGenerator) pick(values []string) string {
+	return values[g.rand.Intn(len(values))]
+}
+
+func (g *DataGenerator) text(column *generatorColumn) string {
+	name := strings.ToLower(column.name)
+	var result string
+	switch {
+	case strings.Contains(name, "email"):
+		result = strings.ToLower(g.pick(generatorFirstNames)+"."+g.pick(generatorLastNames)) + "@example.com"
+	case strings.Contains(name, "first"):
+		result = g.pick(generatorFirstNames)
+	case strings.Contains(name, "last") || strings.Contains(name, "surname"):
+		result = g.pick(generatorLastNames)
+	case strings.Contains(name, "name"):
+		result = g.pick(generatorFirstNames) + " " + g.pick(generatorLastNames)
+	case strings.Contains(name, "city"):
+		result = g.pick(generatorCities)
+	case strings.Contains(name, "country"):
+		result = g.pick(generatorCountries)
+	case strings.Contains(name, "phone"):
+		result = fmt.Sprintf("+1-555-%03d-%04d", g.rand.Intn(1000), g.rand.Intn(10000))
+	case strings.Contains(name, "url"):
+		result = "https://example.com/" + g.pick(generatorWords)
+	case strings.Contains(name, "uuid") || strings.Contains(name, "guid"):
+		var data = make([]byte, 16)
+		g.rand.Read(data)
+		data[6], data[8] = (data[6]&0x0f)|0x40, (data[8]&0x3f)|0x80
+		result = fmt.Sprintf("%x-%x-%x-%x-%x", data[0:4], data[4:6], data[6:8], data[8:10], data[10:])
+	default:
+		var words = make([]string, 1+g.rand.Intn(5))
+		for i := range words {
+			words[i] = g.pick(generatorWords)
+		}
+		result = strings.Join(words, " ")
+	}
+	suffix := ""
+	if column.unique {
+		suffix = fmt.Sprintf("%v", g.nextSequence(name))
+		if index := strings.Index(result, "@"); index != -1 {
+			result, suffix = result[:index], suffix+result[index:]
+		}
+	}
+	if column.length > 0 && len(result)+len(suffix) > column.length {
+		limit := column.length - len(suffix)
+		if limit < 0 {
+			limit = 0
+		}
+		result = result[:limit]
+	}
+	return result + suffix
+}
+
+// NewDataGenerator creates a data generator with passed in seed, the same seed produces the same records
+func NewDataGenerator(seed int64) *DataGenerator {
+	result := &DataGenerator{
+		Epoch:      time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		TimeRange:  5 * 365 * 24 * time.Hour,
+		NullRate:   0.1,
+		unique:     make(map[string]bool),
+		values:     make(map[string][]interface{}),
+		references: make(map[string]*generatorReference),
+	}
+	result.Seed(seed)
+	return result
+}
@@ -0,0 +1,90 @@
+package dsc_test
+
+import (
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/dsc"
+)
+
+type generatedCustomer struct {
+	Id      int `autoincrement:"true"`
+	Name    string
+	Email   string
+	Country string
+	Score   float64
+	Active  bool
+	Created time.Time
+}
+
+type generatedOrder struct {
+	Id         int    `column:"id" primaryKey:"true"`
+	Code       string `column:"code"`
+	CustomerId int    `column:"customer_id"`
+	Note       *string
+}
+
+func TestDataGenerator_Generate(t *testing.T) {
+	config := dsc.NewConfig("sqlite3", "[url]", "url:./test/generator.db")
+	manager, err := dsc.NewManagerFactory().Create(config)
+	if !assert.Nil(t, err) {
+		return
+	}
+	for _, SQL := range []string{
+		"DROP TABLE IF EXISTS customers",
+		"DROP TABLE IF EXISTS orders",
+		"CREATE TABLE customers(id INTEGER PRIMARY KEY AUTOINCREMENT, name VARCHAR(12), email VARCHAR(40), country VARCHAR(2), score DECIMAL(7,2), active BOOLEAN, created DATETIME)",
+		"CREATE TABLE orders(id BIGINT PRIMARY KEY, code VARCHAR(8) UNIQUE, customer_id INTEGER, note TEXT)",
+	} {
+		_, err = manager.Execute(SQL)
+		if !assert.Nil(t, err) {
+			return
+		}
+	}
+	generator := dsc.NewDataGenerator(7).Unique("email")
+	var customers = make([]*generatedCustomer, 0)
+	if !assert.Nil(t, generator.Generate(manager, "customers", &customers, 20)) {
+		return
+	}
+	assert.Equal(t, 20, len(customers))
+	var emails = make(map[string]bool)
+	for _, customer := range customers {
+		assert.Equal(t, 0, customer.Id, "autoincrement column should be left to datastore")
+		assert.True(t, len(customer.Name) <= 12, customer.Name)
+		assert.True(t, len(customer.Country) == 2, customer.Country)
+		assert.False(t, emails[customer.Email], "email should be unique")
+		emails[customer.Email] = true
+	}
+	var again = make([]*generatedCustomer, 0)
+	_ = dsc.NewDataGenerator(7).Unique("email").Generate(manager, "customers", &again, 20)
+	assert.EqualValues(t, customers, again, "the same seed should generate the same records")
+
+	epochOnly := dsc.NewDataGenerator(7)
+	epochOnly.TimeRange = 0
+	var undated = make([]*generatedCustomer, 0)
+	if assert.Nil(t, epochOnly.Generate(manager, "customers", &undated, 2)) {
+		assert.Equal(t, epochOnly.Epoch, undated[1].Created, "empty time range should generate epoch")
+	}
+
+	inserted, _, err := manager.PersistAll(&customers, "customers", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 20, inserted)
+
+	generator = dsc.NewDataGenerator(1).Unique("code").Reference("customer_id", "customers", "id")
+	var orders = make([]generatedOrder, 0)
+	if !assert.Nil(t, generator.Generate(manager, "orders", &orders, 50)) {
+		return
+	}
+	var codes = make(map[string]bool)
+	for _, order := range orders {
+		assert.True(t, order.CustomerId >= 1 && order.CustomerId <= 20, "customer_id should reference customers")
+		assert.True(t, len(order.Code) <= 8)
+		assert.False(t, codes[order.Code], "code should be unique")
+		codes[order.Code] = true
+	}
+	inserted, _, err = manager.PersistAll(&orders, "orders", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 50, inserted)
+}
@@ -0,0 +1,35 @@
+package dsc
+
+import (
+	"fmt"
+)
+
+// DeleteOptions represents DeleteAllWithOptions options
+type DeleteOptions struct {
+	//KeyProvider optional key provider, by default keys are taken from struct primaryKey tags
+	KeyProvider KeyGetter
+	//ExpectedCount expected number of affected rows, on mismatch the transaction is rolled back, zero disables verification
+	ExpectedCount int
+	//SoftDeleteColumn when set rows are marked deleted with UPDATE setting the column to SoftDeleteValue instead of being removed
+	SoftDeleteColumn string
+	//SoftDeleteValue soft delete column value, current UTC time is used when nil
+	SoftDeleteValue interface{}
+}
+
+func (o *DeleteOptions) softDeleteValue() interface{} {
+	if o.SoftDeleteValue == nil {
+		return clock()
+	}
+	return o.SoftDeleteValue
+}
+
+// DeleteCountMismatchError represents error returned when number of deleted rows differs from expected count
+type DeleteCountMismatchError struct {
+	Table    string
+	Expected int
+	Actual   int
+}
+
+func (e *DeleteCountMismatchError) Error() string {
+	return fmt.Sprintf("failed to delete from %v: expected %v row(s) but affected %v, changes were rolled back", e.Table, e.Expected, e.Actual)
+}
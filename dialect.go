@@ -2,6 +2,7 @@ package dsc
 
 import (
 	"errors"
+	"fmt"
 )
 
 var errUnsupportedOperation = errors.New("unsupported operation")
@@ -120,6 +121,19 @@ func (d DefaultDialect) Ping(manager Manager) error {
 	return nil
 }
 
+//SessionSettingSQL returns an error, default dialect does not support session settings
+func (d DefaultDialect) SessionSettingSQL(name string, value interface{}) (string, error) {
+	return "", fmt.Errorf("failed to apply session setting %v due to %v", name, errUnsupportedOperation)
+}
+
+//ApplyTLS returns an error if TLS options were specified, default dialect does not support TLS
+func (d DefaultDialect) ApplyTLS(dsn string, options *TLSConfig) (string, error) {
+	if options == nil {
+		return dsn, nil
+	}
+	return "", fmt.Errorf("failed to apply TLS config due to %v", errUnsupportedOperation)
+}
+
 //NewDefaultDialect crates a defulat dialect. DefaultDialect can be used as a embeddable struct (super class).
 func NewDefaultDialect() DatastoreDialect {
 	return &DefaultDialect{}
@@ -0,0 +1,146 @@
+package dsc
+
+// PlaceholderStyle represents bind parameter placeholder style expected by datastore driver
+type PlaceholderStyle string
+
+const (
+	//PlaceholderQuestion positional ? placeholders (mysql, sqlite, cassandra)
+	PlaceholderQuestion PlaceholderStyle = "?"
+	//PlaceholderDollar numbered $1, $2 placeholders (postgres)
+	PlaceholderDollar PlaceholderStyle = "$"
+	//PlaceholderColon numbered :1, :2 placeholders (oracle)
+	PlaceholderColon PlaceholderStyle = ":"
+	//PlaceholderAt numbered @p1, @p2 placeholders (sqlserver)
+	PlaceholderAt PlaceholderStyle = "@p"
+)
+
+// Capabilities represents datastore features declared by dialect, generic code branches on them instead of driver names
+type Capabilities struct {
+	//Transactions datastore supports transactions
+	Transactions bool
+	//DDLInTransaction DDL statements are transactional (no implicit commit)
+	DDLInTransaction bool
+	//Upsert datastore supports insert or update statement (ON CONFLICT, ON DUPLICATE KEY, MERGE)
+	Upsert bool
+	//Returning insert and update statements support RETURNING clause
+	Returning bool
+	//BatchInsert datastore accepts multi row insert, see CanPersistBatch
+	BatchInsert bool
+	//MaxBatchSize maximum number of rows in multi row insert, 0 means no limit
+	MaxBatchSize int
+	//MaxParameters maximum number of bind parameters in one statement, 0 means no limit
+	MaxParameters int
+	//Placeholder bind parameter placeholder style, dsc SQL uses ? that is rewritten with NormalizeSQL
+	Placeholder PlaceholderStyle
+	//SkipLocked row locking reads can skip rows locked by other transactions
+	SkipLocked bool
+	//CompositeTypes datastore has native array and JSON columns, go slices are bound as arrays, maps and structs as JSON
+	CompositeTypes bool
+	//LargeObjects datastore supports large objects (see CreateLargeObject)
+	LargeObjects bool
+}
+
+// dialectCapabilities returns dialect capabilities, dialect overriding CanPersistBatch without declaring BatchInsert capability can still batch,
+// dialect not implementing CapabilitiesDialect is described by CanHandleTransaction
+func dialectCapabilities(dialect DatastoreDialect) Capabilities {
+	if dialect == nil {
+		return Capabilities{}
+	}
+	result := Capabilities{Transactions: dialect.CanHandleTransaction(), Placeholder: PlaceholderQuestion}
+	if capabilitiesDialect, ok := dialect.(CapabilitiesDialect); ok {
+		result = capabilitiesDialect.Capabilities()
+	}
+	if !result.BatchInsert {
+		result.BatchInsert = dialect.CanPersistBatch()
+	}
+	return result
+}
+
+// Capabilities returns capabilities of datastore without transactions and batch support
+func (d DefaultDialect) Capabilities() Capabilities {
+	return Capabilities{Placeholder: PlaceholderQuestion}
+}
+
+// Capabilities returns capabilities of generic SQL datastore
+func (d sqlDatastoreDialect) Capabilities() Capabilities {
+	result := Capabilities{Transactions: true, Placeholder: PlaceholderQuestion}
+	if d.DatastoreDialect != nil {
+		result.Transactions = d.DatastoreDialect.CanHandleTransaction()
+		result.BatchInsert = d.DatastoreDialect.CanPersistBatch()
+	}
+	return result
+}
+
+// Capabilities returns mysql capabilities, DDL causes implicit commit
+func (d mySQLDialect) Capabilities() Capabilities {
+	return Capabilities{
+		Transactions:  true,
+		Upsert:        true,
+		BatchInsert:   true,
+		MaxParameters: 65535,
+		Placeholder:   PlaceholderQuestion,
+		SkipLocked:    true,
+	}
+}
+
+// Capabilities returns postgres capabilities
+func (d pgDialect) Capabilities() Capabilities {
+	return Capabilities{
+		Transactions:     true,
+		DDLInTransaction: true,
+		Upsert:           true,
+		Returning:        true,
+		BatchInsert:      true,
+		MaxParameters:    65535,
+		Placeholder:      PlaceholderDollar,
+		SkipLocked:       true,
+		CompositeTypes:   true,
+		LargeObjects:     true,
+	}
+}
+
+// Capabilities returns sqlite capabilities
+func (d sqlLiteDialect) Capabilities() Capabilities {
+	return Capabilities{
+		Transactions:     true,
+		DDLInTransaction: true,
+		Upsert:           true,
+		Returning:        true,
+		BatchInsert:      d.CanPersistBatch(),
+		MaxParameters:    999,
+		Placeholder:      PlaceholderQuestion,
+	}
+}
+
+// Capabilities returns oracle capabilities
+func (d oraDialect) Capabilities() Capabilities {
+	return Capabilities{
+		Transactions: true,
+		Upsert:       true,
+		BatchInsert:  true,
+		Placeholder:  PlaceholderColon,
+		SkipLocked:   true,
+	}
+}
+
+// Capabilities returns sqlserver capabilities, generated keys are returned with OUTPUT clause
+func (d msSQLDialect) Capabilities() Capabilities {
+	return Capabilities{
+		Transactions:     true,
+		DDLInTransaction: true,
+		Upsert:           true,
+		BatchInsert:      d.CanPersistBatch(),
+		MaxBatchSize:     1000,
+		MaxParameters:    2100,
+		Placeholder:      PlaceholderAt,
+		SkipLocked:       true,
+	}
+}
+
+// Capabilities returns cassandra capabilities, insert always upserts
+func (d casandraSQLDialect) Capabilities() Capabilities {
+	return Capabilities{
+		Upsert:      true,
+		Placeholder: PlaceholderQuestion,
+	}
+}
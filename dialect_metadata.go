@@ -0,0 +1,167 @@
+package dsc
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Dialect capability names
+const (
+	CapabilityTransaction     = "transaction"
+	CapabilityBatch           = "batch"
+	CapabilityBulkInsert      = "bulkInsert"
+	CapabilitySessionKeyCheck = "sessionKeyCheck"
+	CapabilityUpsert          = "upsert"
+	CapabilityReturning       = "returning"
+	CapabilityDDLTransaction  = "ddlTransaction"
+	CapabilitySkipLocked      = "skipLocked"
+	CapabilityCompositeTypes  = "compositeTypes"
+	CapabilityLargeObjects    = "largeObjects"
+)
+
+// DialectMetadata represents machine readable description of a registered dialect
+type DialectMetadata struct {
+	Driver string
+	//Capabilities sorted capability names derived from the dialect
+	Capabilities []string
+	//Placeholder represents first parameter placeholder as rendered by the dialect, i.e. ?, $1, :1, @p1
+	Placeholder string
+	//MaxBatchSize maximum number of rows in multi row insert, 0 means no limit
+	MaxBatchSize int `json:",omitempty"`
+	//MaxParameters maximum number of bind parameters in one statement, 0 means no limit
+	MaxParameters int `json:",omitempty"`
+	//BulkInsertType bulk insert type if supported
+	BulkInsertType string `json:",omitempty"`
+	//TypeMappings maps datastore column type to go type
+	TypeMappings map[string]string `json:",omitempty"`
+	//MinVersion minimum supported datastore version
+	MinVersion string `json:",omitempty"`
+	//MaxVersion maximum supported datastore version, empty if there is no upper bound
+	MaxVersion string `json:",omitempty"`
+}
+
+var dialectMetadataRegistry = make(map[string]*DialectMetadata)
+var dialectMetadataMutex = &sync.RWMutex{}
+
+// RegisterDialectMetadata registers descriptive metadata (type mappings, version ranges) for a driver, capabilities and placeholder are always derived from the dialect
+func RegisterDialectMetadata(driver string, metadata *DialectMetadata) {
+	dialectMetadataMutex.Lock()
+	defer dialectMetadataMutex.Unlock()
+	dialectMetadataRegistry[driver] = metadata
+}
+
+func newDialectMetadata(driver string, dialect DatastoreDialect) *DialectMetadata {
+	result := &DialectMetadata{Driver: driver, Capabilities: make([]string, 0)}
+	dialectMetadataMutex.RLock()
+	registered, ok := dialectMetadataRegistry[driver]
+	dialectMetadataMutex.RUnlock()
+	if ok {
+		result.MinVersion = registered.MinVersion
+		result.MaxVersion = registered.MaxVersion
+		if len(registered.TypeMappings) > 0 {
+			result.TypeMappings = make(map[string]string)
+			for k, v := range registered.TypeMappings {
+				result.TypeMappings[k] = v
+			}
+		}
+	}
+	capabilities := dialectCapabilities(dialect)
+	for name, supported := range map[string]bool{
+		CapabilityTransaction:    capabilities.Transactions,
+		CapabilityBatch:          capabilities.BatchInsert,
+		CapabilityUpsert:         capabilities.Upsert,
+		CapabilityReturning:      capabilities.Returning,
+		CapabilityDDLTransaction: capabilities.DDLInTransaction,
+		CapabilitySkipLocked:     capabilities.SkipLocked,
+		CapabilityCompositeTypes: capabilities.CompositeTypes,
+		CapabilityLargeObjects:   capabilities.LargeObjects,
+	} {
+		if supported {
+			result.Capabilities = append(result.Capabilities, name)
+		}
+	}
+	result.MaxBatchSize = capabilities.MaxBatchSize
+	result.MaxParameters = capabilities.MaxParameters
+	if result.BulkInsertType = dialect.BulkInsertType(); result.BulkInsertType != "" {
+		result.Capabilities = append(result.Capabilities, CapabilityBulkInsert)
+	}
+	if dialect.IsKeyCheckSwitchSessionLevel() {
+		result.Capabilities = append(result.Capabilities, CapabilitySessionKeyCheck)
+	}
+	sort.Strings(result.Capabilities)
+	result.Placeholder = dialect.NormalizeSQL("?")
+	return result
+}
+
+// GetDialectMetadata returns metadata for passed in registered driver dialect
+func GetDialectMetadata(driver string) (*DialectMetadata, error) {
+	dialect, ok := datastoreDialectableRegistry[driver]
+	if !ok {
+		return nil, fmt.Errorf("failed to lookup datastore dialect: %v", driver)
+	}
+	return newDialectMetadata(driver, dialect), nil
+}
+
+// DialectsMetadata returns metadata of all registered dialects sorted by driver
+func DialectsMetadata() []*DialectMetadata {
+	var drivers = make([]string, 0, len(datastoreDialectableRegistry))
+	for driver := range datastoreDialectableRegistry {
+		drivers = append(drivers, driver)
+	}
+	sort.Strings(drivers)
+	var result = make([]*DialectMetadata, 0, len(drivers))
+	for _, driver := range drivers {
+		result = append(result, newDialectMetadata(driver, datastoreDialectableRegistry[driver]))
+	}
+	return result
+}
+
+var mysqlTypeMappings = map[string]string{
+	"TINYINT": "int8", "SMALLINT": "int16", "INT": "int32", "INTEGER": "int32", "BIGINT": "int64",
+	"FLOAT": "float32", "DOUBLE": "float64", "DECIMAL": "float64", "BIT": "bool",
+	"CHAR": "string", "VARCHAR": "string", "TEXT": "string", "MEDIUMTEXT": "string", "LONGTEXT": "string", "JSON": "string",
+	"BLOB": "[]byte", "BINARY": "[]byte", "VARBINARY": "[]byte",
+	"DATE": "time.Time", "DATETIME": "time.Time", "TIMESTAMP": "time.Time",
+}
+
+var pgTypeMappings = map[string]string{
+	"SMALLINT": "int16", "INTEGER": "int32", "BIGINT": "int64", "SERIAL": "int32", "BIGSERIAL": "int64",
+	"REAL": "float32", "DOUBLE PRECISION": "float64", "NUMERIC": "float64", "BOOLEAN": "bool",
+	"CHAR": "string", "VARCHAR": "string", "TEXT": "string", "UUID": "string", "JSON": "string", "JSONB": "string",
+	"BYTEA": "[]byte", "DATE": "time.Time", "TIMESTAMP": "time.Time", "TIMESTAMPTZ": "time.Time",
+}
+
+var oraTypeMappings = map[string]string{
+	"NUMBER": "float64", "INTEGER": "int64", "FLOAT": "float64", "BINARY_DOUBLE": "float64",
+	"CHAR": "string", "VARCHAR2": "string", "NVARCHAR2": "string", "CLOB": "string",
+	"BLOB": "[]byte", "RAW": "[]byte",
+	"DATE": "time.Time", "TIMESTAMP": "time.Time",
+}
+
+var msSQLTypeMappings = map[string]string{
+	"TINYINT": "uint8", "SMALLINT": "int16", "INT": "int32", "BIGINT": "int64",
+	"REAL": "float32", "FLOAT": "float64", "DECIMAL": "float64", "MONEY": "float64", "BIT": "bool",
+	"CHAR": "string", "VARCHAR": "string", "NVARCHAR": "string", "TEXT": "string", "UNIQUEIDENTIFIER": "string",
+	"BINARY": "[]byte", "VARBINARY": "[]byte",
+	"DATE": "time.Time", "DATETIME": "time.Time", "DATETIME2": "time.Time", "DATETIMEOFFSET": "time.Time",
+}
+
+var sqlLiteTypeMappings = map[string]string{
+	"INTEGER": "int64", "REAL": "float64", "NUMERIC": "float64", "BOOLEAN": "bool",
+	"TEXT": "string", "BLOB": "[]byte",
+	"DATE": "time.Time", "DATETIME": "time.Time", "TIMESTAMP": "time.Time",
+}
+
+func init() {
+	//mysql locking capabilities (SKIP LOCKED, FOR SHARE) require 8.0
+	RegisterDialectMetadata("mysql", &DialectMetadata{TypeMappings: mysqlTypeMappings, MinVersion: "8.0"})
+	RegisterDialectMetadata("pg", &DialectMetadata{TypeMappings: pgTypeMappings, MinVersion: "9.5"})
+	RegisterDialectMetadata("postgres", &DialectMetadata{TypeMappings: pgTypeMappings, MinVersion: "9.5"})
+	RegisterDialectMetadata("ora", &DialectMetadata{TypeMappings: oraTypeMappings, MinVersion: "11g"})
+	RegisterDialectMetadata("oci8", &DialectMetadata{TypeMappings: oraTypeMappings, MinVersion: "11g"})
+	RegisterDialectMetadata("sqlserver", &DialectMetadata{TypeMappings: msSQLTypeMappings, MinVersion: "2012"})
+	RegisterDialectMetadata("sqlite3", &DialectMetadata{TypeMappings: sqlLiteTypeMappings, MinVersion: "3.8"})
+	RegisterDialectMetadata("cql", &DialectMetadata{MinVersion: "3.0"})
+	RegisterDialectMetadata("vertica", &DialectMetadata{MinVersion: "7.0"})
+}
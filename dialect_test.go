@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"github.com/stretchr/testify/assert"
 	"github.com/viant/dsc"
+	"github.com/viant/toolbox"
+	"strings"
 	"testing"
 )
 
@@ -84,3 +86,95 @@ func TestDialectRegistry(t *testing.T) {
 	}()
 	dsc.GetDatastoreDialect("test")
 }
+
+func TestDialectsMetadata(t *testing.T) {
+	metadata := dsc.DialectsMetadata()
+	var drivers = make(map[string]*dsc.DialectMetadata)
+	for _, item := range metadata {
+		drivers[item.Driver] = item
+	}
+	for _, driver := range []string{"mysql", "postgres", "sqlserver", "sqlite3", "csv"} {
+		assert.NotNil(t, drivers[driver], driver)
+	}
+	pg, err := dsc.GetDialectMetadata("postgres")
+	if assert.Nil(t, err) {
+		assert.EqualValues(t, "$1", pg.Placeholder)
+		assert.Contains(t, pg.Capabilities, dsc.CapabilityTransaction)
+		assert.EqualValues(t, "int64", pg.TypeMappings["BIGINT"])
+		assert.EqualValues(t, "9.5", pg.MinVersion)
+	}
+	ms, _ := dsc.GetDialectMetadata("sqlserver")
+	assert.EqualValues(t, "@p1", ms.Placeholder)
+	mysql, _ := dsc.GetDialectMetadata("mysql")
+	assert.EqualValues(t, "?", mysql.Placeholder)
+	assert.Contains(t, mysql.Capabilities, dsc.CapabilityBatch)
+	assert.EqualValues(t, "8.0", mysql.MinVersion, "SKIP LOCKED and FOR SHARE require mysql 8.0")
+	_, err = dsc.GetDialectMetadata("unknown")
+	assert.NotNil(t, err)
+}
+
+type legacyBatchDialect struct {
+	dsc.DefaultDialect
+}
+
+func (d legacyBatchDialect) CanPersistBatch() bool {
+	return true
+}
+
+func TestDialectCapabilities(t *testing.T) {
+
+	for _, metadata := range dsc.DialectsMetadata() {
+		dialect := dsc.GetDatastoreDialect(metadata.Driver)
+		capabilities := dialect.(dsc.CapabilitiesDialect).Capabilities()
+		assert.True(t, strings.HasPrefix(metadata.Placeholder, string(capabilities.Placeholder)), metadata.Driver)
+		assert.Equal(t, dialect.CanHandleTransaction(), capabilities.Transactions, metadata.Driver)
+		assert.Equal(t, dialect.CanPersistBatch(), toolbox.HasSliceAnyElements(metadata.Capabilities, dsc.CapabilityBatch), metadata.Driver)
+	}
+	//dialect overriding CanPersistBatch without Capabilities
+	dsc.RegisterDatastoreDialect("legacyBatch", legacyBatchDialect{})
+	legacy, err := dsc.GetDialectMetadata("legacyBatch")
+	if assert.Nil(t, err) {
+		assert.Contains(t, legacy.Capabilities, dsc.CapabilityBatch)
+	}
+	pg := dsc.GetDatastoreDialect("postgres").(dsc.CapabilitiesDialect).Capabilities()
+	assert.Equal(t, dsc.PlaceholderDollar, pg.Placeholder)
+	assert.True(t, pg.Returning)
+	assert.True(t, pg.CompositeTypes)
+	assert.False(t, dsc.GetDatastoreDialect("mysql").(dsc.CapabilitiesDialect).Capabilities().DDLInTransaction)
+	assert.Equal(t, 2100, dsc.GetDatastoreDialect("sqlserver").(dsc.CapabilitiesDialect).Capabilities().MaxParameters)
+	assert.False(t, dsc.GetDatastoreDialect("csv").(dsc.CapabilitiesDialect).Capabilities().Transactions)
+
+	metadata, err := dsc.GetDialectMetadata("postgres")
+	if assert.Nil(t, err) {
+		assert.Contains(t, metadata.Capabilities, dsc.CapabilityReturning)
+		assert.Contains(t, metadata.Capabilities, dsc.CapabilityLargeObjects)
+		assert.Equal(t, 65535, metadata.MaxParameters)
+	}
+}
+
+func TestRewritePlaceholders(t *testing.T) {
+	var useCases = []struct {
+		description string
+		style       dsc.PlaceholderStyle
+		SQL         string
+		expect      string
+	}{
+		{description: "question style", style: dsc.PlaceholderQuestion, SQL: "SELECT id FROM users WHERE id = ?", expect: "SELECT id FROM users WHERE id = ?"},
+		{description: "dollar style", style: dsc.PlaceholderDollar, SQL: "UPDATE users SET name = ? WHERE id = ?", expect: "UPDATE users SET name = $1 WHERE id = $2"},
+		{description: "colon style", style: dsc.PlaceholderColon, SQL: "SELECT id FROM users WHERE id IN(?, ?)", expect: "SELECT id FROM users WHERE id IN(:1, :2)"},
+		{description: "at style", style: dsc.PlaceholderAt, SQL: "DELETE FROM [what?] WHERE id = ?", expect: "DELETE FROM [what?] WHERE id = @p1"},
+		{description: "literal and comment", style: dsc.PlaceholderDollar, SQL: "SELECT 'why?', \"a?\" FROM t /* ? */ WHERE id = ? -- ?\nAND b = ?", expect: "SELECT 'why?', \"a?\" FROM t /* ? */ WHERE id = $1 -- ?\nAND b = $2"},
+		{description: "escaped quote", style: dsc.PlaceholderDollar, SQL: "SELECT 'it''s ?' WHERE id = ?", expect: "SELECT 'it''s ?' WHERE id = $1"},
+		{description: "array subscript", style: dsc.PlaceholderDollar, SQL: "SELECT tags[?] FROM t WHERE a - ? > 0", expect: "SELECT tags[$1] FROM t WHERE a - $2 > 0"},
+		{description: "already rewritten", style: dsc.PlaceholderDollar, SQL: "SELECT id FROM t WHERE id = $1", expect: "SELECT id FROM t WHERE id = $1"},
+	}
+	for _, useCase := range useCases {
+		assert.EqualValues(t, useCase.expect, dsc.RewritePlaceholders(useCase.SQL, useCase.style), useCase.description)
+	}
+
+	builder := dsc.NewDmlBuilder(&dsc.TableDescriptor{Table: "users", PkColumns: []string{"id"}, Columns: []string{"id", "name"}})
+	assert.EqualValues(t, "UPDATE users SET  name = $1 WHERE  id = $2", dsc.GetDatastoreDialect("pg").NormalizeSQL(builder.UpdateSQL))
+	assert.EqualValues(t, "UPDATE users SET  name = :1 WHERE  id = :2", dsc.GetDatastoreDialect("ora").NormalizeSQL(builder.UpdateSQL))
+	assert.EqualValues(t, "UPDATE users SET  name = @p1 WHERE  id = @p2", dsc.GetDatastoreDialect("sqlserver").NormalizeSQL(builder.UpdateSQL))
+	assert.EqualValues(t, builder.UpdateSQL, dsc.GetDatastoreDialect("mysql").NormalizeSQL(builder.UpdateSQL))
+}
@@ -15,9 +15,11 @@ type DmlBuilder struct {
 	TableDescriptor *TableDescriptor
 	NonPkColumns    *[]string
 	Columns         *[]string
+	InsertColumns   *[]string
 	InsertSQL       string
 	UpdateSQL       string
 	DeleteSQL       string
+	quote           func(identifier string) string
 }
 
 func (b *DmlBuilder) readValues(columns []string, valueProvider func(column string) interface{}) []interface{} {
@@ -28,42 +30,87 @@ func (b *DmlBuilder) readValues(columns []string, valueProvider func(column stri
 	return result
 }
 
-func (b *DmlBuilder) readInsertValues(valueProvider func(column string) interface{}) []interface{} {
-	var columns []string
-	if b.TableDescriptor.Autoincrement {
-		columns = *b.NonPkColumns
-	} else {
-		columns = *b.Columns
+func (b *DmlBuilder) insertColumns() []string {
+	if b.InsertColumns != nil {
+		return *b.InsertColumns
+	} else if b.TableDescriptor.Autoincrement {
+		return *b.NonPkColumns
 	}
-	return b.readValues(columns, valueProvider)
+	return *b.Columns
 }
 
 //GetParametrizedSQL returns GetParametrizedSQL for passed in sqlType, and value provider.
 func (b *DmlBuilder) GetParametrizedSQL(sqlType int, valueProvider func(column string) interface{}) *ParametrizedSQL {
+	return b.GetAuditedParametrizedSQL(sqlType, "", valueProvider)
+}
+
+//GetAuditedParametrizedSQL returns GetParametrizedSQL for passed in sqlType, and value provider, descriptor audit columns are populated with current time and actor, empty actor leaves by columns values as provided.
+func (b *DmlBuilder) GetAuditedParametrizedSQL(sqlType int, actor string, valueProvider func(column string) interface{}) *ParametrizedSQL {
+	var omitted map[string]bool
+	if sqlType != SQLTypeDelete && b.TableDescriptor.hasZeroValuePolicy() {
+		valueProvider, omitted = zeroValueProvider(b.TableDescriptor, *b.Columns, valueProvider)
+	}
+	if b.TableDescriptor.Audit != nil {
+		valueProvider = auditingValueProvider(b.TableDescriptor.Audit, sqlType, actor, valueProvider)
+	}
+	if len(b.TableDescriptor.Encryptors) > 0 {
+		valueProvider = encryptingValueProvider(b.TableDescriptor, valueProvider)
+	}
+	if len(omitted) > 0 {
+		return b.getOmittingParametrizedSQL(sqlType, omitted, valueProvider)
+	}
 	switch sqlType {
 	case SQLTypeInsert:
+		insertColumns := b.insertColumns()
 		return &ParametrizedSQL{
-			SQL:    b.InsertSQL,
-			Values: b.readInsertValues(valueProvider),
-			Type:   SQLTypeInsert,
+			SQL:     b.InsertSQL,
+			Values:  b.readValues(insertColumns, valueProvider),
+			Type:    SQLTypeInsert,
+			Columns: insertColumns,
 		}
 
 	case SQLTypeUpdate:
 		return &ParametrizedSQL{
-			SQL:    b.UpdateSQL,
-			Values: b.readValues(*b.Columns, valueProvider),
-			Type:   SQLTypeUpdate,
+			SQL:     b.UpdateSQL,
+			Values:  b.readValues(*b.Columns, valueProvider),
+			Type:    SQLTypeUpdate,
+			Columns: *b.Columns,
 		}
 	case SQLTypeDelete:
 		return &ParametrizedSQL{
-			SQL:    b.DeleteSQL,
-			Values: b.readValues(b.TableDescriptor.PkColumns, valueProvider),
-			Type:   SQLTypeDelete,
+			SQL:     b.DeleteSQL,
+			Values:  b.readValues(b.TableDescriptor.PkColumns, valueProvider),
+			Type:    SQLTypeDelete,
+			Columns: b.TableDescriptor.PkColumns,
 		}
 	}
 	panic(fmt.Sprintf("Unsupprted sqltype:%v", sqlType))
 }
 
+//getOmittingParametrizedSQL returns insert or update ParametrizedSQL without omitted columns, update without columns to set has empty SQL
+func (b *DmlBuilder) getOmittingParametrizedSQL(sqlType int, omitted map[string]bool, valueProvider func(column string) interface{}) *ParametrizedSQL {
+	if sqlType == SQLTypeInsert {
+		insertColumns := withoutColumns(b.insertColumns(), omitted)
+		return &ParametrizedSQL{
+			SQL:     buildInsertSQL(b.TableDescriptor, insertColumns, b.quote),
+			Values:  b.readValues(insertColumns, valueProvider),
+			Type:    SQLTypeInsert,
+			Columns: insertColumns,
+		}
+	}
+	nonPkColumns := withoutColumns(*b.NonPkColumns, omitted)
+	columns := append(append([]string{}, nonPkColumns...), b.TableDescriptor.PkColumns...)
+	var result = &ParametrizedSQL{
+		Values:  b.readValues(columns, valueProvider),
+		Type:    SQLTypeUpdate,
+		Columns: columns,
+	}
+	if len(nonPkColumns) > 0 {
+		result.SQL = buildUpdateSQL(b.TableDescriptor, nonPkColumns, b.quote)
+	}
+	return result
+}
+
 func buildAssignValueSQL(columns []string, separator string) string {
 	result := ""
 	for _, column := range columns {
@@ -75,37 +122,42 @@ func buildAssignValueSQL(columns []string, separator string) string {
 	return result
 }
 
-func buildInsertSQL(descriptor *TableDescriptor, columns []string, nonPkColumns []string) string {
-	var insertColumns []string
+func buildInsertSQL(descriptor *TableDescriptor, columns []string, quote func(identifier string) string) string {
+	var insertColumns = append([]string{}, columns...)
 	var insertValues []string = make([]string, 0)
-	if descriptor.Autoincrement {
-		insertColumns = append(insertColumns, nonPkColumns...)
-	} else {
-		insertColumns = append(insertColumns, columns...)
-	}
 	for range insertColumns {
 		insertValues = append(insertValues, "?")
 	}
 
 	updateReserved(insertColumns)
-	return fmt.Sprintf(insertSQLTemplate, descriptor.Table, strings.Join(insertColumns, ","), strings.Join(insertValues, ","))
+	return fmt.Sprintf(insertSQLTemplate, quoteIdentifier(quote, descriptor.Table), strings.Join(quoteIdentifiers(quote, insertColumns), ","), strings.Join(insertValues, ","))
 }
 
-func buildUpdateSQL(descriptor *TableDescriptor, nonPkColumns []string) string {
+func buildUpdateSQL(descriptor *TableDescriptor, nonPkColumns []string, quote func(identifier string) string) string {
+	nonPkColumns = append([]string{}, nonPkColumns...)
 	updateReserved(nonPkColumns)
 	pk := append([]string{}, descriptor.PkColumns...)
 	updateReserved(pk)
-	return fmt.Sprintf(updateSQLTemplate, descriptor.Table, buildAssignValueSQL(nonPkColumns, ","), buildAssignValueSQL(pk, " AND "))
+	return fmt.Sprintf(updateSQLTemplate, quoteIdentifier(quote, descriptor.Table), buildAssignValueSQL(quoteIdentifiers(quote, nonPkColumns), ","), buildAssignValueSQL(quoteIdentifiers(quote, pk), " AND "))
 }
 
-func buildDeleteSQL(descriptor *TableDescriptor) string {
+func buildDeleteSQL(descriptor *TableDescriptor, quote func(identifier string) string) string {
 	pk := append([]string{}, descriptor.PkColumns...)
 	updateReserved(pk)
-	return fmt.Sprintf(deleteSQLTemplate, descriptor.Table, buildAssignValueSQL(pk, " AND "))
+	return fmt.Sprintf(deleteSQLTemplate, quoteIdentifier(quote, descriptor.Table), buildAssignValueSQL(quoteIdentifiers(quote, pk), " AND "))
 }
 
-//NewDmlBuilder returns a new DmlBuilder for passed in table descriptor.
+//NewDmlBuilder returns a new DmlBuilder for passed in table descriptor, table and column names are used as provided.
 func NewDmlBuilder(descriptor *TableDescriptor) *DmlBuilder {
+	return newDmlBuilder(descriptor, nil)
+}
+
+//NewDialectDmlBuilder returns a new DmlBuilder for passed in table descriptor, table and column names are quoted with dialect QuoteIdentifier when needed.
+func NewDialectDmlBuilder(descriptor *TableDescriptor, dialect DatastoreDialect) *DmlBuilder {
+	return newDmlBuilder(descriptor, dialectQuoter(dialect))
+}
+
+func newDmlBuilder(descriptor *TableDescriptor, quote func(identifier string) string) *DmlBuilder {
 	pkMap := make(map[string]int)
 
 	if len(descriptor.PkColumns) > 0 {
@@ -114,24 +166,44 @@ func NewDmlBuilder(descriptor *TableDescriptor) *DmlBuilder {
 		}
 	}
 	var nonPkColumns = make([]string, 0)
+	var insertColumns = make([]string, 0)
 	for _, column := range descriptor.Columns {
 		idx, ok := pkMap[strings.ToLower(column)]
 		if ok { //update pk with right case
 			descriptor.PkColumns[idx] = column
-		} else {
+			continue
+		}
+		insertColumns = append(insertColumns, column)
+		if descriptor.Audit == nil || !descriptor.Audit.created(column) { //created audit columns are not updated
 			nonPkColumns = append(nonPkColumns, column)
 		}
 	}
+	if descriptor.Audit != nil { //audit columns not mapped by descriptor columns
+		for _, column := range descriptor.Audit.columns() {
+			if hasColumn(descriptor.Columns, column) {
+				continue
+			}
+			insertColumns = append(insertColumns, column)
+			if !descriptor.Audit.created(column) {
+				nonPkColumns = append(nonPkColumns, column)
+			}
+		}
+	}
 
 	var columns = make([]string, 0)
 	columns = append(columns, nonPkColumns...)
 	columns = append(columns, descriptor.PkColumns...)
+	if !descriptor.Autoincrement {
+		insertColumns = append(insertColumns, descriptor.PkColumns...)
+	}
 	return &DmlBuilder{
 		TableDescriptor: descriptor,
 		NonPkColumns:    &nonPkColumns,
 		Columns:         &columns,
-		InsertSQL:       buildInsertSQL(descriptor, columns, nonPkColumns),
-		UpdateSQL:       buildUpdateSQL(descriptor, nonPkColumns),
-		DeleteSQL:       buildDeleteSQL(descriptor),
+		InsertColumns:   &insertColumns,
+		InsertSQL:       buildInsertSQL(descriptor, insertColumns, quote),
+		UpdateSQL:       buildUpdateSQL(descriptor, nonPkColumns, quote),
+		DeleteSQL:       buildDeleteSQL(descriptor, quote),
+		quote:           quote,
 	}
 }
@@ -2,6 +2,7 @@ package dsc
 
 import (
 	"reflect"
+	"strconv"
 	"strings"
 
 	"github.com/viant/toolbox"
@@ -32,12 +33,36 @@ func (p *metaDmlProvider) SetKey(instancePointer interface{}, seq int64) {
 		var reflectable = reflect.ValueOf(instancePointer)
 		if reflectable.Kind() == reflect.Ptr {
 			field := reflectable.Elem().FieldByName(field)
-			field.SetInt(seq)
+			setKeyValue(field, seq)
 		}
 
 	}
 }
 
+//setKeyValue sets generated key on int, uint, string field or pointer to them, other kinds are left intact
+func setKeyValue(field reflect.Value, seq int64) bool {
+	if !field.IsValid() || !field.CanSet() {
+		return false
+	}
+	switch field.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		field.SetInt(seq)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		field.SetUint(uint64(seq))
+	case reflect.String:
+		field.SetString(strconv.FormatInt(seq, 10))
+	case reflect.Ptr:
+		value := reflect.New(field.Type().Elem())
+		if !setKeyValue(value.Elem(), seq) {
+			return false
+		}
+		field.Set(value)
+	default:
+		return false
+	}
+	return true
+}
+
 func (p *metaDmlProvider) readValues(instance interface{}, columns []string) []interface{} {
 	var result = make([]interface{}, len(columns))
 	var reflectable = reflect.ValueOf(instance)
@@ -76,32 +101,42 @@ func (p *metaDmlProvider) readValue(source reflect.Value, column string) interfa
 
 //Get returns a ParametrizedSQL for specified sqlType and target instance.
 func (p *metaDmlProvider) Get(sqlType int, instance interface{}) *ParametrizedSQL {
+	return p.GetAudited(sqlType, instance, "")
+}
+
+//GetAudited returns a ParametrizedSQL for specified sqlType and target instance with audit columns populated for the actor.
+func (p *metaDmlProvider) GetAudited(sqlType int, instance interface{}, actor string) *ParametrizedSQL {
 	var reflectable = reflect.ValueOf(instance)
 	if reflectable.Kind() == reflect.Ptr {
 		reflectable = reflectable.Elem()
 	}
 	//toolbox.AssertKind(instance, reflect.Type, "instance")
-	return p.dmlBuilder.GetParametrizedSQL(sqlType, func(column string) interface{} {
+	return p.dmlBuilder.GetAuditedParametrizedSQL(sqlType, actor, func(column string) interface{} {
 		return p.readValue(reflectable, column)
 	})
 }
 
-func newMetaDmlProvider(table string, targetType reflect.Type) (DmlProvider, error) {
+func newMetaDmlProvider(table string, targetType reflect.Type, quote func(identifier string) string) (DmlProvider, error) {
 	descriptor, err := NewTableDescriptor(table, targetType)
 	if err != nil {
 		return nil, err
 	}
-	dmlBuilder := NewDmlBuilder(descriptor)
+	dmlBuilder := newDmlBuilder(descriptor, quote)
 	return &metaDmlProvider{dmlBuilder: dmlBuilder,
 		columnToFieldNameMap: toolbox.NewFieldSettingByKey(targetType, "column")}, nil
 }
 
 //NewDmlProviderIfNeeded returns a new NewDmlProvider for a table and target type if passed provider was nil.
 func NewDmlProviderIfNeeded(provider DmlProvider, table string, targetType reflect.Type) (DmlProvider, error) {
+	return newDmlProviderIfNeeded(provider, table, targetType, nil)
+}
+
+// newDmlProviderIfNeeded returns passed in provider or a new provider quoting identifiers with quote function
+func newDmlProviderIfNeeded(provider DmlProvider, table string, targetType reflect.Type, quote func(identifier string) string) (DmlProvider, error) {
 	if provider != nil {
 		return provider, nil
 	}
-	return newMetaDmlProvider(table, targetType)
+	return newMetaDmlProvider(table, targetType, quote)
 }
 
 //NewKeyGetterIfNeeded returns a new key getter if supplied keyGetter was nil for the target type
@@ -109,7 +144,7 @@ func NewKeyGetterIfNeeded(keyGetter KeyGetter, table string, targetType reflect.
 	if keyGetter != nil {
 		return keyGetter, nil
 	}
-	return newMetaDmlProvider(table, targetType)
+	return newMetaDmlProvider(table, targetType, nil)
 }
 
 type mapDmlProvider struct {
@@ -132,16 +167,29 @@ func (p *mapDmlProvider) SetKey(instance interface{}, seq int64) {
 }
 
 func (p *mapDmlProvider) Get(sqlType int, instance interface{}) *ParametrizedSQL {
+	return p.GetAudited(sqlType, instance, "")
+}
+
+func (p *mapDmlProvider) GetAudited(sqlType int, instance interface{}, actor string) *ParametrizedSQL {
 	var record = toolbox.AsMap(instance)
-	return p.dmlBuilder.GetParametrizedSQL(sqlType, func(column string) interface{} {
+	return p.dmlBuilder.GetAuditedParametrizedSQL(sqlType, actor, func(column string) interface{} {
 		return record[column]
 	})
 }
 
 func NewMapDmlProvider(descriptor *TableDescriptor) DmlProvider {
+	return newMapDmlProvider(descriptor, nil)
+}
+
+// NewDialectMapDmlProvider returns a map DmlProvider quoting table and column names with dialect QuoteIdentifier when needed
+func NewDialectMapDmlProvider(descriptor *TableDescriptor, dialect DatastoreDialect) DmlProvider {
+	return newMapDmlProvider(descriptor, dialectQuoter(dialect))
+}
+
+func newMapDmlProvider(descriptor *TableDescriptor, quote func(identifier string) string) DmlProvider {
 	var result = &mapDmlProvider{
 		tableDescriptor: descriptor,
-		dmlBuilder:      NewDmlBuilder(descriptor),
+		dmlBuilder:      newDmlBuilder(descriptor, quote),
 	}
 	return result
 }
@@ -0,0 +1,81 @@
+package dsc
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// asDocumentScanner returns document store scanner, scanner wrapped by manager (NewScanner) is unwrapped
+func asDocumentScanner(source Scanner) (DocumentScanner, bool) {
+	for {
+		if documentScanner, ok := source.(DocumentScanner); ok {
+			return documentScanner, true
+		}
+		wrapped, ok := source.(*scanner)
+		if !ok {
+			return nil, false
+		}
+		source = wrapped.scanner
+	}
+}
+
+// ScanDocument returns current scanner record as map, document store scanners (DocumentScanner) hand over their document as is,
+// other scanners build the document from columns
+func ScanDocument(scanner Scanner) (map[string]interface{}, error) {
+	if documentScanner, ok := asDocumentScanner(scanner); ok {
+		return documentScanner.Document()
+	}
+	values, columns, err := ScanRow(scanner)
+	if err != nil {
+		return nil, err
+	}
+	var result = make(map[string]interface{}, len(columns))
+	for i, column := range columns {
+		result[column] = values[i]
+	}
+	return result, nil
+}
+
+// ScanRawDocument returns current scanner record as JSON, document store scanners (DocumentScanner) hand over their raw document as is
+func ScanRawDocument(scanner Scanner) (json.RawMessage, error) {
+	if documentScanner, ok := asDocumentScanner(scanner); ok {
+		return documentScanner.RawDocument()
+	}
+	document, err := ScanDocument(scanner)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(document)
+}
+
+// ReadAllDocuments executes backend specific query document and streams each record decoded as map to the handler.
+func (m *AbstractManager) ReadAllDocuments(query interface{}, handler func(document map[string]interface{}) (toContinue bool, err error)) error {
+	connection, err := m.Manager.ConnectionProvider().Get()
+	if err != nil {
+		return err
+	}
+	defer connection.Close()
+	return m.nativeManager().ReadAllNativeWithHandlerOnConnection(connection, query, func(scanner Scanner) (bool, error) {
+		document, err := ScanDocument(scanner)
+		if err != nil {
+			return false, fmt.Errorf("failed to read document %v due to %v", query, err)
+		}
+		return handler(document)
+	})
+}
+
+// ReadAllRawDocuments executes backend specific query document and streams each record JSON to the handler.
+func (m *AbstractManager) ReadAllRawDocuments(query interface{}, handler func(document json.RawMessage) (toContinue bool, err error)) error {
+	connection, err := m.Manager.ConnectionProvider().Get()
+	if err != nil {
+		return err
+	}
+	defer connection.Close()
+	return m.nativeManager().ReadAllNativeWithHandlerOnConnection(connection, query, func(scanner Scanner) (bool, error) {
+		document, err := ScanRawDocument(scanner)
+		if err != nil {
+			return false, fmt.Errorf("failed to read document %v due to %v", query, err)
+		}
+		return handler(document)
+	})
+}
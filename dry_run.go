@@ -0,0 +1,73 @@
+package dsc
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TranscriptEntry represents statement recorded in dry-run mode
+type TranscriptEntry struct {
+	SQL        string
+	Parameters []interface{}
+	Timestamp  time.Time
+}
+
+// Transcript represents dry-run statements transcript, when set with Config.DryRun executed statements (Execute, PersistAll, DeleteAll) are
+// recorded instead of being sent to the datastore, queries are still executed, so that persist can classify rows as insertable or updatable
+type Transcript struct {
+	mutex   *sync.Mutex
+	entries []*TranscriptEntry
+}
+
+// record records statement with its bound parameters
+func (t *Transcript) record(SQL string, parameters []interface{}) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.entries = append(t.entries, &TranscriptEntry{SQL: SQL, Parameters: append([]interface{}{}, parameters...), Timestamp: time.Now()})
+}
+
+// Entries returns recorded statements
+func (t *Transcript) Entries() []*TranscriptEntry {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return append([]*TranscriptEntry{}, t.entries...)
+}
+
+// Reset discards recorded statements
+func (t *Transcript) Reset() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.entries = nil
+}
+
+// String returns recorded statements script, each statement is followed by its parameters comment
+func (t *Transcript) String() string {
+	var result = strings.Builder{}
+	for _, entry := range t.Entries() {
+		result.WriteString(entry.SQL)
+		result.WriteString(";")
+		if len(entry.Parameters) > 0 {
+			result.WriteString(fmt.Sprintf(" -- %v", entry.Parameters))
+		}
+		result.WriteString("\n")
+	}
+	return result.String()
+}
+
+// NewTranscript creates dry-run transcript
+func NewTranscript() *Transcript {
+	return &Transcript{mutex: &sync.Mutex{}}
+}
+
+// dryRun records statement if config enables dry-run mode, recorded statement result reports one affected row and no last insert id
+func dryRun(config *Config, SQL string, parameters []interface{}) (sql.Result, bool) {
+	if config.DryRun == nil {
+		return nil, false
+	}
+	config.DryRun.record(SQL, parameters)
+	Logf("[%v]:dry-run %v %v", config.username, SQL, parameters)
+	return NewSQLResult(1, 0), true
+}
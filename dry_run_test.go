@@ -0,0 +1,45 @@
+package dsc_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/dsc"
+)
+
+func TestManager_DryRun(t *testing.T) {
+	manager := GetManager(t)
+	transcript := dsc.NewTranscript()
+	manager.Config().DryRun = transcript
+	defer func() { manager.Config().DryRun = nil }()
+
+	_, err := manager.Execute("UPDATE users SET active = ? WHERE id = ?", 0, 1)
+	assert.Nil(t, err)
+	var users = []User{{Id: 1, Username: "Edison"}, {Username: "Bob"}}
+	inserted, updated, err := manager.PersistAll(&users, "users", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, inserted)
+	assert.Equal(t, 1, updated)
+	deleted, err := manager.DeleteAll(&[]User{{Id: 1}}, "users", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, deleted)
+
+	entries := transcript.Entries()
+	if assert.Equal(t, 4, len(entries)) {
+		assert.Equal(t, "UPDATE users SET active = ? WHERE id = ?", entries[0].SQL)
+		assert.EqualValues(t, []interface{}{0, 1}, entries[0].Parameters)
+		assert.Contains(t, entries[3].SQL, "DELETE FROM users")
+	}
+	assert.Contains(t, transcript.String(), "UPDATE users SET active = ? WHERE id = ?; -- [0 1]\n")
+
+	//datastore was not modified
+	manager.Config().DryRun = nil
+	var actual = make([]User, 0)
+	assert.Nil(t, manager.ReadAll(&actual, "SELECT id, username, active FROM users", nil, nil))
+	if assert.Equal(t, 1, len(actual)) {
+		assert.Equal(t, "Edi", actual[0].Username)
+		assert.True(t, actual[0].Active)
+	}
+	transcript.Reset()
+	assert.Equal(t, 0, len(transcript.Entries()))
+}
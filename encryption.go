@@ -0,0 +1,295 @@
+package dsc
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"database/sql/driver"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/viant/toolbox"
+)
+
+// encryptedValuePrefix prefixes stored ciphertext, stored value format is $enc$<keyID>$<base64 ciphertext>
+const encryptedValuePrefix = "$enc$"
+
+// Encryptor represents column value encryptor, additional data identifies table column, encryptor has to authenticate it together with the key ID,
+// so that ciphertext moved to another column or envelope with swapped key ID fails to decrypt
+type Encryptor interface {
+	//Encrypt encrypts plaintext with current key authenticating additional data, it returns ciphertext and ID of the key used
+	Encrypt(plaintext []byte, additionalData []byte) (ciphertext []byte, keyID string, err error)
+	//Decrypt decrypts ciphertext with key identified by keyID verifying additional data, encryptor should keep retired keys to decrypt values encrypted before key rotation
+	Decrypt(ciphertext []byte, keyID string, additionalData []byte) ([]byte, error)
+}
+
+// columnAdditionalData returns encryption additional data of table column
+func columnAdditionalData(table, column string) []byte {
+	return []byte(normalizeTable(table) + "." + strings.ToLower(column))
+}
+
+// EncryptValue encrypts table column value, stored value carries key ID alongside ciphertext, nil is not encrypted
+func EncryptValue(encryptor Encryptor, table, column string, value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+	var plaintext []byte
+	switch actual := value.(type) {
+	case []byte:
+		plaintext = actual
+	case string:
+		plaintext = []byte(actual)
+	default:
+		plaintext = []byte(toolbox.AsString(value))
+	}
+	ciphertext, keyID, err := encryptor.Encrypt(plaintext, columnAdditionalData(table, column))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt value due to %v", err)
+	}
+	if strings.Contains(keyID, "$") {
+		return nil, fmt.Errorf("failed to encrypt value: invalid key ID %v", keyID)
+	}
+	return encryptedValuePrefix + keyID + "$" + base64.StdEncoding.EncodeToString(ciphertext), nil
+}
+
+// DecryptValue decrypts table column value stored by EncryptValue, values without encryption prefix (i.e. written before encryption was enabled) are returned as is
+func DecryptValue(encryptor Encryptor, table, column string, value interface{}) (interface{}, error) {
+	var text string
+	switch actual := value.(type) {
+	case []byte:
+		text = string(actual)
+	case string:
+		text = actual
+	default:
+		return value, nil
+	}
+	if !strings.HasPrefix(text, encryptedValuePrefix) {
+		return value, nil
+	}
+	envelope := text[len(encryptedValuePrefix):]
+	separator := strings.Index(envelope, "$")
+	if separator == -1 {
+		return nil, fmt.Errorf("failed to decrypt value: invalid envelope")
+	}
+	ciphertext, err := base64.StdEncoding.DecodeString(envelope[separator+1:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt value due to %v", err)
+	}
+	plaintext, err := encryptor.Decrypt(ciphertext, envelope[:separator], columnAdditionalData(table, column))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt value with key %v due to %v", envelope[:separator], err)
+	}
+	return string(plaintext), nil
+}
+
+// aesEncryptor represents AES-GCM encryptor with key ring
+type aesEncryptor struct {
+	keyID   string
+	ciphers map[string]cipher.AEAD
+}
+
+// keyBoundData returns additional data prefixed with key ID
+func keyBoundData(additionalData []byte, keyID string) []byte {
+	return append(append([]byte(keyID), 0), additionalData...)
+}
+
+func (e *aesEncryptor) Encrypt(plaintext []byte, additionalData []byte) ([]byte, string, error) {
+	aead := e.ciphers[e.keyID]
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, "", err
+	}
+	return aead.Seal(nonce, nonce, plaintext, keyBoundData(additionalData, e.keyID)), e.keyID, nil
+}
+
+func (e *aesEncryptor) Decrypt(ciphertext []byte, keyID string, additionalData []byte) ([]byte, error) {
+	aead, ok := e.ciphers[keyID]
+	if !ok {
+		return nil, fmt.Errorf("unknown key %v", keyID)
+	}
+	if len(ciphertext) < aead.NonceSize() {
+		return nil, fmt.Errorf("ciphertext too short")
+	}
+	nonce, sealed := ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():]
+	return aead.Open(nil, nonce, sealed, keyBoundData(additionalData, keyID))
+}
+
+// NewAESEncryptor creates AES-GCM encryptor encrypting with keyID key, keys maps key ID to 16, 24 or 32 bytes AES key,
+// to rotate key add new key to keys and pass its ID, retired keys are still used to decrypt existing values
+func NewAESEncryptor(keyID string, keys map[string][]byte) (Encryptor, error) {
+	if _, ok := keys[keyID]; !ok {
+		return nil, fmt.Errorf("failed to create encryptor: key %v was missing", keyID)
+	}
+	var result = &aesEncryptor{keyID: keyID, ciphers: make(map[string]cipher.AEAD)}
+	for id, key := range keys {
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create encryptor key %v due to %v", id, err)
+		}
+		if result.ciphers[id], err = cipher.NewGCM(block); err != nil {
+			return nil, fmt.Errorf("failed to create encryptor key %v due to %v", id, err)
+		}
+	}
+	return result, nil
+}
+
+var encryptors = make(map[string]Encryptor)
+var encryptorsMutex = &sync.RWMutex{}
+
+// RegisterEncryptor registers named encryptor used by `encryptor` struct tag, nil encryptor removes registration
+func RegisterEncryptor(name string, encryptor Encryptor) {
+	encryptorsMutex.Lock()
+	defer encryptorsMutex.Unlock()
+	if encryptor == nil {
+		delete(encryptors, name)
+		return
+	}
+	encryptors[name] = encryptor
+}
+
+// GetEncryptor returns named encryptor or nil
+func GetEncryptor(name string) Encryptor {
+	encryptorsMutex.RLock()
+	defer encryptorsMutex.RUnlock()
+	return encryptors[name]
+}
+
+// encryptionFailure represents parameter that failed to encrypt, the error is reported by driver when statement is executed
+type encryptionFailure struct {
+	column string
+	err    error
+}
+
+func (f *encryptionFailure) Value() (driver.Value, error) {
+	return nil, fmt.Errorf("failed to encrypt %v due to %v", f.column, f.err)
+}
+
+// encryptingValueProvider returns value provider encrypting descriptor encrypted columns, key columns are left as is
+func encryptingValueProvider(descriptor *TableDescriptor, valueProvider func(column string) interface{}) func(column string) interface{} {
+	return func(column string) interface{} {
+		value := valueProvider(column)
+		encryptor, ok := descriptor.Encryptors[column]
+		if !ok || encryptor == nil || toolbox.HasSliceAnyElements(descriptor.PkColumns, column) {
+			return value
+		}
+		encrypted, err := EncryptValue(encryptor, descriptor.Table, column, value)
+		if err != nil {
+			return &encryptionFailure{column: column, err: err}
+		}
+		return encrypted
+	}
+}
+
+// columnEncryptor represents encryptor of table column
+type columnEncryptor struct {
+	table     string
+	column    string
+	encryptor Encryptor
+}
+
+// encryptedColumnKey returns normalized table and lower case column key
+func encryptedColumnKey(table, column string) string {
+	return normalizeTable(table) + "." + strings.ToLower(column)
+}
+
+// columnEncryptors returns encryptors of registered table descriptors by table and column key (see encryptedColumnKey)
+func columnEncryptors(registry TableDescriptorRegistry) map[string]*columnEncryptor {
+	var result = make(map[string]*columnEncryptor)
+	if registry == nil {
+		return result
+	}
+	for _, table := range registry.Tables() {
+		for column, encryptor := range registry.Get(table).Encryptors {
+			if encryptor != nil {
+				result[encryptedColumnKey(table, column)] = &columnEncryptor{table: table, column: column, encryptor: encryptor}
+			}
+		}
+	}
+	return result
+}
+
+// decrypt decrypts value with the first candidate table column encryptor authenticating it, result column matches
+// encrypted column of each query table with the same name
+func decrypt(candidates []*columnEncryptor, value interface{}) (result interface{}, err error) {
+	for _, candidate := range candidates {
+		if result, err = DecryptValue(candidate.encryptor, candidate.table, candidate.column, value); err == nil {
+			return result, nil
+		}
+	}
+	return nil, err
+}
+
+// decryptingScanner represents a scanner decrypting encrypted columns before assigning them to destinations
+type decryptingScanner struct {
+	Scanner
+	encryptors [][]*columnEncryptor
+	converter  *toolbox.Converter
+}
+
+// Scan scans encrypted columns into interface{}, decrypts and converts them into destinations
+func (s *decryptingScanner) Scan(destinations ...interface{}) error {
+	var scanned = make([]interface{}, len(destinations))
+	var raw = make([]interface{}, len(destinations))
+	for i, destination := range destinations {
+		scanned[i] = destination
+		if i < len(s.encryptors) && len(s.encryptors[i]) > 0 && destination != nil {
+			scanned[i] = &raw[i]
+		}
+	}
+	if err := s.Scanner.Scan(scanned...); err != nil {
+		return err
+	}
+	for i, destination := range destinations {
+		if scanned[i] == destination {
+			continue
+		}
+		value, err := decrypt(s.encryptors[i], raw[i])
+		if err != nil {
+			columns, _ := s.Columns()
+			return fmt.Errorf("failed to decrypt %v due to %v", columns[i], err)
+		}
+		if pointer, ok := destination.(*interface{}); ok {
+			*pointer = value
+			continue
+		}
+		if value == nil {
+			continue
+		}
+		if err = s.converter.AssignConverted(destination, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newDecryptingScanner returns scanner decrypting query columns with encryptors of query tables, or nil if none of the columns is encrypted,
+// encryptors of all tables are used if query tables were not detected
+func newDecryptingScanner(config *Config, query string, columns []string, encryptors map[string]*columnEncryptor) *decryptingScanner {
+	if len(encryptors) == 0 {
+		return nil
+	}
+	tables := readTables(query)
+	if len(tables) == 0 {
+		var unique = make(map[string]bool)
+		for _, encryptor := range encryptors {
+			tables = appendTable(tables, unique, encryptor.table)
+		}
+	}
+	var result = &decryptingScanner{encryptors: make([][]*columnEncryptor, len(columns)), converter: toolbox.NewColumnConverter(config.GetDateLayout())}
+	var encrypted = false
+	for i, column := range columns {
+		for _, table := range tables {
+			if encryptor, ok := encryptors[encryptedColumnKey(table, column)]; ok {
+				result.encryptors[i] = append(result.encryptors[i], encryptor)
+				encrypted = true
+			}
+		}
+	}
+	if !encrypted {
+		return nil
+	}
+	return result
+}
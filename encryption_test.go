@@ -0,0 +1,164 @@
+package dsc_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/dsc"
+	"github.com/viant/toolbox"
+)
+
+type Patient struct {
+	Id   int    `column:"id" primaryKey:"true"`
+	Name string `column:"name"`
+	Ssn  string `column:"ssn" encryptor:"patient_pii"`
+}
+
+type Doctor struct {
+	Id   int    `column:"id" primaryKey:"true"`
+	Name string `column:"name"`
+	Ssn  string `column:"ssn" encryptor:"doctor_pii"`
+}
+
+func TestEncryptValue(t *testing.T) {
+	keys := map[string][]byte{"k1": []byte("0123456789abcdef")}
+	encryptor, err := dsc.NewAESEncryptor("k1", keys)
+	if !assert.Nil(t, err) {
+		return
+	}
+	encrypted, err := dsc.EncryptValue(encryptor, "patients", "ssn", "123-45-6789")
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.True(t, strings.HasPrefix(encrypted.(string), "$enc$k1$"))
+	nilValue, err := dsc.EncryptValue(encryptor, "patients", "ssn", nil)
+	assert.Nil(t, err)
+	assert.Nil(t, nilValue)
+
+	//rotated encryptor encrypts with new key and decrypts values encrypted with retired key
+	keys["k2"] = []byte("fedcba9876543210fedcba9876543210")
+	rotated, err := dsc.NewAESEncryptor("k2", keys)
+	if !assert.Nil(t, err) {
+		return
+	}
+	decrypted, err := dsc.DecryptValue(rotated, "patients", "ssn", encrypted)
+	assert.Nil(t, err)
+	assert.Equal(t, "123-45-6789", decrypted)
+	reencrypted, err := dsc.EncryptValue(rotated, "patients", "ssn", decrypted)
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(reencrypted.(string), "$enc$k2$"))
+
+	plaintext, err := dsc.DecryptValue(rotated, "patients", "ssn", "not encrypted")
+	assert.Nil(t, err)
+	assert.Equal(t, "not encrypted", plaintext)
+	_, err = dsc.DecryptValue(encryptor, "patients", "ssn", reencrypted)
+	assert.NotNil(t, err)
+	_, err = dsc.NewAESEncryptor("k3", keys)
+	assert.NotNil(t, err)
+
+	//table, column and key ID are authenticated
+	_, err = dsc.DecryptValue(rotated, "patients", "name", encrypted)
+	assert.NotNil(t, err, "ciphertext moved to another column should fail")
+	_, err = dsc.DecryptValue(rotated, "doctors", "ssn", encrypted)
+	assert.NotNil(t, err, "ciphertext moved to another table should fail")
+	_, err = dsc.DecryptValue(rotated, "PATIENTS", "SSN", encrypted)
+	assert.Nil(t, err, "table and column should be case insensitive")
+	sameKeys := map[string][]byte{"k1": keys["k1"], "k1b": keys["k1"]}
+	aliased, err := dsc.NewAESEncryptor("k1", sameKeys)
+	if assert.Nil(t, err) {
+		swapped := strings.Replace(encrypted.(string), "$enc$k1$", "$enc$k1b$", 1)
+		_, err = dsc.DecryptValue(aliased, "patients", "ssn", swapped)
+		assert.NotNil(t, err, "swapped key ID should fail")
+	}
+}
+
+func TestManager_Encryption(t *testing.T) {
+	manager := GetManager(t)
+	for _, SQL := range []string{
+		"DROP TABLE IF EXISTS patients",
+		"CREATE TABLE patients(id INTEGER PRIMARY KEY, name VARCHAR(255), ssn TEXT)",
+	} {
+		_, err := manager.Execute(SQL)
+		if !assert.Nil(t, err) {
+			return
+		}
+	}
+	_, err := dsc.NewTableDescriptor("patients", Patient{})
+	assert.NotNil(t, err, "encryptor was not registered yet")
+
+	encryptor, err := dsc.NewAESEncryptor("k1", map[string][]byte{"k1": []byte("0123456789abcdef")})
+	if !assert.Nil(t, err) {
+		return
+	}
+	dsc.RegisterEncryptor("patient_pii", encryptor)
+	defer dsc.RegisterEncryptor("patient_pii", nil)
+
+	var patients = []*Patient{{Id: 1, Name: "Ann", Ssn: "123-45-6789"}, {Id: 2, Name: "Tom", Ssn: "987-65-4321"}}
+	inserted, _, err := manager.PersistAll(&patients, "patients", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, inserted)
+
+	//manager without patients descriptor reads stored ciphertext
+	rawManager, err := dsc.NewManagerFactory().Create(dsc.NewConfig("sqlite3", "[url]", "url:./test/foo.db"))
+	if !assert.Nil(t, err) {
+		return
+	}
+	var stored = make([]map[string]interface{}, 0)
+	err = rawManager.ReadAll(&stored, "SELECT id, name, ssn FROM patients ORDER BY id", nil, nil)
+	if assert.Nil(t, err) && assert.Equal(t, 2, len(stored)) {
+		assert.True(t, strings.HasPrefix(toolbox.AsString(stored[0]["ssn"]), "$enc$k1$"))
+		assert.Equal(t, "Ann", toolbox.AsString(stored[0]["name"]))
+	}
+
+	descriptor, err := dsc.NewTableDescriptor("patients", Patient{})
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Nil(t, rawManager.TableDescriptorRegistry().Register(descriptor))
+	var decrypted = make([]map[string]interface{}, 0)
+	err = rawManager.ReadAll(&decrypted, "SELECT id, ssn FROM patients ORDER BY id", nil, nil)
+	if assert.Nil(t, err) && assert.Equal(t, 2, len(decrypted)) {
+		assert.Equal(t, "123-45-6789", decrypted[0]["ssn"])
+	}
+
+	var actual = make([]*Patient, 0)
+	err = manager.ReadAll(&actual, "SELECT id, name, ssn FROM patients ORDER BY id", nil, nil)
+	if assert.Nil(t, err) && assert.Equal(t, 2, len(actual)) {
+		assert.Equal(t, "123-45-6789", actual[0].Ssn)
+		assert.Equal(t, "987-65-4321", actual[1].Ssn)
+	}
+	var patient = &Patient{}
+	success, err := manager.ReadSingle(patient, "SELECT id, name, ssn FROM patients WHERE id = ?", []interface{}{2}, nil)
+	if assert.Nil(t, err) && assert.True(t, success) {
+		assert.Equal(t, "987-65-4321", patient.Ssn)
+	}
+
+	//same column name in another table uses its own encryptor
+	for _, SQL := range []string{
+		"DROP TABLE IF EXISTS doctors",
+		"CREATE TABLE doctors(id INTEGER PRIMARY KEY, name VARCHAR(255), ssn TEXT)",
+	} {
+		_, err = manager.Execute(SQL)
+		assert.Nil(t, err)
+	}
+	doctorEncryptor, err := dsc.NewAESEncryptor("d1", map[string][]byte{"d1": []byte("abcdef0123456789")})
+	if !assert.Nil(t, err) {
+		return
+	}
+	dsc.RegisterEncryptor("doctor_pii", doctorEncryptor)
+	defer dsc.RegisterEncryptor("doctor_pii", nil)
+	var doctors = []*Doctor{{Id: 1, Name: "House", Ssn: "111-22-3333"}}
+	_, _, err = manager.PersistAll(&doctors, "doctors", nil)
+	assert.Nil(t, err)
+	var doctor = &Doctor{}
+	success, err = manager.ReadSingle(doctor, "SELECT id, name, ssn FROM doctors WHERE id = ?", []interface{}{1}, nil)
+	if assert.Nil(t, err) && assert.True(t, success) {
+		assert.Equal(t, "111-22-3333", doctor.Ssn)
+	}
+	var joined = make([]map[string]interface{}, 0)
+	err = manager.ReadAll(&joined, "SELECT p.ssn AS ssn FROM patients p JOIN doctors d ON d.id = p.id", nil, nil)
+	if assert.Nil(t, err) && assert.Equal(t, 1, len(joined)) {
+		assert.Equal(t, "123-45-6789", joined[0]["ssn"])
+	}
+}
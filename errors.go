@@ -0,0 +1,298 @@
+package dsc
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var (
+	// ErrNotFound represents missing row, table or datastore error kind
+	ErrNotFound = errors.New("not found")
+	// ErrDuplicateKey represents unique or primary key violation error kind
+	ErrDuplicateKey = errors.New("duplicate key")
+	// ErrConstraintViolation represents integrity constraint (unique, foreign key, not null, check) violation error kind
+	ErrConstraintViolation = errors.New("constraint violation")
+	// ErrConnection represents broken or unavailable connection error kind
+	ErrConnection = errors.New("connection error")
+	// ErrRetryable represents transient error kind (deadlock, serialization failure, lock timeout), repeating operation may succeed
+	ErrRetryable = errors.New("retryable error")
+	// ErrPoolExhausted represents connection pool error kind returned when no connection was released within acquire timeout
+	ErrPoolExhausted = errors.New("connection pool exhausted")
+)
+
+// Error represents datastore error classified with dialect specific error codes, errors.Is matches both its kinds and the wrapped driver error
+type Error struct {
+	// Kinds error kinds, i.e. ErrDuplicateKey, ErrConstraintViolation
+	Kinds []error
+	// Err wrapped error
+	Err error
+}
+
+// Error returns wrapped error message
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns wrapped error
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is returns true if target is one of the error kinds
+func (e *Error) Is(target error) bool {
+	for _, kind := range e.Kinds {
+		if kind == target {
+			return true
+		}
+	}
+	return false
+}
+
+// IsNotFound returns true if error represents missing row (sql.ErrNoRows), table or datastore
+func IsNotFound(err error) bool {
+	return err != nil && (errors.Is(err, ErrNotFound) || errors.Is(err, sql.ErrNoRows))
+}
+
+// IsDuplicateKey returns true if error represents unique or primary key violation
+func IsDuplicateKey(err error) bool {
+	return err != nil && errors.Is(err, ErrDuplicateKey)
+}
+
+// IsConstraintViolation returns true if error represents integrity constraint violation, including duplicate key
+func IsConstraintViolation(err error) bool {
+	return err != nil && errors.Is(err, ErrConstraintViolation)
+}
+
+// IsConnectionError returns true if error represents broken or unavailable connection
+func IsConnectionError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, ErrConnection) || errors.Is(err, driver.ErrBadConn) || errors.Is(err, sql.ErrConnDone) {
+		return true
+	}
+	driverErrorsMutex.RLock()
+	defer driverErrorsMutex.RUnlock()
+	for _, candidate := range connectionErrors {
+		if errors.Is(err, candidate) {
+			return true
+		}
+	}
+	var netError net.Error
+	return errors.As(err, &netError)
+}
+
+// IsRetryable returns true if error is transient (deadlock, serialization failure, lock timeout)
+func IsRetryable(err error) bool {
+	return err != nil && errors.Is(err, ErrRetryable)
+}
+
+// IsPoolExhausted returns true if error represents connection pool exhausted within acquire timeout
+func IsPoolExhausted(err error) bool {
+	return err != nil && errors.Is(err, ErrPoolExhausted)
+}
+
+// ErrorCodeExtractor returns driver specific error code, ok is false if error was not reported by the driver
+type ErrorCodeExtractor func(err error) (code string, ok bool)
+
+var errorCodeExtractors = make(map[string]ErrorCodeExtractor)
+var connectionErrors = make([]error, 0)
+var driverErrorsMutex = &sync.RWMutex{}
+
+// RegisterErrorCodeExtractor registers driver error code extractor used by dialect error classification, i.e. mysql error number
+func RegisterErrorCodeExtractor(driver string, extractor ErrorCodeExtractor) {
+	driverErrorsMutex.Lock()
+	defer driverErrorsMutex.Unlock()
+	errorCodeExtractors[driver] = extractor
+}
+
+// RegisterConnectionError registers driver sentinel error reported by IsConnectionError, i.e. mysql.ErrInvalidConn
+func RegisterConnectionError(err error) {
+	driverErrorsMutex.Lock()
+	defer driverErrorsMutex.Unlock()
+	connectionErrors = append(connectionErrors, err)
+}
+
+// driverErrorCode returns error code extracted with driver registered extractor
+func driverErrorCode(driver string, err error) (string, bool) {
+	driverErrorsMutex.RLock()
+	extractor, ok := errorCodeExtractors[driver]
+	driverErrorsMutex.RUnlock()
+	if !ok {
+		return "", false
+	}
+	return extractor(err)
+}
+
+type sqlStateError interface {
+	SQLState() string
+}
+
+// errorMessageContains returns true if error message contains any of passed in fragments (driver errors are often wrapped as text)
+func errorMessageContains(err error, fragments ...string) bool {
+	message := strings.ToLower(err.Error())
+	for _, fragment := range fragments {
+		if strings.Contains(message, strings.ToLower(fragment)) {
+			return true
+		}
+	}
+	return false
+}
+
+// errorClassifierDialect represents dialect mapping driver error codes into error kinds
+type errorClassifierDialect interface {
+	errorKinds(err error) []error
+}
+
+// classifyError wraps error with dialect error kinds, error is returned as is if no kind was matched or it is already classified
+func classifyError(dialect DatastoreDialect, err error) error {
+	if err == nil {
+		return nil
+	}
+	var classified *Error
+	if errors.As(err, &classified) {
+		return err
+	}
+	classifier, ok := dialect.(errorClassifierDialect)
+	if !ok {
+		return err
+	}
+	kinds := classifier.errorKinds(err)
+	if len(kinds) == 0 {
+		return err
+	}
+	return &Error{Kinds: kinds, Err: err}
+}
+
+// errorCodeKinds maps error code to error kinds, duplicate key is also a constraint violation
+func errorCodeKinds(code string, notFound, duplicate, constraint, connection, retryable []string) []error {
+	var result []error
+	matches := func(codes []string) bool {
+		for _, candidate := range codes {
+			if candidate == code || (strings.HasSuffix(candidate, "*") && strings.HasPrefix(code, strings.TrimSuffix(candidate, "*"))) {
+				return true
+			}
+		}
+		return false
+	}
+	if matches(notFound) {
+		result = append(result, ErrNotFound)
+	}
+	if matches(duplicate) {
+		result = append(result, ErrDuplicateKey, ErrConstraintViolation)
+	} else if matches(constraint) {
+		result = append(result, ErrConstraintViolation)
+	}
+	if matches(connection) {
+		result = append(result, ErrConnection)
+	}
+	if matches(retryable) {
+		result = append(result, ErrRetryable)
+	}
+	return result
+}
+
+// errorMessageKinds maps error message fragments to error kinds
+func errorMessageKinds(err error, notFound, duplicate, constraint, connection, retryable []string) []error {
+	var result []error
+	if errorMessageContains(err, notFound...) {
+		result = append(result, ErrNotFound)
+	}
+	if errorMessageContains(err, duplicate...) {
+		result = append(result, ErrDuplicateKey, ErrConstraintViolation)
+	} else if errorMessageContains(err, constraint...) {
+		result = append(result, ErrConstraintViolation)
+	}
+	if errorMessageContains(err, connection...) {
+		result = append(result, ErrConnection)
+	}
+	if errorMessageContains(err, retryable...) {
+		result = append(result, ErrRetryable)
+	}
+	return result
+}
+
+// errorKinds maps MySQL error numbers extracted with registered mysql error code extractor (see github.com/viant/dsc/mysql), or error message
+func (d mySQLDialect) errorKinds(err error) []error {
+	if code, ok := driverErrorCode("mysql", err); ok {
+		return errorCodeKinds(code,
+			[]string{"1049", "1146"},
+			[]string{"1062", "1586"},
+			[]string{"1048", "1216", "1217", "1451", "1452", "3819"},
+			[]string{"1040", "1053", "2002", "2003", "2006", "2013"},
+			[]string{"1205", "1213"})
+	}
+	return errorMessageKinds(err,
+		[]string{"Error 1049", "Error 1146"},
+		[]string{"Error 1062", "Duplicate entry"},
+		[]string{"Error 1048", "Error 1451", "Error 1452", "Error 3819"},
+		[]string{"Error 2006", "Error 2013", "bad connection"},
+		[]string{"Error 1205", "Error 1213", "Deadlock found"})
+}
+
+// errorKinds maps Postgres SQLSTATE codes
+func (d pgDialect) errorKinds(err error) []error {
+	var stateError sqlStateError
+	if errors.As(err, &stateError) {
+		return errorCodeKinds(stateError.SQLState(),
+			[]string{"42P01", "3D000"},
+			[]string{"23505"},
+			[]string{"23*"},
+			[]string{"08*", "57P01"},
+			[]string{"40001", "40P01", "55P03"})
+	}
+	return errorMessageKinds(err,
+		[]string{"SQLSTATE 42P01", "SQLSTATE 3D000"},
+		[]string{"SQLSTATE 23505", "duplicate key value"},
+		[]string{"SQLSTATE 23", "violates"},
+		[]string{"SQLSTATE 08", "connection refused"},
+		[]string{"SQLSTATE 40001", "SQLSTATE 40P01", "SQLSTATE 55P03", "could not serialize access", "deadlock detected"})
+}
+
+type sqlErrorNumber interface {
+	SQLErrorNumber() int32
+}
+
+// errorKinds maps SQL Server error numbers
+func (d msSQLDialect) errorKinds(err error) []error {
+	var numberError sqlErrorNumber
+	if errors.As(err, &numberError) {
+		return errorCodeKinds(strconv.Itoa(int(numberError.SQLErrorNumber())),
+			[]string{"208", "911"},
+			[]string{"2601", "2627"},
+			[]string{"515", "547"},
+			[]string{"233", "10054"},
+			[]string{"1205", "1222"})
+	}
+	return errorMessageKinds(err,
+		[]string{"Invalid object name"},
+		[]string{"Cannot insert duplicate key", "Violation of PRIMARY KEY", "Violation of UNIQUE KEY"},
+		[]string{"conflicted with the FOREIGN KEY", "conflicted with the CHECK", "Cannot insert the value NULL"},
+		[]string{"connection reset", "connection refused"},
+		[]string{"Error 1205", "deadlock victim", "Lock request time out"})
+}
+
+// errorKinds maps Oracle ORA codes
+func (d oraDialect) errorKinds(err error) []error {
+	return errorMessageKinds(err,
+		[]string{"ORA-00942", "ORA-01403"},
+		[]string{"ORA-00001"},
+		[]string{"ORA-01400", "ORA-02290", "ORA-02291", "ORA-02292"},
+		[]string{"ORA-03113", "ORA-03114", "ORA-03135", "ORA-12541", "ORA-12170"},
+		[]string{"ORA-00060", "ORA-08177", "ORA-00054"})
+}
+
+// errorKinds maps SQLite messages
+func (d sqlLiteDialect) errorKinds(err error) []error {
+	return errorMessageKinds(err,
+		[]string{"no such table"},
+		[]string{"UNIQUE constraint failed", "PRIMARY KEY must be unique"},
+		[]string{"constraint failed"},
+		[]string{"unable to open database"},
+		[]string{"database is locked", "SQLITE_BUSY"})
+}
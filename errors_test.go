@@ -0,0 +1,45 @@
+package dsc_test
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/dsc"
+)
+
+func TestErrorClassification(t *testing.T) {
+	manager := GetManager(t)
+
+	_, err := manager.Execute("INSERT INTO users(id, username) VALUES(1, 'Dup')")
+	assert.True(t, dsc.IsDuplicateKey(err), fmt.Sprintf("%v", err))
+	assert.True(t, dsc.IsConstraintViolation(err))
+	assert.False(t, dsc.IsNotFound(err))
+	var sqliteError sqlite3.Error
+	assert.True(t, errors.As(err, &sqliteError), "driver error should be preserved")
+
+	for _, SQL := range []string{"DROP TABLE IF EXISTS accounts", "CREATE TABLE accounts(id INTEGER PRIMARY KEY, name TEXT NOT NULL)"} {
+		_, err = manager.Execute(SQL)
+		assert.Nil(t, err)
+	}
+	_, err = manager.Execute("INSERT INTO accounts(id) VALUES(1)")
+	assert.True(t, dsc.IsConstraintViolation(err), fmt.Sprintf("%v", err))
+	assert.False(t, dsc.IsDuplicateKey(err))
+
+	var record = make([]interface{}, 0)
+	_, err = manager.ReadSingle(&record, "SELECT * FROM missing_table", nil, nil)
+	assert.True(t, dsc.IsNotFound(err), fmt.Sprintf("%v", err))
+
+	_, err = manager.Execute("SELEC 1")
+	assert.NotNil(t, err)
+	assert.False(t, dsc.IsNotFound(err) || dsc.IsDuplicateKey(err) || dsc.IsConstraintViolation(err) || dsc.IsConnectionError(err) || dsc.IsRetryable(err))
+
+	assert.True(t, dsc.IsNotFound(fmt.Errorf("failed to read due to %w", sql.ErrNoRows)))
+	assert.True(t, dsc.IsConnectionError(fmt.Errorf("failed to execute due to %w", driver.ErrBadConn)))
+	assert.True(t, dsc.IsRetryable(&dsc.Error{Kinds: []error{dsc.ErrRetryable}, Err: errors.New("deadlock")}))
+	assert.False(t, dsc.IsRetryable(nil))
+}
@@ -0,0 +1,40 @@
+package dsc
+
+import "fmt"
+
+// ExecuteOptions represents ExecuteAllWithOptions options
+type ExecuteOptions struct {
+	//Transactional wraps all statements in one transaction, failed statement rolls back all of them
+	Transactional bool
+	//BatchSize number of statements executed and committed in one transaction when not transactional, default batchSize config parameter
+	BatchSize int
+}
+
+// ExecuteAllResult represents ExecuteAllWithOptions result
+type ExecuteAllResult struct {
+	//RowsAffected rows affected by each executed statement, -1 if driver does not report it, statements after the failing one are not executed,
+	//on failure it also includes statements of the rolled back batch, only the first Committed entries were committed
+	RowsAffected []int64
+	//FailedIndex index of the first failing statement, -1 when all statements succeeded
+	FailedIndex int
+	//Committed number of statements that were committed
+	Committed int
+}
+
+// StatementError represents ExecuteAllWithOptions statement execution error
+type StatementError struct {
+	//Index statement index
+	Index int
+	SQL   string
+	Err   error
+}
+
+// Error returns error message with statement index
+func (e *StatementError) Error() string {
+	return fmt.Sprintf("failed to execute statement %v: %v due to %v", e.Index, e.SQL, e.Err)
+}
+
+// Unwrap returns underlying error
+func (e *StatementError) Unwrap() error {
+	return e.Err
+}
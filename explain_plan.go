@@ -0,0 +1,347 @@
+package dsc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/viant/toolbox"
+)
+
+// QueryPlan represents normalized query execution plan
+type QueryPlan struct {
+	//Steps plan steps tree, children are executed (or feed data) before their parent
+	Steps []*QueryPlanStep
+	//Cost estimated total cost in datastore specific units if reported
+	Cost float64
+	//Rows estimated number of processed rows if reported
+	Rows int64
+	//Bytes estimated number of processed bytes if reported (i.e. BigQuery dry run)
+	Bytes int64
+	//Raw datastore plan as returned by EXPLAIN (JSON or text)
+	Raw string
+}
+
+// QueryPlanStep represents normalized query plan step
+type QueryPlanStep struct {
+	//Operation datastore specific operation, i.e. Seq Scan, Index Scan, Nested Loop, SCAN, SEARCH
+	Operation string
+	//Table accessed table if any
+	Table string
+	//Index used index if any
+	Index string
+	//FullScan true if step reads whole table without index
+	FullScan bool
+	//Cost estimated step cost if reported
+	Cost float64
+	//Rows estimated step rows if reported
+	Rows int64
+	//Detail datastore specific step description
+	Detail   string           `json:",omitempty"`
+	Children []*QueryPlanStep `json:",omitempty"`
+}
+
+// Walk visits all plan steps in pre-order
+func (p *QueryPlan) Walk(visitor func(step *QueryPlanStep)) {
+	var walk func(steps []*QueryPlanStep)
+	walk = func(steps []*QueryPlanStep) {
+		for _, step := range steps {
+			visitor(step)
+			walk(step.Children)
+		}
+	}
+	walk(p.Steps)
+}
+
+// Indexes returns sorted distinct indexes used by the plan
+func (p *QueryPlan) Indexes() []string {
+	return p.distinct(func(step *QueryPlanStep) string {
+		return step.Index
+	})
+}
+
+// FullScans returns sorted distinct tables read with full table scan
+func (p *QueryPlan) FullScans() []string {
+	return p.distinct(func(step *QueryPlanStep) string {
+		if step.FullScan {
+			return step.Table
+		}
+		return ""
+	})
+}
+
+func (p *QueryPlan) distinct(value func(step *QueryPlanStep) string) []string {
+	var result = make([]string, 0)
+	var unique = make(map[string]bool)
+	p.Walk(func(step *QueryPlanStep) {
+		if item := value(step); item != "" && !unique[item] {
+			unique[item] = true
+			result = append(result, item)
+		}
+	})
+	sort.Strings(result)
+	return result
+}
+
+// readExplainRows passes each EXPLAIN output row values to handler
+func readExplainRows(manager Manager, SQL string, parameters []interface{}, handler func(values []interface{}) error) error {
+	return manager.ReadAllWithHandler(SQL, parameters, func(scanner Scanner) (bool, error) {
+		values, _, err := ScanRow(scanner)
+		if err != nil {
+			return false, err
+		}
+		return true, handler(values)
+	})
+}
+
+// explainValue returns EXPLAIN output value as text
+func explainValue(value interface{}) string {
+	if bytes, ok := value.([]byte); ok {
+		return string(bytes)
+	}
+	return toolbox.AsString(value)
+}
+
+// readExplainJSON returns EXPLAIN output single JSON document
+func readExplainJSON(manager Manager, SQL string, parameters []interface{}) (string, error) {
+	var result = ""
+	err := readExplainRows(manager, SQL, parameters, func(values []interface{}) error {
+		if len(values) > 0 {
+			result += explainValue(values[len(values)-1])
+		}
+		return nil
+	})
+	return result, err
+}
+
+func decodeExplainJSON(raw string, target interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader([]byte(raw)))
+	decoder.UseNumber()
+	if err := decoder.Decode(target); err != nil {
+		return fmt.Errorf("failed to decode query plan %v due to %v", raw, err)
+	}
+	return nil
+}
+
+func explainFloat(value interface{}) float64 {
+	if value == nil {
+		return 0
+	}
+	result, _ := toolbox.ToFloat(value)
+	return result
+}
+
+func explainInt(value interface{}) int64 {
+	if value == nil {
+		return 0
+	}
+	result, _ := toolbox.ToInt(value)
+	return int64(result)
+}
+
+// ExplainPlan returns an error, default dialect does not support query plans
+func (d DefaultDialect) ExplainPlan(manager Manager, SQL string, parameters []interface{}) (*QueryPlan, error) {
+	return nil, fmt.Errorf("failed to explain %v due to %v", SQL, errUnsupportedOperation)
+}
+
+// ExplainPlan returns an error, dialect does not know how to explain queries
+func (d sqlDatastoreDialect) ExplainPlan(manager Manager, SQL string, parameters []interface{}) (*QueryPlan, error) {
+	return nil, fmt.Errorf("failed to explain %v due to %v", SQL, errUnsupportedOperation)
+}
+
+// ExplainPlan returns plan built with EXPLAIN QUERY PLAN, sqlite reports neither costs nor row estimates
+func (d sqlLiteDialect) ExplainPlan(manager Manager, SQL string, parameters []interface{}) (*QueryPlan, error) {
+	var result = &QueryPlan{Steps: make([]*QueryPlanStep, 0)}
+	var steps = make(map[int]*QueryPlanStep)
+	var raw = make([]string, 0)
+	err := readExplainRows(manager, "EXPLAIN QUERY PLAN "+SQL, parameters, func(values []interface{}) error {
+		if len(values) < 4 {
+			return fmt.Errorf("unexpected EXPLAIN QUERY PLAN row: %v", values)
+		}
+		id, parent, detail := int(explainInt(values[0])), int(explainInt(values[1])), explainValue(values[len(values)-1])
+		raw = append(raw, detail)
+		step := newSQLiteQueryPlanStep(detail)
+		steps[id] = step
+		if parentStep, ok := steps[parent]; ok {
+			parentStep.Children = append(parentStep.Children, step)
+		} else {
+			result.Steps = append(result.Steps, step)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to explain %v due to %v", SQL, err)
+	}
+	result.Raw = strings.Join(raw, "\n")
+	return result, nil
+}
+
+// newSQLiteQueryPlanStep parses sqlite plan detail, i.e. SCAN users, SEARCH users USING INDEX users_name (username=?)
+func newSQLiteQueryPlanStep(detail string) *QueryPlanStep {
+	var result = &QueryPlanStep{Detail: detail}
+	fields := strings.Fields(detail)
+	if len(fields) == 0 {
+		return result
+	}
+	result.Operation = fields[0]
+	if result.Operation != "SCAN" && result.Operation != "SEARCH" {
+		return result
+	}
+	if len(fields) > 1 {
+		result.Table = fields[1]
+	}
+	if index := strings.Index(detail, " INDEX "); index != -1 {
+		if name := strings.Fields(detail[index+len(" INDEX "):]); len(name) > 0 && !strings.HasPrefix(name[0], "(") {
+			result.Index = name[0]
+		}
+	} else if strings.Contains(detail, " PRIMARY KEY") {
+		result.Index = "PRIMARY KEY"
+	}
+	result.FullScan = result.Operation == "SCAN" && result.Index == ""
+	return result
+}
+
+// ExplainPlan returns plan built with EXPLAIN FORMAT=JSON
+func (d mySQLDialect) ExplainPlan(manager Manager, SQL string, parameters []interface{}) (*QueryPlan, error) {
+	raw, err := readExplainJSON(manager, "EXPLAIN FORMAT=JSON "+SQL, parameters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to explain %v due to %v", SQL, err)
+	}
+	return newMySQLQueryPlan(raw)
+}
+
+// newMySQLQueryPlan normalizes mysql JSON plan, each accessed table is a step, nested loop tables are children of nested loop step
+func newMySQLQueryPlan(raw string) (*QueryPlan, error) {
+	var document = make(map[string]interface{})
+	if err := decodeExplainJSON(raw, &document); err != nil {
+		return nil, err
+	}
+	var result = &QueryPlan{Raw: raw}
+	block, _ := document["query_block"].(map[string]interface{})
+	if costInfo, ok := block["cost_info"].(map[string]interface{}); ok {
+		result.Cost = explainFloat(costInfo["query_cost"])
+	}
+	result.Steps = mySQLQueryPlanSteps(block)
+	for _, step := range result.Steps {
+		result.Rows += step.Rows
+	}
+	return result, nil
+}
+
+func mySQLQueryPlanSteps(node map[string]interface{}) []*QueryPlanStep {
+	var result = make([]*QueryPlanStep, 0)
+	var keys = toolbox.MapKeysToStringSlice(node)
+	sort.Strings(keys)
+	for _, key := range keys {
+		switch value := node[key].(type) {
+		case map[string]interface{}:
+			if key == "table" {
+				result = append(result, newMySQLQueryPlanStep(value))
+				continue
+			}
+			if key == "cost_info" {
+				continue
+			}
+			if children := mySQLQueryPlanSteps(value); len(children) > 0 {
+				result = append(result, &QueryPlanStep{Operation: key, Children: children})
+			}
+		case []interface{}:
+			var step = &QueryPlanStep{Operation: key}
+			for _, item := range value {
+				if child, ok := item.(map[string]interface{}); ok {
+					step.Children = append(step.Children, mySQLQueryPlanSteps(child)...)
+				}
+			}
+			if len(step.Children) > 0 {
+				for _, child := range step.Children {
+					step.Rows += child.Rows
+					step.Cost += child.Cost
+				}
+				result = append(result, step)
+			}
+		}
+	}
+	return result
+}
+
+func newMySQLQueryPlanStep(table map[string]interface{}) *QueryPlanStep {
+	var result = &QueryPlanStep{
+		Operation: toolbox.AsString(table["access_type"]),
+		Table:     toolbox.AsString(table["table_name"]),
+		Rows:      explainInt(table["rows_examined_per_scan"]),
+	}
+	if key, ok := table["key"]; ok {
+		result.Index = toolbox.AsString(key)
+	}
+	if costInfo, ok := table["cost_info"].(map[string]interface{}); ok {
+		result.Cost = explainFloat(costInfo["prefix_cost"])
+	}
+	if condition, ok := table["attached_condition"]; ok {
+		result.Detail = toolbox.AsString(condition)
+	}
+	result.FullScan = result.Operation == "ALL"
+	if children := mySQLQueryPlanSteps(table); len(children) > 0 {
+		result.Children = children
+	}
+	return result
+}
+
+// ExplainPlan returns plan built with EXPLAIN (FORMAT JSON), cost and rows are planner estimates, query is not executed
+func (d pgDialect) ExplainPlan(manager Manager, SQL string, parameters []interface{}) (*QueryPlan, error) {
+	raw, err := readExplainJSON(manager, "EXPLAIN (FORMAT JSON) "+SQL, parameters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to explain %v due to %v", SQL, err)
+	}
+	return newPgQueryPlan(raw)
+}
+
+// newPgQueryPlan normalizes postgres JSON plan
+func newPgQueryPlan(raw string) (*QueryPlan, error) {
+	var document = make([]map[string]interface{}, 0)
+	if err := decodeExplainJSON(raw, &document); err != nil {
+		return nil, err
+	}
+	var result = &QueryPlan{Raw: raw, Steps: make([]*QueryPlanStep, 0)}
+	for _, item := range document {
+		plan, ok := item["Plan"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		step := newPgQueryPlanStep(plan)
+		result.Cost += step.Cost
+		result.Rows += step.Rows
+		result.Steps = append(result.Steps, step)
+	}
+	return result, nil
+}
+
+func newPgQueryPlanStep(plan map[string]interface{}) *QueryPlanStep {
+	var result = &QueryPlanStep{
+		Operation: toolbox.AsString(plan["Node Type"]),
+		Cost:      explainFloat(plan["Total Cost"]),
+		Rows:      explainInt(plan["Plan Rows"]),
+	}
+	if table, ok := plan["Relation Name"]; ok {
+		result.Table = toolbox.AsString(table)
+	}
+	if index, ok := plan["Index Name"]; ok {
+		result.Index = toolbox.AsString(index)
+	}
+	for _, key := range []string{"Filter", "Index Cond", "Hash Cond", "Join Filter"} {
+		if condition, ok := plan[key]; ok {
+			result.Detail = toolbox.AsString(condition)
+			break
+		}
+	}
+	result.FullScan = result.Operation == "Seq Scan"
+	if children, ok := plan["Plans"].([]interface{}); ok {
+		for _, child := range children {
+			if childPlan, ok := child.(map[string]interface{}); ok {
+				result.Children = append(result.Children, newPgQueryPlanStep(childPlan))
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,79 @@
+package dsc
+
+import (
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSqlLiteDialect_ExplainPlan(t *testing.T) {
+	manager, err := NewManagerFactory().Create(NewConfig("sqlite3", "[url]", "url:./test/explain.db"))
+	if !assert.Nil(t, err) {
+		return
+	}
+	for _, SQL := range []string{
+		"DROP TABLE IF EXISTS explain_users",
+		"CREATE TABLE explain_users (id INTEGER PRIMARY KEY, username VARCHAR(255), city VARCHAR(255))",
+		"CREATE INDEX explain_users_username ON explain_users(username)",
+	} {
+		_, err = manager.Execute(SQL)
+		assert.Nil(t, err)
+	}
+	dialect := GetDatastoreDialect("sqlite3").(ExplainDialect)
+	plan, err := dialect.ExplainPlan(manager, "SELECT * FROM explain_users WHERE username = ?", []interface{}{"Bob"})
+	if assert.Nil(t, err) {
+		assert.EqualValues(t, []string{"explain_users_username"}, plan.Indexes())
+		assert.EqualValues(t, []string{}, plan.FullScans())
+		assert.Contains(t, plan.Raw, "explain_users_username")
+	}
+	plan, err = dialect.ExplainPlan(manager, "SELECT * FROM explain_users WHERE city = ?", []interface{}{"Warsaw"})
+	if assert.Nil(t, err) {
+		assert.EqualValues(t, []string{"explain_users"}, plan.FullScans())
+	}
+	_, err = GetDatastoreDialect("ndjson").(ExplainDialect).ExplainPlan(manager, "SELECT 1", nil)
+	assert.NotNil(t, err)
+}
+
+func TestNewMySQLQueryPlan(t *testing.T) {
+	plan, err := newMySQLQueryPlan(`{"query_block": {"select_id": 1, "cost_info": {"query_cost": "2.40"},
+		"nested_loop": [
+			{"table": {"table_name": "u", "access_type": "ALL", "rows_examined_per_scan": 10, "cost_info": {"prefix_cost": "1.25"}, "attached_condition": "(u.city = 'Warsaw')"}},
+			{"table": {"table_name": "o", "access_type": "ref", "key": "orders_user_id", "possible_keys": ["orders_user_id"], "rows_examined_per_scan": 2, "cost_info": {"prefix_cost": "2.40"}}}
+		]}}`)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.EqualValues(t, 2.40, plan.Cost)
+	assert.EqualValues(t, 12, plan.Rows)
+	if assert.Equal(t, 1, len(plan.Steps)) {
+		assert.Equal(t, "nested_loop", plan.Steps[0].Operation)
+		assert.Equal(t, 2, len(plan.Steps[0].Children))
+		assert.Equal(t, "(u.city = 'Warsaw')", plan.Steps[0].Children[0].Detail)
+	}
+	assert.EqualValues(t, []string{"orders_user_id"}, plan.Indexes())
+	assert.EqualValues(t, []string{"u"}, plan.FullScans())
+}
+
+func TestNewPgQueryPlan(t *testing.T) {
+	plan, err := newPgQueryPlan(`[{"Plan": {"Node Type": "Hash Join", "Total Cost": 35.5, "Plan Rows": 7, "Hash Cond": "(o.user_id = u.id)",
+		"Plans": [
+			{"Node Type": "Seq Scan", "Relation Name": "orders", "Total Cost": 22.7, "Plan Rows": 1270},
+			{"Node Type": "Hash", "Total Cost": 8.3, "Plan Rows": 1, "Plans": [
+				{"Node Type": "Index Scan", "Relation Name": "users", "Index Name": "users_pkey", "Total Cost": 8.3, "Plan Rows": 1, "Index Cond": "(id = 1)"}
+			]}
+		]}}]`)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.EqualValues(t, 35.5, plan.Cost)
+	assert.EqualValues(t, 7, plan.Rows)
+	if assert.Equal(t, 1, len(plan.Steps)) {
+		assert.Equal(t, "Hash Join", plan.Steps[0].Operation)
+		assert.Equal(t, "(o.user_id = u.id)", plan.Steps[0].Detail)
+	}
+	assert.EqualValues(t, []string{"users_pkey"}, plan.Indexes())
+	assert.EqualValues(t, []string{"orders"}, plan.FullScans())
+	_, err = newPgQueryPlan("{")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,472 @@
+package dsc
+
+import (
+	"bufio"
+	"database/sql"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/viant/toolbox"
+	"github.com/viant/toolbox/storage"
+)
+
+// Export formats
+const (
+	ExportFormatCSV     = "csv"
+	ExportFormatNDJSON  = "ndjson"
+	ExportFormatParquet = "parquet"
+	ExportFormatArrow   = "arrow"
+)
+
+// ErrStopExport can be returned by ExportWriter.Write once the row was written to stop reading remaining rows without error
+var ErrStopExport = errors.New("export stopped")
+
+// ExportColumn represents exported column
+type ExportColumn struct {
+	Name string
+	//Type upper case database type name, i.e. VARCHAR, DECIMAL, empty if driver does not report it
+	Type string
+	//ScanType go type reported by driver, nil if driver does not report it
+	ScanType reflect.Type
+	//Binary flags binary column (BLOB, BINARY, BYTEA, RAW), its values are passed as []byte
+	Binary bool
+}
+
+var nullTypes = map[reflect.Type]reflect.Type{
+	reflect.TypeOf(sql.NullString{}):  reflect.TypeOf(""),
+	reflect.TypeOf(sql.NullInt64{}):   reflect.TypeOf(int64(0)),
+	reflect.TypeOf(sql.NullInt32{}):   reflect.TypeOf(int32(0)),
+	reflect.TypeOf(sql.NullInt16{}):   reflect.TypeOf(int16(0)),
+	reflect.TypeOf(sql.NullFloat64{}): reflect.TypeOf(float64(0)),
+	reflect.TypeOf(sql.NullBool{}):    reflect.TypeOf(false),
+	reflect.TypeOf(sql.NullTime{}):    reflect.TypeOf(time.Time{}),
+}
+
+// ValueType returns column value type, pointers and sql.Null* scan types are unwrapped, nil if driver does not report scan type
+func (c *ExportColumn) ValueType() reflect.Type {
+	if c.ScanType == nil {
+		return nil
+	}
+	result := c.ScanType
+	for result.Kind() == reflect.Ptr {
+		result = result.Elem()
+	}
+	if valueType, ok := nullTypes[result]; ok {
+		return valueType
+	}
+	return result
+}
+
+// ExportWriter represents streaming export format writer
+type ExportWriter interface {
+	//Write writes formatted row values
+	Write(values []interface{}) error
+	//Close flushes buffered rows and writes format footer if needed, it does not close underlying writer
+	Close() error
+}
+
+// ExportWriterProvider creates export writer for columns
+type ExportWriterProvider func(writer io.Writer, columns []*ExportColumn, options *ExportOptions) (ExportWriter, error)
+
+// ExportFormatting represents export value formatting
+type ExportFormatting struct {
+	//TimeLayout time layout used by text formats, default time.RFC3339Nano
+	TimeLayout string
+	//NullValue CSV null representation, default empty string
+	NullValue string
+	//TypeFormatters value formatters keyed by upper case database type name, i.e. DECIMAL
+	TypeFormatters map[string]func(value interface{}) interface{}
+}
+
+// ExportOptions represents export options
+type ExportOptions struct {
+	//Format export format, for URL export inferred from extension if empty: csv, ndjson (.jsonl, .json) or parquet
+	Format string
+	//Delimiter CSV delimiter, default ','
+	Delimiter rune
+	//NoHeader skips CSV header
+	NoHeader bool
+	//Formatting overrides dialect export formatting, writer providers receive it merged with dialect defaults
+	Formatting *ExportFormatting
+	//Provider optional writer provider used instead of registered Format provider
+	Provider ExportWriterProvider
+}
+
+var exportWriterProviders = make(map[string]ExportWriterProvider)
+var exportFormattings = make(map[string]*ExportFormatting)
+var exportMutex = &sync.RWMutex{}
+
+// RegisterExportFormat registers export format writer provider, nil provider removes format
+func RegisterExportFormat(format string, provider ExportWriterProvider) {
+	exportMutex.Lock()
+	defer exportMutex.Unlock()
+	if provider == nil {
+		delete(exportWriterProviders, format)
+		return
+	}
+	exportWriterProviders[format] = provider
+}
+
+// GetExportFormat returns export format writer provider or nil
+func GetExportFormat(format string) ExportWriterProvider {
+	exportMutex.RLock()
+	defer exportMutex.RUnlock()
+	return exportWriterProviders[format]
+}
+
+// RegisterExportFormatting registers driver default export formatting, nil formatting removes it
+func RegisterExportFormatting(driver string, formatting *ExportFormatting) {
+	exportMutex.Lock()
+	defer exportMutex.Unlock()
+	if formatting == nil {
+		delete(exportFormattings, driver)
+		return
+	}
+	exportFormattings[driver] = formatting
+}
+
+// GetExportFormatting returns driver export formatting or nil
+func GetExportFormatting(driver string) *ExportFormatting {
+	exportMutex.RLock()
+	defer exportMutex.RUnlock()
+	return exportFormattings[driver]
+}
+
+// asJSONNumber formats decimal text returned by driver as number
+func asJSONNumber(value interface{}) interface{} {
+	switch actual := value.(type) {
+	case []byte:
+		return json.Number(actual)
+	case string:
+		return json.Number(actual)
+	}
+	return value
+}
+
+// exportFormatter represents export formatter resolved for query columns
+type exportFormatter struct {
+	formatting *ExportFormatting
+	columns    []*ExportColumn
+	formatters []func(value interface{}) interface{}
+	binary     []bool
+}
+
+func newExportFormatter(formatting *ExportFormatting, columns []*ExportColumn) *exportFormatter {
+	var result = &exportFormatter{
+		formatting: formatting,
+		columns:    columns,
+		formatters: make([]func(value interface{}) interface{}, len(columns)),
+		binary:     make([]bool, len(columns)),
+	}
+	for i, column := range columns {
+		if formatting.TypeFormatters != nil {
+			result.formatters[i] = formatting.TypeFormatters[column.Type]
+		}
+		result.binary[i] = column.Binary
+	}
+	return result
+}
+
+// format formats driver values in place, text returned as []byte is converted to string, binary values are kept as []byte
+func (f *exportFormatter) format(values []interface{}) {
+	for i, value := range values {
+		if value == nil {
+			continue
+		}
+		if f.formatters[i] != nil {
+			values[i] = f.formatters[i](value)
+			continue
+		}
+		if bytes, ok := value.([]byte); ok && !f.binary[i] {
+			values[i] = string(bytes)
+		}
+	}
+}
+
+// text returns value text representation for text formats
+func (f *exportFormatter) text(value interface{}) string {
+	switch actual := value.(type) {
+	case nil:
+		return f.formatting.NullValue
+	case string:
+		return actual
+	case []byte:
+		return base64.StdEncoding.EncodeToString(actual)
+	case time.Time:
+		return actual.Format(f.formatting.TimeLayout)
+	case *time.Time:
+		return actual.Format(f.formatting.TimeLayout)
+	}
+	return toolbox.AsString(value)
+}
+
+type csvExportWriter struct {
+	*exportFormatter
+	writer *csv.Writer
+	record []string
+}
+
+func (w *csvExportWriter) Write(values []interface{}) error {
+	for i, value := range values {
+		w.record[i] = w.text(value)
+	}
+	return w.writer.Write(w.record)
+}
+
+func (w *csvExportWriter) Close() error {
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+func newCSVExportWriter(writer io.Writer, columns []*ExportColumn, options *ExportOptions) (ExportWriter, error) {
+	var result = &csvExportWriter{exportFormatter: newExportFormatter(options.Formatting, columns), writer: csv.NewWriter(writer), record: make([]string, len(columns))}
+	if options.Delimiter != 0 {
+		result.writer.Comma = options.Delimiter
+	}
+	if !options.NoHeader {
+		for i, column := range columns {
+			result.record[i] = column.Name
+		}
+		if err := result.writer.Write(result.record); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+type ndjsonExportWriter struct {
+	*exportFormatter
+	writer *bufio.Writer
+	keys   [][]byte
+}
+
+func (w *ndjsonExportWriter) Write(values []interface{}) error {
+	_ = w.writer.WriteByte('{')
+	for i, value := range values {
+		if i > 0 {
+			_ = w.writer.WriteByte(',')
+		}
+		_, _ = w.writer.Write(w.keys[i])
+		switch actual := value.(type) {
+		case time.Time:
+			value = actual.Format(w.formatting.TimeLayout)
+		case *time.Time:
+			value = actual.Format(w.formatting.TimeLayout)
+		}
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("failed to encode %v due to %v", w.columns[i].Name, err)
+		}
+		_, _ = w.writer.Write(encoded)
+	}
+	_, err := w.writer.WriteString("}\n")
+	return err
+}
+
+func (w *ndjsonExportWriter) Close() error {
+	return w.writer.Flush()
+}
+
+func newNDJSONExportWriter(writer io.Writer, columns []*ExportColumn, options *ExportOptions) (ExportWriter, error) {
+	var result = &ndjsonExportWriter{exportFormatter: newExportFormatter(options.Formatting, columns), writer: bufio.NewWriter(writer), keys: make([][]byte, len(columns))}
+	for i, column := range columns {
+		key, err := json.Marshal(column.Name)
+		if err != nil {
+			return nil, err
+		}
+		result.keys[i] = append(key, ':')
+	}
+	return result, nil
+}
+
+// exportFormatting returns options formatting merged with driver defaults
+func exportFormatting(driver string, options *ExportOptions) *ExportFormatting {
+	var result = &ExportFormatting{TimeLayout: time.RFC3339Nano, TypeFormatters: make(map[string]func(value interface{}) interface{})}
+	var formattings = []*ExportFormatting{GetExportFormatting(driver)}
+	if options != nil {
+		formattings = append(formattings, options.Formatting)
+	}
+	for _, formatting := range formattings {
+		if formatting == nil {
+			continue
+		}
+		if formatting.TimeLayout != "" {
+			result.TimeLayout = formatting.TimeLayout
+		}
+		if formatting.NullValue != "" {
+			result.NullValue = formatting.NullValue
+		}
+		for k, v := range formatting.TypeFormatters {
+			result.TypeFormatters[strings.ToUpper(k)] = v
+		}
+	}
+	return result
+}
+
+// NewExportColumns returns scanner export columns
+func NewExportColumns(scanner Scanner) ([]*ExportColumn, error) {
+	names, err := scanner.Columns()
+	if err != nil {
+		return nil, err
+	}
+	var result = make([]*ExportColumn, len(names))
+	for i, name := range names {
+		result[i] = &ExportColumn{Name: name}
+	}
+	if types, err := scanner.ColumnTypes(); err == nil && len(types) == len(names) {
+		for i, columnType := range types {
+			if columnType == nil {
+				continue
+			}
+			result[i].Type = strings.ToUpper(columnType.DatabaseTypeName())
+			result[i].Binary = strings.Contains(result[i].Type, "BLOB") || strings.Contains(result[i].Type, "BINARY") || result[i].Type == "BYTEA" || result[i].Type == "RAW"
+			if scanType := columnType.ScanType(); scanType != nil && scanType.Kind() != reflect.Interface {
+				result[i].ScanType = scanType
+			}
+		}
+	}
+	return result, nil
+}
+
+// Export streams query rows into writer with export format, it returns number of exported rows
+func (m *AbstractManager) Export(writer io.Writer, query string, parameters []interface{}, options *ExportOptions) (int, error) {
+	var exportOptions = ExportOptions{}
+	if options != nil {
+		exportOptions = *options
+	}
+	format := exportOptions.Format
+	if format == "" {
+		format = ExportFormatCSV
+	}
+	provider := exportOptions.Provider
+	if provider == nil {
+		provider = GetExportFormat(format)
+	}
+	if provider == nil {
+		return 0, fmt.Errorf("unsupported export format: %v", format)
+	}
+	exportOptions.Formatting = exportFormatting(m.Manager.Config().DriverName, options)
+	var exportWriter ExportWriter
+	var formatter *exportFormatter
+	var values, pointers []interface{}
+	count := 0
+	err := m.Manager.ReadAllWithHandler(query, parameters, func(scanner Scanner) (bool, error) {
+		if exportWriter == nil {
+			columns, err := NewExportColumns(scanner)
+			if err != nil {
+				return false, err
+			}
+			if exportWriter, err = provider(writer, columns, &exportOptions); err != nil {
+				return false, err
+			}
+			formatter = newExportFormatter(exportOptions.Formatting, columns)
+			values = make([]interface{}, len(columns))
+			pointers = make([]interface{}, len(columns))
+			for i := range values {
+				pointers[i] = &values[i]
+			}
+		}
+		if _, ok := scanner.(ColumnValueProvider); ok {
+			rowValues, _, err := ScanRow(scanner)
+			if err != nil {
+				return false, err
+			}
+			copy(values, rowValues)
+		} else {
+			for i := range values {
+				values[i] = nil
+			}
+			if err := scanner.Scan(pointers...); err != nil {
+				return false, fmt.Errorf("failed to scan row due to %v", err)
+			}
+		}
+		formatter.format(values)
+		if err := exportWriter.Write(values); err != nil {
+			if errors.Is(err, ErrStopExport) {
+				count++
+				return false, nil
+			}
+			return false, fmt.Errorf("failed to write %v row due to %w", format, err)
+		}
+		count++
+		return true, nil
+	})
+	if exportWriter != nil {
+		if closeErr := exportWriter.Close(); err == nil && closeErr != nil {
+			err = fmt.Errorf("failed to close %v writer due to %v", format, closeErr)
+		}
+	}
+	return count, err
+}
+
+// exportFormatForURL returns export format for URL extension
+func exportFormatForURL(URL string) string {
+	switch strings.ToLower(path.Ext(URL)) {
+	case ".ndjson", ".jsonl", ".json":
+		return ExportFormatNDJSON
+	case ".parquet":
+		return ExportFormatParquet
+	case ".arrow", ".arrows":
+		return ExportFormatArrow
+	}
+	return ExportFormatCSV
+}
+
+// ExportToURL streams query rows into storage URL (i.e. file:///tmp/users.csv) with export format, it returns number of exported rows
+func (m *AbstractManager) ExportToURL(URL string, query string, parameters []interface{}, options *ExportOptions) (int, error) {
+	var exportOptions = ExportOptions{}
+	if options != nil {
+		exportOptions = *options
+	}
+	if exportOptions.Format == "" {
+		exportOptions.Format = exportFormatForURL(URL)
+	}
+	service, err := storage.NewServiceForURL(URL, m.Manager.Config().Credentials)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get storage service for %v due to %v", URL, err)
+	}
+	reader, writer := io.Pipe()
+	var uploadErr error
+	done := make(chan bool)
+	go func() {
+		defer close(done)
+		uploadErr = service.Upload(URL, reader)
+		_ = reader.CloseWithError(uploadErr)
+	}()
+	count, err := m.Export(writer, query, parameters, &exportOptions)
+	_ = writer.CloseWithError(err)
+	<-done
+	if err != nil {
+		return count, err
+	}
+	if uploadErr != nil {
+		return count, fmt.Errorf("failed to upload %v due to %v", URL, uploadErr)
+	}
+	return count, nil
+}
+
+func init() {
+	RegisterExportFormat(ExportFormatCSV, newCSVExportWriter)
+	RegisterExportFormat(ExportFormatNDJSON, newNDJSONExportWriter)
+	decimal := &ExportFormatting{TypeFormatters: map[string]func(value interface{}) interface{}{
+		"DECIMAL": asJSONNumber,
+		"NUMERIC": asJSONNumber,
+	}}
+	for _, driver := range []string{"mysql", "pg", "postgres"} {
+		RegisterExportFormatting(driver, decimal)
+	}
+	RegisterExportFormatting("sqlserver", &ExportFormatting{TypeFormatters: map[string]func(value interface{}) interface{}{
+		"DECIMAL": asJSONNumber,
+		"NUMERIC": asJSONNumber,
+		"MONEY":   asJSONNumber,
+	}})
+}
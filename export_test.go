@@ -0,0 +1,52 @@
+package dsc_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path"
+	"strings"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/dsc"
+)
+
+func TestManager_Export(t *testing.T) {
+	manager, err := dsc.NewManagerFactory().Create(dsc.NewConfig("sqlite3", "[url]", "url:./test/export.db"))
+	if !assert.Nil(t, err) {
+		return
+	}
+	for _, SQL := range []string{"DROP TABLE IF EXISTS accounts", "CREATE TABLE accounts(id INTEGER PRIMARY KEY, name TEXT, balance REAL, avatar BLOB)",
+		"INSERT INTO accounts(id, name, balance, avatar) VALUES(1, 'Bob, Jr', 10.5, X'0102')", "INSERT INTO accounts(id, name) VALUES(2, 'Ann')"} {
+		_, err = manager.Execute(SQL)
+		assert.Nil(t, err)
+	}
+	const query = "SELECT id, name, balance, avatar FROM accounts ORDER BY id"
+
+	buffer := new(bytes.Buffer)
+	count, err := manager.(dsc.Exporter).Export(buffer, query, nil, &dsc.ExportOptions{Formatting: &dsc.ExportFormatting{NullValue: "NULL"}})
+	if assert.Nil(t, err) {
+		assert.Equal(t, 2, count)
+		assert.Equal(t, "id,name,balance,avatar\n1,\"Bob, Jr\",10.5,AQI=\n2,Ann,NULL,NULL\n", buffer.String())
+	}
+
+	buffer.Reset()
+	count, err = manager.(dsc.Exporter).Export(buffer, query, nil, &dsc.ExportOptions{Format: dsc.ExportFormatNDJSON})
+	if assert.Nil(t, err) {
+		assert.Equal(t, 2, count)
+		assert.Equal(t, `{"id":1,"name":"Bob, Jr","balance":10.5,"avatar":"AQI="}`+"\n"+`{"id":2,"name":"Ann","balance":null,"avatar":null}`+"\n", buffer.String())
+	}
+
+	filename := path.Join(t.TempDir(), "accounts.jsonl")
+	count, err = manager.(dsc.Exporter).ExportToURL("file://"+filename, "SELECT id FROM accounts WHERE id > ?", []interface{}{1}, nil)
+	if assert.Nil(t, err) {
+		assert.Equal(t, 1, count)
+		content, err := ioutil.ReadFile(filename)
+		assert.Nil(t, err)
+		assert.Equal(t, `{"id":2}`, strings.TrimSpace(string(content)))
+	}
+
+	_, err = manager.(dsc.Exporter).Export(buffer, query, nil, &dsc.ExportOptions{Format: "xml"})
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,418 @@
+// Package fake provides in-memory "fake" datastore manager, and go-sqlmock backed manager for unit tests not requiring a database:
+//
+//	import _ "github.com/viant/dsc/fake"
+//
+//	manager, err := dsc.NewManagerFactory().Create(dsc.NewConfig("fake", "", "name:app"))
+//	manager, mock, err := fake.NewSQLMock()
+package fake
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/viant/dsc"
+	"github.com/viant/toolbox"
+)
+
+// DriverName fake datastore driver name
+const DriverName = "fake"
+
+const (
+	storeNameKey     = "name"
+	defaultStoreName = "default"
+)
+
+var createTableExpr = regexp.MustCompile(`(?is)^\s*CREATE\s+TABLE\s+(IF\s+NOT\s+EXISTS\s+)?([^\s(]+)\s*\((.*)\)\s*;?\s*$`)
+var dropTableExpr = regexp.MustCompile(`(?is)^\s*DROP\s+TABLE\s+(IF\s+EXISTS\s+)?([^\s;]+)\s*;?\s*$`)
+var truncateTableExpr = regexp.MustCompile(`(?is)^\s*TRUNCATE\s+(TABLE\s+)?([^\s;]+)\s*;?\s*$`)
+
+func storeName(config *dsc.Config) string {
+	if name := config.Get(storeNameKey); name != "" {
+		return name
+	}
+	return defaultStoreName
+}
+
+// withoutOptions removes query options (i.e. dsc.WithQueryTimeout) from parameters
+func withoutOptions(parameters []interface{}) []interface{} {
+	var result = make([]interface{}, 0, len(parameters))
+	for _, parameter := range parameters {
+		if _, ok := parameter.(dsc.QueryOption); ok {
+			continue
+		}
+		result = append(result, parameter)
+	}
+	return result
+}
+
+// lookup returns case insensitive record value
+func lookup(record map[string]interface{}, column string) interface{} {
+	if value, ok := record[column]; ok {
+		return value
+	}
+	for key, value := range record {
+		if strings.EqualFold(key, column) {
+			return value
+		}
+	}
+	return nil
+}
+
+type connection struct {
+	*dsc.AbstractConnection
+	store    *Store
+	snapshot map[string]*table
+}
+
+func (c *connection) Close() error {
+	return nil
+}
+
+func (c *connection) CloseNow() error {
+	return nil
+}
+
+// Begin takes store snapshot restored on rollback, concurrent changes made by other connections are discarded on rollback
+func (c *connection) Begin() error {
+	c.snapshot = c.store.snapshot()
+	return nil
+}
+
+func (c *connection) Commit() error {
+	if c.snapshot == nil {
+		return errors.New("no active transaction")
+	}
+	c.snapshot = nil
+	return nil
+}
+
+func (c *connection) Rollback() error {
+	if c.snapshot == nil {
+		return errors.New("no active transaction")
+	}
+	c.store.restore(c.snapshot)
+	c.snapshot = nil
+	return nil
+}
+
+func (c *connection) Unwrap(target interface{}) interface{} {
+	return errors.New("unsupported")
+}
+
+type connectionProvider struct {
+	*dsc.AbstractConnectionProvider
+}
+
+func (p *connectionProvider) NewConnection() (dsc.Connection, error) {
+	config := p.Config()
+	var result = &connection{store: GetStore(storeName(config))}
+	result.AbstractConnection = dsc.NewAbstractConnection(config, p.ConnectionProvider.ConnectionPool(), result)
+	return result, nil
+}
+
+func newConnectionProvider(config *dsc.Config) dsc.ConnectionProvider {
+	if config.MaxPoolSize == 0 {
+		config.MaxPoolSize = 1
+	}
+	provider := &connectionProvider{}
+	provider.AbstractConnectionProvider = dsc.NewAbstractConnectionProvider(config, make(chan dsc.Connection, config.MaxPoolSize), provider)
+	return provider
+}
+
+// Manager represents in-memory datastore manager, it supports CREATE/DROP/TRUNCATE TABLE, INSERT, UPDATE, DELETE
+// and single table SELECT with WHERE criteria supported by dsc SQL parser, tables are kept in store named with config name parameter
+type Manager struct {
+	*dsc.AbstractManager
+	store *Store
+}
+
+// Store returns manager in-memory store
+func (m *Manager) Store() *Store {
+	return m.store
+}
+
+// executeDDL executes table DDL, it returns false if statement is not DDL
+func (m *Manager) executeDDL(SQL string) (sql.Result, bool, error) {
+	m.store.mutex.Lock()
+	defer m.store.mutex.Unlock()
+	if matched := createTableExpr.FindStringSubmatch(SQL); len(matched) > 0 {
+		if m.store.table(matched[2]) != nil {
+			if matched[1] != "" {
+				return dsc.NewSQLResult(0, 0), true, nil
+			}
+			return nil, true, fmt.Errorf("table %v already exists", matched[2])
+		}
+		m.store.tables[strings.ToLower(matched[2])] = newTable(matched[2], matched[3])
+		return dsc.NewSQLResult(0, 0), true, nil
+	}
+	if matched := dropTableExpr.FindStringSubmatch(SQL); len(matched) > 0 {
+		if m.store.table(matched[2]) == nil && matched[1] == "" {
+			return nil, true, fmt.Errorf("table %v does not exist", matched[2])
+		}
+		delete(m.store.tables, strings.ToLower(matched[2]))
+		return dsc.NewSQLResult(0, 0), true, nil
+	}
+	if matched := truncateTableExpr.FindStringSubmatch(SQL); len(matched) > 0 {
+		storeTable := m.store.table(matched[2])
+		if storeTable == nil {
+			return nil, true, fmt.Errorf("table %v does not exist", matched[2])
+		}
+		storeTable.rows = nil
+		return dsc.NewSQLResult(0, 0), true, nil
+	}
+	return nil, false, nil
+}
+
+// criteriaColumns returns criteria left operand names
+func criteriaColumns(criteria *dsc.SQLCriteria) []string {
+	var result = make([]string, 0)
+	if criteria == nil {
+		return result
+	}
+	for _, criterion := range criteria.Criteria {
+		if criterion.Criteria != nil {
+			result = append(result, criteriaColumns(criterion.Criteria)...)
+			continue
+		}
+		if name, ok := criterion.LeftOperand.(string); ok {
+			result = append(result, name)
+		}
+	}
+	return result
+}
+
+// rowPredicate represents statement criteria predicate matching criteria columns case insensitively
+type rowPredicate struct {
+	predicate toolbox.Predicate
+	columns   []string
+}
+
+func (p *rowPredicate) Apply(value interface{}) bool {
+	row, ok := value.(map[string]interface{})
+	if !ok {
+		return p.predicate.Apply(value)
+	}
+	var view map[string]interface{}
+	for _, column := range p.columns {
+		if _, ok := row[column]; ok {
+			continue
+		}
+		if view == nil {
+			view = copyRecord(row)
+		}
+		view[column] = lookup(row, column)
+	}
+	if view == nil {
+		view = row
+	}
+	return p.predicate.Apply(view)
+}
+
+func (m *Manager) predicate(statement *dsc.BaseStatement, parameters toolbox.Iterator) (toolbox.Predicate, error) {
+	if statement.SQLCriteria == nil || len(statement.Criteria) == 0 {
+		return nil, nil
+	}
+	predicate, err := dsc.NewSQLCriteriaPredicate(parameters, statement.SQLCriteria)
+	if err != nil {
+		return nil, err
+	}
+	return &rowPredicate{predicate: predicate, columns: criteriaColumns(statement.SQLCriteria)}, nil
+}
+
+// ExecuteOnConnection executes DDL or DML statement on in-memory store
+func (m *Manager) ExecuteOnConnection(connection dsc.Connection, SQL string, sqlParameters []interface{}) (sql.Result, error) {
+	if result, ok, err := m.executeDDL(SQL); ok {
+		return result, err
+	}
+	statement, err := dsc.NewDmlParser().Parse(SQL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse sql: %v, %v", SQL, err)
+	}
+	parameters := toolbox.NewSliceIterator(withoutOptions(sqlParameters))
+	var record map[string]interface{}
+	if statement.Type != "DELETE" {
+		if record, err = statement.ColumnValueMap(parameters); err != nil {
+			return nil, fmt.Errorf("failed to execute %v due to %v", SQL, err)
+		}
+	}
+	predicate, err := m.predicate(statement.BaseStatement, parameters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute %v due to %v", SQL, err)
+	}
+	m.store.mutex.Lock()
+	defer m.store.mutex.Unlock()
+	if statement.Type == "INSERT" {
+		lastInsertID, err := m.store.getOrCreateTable(statement.Table).insert(record)
+		if err != nil {
+			return nil, err
+		}
+		return dsc.NewSQLResult(1, lastInsertID), nil
+	}
+	storeTable := m.store.table(statement.Table)
+	if storeTable == nil {
+		return nil, fmt.Errorf("failed to execute %v: table %v does not exist", SQL, statement.Table)
+	}
+	var count int64
+	var rows = make([]map[string]interface{}, 0, len(storeTable.rows))
+	for _, row := range storeTable.rows {
+		if predicate != nil && !predicate.Apply(row) {
+			rows = append(rows, row)
+			continue
+		}
+		count++
+		if statement.Type == "UPDATE" {
+			record = storeTable.canonical(record)
+			for key, value := range record {
+				row[key] = value
+			}
+			storeTable.addColumns(record)
+			rows = append(rows, row)
+		}
+	}
+	storeTable.rows = rows
+	return dsc.NewSQLResult(count, 0), nil
+}
+
+// ReadAllOnWithHandlerOnConnection reads matched rows of in-memory table
+func (m *Manager) ReadAllOnWithHandlerOnConnection(connection dsc.Connection, query string, sqlParameters []interface{}, readingHandler func(scanner dsc.Scanner) (toContinue bool, err error)) error {
+	statement, err := dsc.NewQueryParser().Parse(query)
+	if err != nil {
+		return fmt.Errorf("failed to parse statement %v, %v", query, err)
+	}
+	predicate, err := m.predicate(statement.BaseStatement, toolbox.NewSliceIterator(withoutOptions(sqlParameters)))
+	if err != nil {
+		return fmt.Errorf("failed to read data from %v due to %v", query, err)
+	}
+	var columns, aliases []string
+	var rows []map[string]interface{}
+	m.store.mutex.RLock()
+	storeTable := m.store.table(statement.Table)
+	if storeTable != nil {
+		if statement.AllField || len(statement.Columns) == 0 {
+			columns = append([]string{}, storeTable.columns...)
+			aliases = columns
+		} else {
+			for _, column := range statement.Columns {
+				columns = append(columns, column.Name)
+				alias := column.Alias
+				if alias == "" {
+					alias = column.Name
+				}
+				aliases = append(aliases, alias)
+			}
+		}
+		for _, row := range storeTable.rows {
+			if predicate == nil || predicate.Apply(row) {
+				rows = append(rows, copyRecord(row))
+			}
+		}
+	}
+	m.store.mutex.RUnlock()
+	if storeTable == nil {
+		return fmt.Errorf("failed to read data from %v: table %v does not exist", query, statement.Table)
+	}
+	for _, row := range rows {
+		var values = make(map[string]interface{}, len(columns))
+		for i, column := range columns {
+			values[aliases[i]] = lookup(row, column)
+		}
+		scanner := dsc.NewFileScanner(m.Config(), aliases, nil)
+		scanner.Values = values
+		toContinue, err := readingHandler(scanner)
+		if err != nil {
+			return fmt.Errorf("failed to read data on statement %v, due to\n\t%v", query, err)
+		}
+		if !toContinue {
+			return nil
+		}
+	}
+	return nil
+}
+
+type managerFactory struct{}
+
+func (f *managerFactory) Create(config *dsc.Config) (dsc.Manager, error) {
+	var result = &Manager{store: GetStore(storeName(config))}
+	result.AbstractManager = dsc.NewAbstractManager(config, newConnectionProvider(config), result)
+	return result, nil
+}
+
+func (f *managerFactory) CreateFromURL(URL string) (dsc.Manager, error) {
+	config, err := dsc.NewConfigFromURL(URL)
+	if err != nil {
+		return nil, err
+	}
+	return f.Create(config)
+}
+
+// dialect represents fake datastore dialect, datastore is the store name
+type dialect struct {
+	dsc.DefaultDialect
+}
+
+func (d dialect) store(manager dsc.Manager) *Store {
+	return GetStore(storeName(manager.Config()))
+}
+
+func (d dialect) GetDatastores(manager dsc.Manager) ([]string, error) {
+	return []string{d.store(manager).Name()}, nil
+}
+
+func (d dialect) GetCurrentDatastore(manager dsc.Manager) (string, error) {
+	return d.store(manager).Name(), nil
+}
+
+func (d dialect) GetTables(manager dsc.Manager, datastore string) ([]string, error) {
+	return d.store(manager).Tables(), nil
+}
+
+func (d dialect) DropTable(manager dsc.Manager, datastore string, table string) error {
+	_, err := manager.Execute(fmt.Sprintf("DROP TABLE IF EXISTS %v", table))
+	return err
+}
+
+func (d dialect) CreateTable(manager dsc.Manager, datastore string, table string, specification interface{}) error {
+	_, err := manager.Execute(fmt.Sprintf("CREATE TABLE %v(%v)", table, specification))
+	return err
+}
+
+func (d dialect) GetColumns(manager dsc.Manager, datastore, table string) ([]dsc.Column, error) {
+	store := d.store(manager)
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+	var result = make([]dsc.Column, 0)
+	if storeTable := store.table(table); storeTable != nil {
+		for _, column := range storeTable.columns {
+			result = append(result, dsc.NewSimpleColumn(column, storeTable.columnTypes[column]))
+		}
+	}
+	return result, nil
+}
+
+func (d dialect) GetKeyName(manager dsc.Manager, datastore, table string) string {
+	store := d.store(manager)
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+	if storeTable := store.table(table); storeTable != nil {
+		return strings.Join(storeTable.pkColumns, ",")
+	}
+	return ""
+}
+
+func (d dialect) IsAutoincrement(manager dsc.Manager, datastore, table string) bool {
+	store := d.store(manager)
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+	if storeTable := store.table(table); storeTable != nil {
+		return storeTable.autoincrement
+	}
+	return false
+}
+
+func init() {
+	dsc.RegisterManagerFactory(DriverName, &managerFactory{})
+	dsc.RegisterDatastoreDialect(DriverName, &dialect{})
+}
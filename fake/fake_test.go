@@ -0,0 +1,113 @@
+package fake_test
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/dsc"
+	"github.com/viant/dsc/fake"
+)
+
+type User struct {
+	Id       int    `autoincrement:"true"`
+	Username string `column:"username"`
+	Active   bool   `column:"active"`
+}
+
+func TestManager(t *testing.T) {
+	manager, err := dsc.NewManagerFactory().Create(dsc.NewConfig(fake.DriverName, "", "name:manager_test"))
+	if !assert.Nil(t, err) {
+		return
+	}
+	fake.GetStore("manager_test").Reset()
+	_, err = manager.Execute("CREATE TABLE users(id INTEGER PRIMARY KEY AUTOINCREMENT, username VARCHAR(255), active BOOLEAN)")
+	assert.Nil(t, err)
+	_, err = manager.Execute("CREATE TABLE users(id INTEGER)")
+	assert.NotNil(t, err)
+
+	result, err := manager.Execute("INSERT INTO users(username, active) VALUES(?, ?)", "Bob", true)
+	if assert.Nil(t, err) {
+		lastInsertID, _ := result.LastInsertId()
+		assert.EqualValues(t, 1, lastInsertID)
+	}
+	var users = []*User{{Username: "Alice", Active: true}, {Id: 1, Username: "Bobby", Active: false}}
+	inserted, updated, err := manager.PersistAll(&users, "users", nil)
+	if assert.Nil(t, err) {
+		assert.Equal(t, 1, inserted)
+		assert.Equal(t, 1, updated)
+		assert.Equal(t, 2, users[0].Id)
+	}
+	_, err = manager.Execute("INSERT INTO users(id, username) VALUES(?, ?)", 1, "duplicate")
+	assert.NotNil(t, err)
+
+	var actual = make([]User, 0)
+	err = manager.ReadAll(&actual, "SELECT id, username, active FROM users WHERE active = ?", []interface{}{true}, nil)
+	if assert.Nil(t, err) && assert.Equal(t, 1, len(actual)) {
+		assert.Equal(t, "Alice", actual[0].Username)
+	}
+	var user = &User{}
+	success, err := manager.ReadSingle(user, "SELECT * FROM users WHERE id = ?", []interface{}{1}, nil)
+	if assert.Nil(t, err) && assert.True(t, success) {
+		assert.Equal(t, "Bobby", user.Username)
+	}
+
+	dialect := dsc.GetDatastoreDialect(fake.DriverName)
+	assert.Equal(t, "id", dialect.GetKeyName(manager, "manager_test", "users"))
+	tables, err := dialect.GetTables(manager, "manager_test")
+	assert.Nil(t, err)
+	assert.EqualValues(t, []string{"users"}, tables)
+	columns, err := dialect.GetColumns(manager, "manager_test", "users")
+	if assert.Nil(t, err) && assert.Equal(t, 3, len(columns)) {
+		assert.Equal(t, "username", columns[1].Name())
+		assert.Equal(t, "VARCHAR(255)", columns[1].DatabaseTypeName())
+	}
+
+	//rolled back transaction restores store
+	connection, err := manager.ConnectionProvider().Get()
+	if assert.Nil(t, err) {
+		assert.Nil(t, connection.Begin())
+		_, err = manager.ExecuteOnConnection(connection, "DELETE FROM users", nil)
+		assert.Nil(t, err)
+		assert.Equal(t, 0, len(fake.GetStore("manager_test").Rows("users")))
+		assert.Nil(t, connection.Rollback())
+		assert.Nil(t, connection.Close())
+	}
+	assert.Equal(t, 2, len(fake.GetStore("manager_test").Rows("users")))
+
+	result, err = manager.Execute("DELETE FROM users WHERE id = ?", 2)
+	if assert.Nil(t, err) {
+		affected, _ := result.RowsAffected()
+		assert.EqualValues(t, 1, affected)
+	}
+	_, err = manager.Execute("TRUNCATE TABLE users")
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(fake.GetStore("manager_test").Rows("users")))
+	assert.Nil(t, dialect.DropTable(manager, "manager_test", "users"))
+	err = manager.ReadAll(&actual, "SELECT id FROM users", nil, nil)
+	assert.NotNil(t, err)
+}
+
+func TestNewSQLMock(t *testing.T) {
+	manager, mock, err := fake.NewSQLMock()
+	if !assert.Nil(t, err) {
+		return
+	}
+	mock.ExpectExec("UPDATE users SET active = \\? WHERE id = \\?").
+		WithArgs(false, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectPrepare("SELECT id, username, active FROM users").ExpectQuery().
+		WillReturnRows(sqlmock.NewRows([]string{"id", "username", "active"}).AddRow(1, "Bob", false))
+
+	result, err := manager.Execute("UPDATE users SET active = ? WHERE id = ?", false, 1)
+	if assert.Nil(t, err) {
+		affected, _ := result.RowsAffected()
+		assert.EqualValues(t, 1, affected)
+	}
+	var users = make([]User, 0)
+	err = manager.ReadAll(&users, "SELECT id, username, active FROM users", nil, nil)
+	if assert.Nil(t, err) && assert.Equal(t, 1, len(users)) {
+		assert.Equal(t, "Bob", users[0].Username)
+	}
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,39 @@
+package fake
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/viant/dsc"
+)
+
+// SQLMockDriverName go-sqlmock driver name, it uses mysql dialect so that ? placeholders are passed to mock unchanged
+const SQLMockDriverName = "sqlmock"
+
+var sqlMockSequence int64
+
+// NewSQLMockManager creates sql manager connected to go-sqlmock database registered with sqlmock.NewWithDSN, use it to pass sqlmock options (i.e. QueryMatcherOption)
+func NewSQLMockManager(dsn string) (dsc.Manager, error) {
+	config := dsc.NewConfig(SQLMockDriverName, "[dsn]", "dsn:"+dsn)
+	return dsc.NewManagerFactory().Create(config)
+}
+
+// NewSQLMock creates sql manager connected to new go-sqlmock database, statements executed by manager are verified against mock expectations,
+// note that manager prepares queries, so reads are expected with mock.ExpectPrepare(query).ExpectQuery()
+func NewSQLMock() (dsc.Manager, sqlmock.Sqlmock, error) {
+	dsn := fmt.Sprintf("dsc_sqlmock_%v", atomic.AddInt64(&sqlMockSequence, 1))
+	_, mock, err := sqlmock.NewWithDSN(dsn)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create sqlmock due to %v", err)
+	}
+	manager, err := NewSQLMockManager(dsn)
+	if err != nil {
+		return nil, nil, err
+	}
+	return manager, mock, nil
+}
+
+func init() {
+	dsc.RegisterDatastoreDialect(SQLMockDriverName, dsc.GetDatastoreDialect("mysql"))
+}
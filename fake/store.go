@@ -0,0 +1,262 @@
+package fake
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/viant/toolbox"
+)
+
+var primaryKeyConstraint = regexp.MustCompile(`(?is)^(CONSTRAINT\s+\S+\s+)?PRIMARY\s+KEY\s*\((.+)\)`)
+var autoincrementColumn = regexp.MustCompile(`(?i)\b(AUTOINCREMENT|AUTO_INCREMENT|SERIAL|BIGSERIAL|IDENTITY)\b`)
+var constraintKeywords = map[string]bool{"CONSTRAINT": true, "PRIMARY": true, "UNIQUE": true, "FOREIGN": true, "CHECK": true, "KEY": true, "INDEX": true}
+
+// table represents in-memory table
+type table struct {
+	name          string
+	columns       []string
+	columnTypes   map[string]string
+	pkColumns     []string
+	autoincrement bool
+	sequence      int64
+	rows          []map[string]interface{}
+}
+
+func (t *table) hasColumn(column string) bool {
+	for _, candidate := range t.columns {
+		if strings.EqualFold(candidate, column) {
+			return true
+		}
+	}
+	return false
+}
+
+// canonical returns record with keys renamed to matching table column names
+func (t *table) canonical(record map[string]interface{}) map[string]interface{} {
+	var result = make(map[string]interface{}, len(record))
+	for key, value := range record {
+		for _, column := range t.columns {
+			if strings.EqualFold(column, key) {
+				key = column
+				break
+			}
+		}
+		result[key] = value
+	}
+	return result
+}
+
+// addColumns adds record columns not yet known to the table in sorted order
+func (t *table) addColumns(record map[string]interface{}) {
+	var columns = toolbox.MapKeysToStringSlice(record)
+	sort.Strings(columns)
+	for _, column := range columns {
+		if !t.hasColumn(column) {
+			t.columns = append(t.columns, column)
+		}
+	}
+}
+
+func (t *table) key(record map[string]interface{}) (string, bool) {
+	var parts = make([]string, len(t.pkColumns))
+	for i, column := range t.pkColumns {
+		value, ok := record[column]
+		if !ok || value == nil {
+			return "", false
+		}
+		parts[i] = toolbox.AsString(value)
+	}
+	return strings.Join(parts, "\x00"), true
+}
+
+// insert inserts record, it assigns sequence value to missing autoincrement key and rejects duplicate keys
+func (t *table) insert(record map[string]interface{}) (int64, error) {
+	var lastInsertID int64
+	record = t.canonical(record)
+	if t.autoincrement && len(t.pkColumns) == 1 {
+		if value, ok := record[t.pkColumns[0]]; !ok || value == nil {
+			t.sequence++
+			record[t.pkColumns[0]] = t.sequence
+			lastInsertID = t.sequence
+		} else if id, err := toolbox.ToInt(value); err == nil && int64(id) > t.sequence {
+			t.sequence = int64(id)
+		}
+	}
+	if key, ok := t.key(record); ok {
+		for _, row := range t.rows {
+			if rowKey, _ := t.key(row); rowKey == key {
+				return 0, fmt.Errorf("duplicate key %v in table %v", strings.Replace(key, "\x00", ",", -1), t.name)
+			}
+		}
+	}
+	t.addColumns(record)
+	t.rows = append(t.rows, record)
+	return lastInsertID, nil
+}
+
+func (t *table) clone() *table {
+	var result = *t
+	result.columns = append([]string{}, t.columns...)
+	result.pkColumns = append([]string{}, t.pkColumns...)
+	result.rows = make([]map[string]interface{}, len(t.rows))
+	for i, row := range t.rows {
+		result.rows[i] = copyRecord(row)
+	}
+	return &result
+}
+
+func copyRecord(record map[string]interface{}) map[string]interface{} {
+	var result = make(map[string]interface{}, len(record))
+	for key, value := range record {
+		result[key] = value
+	}
+	return result
+}
+
+// splitDefinitions splits CREATE TABLE body by top level commas
+func splitDefinitions(body string) []string {
+	var result = make([]string, 0)
+	var depth, start = 0, 0
+	for i, c := range body {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				result = append(result, strings.TrimSpace(body[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	return append(result, strings.TrimSpace(body[start:]))
+}
+
+func unquote(name string) string {
+	return strings.Trim(strings.TrimSpace(name), "`\"[]")
+}
+
+// newTable creates table from CREATE TABLE column definitions
+func newTable(name, body string) *table {
+	var result = &table{name: name, columns: make([]string, 0), columnTypes: make(map[string]string)}
+	for _, definition := range splitDefinitions(body) {
+		if matched := primaryKeyConstraint.FindStringSubmatch(definition); len(matched) > 0 {
+			result.pkColumns = make([]string, 0)
+			for _, column := range strings.Split(matched[2], ",") {
+				result.pkColumns = append(result.pkColumns, unquote(column))
+			}
+			continue
+		}
+		fields := strings.Fields(definition)
+		if len(fields) == 0 || constraintKeywords[strings.ToUpper(fields[0])] {
+			continue
+		}
+		column := unquote(fields[0])
+		result.columns = append(result.columns, column)
+		if len(fields) > 1 {
+			result.columnTypes[column] = strings.ToUpper(fields[1])
+		}
+		if strings.Contains(strings.ToUpper(definition), "PRIMARY KEY") {
+			result.pkColumns = []string{column}
+		}
+		if autoincrementColumn.MatchString(definition) {
+			result.autoincrement = true
+		}
+	}
+	return result
+}
+
+// Store represents in-memory tables, store is shared by all managers configured with the same name
+type Store struct {
+	name   string
+	mutex  *sync.RWMutex
+	tables map[string]*table
+}
+
+func (s *Store) table(name string) *table {
+	return s.tables[strings.ToLower(name)]
+}
+
+// getOrCreateTable returns table, tables are created implicitly on the first insert
+func (s *Store) getOrCreateTable(name string) *table {
+	result := s.table(name)
+	if result == nil {
+		result = &table{name: name, columns: make([]string, 0), columnTypes: make(map[string]string)}
+		s.tables[strings.ToLower(name)] = result
+	}
+	return result
+}
+
+// Name returns store name
+func (s *Store) Name() string {
+	return s.name
+}
+
+// Tables returns sorted table names
+func (s *Store) Tables() []string {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	var result = make([]string, 0, len(s.tables))
+	for _, table := range s.tables {
+		result = append(result, table.name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Rows returns copy of table rows, or nil if table does not exist
+func (s *Store) Rows(table string) []map[string]interface{} {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	storeTable := s.table(table)
+	if storeTable == nil {
+		return nil
+	}
+	var result = make([]map[string]interface{}, len(storeTable.rows))
+	for i, row := range storeTable.rows {
+		result[i] = copyRecord(row)
+	}
+	return result
+}
+
+// Reset drops all tables
+func (s *Store) Reset() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.tables = make(map[string]*table)
+}
+
+func (s *Store) snapshot() map[string]*table {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	var result = make(map[string]*table, len(s.tables))
+	for key, table := range s.tables {
+		result[key] = table.clone()
+	}
+	return result
+}
+
+func (s *Store) restore(tables map[string]*table) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.tables = tables
+}
+
+var stores = make(map[string]*Store)
+var storesMutex = &sync.Mutex{}
+
+// GetStore returns named in-memory store, it creates store if needed
+func GetStore(name string) *Store {
+	storesMutex.Lock()
+	defer storesMutex.Unlock()
+	if result, ok := stores[name]; ok {
+		return result
+	}
+	result := &Store{name: name, mutex: &sync.RWMutex{}, tables: make(map[string]*table)}
+	stores[name] = result
+	return result
+}
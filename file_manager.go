@@ -39,7 +39,7 @@ type FileManager struct {
 func (m *FileManager) Init() error {
 	m.baseURL = url.NewResource(m.Config().Get("url"))
 	var err error
-	m.service, err = storage.NewServiceForURL(m.baseURL.URL, m.config.Credentials)
+	m.service, err = storage.NewServiceForURL(m.baseURL.URL, m.Config().Credentials)
 	extension := m.Config().Get("ext")
 	m.useGzipCompressions = extension == "gzip"
 	return err
@@ -51,7 +51,7 @@ func (m *FileManager) convertIfNeeded(source interface{}) interface{} {
 		return nil
 	}
 	if toolbox.IsTime(source) {
-		dateLayout := m.config.GetDateLayout()
+		dateLayout := m.Config().GetDateLayout()
 		if dateLayout == "" {
 			dateLayout = toolbox.DefaultDateLayout
 		}
@@ -404,7 +404,7 @@ func (m *FileManager) readWithPredicate(connection Connection, statement *QueryS
 			columns = append(columns, column.Name)
 		}
 	}
-	fileScanner := NewFileScanner(m.config, columns, nil)
+	fileScanner := NewFileScanner(m.Config(), columns, nil)
 	err := m.fetchRecords(statement.Table, predicate, func(record map[string]interface{}, matched bool) (bool, error) {
 
 		if !matched {
@@ -0,0 +1,359 @@
+// Package fixtures loads test datasets into tables and verifies table contents against expected datasets:
+//
+//	datasets, err := fixtures.Read("test/fixtures/users.yaml", "test/fixtures/orders.csv")
+//	err = fixtures.Load(manager, nil, datasets...)
+//	fixtures.Assert(t, manager, expected...)
+package fixtures
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/viant/dsc"
+	"github.com/viant/toolbox"
+	"github.com/viant/toolbox/url"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	//TruncateDelete removes existing rows with DELETE FROM, dependent tables are cleared first
+	TruncateDelete = "delete"
+	//TruncateTable removes existing rows with TRUNCATE TABLE, dependent tables are cleared first
+	TruncateTable = "truncate"
+	//TruncateNone keeps existing rows
+	TruncateNone = "none"
+)
+
+// Dataset represents table rows
+type Dataset struct {
+	Table string
+	//DependsOn tables referenced by this table foreign keys, referenced tables are loaded first and cleared last, if empty Load discovers them from datastore foreign key metadata
+	DependsOn []string
+	//Keys columns identifying rows during verification, table key is used if empty
+	Keys []string
+	Rows []map[string]interface{}
+}
+
+// LoadOptions represents load options
+type LoadOptions struct {
+	//Truncate strategy applied to dataset tables before load, TruncateDelete by default
+	Truncate string
+	//DisableForeignKeyCheck disables datastore foreign key check for load duration if supported by dialect
+	DisableForeignKeyCheck bool
+}
+
+// Order returns datasets ordered so that referenced tables precede tables depending on them, it returns an error for cyclic dependencies
+func Order(datasets []*Dataset) ([]*Dataset, error) {
+	var byTable = make(map[string]*Dataset)
+	for _, dataset := range datasets {
+		if _, ok := byTable[dataset.Table]; ok {
+			return nil, fmt.Errorf("failed to order datasets: duplicate table %v", dataset.Table)
+		}
+		byTable[dataset.Table] = dataset
+	}
+	var result = make([]*Dataset, 0, len(datasets))
+	var state = make(map[string]int) //1 visiting, 2 visited
+	var visit func(dataset *Dataset, path []string) error
+	visit = func(dataset *Dataset, path []string) error {
+		switch state[dataset.Table] {
+		case 1:
+			return fmt.Errorf("failed to order datasets: cyclic dependency %v", strings.Join(append(path, dataset.Table), " -> "))
+		case 2:
+			return nil
+		}
+		state[dataset.Table] = 1
+		for _, table := range dataset.DependsOn {
+			if dependency, ok := byTable[table]; ok && table != dataset.Table {
+				if err := visit(dependency, append(path, dataset.Table)); err != nil {
+					return err
+				}
+			}
+		}
+		state[dataset.Table] = 2
+		result = append(result, dataset)
+		return nil
+	}
+	for _, dataset := range datasets {
+		if err := visit(dataset, nil); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// withForeignKeyDependencies returns datasets with DependsOn discovered from dialect foreign keys for datasets without explicit dependencies,
+// datasets are returned unchanged if dialect does not implement dsc.IndexDialect
+func withForeignKeyDependencies(manager dsc.Manager, datasets []*Dataset) ([]*Dataset, error) {
+	dialect := dsc.GetDatastoreDialect(manager.Config().DriverName)
+	indexDialect, ok := dialect.(dsc.IndexDialect)
+	if !ok {
+		return datasets, nil
+	}
+	datastore, err := dialect.GetCurrentDatastore(manager)
+	if err != nil {
+		return nil, err
+	}
+	var result = make([]*Dataset, len(datasets))
+	for i, dataset := range datasets {
+		result[i] = dataset
+		if len(dataset.DependsOn) > 0 {
+			continue
+		}
+		foreignKeys, err := indexDialect.GetForeignKeys(manager, datastore, dataset.Table)
+		if err != nil {
+			return nil, err
+		}
+		if len(foreignKeys) == 0 {
+			continue
+		}
+		discovered := *dataset
+		for _, foreignKey := range foreignKeys {
+			discovered.DependsOn = append(discovered.DependsOn, foreignKey.ReferencedTable)
+		}
+		result[i] = &discovered
+	}
+	return result, nil
+}
+
+// clearTables removes existing dataset rows in reverse dependency order
+func clearTables(manager dsc.Manager, connection dsc.Connection, strategy string, datasets []*Dataset) error {
+	var template string
+	switch strategy {
+	case TruncateNone:
+		return nil
+	case TruncateDelete, "":
+		template = "DELETE FROM %v"
+	case TruncateTable:
+		template = "TRUNCATE TABLE %v"
+	default:
+		return fmt.Errorf("unsupported truncate strategy: %v", strategy)
+	}
+	for i := len(datasets) - 1; i >= 0; i-- {
+		SQL := fmt.Sprintf(template, dsc.QuoteIdentifiers(manager.Config(), datasets[i].Table)[0])
+		if _, err := manager.ExecuteOnConnection(connection, SQL, nil); err != nil {
+			return fmt.Errorf("failed to clear %v due to %v", datasets[i].Table, err)
+		}
+	}
+	return nil
+}
+
+// insert inserts dataset rows, each row inserts only its own columns so that omitted columns take their defaults
+func insert(manager dsc.Manager, connection dsc.Connection, dataset *Dataset) error {
+	for i, row := range dataset.Rows {
+		columns := toolbox.MapKeysToStringSlice(row)
+		sort.Strings(columns)
+		quoted := dsc.QuoteIdentifiers(manager.Config(), append([]string{dataset.Table}, columns...)...)
+		var record = make(map[string]interface{}, len(row))
+		for j, column := range columns {
+			record[quoted[j+1]] = row[column]
+		}
+		descriptor := &dsc.TableDescriptor{Table: quoted[0], Columns: quoted[1:]}
+		parametrizedSQL := dsc.NewMapDmlProvider(descriptor).Get(dsc.SQLTypeInsert, record)
+		if _, err := manager.ExecuteOnConnection(connection, parametrizedSQL.SQL, parametrizedSQL.Values); err != nil {
+			return fmt.Errorf("failed to load %v row %v due to %v", dataset.Table, i, err)
+		}
+	}
+	return nil
+}
+
+// Load clears dataset tables with options truncate strategy and inserts dataset rows in dependency order (DependsOn or discovered foreign keys) within a transaction
+func Load(manager dsc.Manager, options *LoadOptions, datasets ...*Dataset) (err error) {
+	if options == nil {
+		options = &LoadOptions{}
+	}
+	if datasets, err = withForeignKeyDependencies(manager, datasets); err != nil {
+		return err
+	}
+	ordered, err := Order(datasets)
+	if err != nil {
+		return err
+	}
+	connection, err := manager.ConnectionProvider().Get()
+	if err != nil {
+		return err
+	}
+	defer connection.Close()
+	dialect := dsc.GetDatastoreDialect(manager.Config().DriverName)
+	if options.DisableForeignKeyCheck {
+		if err = dialect.DisableForeignKeyCheck(manager, connection); err != nil {
+			return fmt.Errorf("failed to disable foreign key check due to %v", err)
+		}
+		defer func() {
+			if enableErr := dialect.EnableForeignKeyCheck(manager, connection); enableErr != nil && err == nil {
+				err = fmt.Errorf("failed to enable foreign key check due to %v", enableErr)
+			}
+		}()
+	}
+	if err = connection.Begin(); err != nil {
+		return err
+	}
+	if err = clearTables(manager, connection, options.Truncate, ordered); err == nil {
+		for _, dataset := range ordered {
+			if err = insert(manager, connection, dataset); err != nil {
+				break
+			}
+		}
+	}
+	if err != nil {
+		if rollbackErr := connection.Rollback(); rollbackErr != nil {
+			return fmt.Errorf("failed to rollback due to %v, %v", err, rollbackErr)
+		}
+		return err
+	}
+	return connection.Commit()
+}
+
+// LoadFromURL reads datasets from URLs and loads them
+func LoadFromURL(manager dsc.Manager, options *LoadOptions, URLs ...string) error {
+	datasets, err := Read(URLs...)
+	if err != nil {
+		return err
+	}
+	return Load(manager, options, datasets...)
+}
+
+// Read reads datasets from JSON, YAML or CSV URLs, JSON and YAML document maps table to rows or to dataset (DependsOn, Keys, Rows),
+// CSV file name is used as table, its header as columns, empty values are loaded as NULL
+func Read(URLs ...string) ([]*Dataset, error) {
+	var result = make([]*Dataset, 0)
+	for _, URL := range URLs {
+		text, err := url.NewResource(URL).DownloadText()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load dataset %v due to %v", URL, err)
+		}
+		var datasets []*Dataset
+		switch extension := strings.ToLower(path.Ext(URL)); extension {
+		case ".csv":
+			datasets, err = readCSV(strings.TrimSuffix(path.Base(URL), path.Ext(URL)), text)
+		case ".json":
+			var document interface{}
+			if err = json.Unmarshal([]byte(text), &document); err == nil {
+				datasets, err = newDatasets(document)
+			}
+		case ".yaml", ".yml":
+			var document interface{}
+			if err = yaml.Unmarshal([]byte(text), &document); err == nil {
+				datasets, err = newDatasets(normalize(document))
+			}
+		default:
+			err = fmt.Errorf("unsupported dataset format: %v", extension)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read dataset %v due to %v", URL, err)
+		}
+		result = append(result, datasets...)
+	}
+	return result, nil
+}
+
+func readCSV(table, text string) ([]*Dataset, error) {
+	records, err := csv.NewReader(strings.NewReader(text)).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	var result = &Dataset{Table: table, Rows: make([]map[string]interface{}, 0)}
+	if len(records) == 0 {
+		return []*Dataset{result}, nil
+	}
+	header := records[0]
+	for _, record := range records[1:] {
+		var row = make(map[string]interface{})
+		for i, column := range header {
+			if i < len(record) && record[i] != "" {
+				row[column] = record[i]
+			} else {
+				row[column] = nil
+			}
+		}
+		result.Rows = append(result.Rows, row)
+	}
+	return []*Dataset{result}, nil
+}
+
+// normalize converts YAML map[interface{}]interface{} into map[string]interface{}
+func normalize(value interface{}) interface{} {
+	switch actual := value.(type) {
+	case map[interface{}]interface{}:
+		var result = make(map[string]interface{})
+		for key, item := range actual {
+			result[toolbox.AsString(key)] = normalize(item)
+		}
+		return result
+	case []interface{}:
+		for i, item := range actual {
+			actual[i] = normalize(item)
+		}
+	}
+	return value
+}
+
+// field returns case insensitive map field
+func field(source map[string]interface{}, name string) (interface{}, bool) {
+	for key, value := range source {
+		if strings.EqualFold(key, name) {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+func asStrings(value interface{}) []string {
+	var result = make([]string, 0)
+	if value == nil {
+		return result
+	}
+	for _, item := range toolbox.AsSlice(value) {
+		result = append(result, toolbox.AsString(item))
+	}
+	return result
+}
+
+func asRows(table string, value interface{}) ([]map[string]interface{}, error) {
+	items, ok := value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected %v rows array but had %T", table, value)
+	}
+	var result = make([]map[string]interface{}, 0, len(items))
+	for _, item := range items {
+		row, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected %v row object but had %T", table, item)
+		}
+		result = append(result, row)
+	}
+	return result, nil
+}
+
+// newDatasets creates datasets from document mapping table to rows or to dataset object, tables are sorted by name
+func newDatasets(document interface{}) ([]*Dataset, error) {
+	source, ok := document.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected table map document but had %T", document)
+	}
+	tables := toolbox.MapKeysToStringSlice(source)
+	sort.Strings(tables)
+	var result = make([]*Dataset, 0, len(tables))
+	for _, table := range tables {
+		var dataset = &Dataset{Table: table}
+		var rows = source[table]
+		if object, ok := rows.(map[string]interface{}); ok {
+			dependsOn, _ := field(object, "DependsOn")
+			keys, _ := field(object, "Keys")
+			dataset.DependsOn = asStrings(dependsOn)
+			dataset.Keys = asStrings(keys)
+			if rows, ok = field(object, "Rows"); !ok {
+				rows = []interface{}{}
+			}
+		}
+		var err error
+		if dataset.Rows, err = asRows(table, rows); err != nil {
+			return nil, err
+		}
+		result = append(result, dataset)
+	}
+	return result, nil
+}
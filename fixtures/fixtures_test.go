@@ -0,0 +1,136 @@
+package fixtures_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/dsc"
+	"github.com/viant/dsc/fixtures"
+)
+
+type recordingT struct {
+	errors []string
+}
+
+func (t *recordingT) Errorf(format string, args ...interface{}) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func newManager(t *testing.T) dsc.Manager {
+	config := dsc.NewConfig("sqlite3", "[url]", "url:"+filepath.Join(t.TempDir(), "fixtures.db"))
+	manager, err := dsc.NewManagerFactory().Create(config)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	for _, SQL := range []string{
+		"CREATE TABLE users(id INTEGER PRIMARY KEY, name TEXT)",
+		"CREATE TABLE orders(id INTEGER PRIMARY KEY, user_id INTEGER REFERENCES users(id), amount REAL)",
+		"CREATE TABLE order_items(id INTEGER PRIMARY KEY, order_id INTEGER REFERENCES orders(id), sku TEXT, note TEXT)",
+		"INSERT INTO users(id, name) VALUES(100, 'stale')",
+	} {
+		_, err = manager.Execute(SQL)
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+	}
+	return manager
+}
+
+func TestOrder(t *testing.T) {
+	ordered, err := fixtures.Order([]*fixtures.Dataset{
+		{Table: "order_items", DependsOn: []string{"orders"}},
+		{Table: "orders", DependsOn: []string{"users"}},
+		{Table: "users"},
+	})
+	if assert.Nil(t, err) {
+		var tables = make([]string, 0)
+		for _, dataset := range ordered {
+			tables = append(tables, dataset.Table)
+		}
+		assert.EqualValues(t, []string{"users", "orders", "order_items"}, tables)
+	}
+	_, err = fixtures.Order([]*fixtures.Dataset{
+		{Table: "a", DependsOn: []string{"b"}},
+		{Table: "b", DependsOn: []string{"a"}},
+	})
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "a -> b -> a")
+	}
+}
+
+func TestLoadAndVerify(t *testing.T) {
+	manager := newManager(t)
+	datasets, err := fixtures.Read("test/users.yaml", "test/order_items.csv")
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, 3, len(datasets))
+	for _, dataset := range datasets {
+		if dataset.Table == "order_items" {
+			dataset.DependsOn = []string{"orders"}
+		}
+	}
+	err = fixtures.Load(manager, &fixtures.LoadOptions{DisableForeignKeyCheck: true}, datasets...)
+	if !assert.Nil(t, err) {
+		return
+	}
+	report, err := fixtures.VerifyFromURL(manager, "test/expect.json")
+	if assert.Nil(t, err) {
+		assert.True(t, report.Passed(), report.String())
+	}
+	assert.True(t, fixtures.Assert(t, manager, &fixtures.Dataset{Table: "orders", Rows: []map[string]interface{}{{"id": 1, "amount": 12.5}}}))
+
+	_, err = manager.Execute("UPDATE users SET name = 'Bobby' WHERE id = 1")
+	assert.Nil(t, err)
+	_, err = manager.Execute("INSERT INTO users(id, name) VALUES(3, 'Eve')")
+	assert.Nil(t, err)
+	recorder := &recordingT{}
+	assert.False(t, fixtures.Assert(recorder, manager,
+		&fixtures.Dataset{Table: "users", Rows: []map[string]interface{}{{"id": 1, "name": "Bob"}, {"id": 2, "name": "Alice"}, {"id": 4, "name": "Dan"}}}))
+	if assert.Equal(t, 1, len(recorder.errors)) {
+		message := recorder.errors[0]
+		assert.True(t, strings.Contains(message, "users[id=1].name: expected Bob, but had Bobby"), message)
+		assert.True(t, strings.Contains(message, "users[id=4]: missing row"), message)
+		assert.True(t, strings.Contains(message, "users[id=3]: unexpected row"), message)
+	}
+
+	//load again replaces existing rows
+	err = fixtures.Load(manager, nil, datasets...)
+	assert.Nil(t, err)
+	report, err = fixtures.VerifyFromURL(manager, "test/expect.json")
+	if assert.Nil(t, err) {
+		assert.True(t, report.Passed(), report.String())
+	}
+	err = fixtures.Load(manager, &fixtures.LoadOptions{Truncate: "purge"}, datasets...)
+	assert.NotNil(t, err)
+}
+
+func TestLoad_ForeignKeyOrder(t *testing.T) {
+	config := dsc.NewConfig("sqlite3", "[url]", "url:"+filepath.Join(t.TempDir(), "fixtures.db"))
+	config.SessionSettings = map[string]interface{}{"foreign_keys": "on"}
+	manager, err := dsc.NewManagerFactory().Create(config)
+	if !assert.Nil(t, err) {
+		return
+	}
+	for _, SQL := range []string{
+		"CREATE TABLE users(id INTEGER PRIMARY KEY, name TEXT)",
+		"CREATE TABLE orders(id INTEGER PRIMARY KEY, user_id INTEGER REFERENCES users(id), amount REAL)",
+	} {
+		_, err = manager.Execute(SQL)
+		if !assert.Nil(t, err) {
+			return
+		}
+	}
+	var datasets = []*fixtures.Dataset{
+		{Table: "orders", Rows: []map[string]interface{}{{"id": 1, "user_id": 1, "amount": 3.5}}},
+		{Table: "users", Rows: []map[string]interface{}{{"id": 1, "name": "Bob"}}},
+	}
+	assert.Nil(t, fixtures.Load(manager, nil, datasets...), "referenced table should be loaded first")
+	assert.Nil(t, fixtures.Load(manager, nil, datasets...), "dependent table should be cleared first")
+	assert.Equal(t, 0, len(datasets[0].DependsOn), "datasets should not be modified")
+	assert.True(t, fixtures.Assert(t, manager, datasets...))
+}
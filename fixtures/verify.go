@@ -0,0 +1,207 @@
+package fixtures
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/viant/dsc"
+	"github.com/viant/toolbox"
+)
+
+// Diff represents difference between expected and actual table contents
+type Diff struct {
+	Table string
+	//Key identifies row, key column values or row position when table has no key
+	Key string
+	//Column mismatched column, empty for missing or unexpected row
+	Column   string
+	Expected interface{}
+	Actual   interface{}
+}
+
+// String returns diff description
+func (d *Diff) String() string {
+	switch {
+	case d.Column != "":
+		return fmt.Sprintf("%v[%v].%v: expected %v, but had %v", d.Table, d.Key, d.Column, d.Expected, d.Actual)
+	case d.Actual == nil:
+		return fmt.Sprintf("%v[%v]: missing row %v", d.Table, d.Key, d.Expected)
+	default:
+		return fmt.Sprintf("%v[%v]: unexpected row %v", d.Table, d.Key, d.Actual)
+	}
+}
+
+// Report represents verification report
+type Report struct {
+	Diffs []*Diff
+}
+
+// Passed returns true if table contents matched expected datasets
+func (r *Report) Passed() bool {
+	return len(r.Diffs) == 0
+}
+
+// String returns report diffs, one per line
+func (r *Report) String() string {
+	var lines = make([]string, len(r.Diffs))
+	for i, diff := range r.Diffs {
+		lines[i] = diff.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// TestingT represents testing.T subset used by Assert
+type TestingT interface {
+	Errorf(format string, args ...interface{})
+}
+
+// keys returns dataset keys or table key reported by dialect
+func keys(manager dsc.Manager, dataset *Dataset) []string {
+	if len(dataset.Keys) > 0 {
+		return dataset.Keys
+	}
+	dialect := dsc.GetDatastoreDialect(manager.Config().DriverName)
+	datastore, _ := dialect.GetCurrentDatastore(manager)
+	var result = make([]string, 0)
+	for _, key := range strings.Split(dialect.GetKeyName(manager, datastore, dataset.Table), ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			result = append(result, key)
+		}
+	}
+	return result
+}
+
+// lookup returns case insensitive row value
+func lookup(row map[string]interface{}, column string) (interface{}, bool) {
+	if value, ok := row[column]; ok {
+		return value, true
+	}
+	return field(row, column)
+}
+
+func rowKey(row map[string]interface{}, keys []string) string {
+	var parts = make([]string, len(keys))
+	for i, key := range keys {
+		value, _ := lookup(row, key)
+		parts[i] = fmt.Sprintf("%v=%v", key, asText(value))
+	}
+	return strings.Join(parts, ",")
+}
+
+func asText(value interface{}) string {
+	if bytes, ok := value.([]byte); ok {
+		return string(bytes)
+	}
+	return toolbox.AsString(value)
+}
+
+// matches returns true if actual value matches expected, values are compared as text, time values are compared as instants
+func matches(expected, actual interface{}) bool {
+	if expected == nil || actual == nil {
+		return expected == nil && actual == nil
+	}
+	if actualTime, ok := actual.(time.Time); ok {
+		expectedTime, err := toolbox.ToTime(expected, toolbox.DefaultDateLayout)
+		return err == nil && expectedTime.Equal(actualTime)
+	}
+	return asText(expected) == asText(actual)
+}
+
+func readRows(manager dsc.Manager, table string) ([]map[string]interface{}, error) {
+	var result = make([]map[string]interface{}, 0)
+	err := manager.ReadAllWithHandler("SELECT * FROM "+table, nil, func(scanner dsc.Scanner) (bool, error) {
+		values, columns, err := dsc.ScanRow(scanner)
+		if err != nil {
+			return false, err
+		}
+		var row = make(map[string]interface{})
+		for i, column := range columns {
+			row[column] = values[i]
+		}
+		result = append(result, row)
+		return true, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %v due to %v", table, err)
+	}
+	return result, nil
+}
+
+// verify compares expected rows columns with actual rows matched by key, or by position if table has no key
+func verify(table string, keys []string, expected, actual []map[string]interface{}) []*Diff {
+	var result = make([]*Diff, 0)
+	var actualByKey = make(map[string]map[string]interface{})
+	var actualKeys = make([]string, 0)
+	for i, row := range actual {
+		key := fmt.Sprintf("#%v", i)
+		if len(keys) > 0 {
+			key = rowKey(row, keys)
+		}
+		actualByKey[key] = row
+		actualKeys = append(actualKeys, key)
+	}
+	var matched = make(map[string]bool)
+	for i, expectedRow := range expected {
+		key := fmt.Sprintf("#%v", i)
+		if len(keys) > 0 {
+			key = rowKey(expectedRow, keys)
+		}
+		actualRow, ok := actualByKey[key]
+		if !ok {
+			result = append(result, &Diff{Table: table, Key: key, Expected: expectedRow})
+			continue
+		}
+		matched[key] = true
+		columns := toolbox.MapKeysToStringSlice(expectedRow)
+		sort.Strings(columns)
+		for _, column := range columns {
+			actualValue, _ := lookup(actualRow, column)
+			if !matches(expectedRow[column], actualValue) {
+				result = append(result, &Diff{Table: table, Key: key, Column: column, Expected: expectedRow[column], Actual: actualValue})
+			}
+		}
+	}
+	for _, key := range actualKeys {
+		if !matched[key] {
+			result = append(result, &Diff{Table: table, Key: key, Actual: actualByKey[key]})
+		}
+	}
+	return result
+}
+
+// Verify compares table contents with expected datasets, only columns present in expected rows are compared, rows not present in expected dataset are reported as unexpected
+func Verify(manager dsc.Manager, expected ...*Dataset) (*Report, error) {
+	var result = &Report{Diffs: make([]*Diff, 0)}
+	for _, dataset := range expected {
+		actual, err := readRows(manager, dataset.Table)
+		if err != nil {
+			return nil, err
+		}
+		result.Diffs = append(result.Diffs, verify(dataset.Table, keys(manager, dataset), dataset.Rows, actual)...)
+	}
+	return result, nil
+}
+
+// VerifyFromURL verifies table contents with expected datasets read from URLs
+func VerifyFromURL(manager dsc.Manager, URLs ...string) (*Report, error) {
+	datasets, err := Read(URLs...)
+	if err != nil {
+		return nil, err
+	}
+	return Verify(manager, datasets...)
+}
+
+// Assert verifies table contents with expected datasets, it reports all diffs with single t.Errorf and returns true if contents matched
+func Assert(t TestingT, manager dsc.Manager, expected ...*Dataset) bool {
+	report, err := Verify(manager, expected...)
+	if err != nil {
+		t.Errorf("%v", err)
+		return false
+	}
+	if !report.Passed() {
+		t.Errorf("table contents did not match expected datasets:\n%v", report)
+	}
+	return report.Passed()
+}
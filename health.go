@@ -0,0 +1,150 @@
+package dsc
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const defaultValidationQuery = "SELECT 1"
+
+// HealthStatus represents manager health check result
+type HealthStatus struct {
+	//Healthy true when datastore responded to validation query
+	Healthy bool
+	//Error ping error message
+	Error string `json:",omitempty"`
+	//Latency ping duration
+	Latency time.Duration
+	//Pool connection pool snapshot
+	Pool PoolStats
+}
+
+// HealthReport represents aggregated health check of registered managers
+type HealthReport struct {
+	//Healthy true when all managers are healthy
+	Healthy   bool
+	Timestamp time.Time
+	Managers  map[string]*HealthStatus
+}
+
+// validationQueryDialect represents a dialect with specific connection validation query
+type validationQueryDialect interface {
+	validationQuery() string
+}
+
+func (d oraDialect) validationQuery() string {
+	return "SELECT 1 FROM DUAL"
+}
+
+func (d casandraSQLDialect) validationQuery() string {
+	return "SELECT now() FROM system.local"
+}
+
+// validationQuery returns dialect validation query
+func validationQuery(dialect DatastoreDialect) string {
+	if validator, ok := dialect.(validationQueryDialect); ok {
+		return validator.validationQuery()
+	}
+	return defaultValidationQuery
+}
+
+// ping pings database and runs validation query
+func (c *sqlConnection) ping(ctx context.Context, query string) error {
+	db, err := asSQLDb(c.db)
+	if err != nil {
+		return err
+	}
+	if err = db.PingContext(ctx); err != nil {
+		return err
+	}
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+	}
+	return rows.Err()
+}
+
+// connectionPinger represents a connection validated with a query
+type connectionPinger interface {
+	ping(ctx context.Context, query string) error
+}
+
+// Ping validates datastore connectivity with dialect specific validation query, connections without query support are validated by being acquired
+func (m *AbstractManager) Ping(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	connection, err := m.Manager.ConnectionProvider().Get()
+	if err != nil {
+		return err
+	}
+	defer connection.Close()
+	pinger, ok := connection.(connectionPinger)
+	if !ok {
+		return nil
+	}
+	if err = pinger.ping(ctx, validationQuery(GetDatastoreDialect(m.Config().DriverName))); err != nil {
+		return classifyError(GetDatastoreDialect(m.Config().DriverName), fmt.Errorf("failed to ping %v due to %w", m.Config().DriverName, err))
+	}
+	return nil
+}
+
+// healthChecker returns manager implementing health check, or abstract manager if it does not implement HealthChecker
+func (m *AbstractManager) healthChecker() HealthChecker {
+	if checker, ok := m.Manager.(HealthChecker); ok {
+		return checker
+	}
+	return m
+}
+
+// managerHealthCheck returns manager health status, manager not implementing HealthChecker is pinged with its connection provider
+func managerHealthCheck(ctx context.Context, manager Manager) *HealthStatus {
+	if checker, ok := manager.(HealthChecker); ok {
+		return checker.HealthCheck(ctx)
+	}
+	return NewAbstractManager(manager.Config(), manager.ConnectionProvider(), manager).HealthCheck(ctx)
+}
+
+// HealthCheck pings datastore and reports its status with connection pool snapshot
+func (m *AbstractManager) HealthCheck(ctx context.Context) *HealthStatus {
+	started := time.Now()
+	err := m.healthChecker().Ping(ctx)
+	result := &HealthStatus{Healthy: err == nil, Latency: time.Now().Sub(started)}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	if provider, ok := m.Manager.ConnectionProvider().(PoolStatsProvider); ok {
+		result.Pool = provider.Stats()
+	}
+	return result
+}
+
+// HealthCheck checks all registered managers concurrently
+func (r commonManagerRegistry) HealthCheck(ctx context.Context) *HealthReport {
+	names := r.Names()
+	var result = &HealthReport{Healthy: true, Timestamp: time.Now(), Managers: make(map[string]*HealthStatus)}
+	var mutex = &sync.Mutex{}
+	var waitGroup = &sync.WaitGroup{}
+	for _, name := range names {
+		manager := r.Get(name)
+		if manager == nil {
+			continue
+		}
+		waitGroup.Add(1)
+		go func(name string, manager Manager) {
+			defer waitGroup.Done()
+			status := managerHealthCheck(ctx, manager)
+			mutex.Lock()
+			defer mutex.Unlock()
+			result.Managers[name] = status
+			result.Healthy = result.Healthy && status.Healthy
+		}(name, manager)
+	}
+	waitGroup.Wait()
+	return result
+}
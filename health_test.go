@@ -0,0 +1,40 @@
+package dsc_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/dsc"
+)
+
+func TestManager_HealthCheck(t *testing.T) {
+	manager := GetManager(t)
+	assert.Nil(t, manager.(dsc.HealthChecker).Ping(context.Background()))
+	status := manager.(dsc.HealthChecker).HealthCheck(context.Background())
+	assert.True(t, status.Healthy)
+	assert.Equal(t, "", status.Error)
+	assert.True(t, status.Pool.Opened > 0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.NotNil(t, manager.(dsc.HealthChecker).Ping(ctx))
+
+	broken, err := dsc.NewManagerFactory().Create(dsc.NewConfig("sqlite3", "[url]", "url:./test/missing/dir/db.db"))
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.NotNil(t, broken.(dsc.HealthChecker).Ping(context.Background()))
+
+	registry := dsc.NewManagerRegistry()
+	registry.Register("main", manager)
+	registry.Register("broken", broken)
+	assert.EqualValues(t, 2, len(registry.(dsc.ManagerLister).Names()))
+	report := registry.(dsc.RegistryHealthChecker).HealthCheck(context.Background())
+	assert.False(t, report.Healthy)
+	if assert.Equal(t, 2, len(report.Managers)) {
+		assert.True(t, report.Managers["main"].Healthy)
+		assert.False(t, report.Managers["broken"].Healthy)
+		assert.NotEqual(t, "", report.Managers["broken"].Error)
+	}
+}
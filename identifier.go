@@ -0,0 +1,164 @@
+package dsc
+
+import (
+	"regexp"
+	"strings"
+)
+
+// QuoteIdentifiersKey represents a config parameter, when set to false generated SQL uses table and column names as provided (i.e. when they are already quoted manually)
+const QuoteIdentifiersKey = "quoteIdentifiers"
+
+var simpleIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_$]*$`)
+
+// identifierQuoting represents dialect identifier quoting rules
+type identifierQuoting struct {
+	open  string
+	close string
+	//fold case applied by datastore to unquoted identifiers, quoted reserved words are folded so that they match unquoted ones, nil if datastore compares identifiers case insensitively
+	fold func(string) string
+}
+
+var (
+	ansiQuoting      = &identifierQuoting{open: `"`, close: `"`}
+	mySQLQuoting     = &identifierQuoting{open: "`", close: "`"}
+	msSQLQuoting     = &identifierQuoting{open: "[", close: "]"}
+	lowerFoldQuoting = &identifierQuoting{open: `"`, close: `"`, fold: strings.ToLower}
+	upperFoldQuoting = &identifierQuoting{open: `"`, close: `"`, fold: strings.ToUpper}
+)
+
+// isQuoted returns true if identifier part uses any known quoting style
+func isQuoted(part string) bool {
+	if len(part) < 2 {
+		return false
+	}
+	first, last := part[0], part[len(part)-1]
+	return (first == '"' && last == '"') || (first == '`' && last == '`') || (first == '[' && last == ']')
+}
+
+// unquotePart returns identifier part without quotes of any known quoting style, with escaped closing quotes unescaped
+func unquotePart(part string) string {
+	closing := part[len(part)-1:]
+	return strings.Replace(part[1:len(part)-1], closing+closing, closing, -1)
+}
+
+// quotePart quotes identifier part that is a reserved word or has characters other than letters, digits and underscore,
+// part quoted with other dialect quoting style is requoted, mixed case part is left unquoted so that datastore case folding still applies
+func (q *identifierQuoting) quotePart(part string) string {
+	if part == "" || part == "*" {
+		return part
+	}
+	if isQuoted(part) {
+		if strings.HasPrefix(part, q.open) && strings.HasSuffix(part, q.close) {
+			return part
+		}
+		return q.open + strings.Replace(unquotePart(part), q.close, q.close+q.close, -1) + q.close
+	}
+	reserved := reservedKeyword[strings.ToLower(part)]
+	if !reserved && simpleIdentifier.MatchString(part) {
+		return part
+	}
+	if reserved && q.fold != nil {
+		part = q.fold(part)
+	}
+	return q.open + strings.Replace(part, q.close, q.close+q.close, -1) + q.close
+}
+
+// quote quotes each part of qualified identifier, i.e. schema.table
+func (q *identifierQuoting) quote(identifier string) string {
+	if !strings.Contains(identifier, ".") || isQuoted(identifier) {
+		return q.quotePart(identifier)
+	}
+	var parts = strings.Split(identifier, ".")
+	for i, part := range parts {
+		parts[i] = q.quotePart(part)
+	}
+	return strings.Join(parts, ".")
+}
+
+// QuoteIdentifier returns identifier unchanged, default dialect does not use SQL
+func (d DefaultDialect) QuoteIdentifier(identifier string) string {
+	return identifier
+}
+
+// QuoteIdentifier returns ANSI double quoted identifier if needed
+func (d sqlDatastoreDialect) QuoteIdentifier(identifier string) string {
+	return ansiQuoting.quote(identifier)
+}
+
+// QuoteIdentifier returns backtick quoted identifier if needed
+func (d mySQLDialect) QuoteIdentifier(identifier string) string {
+	return mySQLQuoting.quote(identifier)
+}
+
+// QuoteIdentifier returns double quoted identifier if needed, quoted reserved words are folded to lower case as postgres folds unquoted identifiers
+func (d pgDialect) QuoteIdentifier(identifier string) string {
+	return lowerFoldQuoting.quote(identifier)
+}
+
+// QuoteIdentifier returns double quoted identifier if needed, quoted reserved words are folded to upper case as oracle folds unquoted identifiers
+func (d oraDialect) QuoteIdentifier(identifier string) string {
+	return upperFoldQuoting.quote(identifier)
+}
+
+// QuoteIdentifier returns double quoted identifier if needed
+func (d sqlLiteDialect) QuoteIdentifier(identifier string) string {
+	return ansiQuoting.quote(identifier)
+}
+
+// QuoteIdentifier returns bracket quoted identifier if needed
+func (d msSQLDialect) QuoteIdentifier(identifier string) string {
+	return msSQLQuoting.quote(identifier)
+}
+
+// QuoteIdentifier returns double quoted identifier if needed, quoted reserved words are folded to lower case as cassandra folds unquoted identifiers
+func (d casandraSQLDialect) QuoteIdentifier(identifier string) string {
+	return lowerFoldQuoting.quote(identifier)
+}
+
+// identifierQuoter returns manager dialect identifier quoting function, or nil if quoting was disabled with quoteIdentifiers config parameter
+func identifierQuoter(config *Config) func(identifier string) string {
+	if config == nil {
+		return nil
+	}
+	if len(config.Parameters) > 0 && !config.GetBoolean(QuoteIdentifiersKey, true) {
+		return nil
+	}
+	if _, ok := datastoreDialectableRegistry[config.DriverName]; !ok && !isSQLDatabase(config.DriverName) {
+		return nil
+	}
+	return dialectQuoter(GetDatastoreDialect(config.DriverName))
+}
+
+// dialectQuoter returns dialect identifier quoting function, or nil if dialect does not implement IdentifierDialect
+func dialectQuoter(dialect DatastoreDialect) func(identifier string) string {
+	if identifierDialect, ok := dialect.(IdentifierDialect); ok {
+		return identifierDialect.QuoteIdentifier
+	}
+	return nil
+}
+
+// QuoteIdentifiers returns identifiers quoted with config driver dialect QuoteIdentifier when needed,
+// identifiers are returned unchanged if quoting was disabled with quoteIdentifiers config parameter
+func QuoteIdentifiers(config *Config, identifiers ...string) []string {
+	return quoteIdentifiers(identifierQuoter(config), identifiers)
+}
+
+// quoteIdentifiers returns identifiers quoted with quote function, nil function returns identifiers unchanged
+func quoteIdentifiers(quote func(identifier string) string, identifiers []string) []string {
+	if quote == nil {
+		return identifiers
+	}
+	var result = make([]string, len(identifiers))
+	for i, identifier := range identifiers {
+		result[i] = quote(identifier)
+	}
+	return result
+}
+
+// quoteIdentifier returns identifier quoted with quote function, nil function returns identifier unchanged
+func quoteIdentifier(quote func(identifier string) string, identifier string) string {
+	if quote == nil {
+		return identifier
+	}
+	return quote(identifier)
+}
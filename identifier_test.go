@@ -0,0 +1,54 @@
+package dsc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuoteIdentifier(t *testing.T) {
+	var useCases = []struct {
+		description string
+		driver      string
+		identifier  string
+		expect      string
+	}{
+		{description: "simple name", driver: "pg", identifier: "users", expect: "users"},
+		{description: "pg mixed case", driver: "pg", identifier: "userName", expect: "userName"},
+		{description: "pg reserved word folded", driver: "pg", identifier: "Order", expect: `"order"`},
+		{description: "pg qualified name", driver: "pg", identifier: "public.user", expect: `public."user"`},
+		{description: "oracle reserved word folded", driver: "ora", identifier: "user", expect: `"USER"`},
+		{description: "mysql reserved word", driver: "mysql", identifier: "order", expect: "`order`"},
+		{description: "mysql mixed case", driver: "mysql", identifier: "userName", expect: "userName"},
+		{description: "mssql reserved word", driver: "mssql", identifier: "order", expect: "[order]"},
+		{description: "sqlite special characters", driver: "sqlite3", identifier: "order items", expect: `"order items"`},
+		{description: "already quoted", driver: "pg", identifier: `"Group"`, expect: `"Group"`},
+		{description: "embedded quote", driver: "sqlite3", identifier: `a"b`, expect: `"a""b"`},
+		{description: "pg backtick quoted", driver: "pg", identifier: "`Group`", expect: `"Group"`},
+		{description: "mysql double quoted", driver: "mysql", identifier: `public."user"`, expect: "public.`user`"},
+		{description: "mssql backtick quoted with embedded quote", driver: "mssql", identifier: "`a]``b`", expect: "[a]]`b]"},
+	}
+	for _, useCase := range useCases {
+		dialect := GetDatastoreDialect(useCase.driver).(IdentifierDialect)
+		assert.EqualValues(t, useCase.expect, dialect.QuoteIdentifier(useCase.identifier), useCase.description)
+	}
+}
+
+func TestIdentifierQuoter(t *testing.T) {
+	descriptor := &TableDescriptor{Table: "order", PkColumns: []string{"id"}, Columns: []string{"id", "group"}}
+
+	config := NewConfig("sqlite3", "[url]", "url:./test/quote.db")
+	builder := newDmlBuilder(descriptor, identifierQuoter(config))
+	assert.EqualValues(t, `INSERT INTO "order"("group",id) VALUES(?,?)`, builder.InsertSQL)
+	assert.EqualValues(t, `UPDATE "order" SET  "group" = ? WHERE  id = ?`, builder.UpdateSQL)
+	assert.EqualValues(t, `DELETE FROM "order" WHERE  id = ?`, builder.DeleteSQL)
+
+	config = NewConfig("sqlite3", "[url]", "url:./test/quote.db,quoteIdentifiers:false")
+	assert.Nil(t, identifierQuoter(config))
+	builder = newDmlBuilder(descriptor, identifierQuoter(config))
+	assert.EqualValues(t, `INSERT INTO order(group,id) VALUES(?,?)`, builder.InsertSQL)
+	assert.EqualValues(t, []string{"order", "group"}, QuoteIdentifiers(config, "order", "group"))
+
+	config = NewConfig("mysql", "[url]", "url:root@tcp(127.0.0.1:3306)/db")
+	assert.EqualValues(t, []string{"`order`", "id"}, QuoteIdentifiers(config, "order", "id"))
+}
@@ -0,0 +1,81 @@
+package dsc
+
+import (
+	"sync"
+	"time"
+)
+
+// TrimIdleKey represents config parameter enabling idle state trimming of pooled connections
+const TrimIdleKey = "trimIdle"
+
+// IdleTrimmer represents a driver specific strategy releasing session state and buffers held by idle pooled connections
+type IdleTrimmer interface {
+	// Trim is called when connection returns to the pool, error closes the connection instead
+	Trim(connection Connection) error
+	// Restore is called when trimmed connection is taken from the pool, error vetoes the connection
+	Restore(connection Connection) error
+}
+
+var idleTrimmerRegistry = make(map[string]IdleTrimmer)
+var idleTrimmerMutex = &sync.RWMutex{}
+
+// RegisterIdleTrimmer registers idle trimmer for a driver, it is used when trimIdle config parameter is set, nil trimmer restores the default one
+func RegisterIdleTrimmer(driver string, trimmer IdleTrimmer) {
+	idleTrimmerMutex.Lock()
+	defer idleTrimmerMutex.Unlock()
+	if trimmer == nil {
+		delete(idleTrimmerRegistry, driver)
+		return
+	}
+	idleTrimmerRegistry[driver] = trimmer
+}
+
+// GetIdleTrimmer returns idle trimmer registered for a driver or default trimmer closing idle database/sql driver connections
+func GetIdleTrimmer(driver string) IdleTrimmer {
+	idleTrimmerMutex.RLock()
+	defer idleTrimmerMutex.RUnlock()
+	if trimmer, ok := idleTrimmerRegistry[driver]; ok {
+		return trimmer
+	}
+	return defaultIdleTrimmer
+}
+
+// idleTrimmer returns idle trimmer when enabled with config
+func (cp *AbstractConnectionProvider) idleTrimmer() IdleTrimmer {
+	config := cp.ConnectionProvider.Config()
+	if config == nil {
+		return nil
+	}
+	config.initLock()
+	if !config.GetBoolean(TrimIdleKey, false) {
+		return nil
+	}
+	return GetIdleTrimmer(config.DriverName)
+}
+
+var defaultIdleTrimmer IdleTrimmer = &sqlIdleTrimmer{}
+
+// trimmedConnMaxIdleTime represents idle time after which database/sql closes driver connections of trimmed connection
+const trimmedConnMaxIdleTime = time.Millisecond
+
+// sqlIdleTrimmer closes idle database/sql driver connections (with their session buffers) while dsc connection sits in the pool, other connections are left intact,
+// max idle connections setting is not changed, connections are closed by database/sql idle time cleaner
+type sqlIdleTrimmer struct{}
+
+// Trim shortens driver connections idle time, so that idle ones get closed
+func (t *sqlIdleTrimmer) Trim(connection Connection) error {
+	sqlConnection, ok := connection.(*sqlConnection)
+	if !ok || sqlConnection.tx != nil {
+		return nil
+	}
+	sqlConnection.db.SetConnMaxIdleTime(trimmedConnMaxIdleTime)
+	return nil
+}
+
+// Restore restores configured driver connections idle time
+func (t *sqlIdleTrimmer) Restore(connection Connection) error {
+	if sqlConnection, ok := connection.(*sqlConnection); ok {
+		sqlConnection.db.SetConnMaxIdleTime(connection.Config().GetDuration(connMaxIdleTimeMsKey, time.Millisecond, defaultConnMaxIdleTimeMs))
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package dsc_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/dsc"
+)
+
+type countingTrimmer struct {
+	trimmed  int
+	restored int
+}
+
+func (t *countingTrimmer) Trim(connection dsc.Connection) error {
+	t.trimmed++
+	return nil
+}
+
+func (t *countingTrimmer) Restore(connection dsc.Connection) error {
+	t.restored++
+	return nil
+}
+
+func TestIdleTrimmer(t *testing.T) {
+	{
+		config := dsc.NewConfig("sqlite3", "[url]", "url:./test/trim.db,trimIdle:true")
+		manager, err := dsc.NewManagerFactory().Create(config)
+		if !assert.Nil(t, err) {
+			return
+		}
+		_, err = manager.Execute("CREATE TABLE IF NOT EXISTS trimmed(id INTEGER)")
+		assert.Nil(t, err)
+		connection, err := manager.ConnectionProvider().Get()
+		if !assert.Nil(t, err) {
+			return
+		}
+		db := connection.Unwrap((*sql.DB)(nil)).(*sql.DB)
+		assert.Nil(t, db.Ping())
+		assert.Nil(t, connection.Close())
+		assert.Eventually(t, func() bool {
+			return db.Stats().Idle == 0
+		}, 5*time.Second, 50*time.Millisecond, "idle driver connections should be closed")
+		_, err = manager.Execute("INSERT INTO trimmed(id) VALUES(1)")
+		assert.Nil(t, err, "trimmed connection should be restored")
+		connection, err = manager.ConnectionProvider().Get()
+		if !assert.Nil(t, err) {
+			return
+		}
+		db = connection.Unwrap((*sql.DB)(nil)).(*sql.DB)
+		assert.Nil(t, db.Ping())
+		assert.Equal(t, 1, db.Stats().Idle, "restored connection should keep idle driver connection")
+		assert.Nil(t, connection.Close())
+	}
+	{
+		trimmer := &countingTrimmer{}
+		dsc.RegisterIdleTrimmer("sqlite3", trimmer)
+		defer dsc.RegisterIdleTrimmer("sqlite3", nil)
+		for _, trimIdle := range []string{"false", "true"} {
+			config := dsc.NewConfig("sqlite3", "[url]", "url:./test/trim.db,trimIdle:"+trimIdle)
+			manager, err := dsc.NewManagerFactory().Create(config)
+			if !assert.Nil(t, err) {
+				return
+			}
+			trimmer.trimmed, trimmer.restored = 0, 0
+			for i := 0; i < 2; i++ {
+				_, err = manager.Execute("SELECT 1")
+				assert.Nil(t, err)
+			}
+			if trimIdle == "true" {
+				assert.True(t, trimmer.trimmed >= 2)
+				assert.True(t, trimmer.restored >= 1)
+			} else {
+				assert.Equal(t, 0, trimmer.trimmed+trimmer.restored)
+			}
+		}
+	}
+}
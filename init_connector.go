@@ -0,0 +1,116 @@
+package dsc
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+)
+
+// dsnConnector represents connector of driver not implementing driver.DriverContext
+type dsnConnector struct {
+	dsn    string
+	driver driver.Driver
+}
+
+// Connect opens driver connection
+func (c *dsnConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	return c.driver.Open(c.dsn)
+}
+
+// Driver returns connector driver
+func (c *dsnConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+// initConnector represents connector running init SQL and session settings on every new physical connection,
+// so that connections opened later by database/sql pool share the same session state
+type initConnector struct {
+	driver.Connector
+	statements []string
+}
+
+// Connect opens driver connection and executes init statements on it
+func (c *initConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, statement := range c.statements {
+		if err = execOnDriverConn(ctx, conn, statement); err != nil {
+			_ = conn.Close()
+			return nil, fmt.Errorf("failed to execute %v due to %v", statement, err)
+		}
+	}
+	return conn, nil
+}
+
+// Close closes underlying connector if it holds resources, sql.DB calls it on close
+func (c *initConnector) Close() error {
+	if closer, ok := c.Connector.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// execOnDriverConn executes statement without parameters on driver connection
+func execOnDriverConn(ctx context.Context, conn driver.Conn, statement string) error {
+	if execer, ok := conn.(driver.ExecerContext); ok {
+		_, err := execer.ExecContext(ctx, statement, nil)
+		if err != driver.ErrSkip {
+			return err
+		}
+	}
+	if execer, ok := conn.(driver.Execer); ok {
+		_, err := execer.Exec(statement, nil)
+		if err != driver.ErrSkip {
+			return err
+		}
+	}
+	stmt, err := conn.Prepare(statement)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	_, err = stmt.Exec(nil)
+	return err
+}
+
+// initStatements returns config InitSQL and session settings statements executed on every new connection
+func initStatements(config *Config, dialect DatastoreDialect) ([]string, error) {
+	var result = make([]string, 0)
+	collect := func(SQL string) (sql.Result, error) {
+		result = append(result, SQL)
+		return nil, nil
+	}
+	for _, script := range config.InitSQL {
+		if _, err := executeScript(config.DriverName, script, collect); err != nil {
+			return nil, fmt.Errorf("failed to parse init SQL on %v due to %v", config.Descriptor, err)
+		}
+	}
+	err := applySessionSettings(dialect, config.SessionSettings, func(SQL string) error {
+		_, err := collect(SQL)
+		return err
+	})
+	return result, err
+}
+
+// openDB opens sql.DB, init statements are executed by connector on every physical connection
+func openDB(driverName, dsn string, statements []string) (*sql.DB, error) {
+	db, err := sql.Open(driverName, dsn)
+	if err != nil || len(statements) == 0 {
+		return db, err
+	}
+	var connector driver.Connector
+	if driverContext, ok := db.Driver().(driver.DriverContext); ok {
+		if connector, err = driverContext.OpenConnector(dsn); err != nil {
+			_ = db.Close()
+			return nil, err
+		}
+	} else {
+		connector = &dsnConnector{dsn: dsn, driver: db.Driver()}
+	}
+	_ = db.Close()
+	return sql.OpenDB(&initConnector{Connector: connector, statements: statements}), nil
+}
@@ -0,0 +1,230 @@
+package dsc
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Job statuses
+const (
+	JobStatusPending = "pending"
+	JobStatusRunning = "running"
+	JobStatusDone    = "done"
+	JobStatusDead    = "dead"
+)
+
+const jobColumns = "id, queue, payload, status, attempts, last_error, worker, available_at, heartbeat_at"
+
+// Job represents a job queue table row, the table is expected to define the following columns:
+// id (autoincrement primary key), queue, payload, status, attempts, last_error, worker, available_at, heartbeat_at
+type Job struct {
+	ID          int64      `column:"id" primaryKey:"true" autoincrement:"true"`
+	Queue       string     `column:"queue"`
+	Payload     string     `column:"payload"`
+	Status      string     `column:"status"`
+	Attempts    int        `column:"attempts"`
+	LastError   string     `column:"last_error"`
+	Worker      string     `column:"worker"`
+	AvailableAt time.Time  `column:"available_at"`
+	HeartbeatAt *time.Time `column:"heartbeat_at"`
+}
+
+// JobQueue implements database as queue pattern: jobs are claimed with row level lock skipping rows locked by other workers,
+// running jobs are kept alive with heartbeat, jobs with expired lease are reclaimed, and jobs exceeding max attempts are moved to dead letter status.
+type JobQueue struct {
+	manager Manager
+	table   string
+	queue   string
+	//MaxAttempts number of attempts after which a job is dead lettered
+	MaxAttempts int
+	//LeaseTimeout duration after which running job without heartbeat can be reclaimed by another worker
+	LeaseTimeout time.Duration
+	//RetryDelay delay multiplied by attempts before failed job becomes available again
+	RetryDelay time.Duration
+}
+
+func (q *JobQueue) now() time.Time {
+	return time.Now().UTC().Truncate(time.Second)
+}
+
+// lockClaim returns true if claim read locks selected job, oracle rejects FOR UPDATE with row limiting clause,
+// so that oracle claim relies on UPDATE guarded by job status and attempts, lost claim is retried
+func (q *JobQueue) lockClaim() bool {
+	switch q.manager.Config().DriverName {
+	case "ora", "oci8":
+		return false
+	}
+	return dialectCapabilities(GetDatastoreDialect(q.manager.Config().DriverName)).SkipLocked
+}
+
+// claimSQL returns dialect specific SQL selecting the first claimable job, row lock is added with claim read options
+func (q *JobQueue) claimSQL() string {
+	var top, limit = "", " LIMIT 1"
+	switch q.manager.Config().DriverName {
+	case "ora", "oci8":
+		limit = " FETCH FIRST 1 ROWS ONLY"
+	case "sqlserver":
+		top, limit = "TOP 1 ", ""
+	}
+	return fmt.Sprintf("SELECT %v%v FROM %v WHERE queue = ? AND ((status = ? AND available_at <= ?) OR (status = ? AND heartbeat_at < ?)) ORDER BY available_at, id%v",
+		top, jobColumns, q.table, limit)
+}
+
+// Enqueue adds a job with passed in payload, it returns job id
+func (q *JobQueue) Enqueue(payload string) (int64, error) {
+	return q.EnqueueAt(payload, q.now())
+}
+
+// EnqueueAt adds a job that becomes available at passed in time, it returns job id
+func (q *JobQueue) EnqueueAt(payload string, availableAt time.Time) (int64, error) {
+	job := &Job{Queue: q.queue, Payload: payload, Status: JobStatusPending, AvailableAt: availableAt.UTC().Truncate(time.Second)}
+	if _, _, err := q.manager.PersistSingle(job, q.table, nil); err != nil {
+		return 0, fmt.Errorf("failed to enqueue job on %v due to %v", q.table, err)
+	}
+	return job.ID, nil
+}
+
+// Claim claims the next available (or lease expired) job for passed in worker, it returns nil if no job is available
+func (q *JobQueue) Claim(worker string) (*Job, error) {
+	connection, err := q.manager.ConnectionProvider().Get()
+	if err != nil {
+		return nil, err
+	}
+	defer connection.Close()
+	for {
+		if err = connection.Begin(); err != nil {
+			return nil, err
+		}
+		job, claimed, err := q.claim(connection, worker)
+		if err != nil {
+			_ = connection.Rollback()
+			return nil, err
+		}
+		if err = connection.Commit(); err != nil {
+			return nil, err
+		}
+		if job == nil || claimed {
+			return job, nil
+		}
+	}
+}
+
+// claim returns claimed job, or job with false if the job was dead lettered or taken by another worker and claim needs to be retried
+func (q *JobQueue) claim(connection Connection, worker string) (*Job, bool, error) {
+	now := q.now()
+	expired := now.Add(-q.LeaseTimeout)
+	job := &Job{}
+	parameters := []interface{}{q.queue, JobStatusPending, now, JobStatusRunning, expired}
+	if q.lockClaim() {
+		parameters = append(parameters, WithForUpdate(), WithSkipLocked())
+	}
+	success, err := q.manager.ReadSingleOnConnection(connection, job, q.claimSQL(), parameters, nil)
+	if err != nil || !success {
+		return nil, false, err
+	}
+	if job.Status == JobStatusRunning && job.Attempts >= q.MaxAttempts {
+		SQL := fmt.Sprintf("UPDATE %v SET status = ?, last_error = ? WHERE id = ? AND status = ?", q.table)
+		_, err = q.manager.ExecuteOnConnection(connection, SQL, []interface{}{JobStatusDead, "lease expired", job.ID, JobStatusRunning})
+		return job, false, err
+	}
+	SQL := fmt.Sprintf("UPDATE %v SET status = ?, worker = ?, heartbeat_at = ?, attempts = attempts + 1 WHERE id = ? AND status = ? AND attempts = ?", q.table)
+	result, err := q.manager.ExecuteOnConnection(connection, SQL, []interface{}{JobStatusRunning, worker, now, job.ID, job.Status, job.Attempts})
+	if err != nil {
+		return nil, false, err
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return job, false, nil
+	}
+	job.Status = JobStatusRunning
+	job.Worker = worker
+	job.HeartbeatAt = &now
+	job.Attempts++
+	return job, true, nil
+}
+
+// updateRunning updates running job owned by job worker, it returns error if the lease was lost
+func (q *JobQueue) updateRunning(job *Job, assignment string, values ...interface{}) error {
+	SQL := fmt.Sprintf("UPDATE %v SET %v WHERE id = ? AND status = ? AND worker = ?", q.table, assignment)
+	result, err := q.manager.Execute(SQL, append(values, job.ID, JobStatusRunning, job.Worker)...)
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return fmt.Errorf("failed to update job %v: lease was lost by %v", job.ID, job.Worker)
+	}
+	return nil
+}
+
+// Heartbeat extends claimed job lease
+func (q *JobQueue) Heartbeat(job *Job) error {
+	now := q.now()
+	if err := q.updateRunning(job, "heartbeat_at = ?", now); err != nil {
+		return err
+	}
+	job.HeartbeatAt = &now
+	return nil
+}
+
+// Complete marks claimed job as done
+func (q *JobQueue) Complete(job *Job) error {
+	if err := q.updateRunning(job, "status = ?", JobStatusDone); err != nil {
+		return err
+	}
+	job.Status = JobStatusDone
+	return nil
+}
+
+// Fail records job failure, job is retried after RetryDelay * attempts, or dead lettered once MaxAttempts is reached
+func (q *JobQueue) Fail(job *Job, cause error) error {
+	status := JobStatusPending
+	if job.Attempts >= q.MaxAttempts {
+		status = JobStatusDead
+	}
+	availableAt := q.now().Add(q.RetryDelay * time.Duration(job.Attempts))
+	lastError := ""
+	if cause != nil {
+		lastError = cause.Error()
+	}
+	if err := q.updateRunning(job, "status = ?, last_error = ?, available_at = ?", status, lastError, availableAt); err != nil {
+		return err
+	}
+	job.Status = status
+	job.LastError = lastError
+	job.AvailableAt = availableAt
+	return nil
+}
+
+// DeadLetters returns dead lettered jobs
+func (q *JobQueue) DeadLetters() ([]*Job, error) {
+	var result = make([]*Job, 0)
+	SQL := fmt.Sprintf("SELECT %v FROM %v WHERE queue = ? AND status = ? ORDER BY id", jobColumns, q.table)
+	err := q.manager.ReadAll(&result, SQL, []interface{}{q.queue, JobStatusDead}, nil)
+	return result, err
+}
+
+// Requeue moves dead lettered jobs with passed in ids back to pending status with reset attempts
+func (q *JobQueue) Requeue(ids ...int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	var parameters = []interface{}{JobStatusPending, q.now(), q.queue, JobStatusDead}
+	for _, id := range ids {
+		parameters = append(parameters, id)
+	}
+	SQL := fmt.Sprintf("UPDATE %v SET status = ?, attempts = 0, available_at = ? WHERE queue = ? AND status = ? AND id IN(%v)", q.table, strings.Repeat(",?", len(ids))[1:])
+	_, err := q.manager.Execute(SQL, parameters...)
+	return err
+}
+
+// NewJobQueue creates a job queue for passed in table and queue name
+func NewJobQueue(manager Manager, table, queue string) *JobQueue {
+	return &JobQueue{
+		manager:      manager,
+		table:        table,
+		queue:        queue,
+		MaxAttempts:  5,
+		LeaseTimeout: 5 * time.Minute,
+		RetryDelay:   time.Second,
+	}
+}
@@ -0,0 +1,39 @@
+package dsc
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// configManager represents manager returning passed in config
+type configManager struct {
+	Manager
+	config *Config
+}
+
+func (m *configManager) Config() *Config {
+	return m.config
+}
+
+func TestJobQueue_ClaimSQL(t *testing.T) {
+	var useCases = []struct {
+		driver string
+		suffix string
+		prefix string
+		lock   bool
+	}{
+		{driver: "sqlite3", suffix: "ORDER BY available_at, id LIMIT 1", prefix: "SELECT id"},
+		{driver: "pg", suffix: "ORDER BY available_at, id LIMIT 1", prefix: "SELECT id", lock: true},
+		{driver: "ora", suffix: "ORDER BY available_at, id FETCH FIRST 1 ROWS ONLY", prefix: "SELECT id"},
+		{driver: "sqlserver", suffix: "ORDER BY available_at, id", prefix: "SELECT TOP 1 id", lock: true},
+	}
+	for _, useCase := range useCases {
+		queue := NewJobQueue(&configManager{config: &Config{DriverName: useCase.driver}}, "jobs", "emails")
+		SQL := queue.claimSQL()
+		assert.True(t, strings.HasSuffix(SQL, useCase.suffix), useCase.driver+": "+SQL)
+		assert.True(t, strings.HasPrefix(SQL, useCase.prefix), useCase.driver+": "+SQL)
+		assert.EqualValues(t, useCase.lock, queue.lockClaim(), useCase.driver)
+	}
+}
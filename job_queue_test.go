@@ -0,0 +1,95 @@
+package dsc_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/dsc"
+)
+
+func TestJobQueue(t *testing.T) {
+	config := dsc.NewConfig("sqlite3", "[url]", "url:./test/jobs.db")
+	manager, err := dsc.NewManagerFactory().Create(config)
+	if !assert.Nil(t, err) {
+		return
+	}
+	for _, SQL := range []string{
+		"DROP TABLE IF EXISTS jobs",
+		"CREATE TABLE jobs(id INTEGER PRIMARY KEY AUTOINCREMENT, queue TEXT, payload TEXT, status TEXT, attempts INTEGER, last_error TEXT, worker TEXT, available_at DATETIME, heartbeat_at DATETIME)",
+	} {
+		_, err = manager.Execute(SQL)
+		if !assert.Nil(t, err) {
+			return
+		}
+	}
+	queue := dsc.NewJobQueue(manager, "jobs", "emails")
+	queue.MaxAttempts = 2
+	queue.RetryDelay = 0
+
+	id1, err := queue.Enqueue("job1")
+	assert.Nil(t, err)
+	id2, err := queue.Enqueue("job2")
+	assert.Nil(t, err)
+	_, err = queue.EnqueueAt("later", time.Now().Add(time.Hour))
+	assert.Nil(t, err)
+
+	job1, err := queue.Claim("w1")
+	if !assert.Nil(t, err) || !assert.NotNil(t, job1) {
+		return
+	}
+	assert.EqualValues(t, id1, job1.ID)
+	assert.EqualValues(t, 1, job1.Attempts)
+	job2, err := queue.Claim("w2")
+	if !assert.Nil(t, err) || !assert.NotNil(t, job2) {
+		return
+	}
+	assert.EqualValues(t, id2, job2.ID)
+	none, err := queue.Claim("w3")
+	assert.Nil(t, err)
+	assert.Nil(t, none)
+
+	assert.Nil(t, queue.Heartbeat(job1))
+	assert.Nil(t, queue.Complete(job1))
+	assert.NotNil(t, queue.Heartbeat(job1), "completed job should not be running")
+
+	assert.Nil(t, queue.Fail(job2, errors.New("smtp down")))
+	assert.EqualValues(t, dsc.JobStatusPending, job2.Status)
+	retried, err := queue.Claim("w2")
+	if !assert.Nil(t, err) || !assert.NotNil(t, retried) {
+		return
+	}
+	assert.EqualValues(t, 2, retried.Attempts)
+	assert.EqualValues(t, "smtp down", retried.LastError)
+	assert.Nil(t, queue.Fail(retried, errors.New("smtp still down")))
+	assert.EqualValues(t, dsc.JobStatusDead, retried.Status)
+
+	dead, err := queue.DeadLetters()
+	assert.Nil(t, err)
+	if assert.Equal(t, 1, len(dead)) {
+		assert.EqualValues(t, id2, dead[0].ID)
+	}
+	assert.Nil(t, queue.Requeue(id2))
+
+	requeued, err := queue.Claim("w1")
+	if !assert.Nil(t, err) || !assert.NotNil(t, requeued) {
+		return
+	}
+	assert.EqualValues(t, 1, requeued.Attempts)
+
+	queue.LeaseTimeout = -time.Second
+	reclaimed, err := queue.Claim("w2")
+	if !assert.Nil(t, err) || !assert.NotNil(t, reclaimed) {
+		return
+	}
+	assert.EqualValues(t, requeued.ID, reclaimed.ID, "expired lease should be reclaimed")
+	assert.NotNil(t, queue.Heartbeat(requeued), "lease should be lost")
+
+	none, err = queue.Claim("w3")
+	assert.Nil(t, err)
+	assert.Nil(t, none, "job exceeding max attempts should be dead lettered")
+	dead, _ = queue.DeadLetters()
+	assert.Equal(t, 1, len(dead))
+}
@@ -0,0 +1,291 @@
+package dsc
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/viant/toolbox"
+)
+
+// UUIDv7KeyGeneratorName name of registered UUIDv7 key generator
+const UUIDv7KeyGeneratorName = "uuidv7"
+
+// snowflakeEpoch custom epoch of snowflake IDs (2020-01-01T00:00:00Z) in milliseconds
+const snowflakeEpoch = int64(1577836800000)
+
+const (
+	snowflakeNodeBits     = 10
+	snowflakeSequenceBits = 12
+	snowflakeMaxNode      = -1 ^ (-1 << snowflakeNodeBits)
+	snowflakeMaxSequence  = -1 ^ (-1 << snowflakeSequenceBits)
+)
+
+// KeyGenerator represents primary key generator used by PersistAll for rows with zero valued key,
+// it is selected with TableDescriptor.KeyGenerator and applies to tables with single column key that do not use autoincrement
+type KeyGenerator interface {
+	// NextKey returns next key for the table
+	NextKey(manager Manager, table string) (interface{}, error)
+}
+
+// KeyGeneratorFunc represents function adapter for KeyGenerator
+type KeyGeneratorFunc func(manager Manager, table string) (interface{}, error)
+
+// NextKey calls the function
+func (f KeyGeneratorFunc) NextKey(manager Manager, table string) (interface{}, error) {
+	return f(manager, table)
+}
+
+// sequenceDialect represents a dialect supporting database sequences
+type sequenceDialect interface {
+	nextSequenceValueSQL(sequence string) string
+}
+
+func (d pgDialect) nextSequenceValueSQL(sequence string) string {
+	return fmt.Sprintf("SELECT nextval('%v')", sequence)
+}
+
+func (d oraDialect) nextSequenceValueSQL(sequence string) string {
+	return fmt.Sprintf("SELECT %v.NEXTVAL FROM dual", sequence)
+}
+
+func (d msSQLDialect) nextSequenceValueSQL(sequence string) string {
+	return fmt.Sprintf("SELECT NEXT VALUE FOR %v", sequence)
+}
+
+// NewSequenceKeyGenerator returns generator reading next value of database sequence, it is supported by postgres, oracle and sqlserver dialects
+func NewSequenceKeyGenerator(sequence string) KeyGenerator {
+	return KeyGeneratorFunc(func(manager Manager, table string) (interface{}, error) {
+		dialect, ok := GetDatastoreDialect(manager.Config().DriverName).(sequenceDialect)
+		if !ok {
+			return nil, fmt.Errorf("failed to generate %v key: sequences are not supported by %v", table, manager.Config().DriverName)
+		}
+		var result = make([]interface{}, 0)
+		success, err := manager.ReadSingle(&result, dialect.nextSequenceValueSQL(sequence), nil, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate %v key from sequence %v due to %v", table, sequence, err)
+		}
+		if !success || len(result) == 0 {
+			return nil, fmt.Errorf("failed to generate %v key: sequence %v returned no value", table, sequence)
+		}
+		return int64(toolbox.AsInt(result[0])), nil
+	})
+}
+
+// NewUUIDv7KeyGenerator returns generator of time ordered UUIDv7 string keys
+func NewUUIDv7KeyGenerator() KeyGenerator {
+	return KeyGeneratorFunc(func(manager Manager, table string) (interface{}, error) {
+		key, err := uuid.NewV7()
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate %v key due to %v", table, err)
+		}
+		return key.String(), nil
+	})
+}
+
+// snowflakeKeyGenerator represents snowflake-style 64 bit ID generator: 41 bits of milliseconds since 2020, 10 bits node and 12 bits sequence
+type snowflakeKeyGenerator struct {
+	mutex     sync.Mutex
+	node      int64
+	timestamp int64
+	sequence  int64
+}
+
+func (g *snowflakeKeyGenerator) NextKey(manager Manager, table string) (interface{}, error) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	now := time.Now().UnixNano()/int64(time.Millisecond) - snowflakeEpoch
+	if now < g.timestamp { //clock moved backwards, keep IDs increasing
+		now = g.timestamp
+	}
+	if now == g.timestamp {
+		g.sequence = (g.sequence + 1) & snowflakeMaxSequence
+		if g.sequence == 0 { //sequence exhausted within millisecond, wait for the next one
+			for now <= g.timestamp {
+				time.Sleep(100 * time.Microsecond)
+				now = time.Now().UnixNano()/int64(time.Millisecond) - snowflakeEpoch
+			}
+		}
+	} else {
+		g.sequence = 0
+	}
+	g.timestamp = now
+	return now<<(snowflakeNodeBits+snowflakeSequenceBits) | g.node<<snowflakeSequenceBits | g.sequence, nil
+}
+
+// NewSnowflakeKeyGenerator returns generator of snowflake-style int64 keys, node (0-1023) has to be unique for each process generating keys for the same table
+func NewSnowflakeKeyGenerator(node int64) (KeyGenerator, error) {
+	if node < 0 || node > snowflakeMaxNode {
+		return nil, fmt.Errorf("failed to create snowflake key generator: node %v was out of range 0-%v", node, snowflakeMaxNode)
+	}
+	return &snowflakeKeyGenerator{node: node}, nil
+}
+
+// hiLoBlock represents allocated block of keys
+type hiLoBlock struct {
+	next  int64
+	limit int64
+}
+
+// hiLoKeyGenerator represents hi/lo key allocator
+type hiLoKeyGenerator struct {
+	mutex     sync.Mutex
+	hi        KeyGenerator
+	blockSize int64
+	blocks    map[string]*hiLoBlock
+}
+
+func (g *hiLoKeyGenerator) NextKey(manager Manager, table string) (interface{}, error) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	block, ok := g.blocks[table]
+	if !ok || block.next >= block.limit {
+		value, err := g.hi.NextKey(manager, table)
+		if err != nil {
+			return nil, err
+		}
+		hi, err := toolbox.ToInt(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to allocate %v keys: invalid hi value %v", table, value)
+		}
+		block = &hiLoBlock{next: int64(hi) * g.blockSize, limit: int64(hi)*g.blockSize + g.blockSize}
+		g.blocks[table] = block
+	}
+	result := block.next
+	block.next++
+	return result, nil
+}
+
+// NewHiLoKeyGenerator returns hi/lo allocator, each hi value obtained from hi generator (i.e. database sequence) reserves blockSize keys: hi * blockSize ... hi * blockSize + blockSize - 1
+func NewHiLoKeyGenerator(hi KeyGenerator, blockSize int) KeyGenerator {
+	if blockSize <= 0 {
+		blockSize = 1
+	}
+	return &hiLoKeyGenerator{hi: hi, blockSize: int64(blockSize), blocks: make(map[string]*hiLoBlock)}
+}
+
+var keyGenerators = map[string]KeyGenerator{UUIDv7KeyGeneratorName: NewUUIDv7KeyGenerator()}
+var keyGeneratorsMutex = &sync.RWMutex{}
+
+// RegisterKeyGenerator registers named key generator used by `keyGenerator` struct tag, nil generator removes registration
+func RegisterKeyGenerator(name string, generator KeyGenerator) {
+	keyGeneratorsMutex.Lock()
+	defer keyGeneratorsMutex.Unlock()
+	if generator == nil {
+		delete(keyGenerators, name)
+		return
+	}
+	keyGenerators[name] = generator
+}
+
+// GetKeyGenerator returns named key generator or nil
+func GetKeyGenerator(name string) KeyGenerator {
+	keyGeneratorsMutex.RLock()
+	defer keyGeneratorsMutex.RUnlock()
+	return keyGenerators[name]
+}
+
+// newKeyGenerator returns key generator for primary key field with `keyGenerator` (registered generator name) or `sequence` (database sequence name) tag
+func newKeyGenerator(targetType reflect.Type, pkColumns []string) (KeyGenerator, error) {
+	var result KeyGenerator
+	mapping := toolbox.BuildTagMapping(targetType, "column", "transient", true, true, []string{"column", "keyGenerator", "sequence"})
+	for _, fieldMapping := range mapping {
+		name, hasGenerator := fieldMapping["keyGenerator"]
+		sequence, hasSequence := fieldMapping["sequence"]
+		if !hasGenerator && !hasSequence {
+			continue
+		}
+		column, ok := fieldMapping["column"]
+		if !ok {
+			column = fieldMapping["fieldName"]
+		}
+		if len(pkColumns) != 1 || !strings.EqualFold(pkColumns[0], column) {
+			return nil, fmt.Errorf("key generator of column %v requires single column primary key", column)
+		}
+		if hasSequence {
+			result = NewSequenceKeyGenerator(sequence)
+			continue
+		}
+		if result = GetKeyGenerator(name); result == nil {
+			return nil, fmt.Errorf("key generator %v of column %v was not registered", name, column)
+		}
+	}
+	return result, nil
+}
+
+// generateKeys sets keys generated by descriptor key generator on rows with zero valued key
+func generateKeys(manager Manager, descriptor *TableDescriptor, dataPointer interface{}) error {
+	if descriptor.KeyGenerator == nil || descriptor.Autoincrement || len(descriptor.PkColumns) != 1 {
+		return nil
+	}
+	var fieldNames = make(map[reflect.Type]string)
+	slice := reflect.ValueOf(dataPointer).Elem()
+	for i := 0; i < slice.Len(); i++ {
+		if err := generateKey(manager, descriptor, fieldNames, slice.Index(i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func generateKey(manager Manager, descriptor *TableDescriptor, fieldNames map[reflect.Type]string, item reflect.Value) error {
+	column := descriptor.PkColumns[0]
+	switch item.Kind() {
+	case reflect.Interface:
+		if item.IsNil() {
+			return nil
+		}
+		value := reflect.New(item.Elem().Type()).Elem()
+		value.Set(item.Elem())
+		if err := generateKey(manager, descriptor, fieldNames, value); err != nil {
+			return err
+		}
+		item.Set(value)
+	case reflect.Ptr:
+		if !item.IsNil() {
+			return generateKey(manager, descriptor, fieldNames, item.Elem())
+		}
+	case reflect.Map:
+		if item.IsNil() || item.Type().Key().Kind() != reflect.String {
+			return nil
+		}
+		key := reflect.ValueOf(column).Convert(item.Type().Key())
+		if existing := item.MapIndex(key); existing.IsValid() && !isZeroValue(existing) {
+			return nil
+		}
+		value, err := descriptor.KeyGenerator.NextKey(manager, descriptor.Table)
+		if err != nil {
+			return err
+		}
+		item.SetMapIndex(key, reflect.ValueOf(value))
+	case reflect.Struct:
+		fieldName, ok := fieldNames[item.Type()]
+		if !ok {
+			fieldName = toolbox.NewFieldSettingByKey(item.Type(), "column")[strings.ToLower(column)]["fieldName"]
+			fieldNames[item.Type()] = fieldName
+		}
+		field := item.FieldByName(fieldName)
+		if fieldName == "" || !field.IsValid() || !field.CanSet() || !field.IsZero() {
+			return nil
+		}
+		value, err := descriptor.KeyGenerator.NextKey(manager, descriptor.Table)
+		if err != nil {
+			return err
+		}
+		return toolbox.DefaultConverter.AssignConverted(field.Addr().Interface(), value)
+	}
+	return nil
+}
+
+func isZeroValue(value reflect.Value) bool {
+	if value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return true
+		}
+		value = value.Elem()
+	}
+	return value.IsZero()
+}
@@ -0,0 +1,111 @@
+package dsc_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/dsc"
+	"github.com/viant/toolbox"
+)
+
+type Ticket struct {
+	Id    string `column:"id" primaryKey:"true" keyGenerator:"uuidv7"`
+	Title string `column:"title"`
+}
+
+type Invoice struct {
+	Id     int64   `column:"id" primaryKey:"true" keyGenerator:"invoice_hilo"`
+	Amount float64 `column:"amount"`
+}
+
+func TestKeyGenerators(t *testing.T) {
+	{ //snowflake keys are unique and increasing
+		generator, err := dsc.NewSnowflakeKeyGenerator(7)
+		if assert.Nil(t, err) {
+			var previous int64
+			for i := 0; i < 5000; i++ {
+				key, err := generator.NextKey(nil, "events")
+				if !assert.Nil(t, err) {
+					break
+				}
+				assert.True(t, key.(int64) > previous)
+				assert.EqualValues(t, 7, key.(int64)>>12&1023)
+				previous = key.(int64)
+			}
+		}
+		_, err = dsc.NewSnowflakeKeyGenerator(1024)
+		assert.NotNil(t, err)
+	}
+	{ //hi/lo allocates block of keys per hi value
+		var hi = 0
+		generator := dsc.NewHiLoKeyGenerator(dsc.KeyGeneratorFunc(func(manager dsc.Manager, table string) (interface{}, error) {
+			hi++
+			return hi, nil
+		}), 3)
+		var keys = make([]interface{}, 0)
+		for i := 0; i < 4; i++ {
+			key, err := generator.NextKey(nil, "invoices")
+			assert.Nil(t, err)
+			keys = append(keys, key)
+		}
+		assert.EqualValues(t, []interface{}{int64(3), int64(4), int64(5), int64(6)}, keys)
+		assert.Equal(t, 2, hi)
+	}
+	{ //sqlite does not support sequences
+		manager := GetManager(t)
+		_, err := dsc.NewSequenceKeyGenerator("invoice_seq").NextKey(manager, "invoices")
+		assert.NotNil(t, err)
+	}
+	{
+		_, err := dsc.NewTableDescriptor("invoices", Invoice{})
+		assert.NotNil(t, err, "key generator was not registered yet")
+		_, err = dsc.NewTableDescriptor("tickets", struct {
+			Id    int    `primaryKey:"true"`
+			Title string `column:"title" keyGenerator:"uuidv7"`
+		}{})
+		assert.NotNil(t, err, "key generator requires primary key column")
+	}
+}
+
+func TestManager_PersistAllWithKeyGenerator(t *testing.T) {
+	manager := GetManager(t)
+	for _, SQL := range []string{
+		"DROP TABLE IF EXISTS tickets",
+		"CREATE TABLE tickets(id VARCHAR(36) PRIMARY KEY, title VARCHAR(255))",
+		"DROP TABLE IF EXISTS invoices",
+		"CREATE TABLE invoices(id INTEGER PRIMARY KEY, amount DECIMAL(7,2))",
+	} {
+		_, err := manager.Execute(SQL)
+		if !assert.Nil(t, err) {
+			return
+		}
+	}
+
+	var tickets = []Ticket{{Title: "first"}, {Id: "manual", Title: "second"}}
+	inserted, _, err := manager.PersistAll(&tickets, "tickets", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, inserted)
+	assert.Equal(t, 36, len(tickets[0].Id))
+	assert.Equal(t, "manual", tickets[1].Id)
+	tickets[0].Title = "updated"
+	_, updated, err := manager.PersistAll(&tickets, "tickets", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, updated)
+
+	dsc.RegisterKeyGenerator("invoice_hilo", dsc.NewHiLoKeyGenerator(dsc.KeyGeneratorFunc(func(manager dsc.Manager, table string) (interface{}, error) {
+		return 1, nil
+	}), 100))
+	defer dsc.RegisterKeyGenerator("invoice_hilo", nil)
+	var invoices = []*Invoice{{Amount: 10}, {Amount: 20}}
+	inserted, _, err = manager.PersistAll(&invoices, "invoices", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, inserted)
+	assert.EqualValues(t, 100, invoices[0].Id)
+	assert.EqualValues(t, 101, invoices[1].Id)
+
+	var stored = make([]map[string]interface{}, 0)
+	err = manager.ReadAll(&stored, "SELECT id, amount FROM invoices ORDER BY id", nil, nil)
+	if assert.Nil(t, err) && assert.Equal(t, 2, len(stored)) {
+		assert.Equal(t, "101", toolbox.AsString(stored[1]["id"]))
+	}
+}
@@ -0,0 +1,133 @@
+package dsc
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// GeneratedKeyStrategy represents autoincrement/generated key retrieval strategy used while persisting data into autoincrement table
+type GeneratedKeyStrategy interface {
+	// Insert executes insert statement on connection, returned result LastInsertId is the generated key, zero means the key is unknown
+	Insert(manager Manager, connection Connection, table string, SQL string, parameters []interface{}) (sql.Result, error)
+}
+
+// GeneratedKeyStrategyFunc represents function based generated key strategy
+type GeneratedKeyStrategyFunc func(manager Manager, connection Connection, table string, SQL string, parameters []interface{}) (sql.Result, error)
+
+// Insert executes insert statement and returns result with generated key
+func (f GeneratedKeyStrategyFunc) Insert(manager Manager, connection Connection, table string, SQL string, parameters []interface{}) (sql.Result, error) {
+	return f(manager, connection, table, SQL, parameters)
+}
+
+// LastInsertIDKeyStrategy uses driver sql.Result LastInsertId, it is the default strategy
+var LastInsertIDKeyStrategy GeneratedKeyStrategy = &lastInsertIDKeyStrategy{}
+
+type lastInsertIDKeyStrategy struct{}
+
+// Insert executes insert statement, driver result provides generated key
+func (s *lastInsertIDKeyStrategy) Insert(manager Manager, connection Connection, table string, SQL string, parameters []interface{}) (sql.Result, error) {
+	return manager.ExecuteOnConnection(connection, SQL, parameters)
+}
+
+// NewReturningKeyStrategy returns strategy appending RETURNING <pk> clause to insert statement (i.e. postgres, sqlite)
+func NewReturningKeyStrategy() GeneratedKeyStrategy {
+	return GeneratedKeyStrategyFunc(func(manager Manager, connection Connection, table string, SQL string, parameters []interface{}) (sql.Result, error) {
+		column, err := generatedKeyColumn(manager, table)
+		if err != nil {
+			return nil, err
+		}
+		return readGeneratedKey(manager, connection, SQL+" RETURNING "+column, parameters)
+	})
+}
+
+// NewOutputKeyStrategy returns strategy adding OUTPUT INSERTED.<pk> clause to insert statement (i.e. sqlserver)
+func NewOutputKeyStrategy() GeneratedKeyStrategy {
+	return GeneratedKeyStrategyFunc(func(manager Manager, connection Connection, table string, SQL string, parameters []interface{}) (sql.Result, error) {
+		column, err := generatedKeyColumn(manager, table)
+		if err != nil {
+			return nil, err
+		}
+		index := strings.Index(strings.ToUpper(SQL), " VALUES")
+		if index == -1 {
+			return nil, fmt.Errorf("failed to add OUTPUT clause, VALUES was missing in %v", SQL)
+		}
+		return readGeneratedKey(manager, connection, SQL[:index]+" OUTPUT INSERTED."+column+SQL[index:], parameters)
+	})
+}
+
+// NewSequenceKeyStrategy returns strategy executing insert followed by sequence query on the same connection,
+// ${table} and ${column} placeholders are replaced with table and its pk column, i.e. SELECT currval(pg_get_serial_sequence('${table}', '${column}'))
+func NewSequenceKeyStrategy(query string) GeneratedKeyStrategy {
+	return GeneratedKeyStrategyFunc(func(manager Manager, connection Connection, table string, SQL string, parameters []interface{}) (sql.Result, error) {
+		column, err := generatedKeyColumn(manager, table)
+		if err != nil {
+			return nil, err
+		}
+		result, err := manager.ExecuteOnConnection(connection, SQL, parameters)
+		if err != nil {
+			return nil, err
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return nil, err
+		}
+		keyQuery := strings.NewReplacer("${table}", table, "${column}", column).Replace(query)
+		keyResult, err := readGeneratedKey(manager, connection, keyQuery, nil)
+		if err != nil {
+			return nil, err
+		}
+		key, _ := keyResult.LastInsertId()
+		return NewSQLResult(affected, key), nil
+	})
+}
+
+func generatedKeyColumn(manager Manager, table string) (string, error) {
+	descriptor := manager.TableDescriptorRegistry().Get(table)
+	if descriptor == nil || len(descriptor.PkColumns) == 0 {
+		return "", fmt.Errorf("failed to lookup generated key column, primary key was empty for %v", table)
+	}
+	return descriptor.PkColumns[0], nil
+}
+
+func readGeneratedKey(manager Manager, connection Connection, SQL string, parameters []interface{}) (sql.Result, error) {
+	var key int64
+	var affected int64
+	err := manager.ReadAllOnWithHandlerOnConnection(connection, SQL, parameters, func(scanner Scanner) (bool, error) {
+		affected++
+		return true, scanner.Scan(&key)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read generated key due to %v", err)
+	}
+	return NewSQLResult(affected, key), nil
+}
+
+var generatedKeyStrategyRegistry = make(map[string]GeneratedKeyStrategy)
+var generatedKeyStrategyMutex = &sync.RWMutex{}
+
+// RegisterGeneratedKeyStrategy registers generated key strategy for a driver, nil strategy restores the dialect default
+func RegisterGeneratedKeyStrategy(driver string, strategy GeneratedKeyStrategy) {
+	generatedKeyStrategyMutex.Lock()
+	defer generatedKeyStrategyMutex.Unlock()
+	if strategy == nil {
+		delete(generatedKeyStrategyRegistry, driver)
+		return
+	}
+	generatedKeyStrategyRegistry[driver] = strategy
+}
+
+// GetGeneratedKeyStrategy returns strategy registered for a driver, driver dialect if it implements GeneratedKeyStrategy, or LastInsertIDKeyStrategy
+func GetGeneratedKeyStrategy(driver string) GeneratedKeyStrategy {
+	generatedKeyStrategyMutex.RLock()
+	strategy, ok := generatedKeyStrategyRegistry[driver]
+	generatedKeyStrategyMutex.RUnlock()
+	if ok {
+		return strategy
+	}
+	if strategy, ok := GetDatastoreDialect(driver).(GeneratedKeyStrategy); ok {
+		return strategy
+	}
+	return LastInsertIDKeyStrategy
+}
@@ -0,0 +1,48 @@
+package dsc_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/dsc"
+)
+
+type keyStrategyItem struct {
+	Id   string `autoincrement:"true"`
+	Name string
+}
+
+func TestGeneratedKeyStrategy(t *testing.T) {
+	manager := GetManager(t)
+	assert.NotNil(t, dsc.GetGeneratedKeyStrategy("sqlite3"))
+
+	var statements = make([]string, 0)
+	returning := dsc.NewReturningKeyStrategy()
+	dsc.RegisterGeneratedKeyStrategy("sqlite3", dsc.GeneratedKeyStrategyFunc(func(manager dsc.Manager, connection dsc.Connection, table string, SQL string, parameters []interface{}) (sql.Result, error) {
+		statements = append(statements, SQL)
+		return returning.Insert(manager, connection, table, SQL, parameters)
+	}))
+	defer dsc.RegisterGeneratedKeyStrategy("sqlite3", nil)
+
+	users := []User{{Username: "Bogi"}, {Username: "Kiki"}}
+	inserted, _, err := manager.PersistAll(&users, "users", nil)
+	if assert.Nil(t, err) {
+		assert.Equal(t, 2, inserted)
+		assert.Equal(t, 2, users[0].Id)
+		assert.Equal(t, 3, users[1].Id)
+		assert.Equal(t, 2, len(statements))
+	}
+
+	dsc.RegisterGeneratedKeyStrategy("sqlite3", dsc.NewSequenceKeyStrategy("SELECT MAX(${column}) FROM ${table}"))
+	for _, SQL := range []string{"DROP TABLE IF EXISTS items", "CREATE TABLE items(Id INTEGER PRIMARY KEY AUTOINCREMENT, Name TEXT)"} {
+		_, err = manager.Execute(SQL)
+		assert.Nil(t, err)
+	}
+	item := keyStrategyItem{Name: "abc"}
+	inserted, _, err = manager.PersistSingle(&item, "items", nil)
+	if assert.Nil(t, err) {
+		assert.Equal(t, 1, inserted)
+		assert.Equal(t, "1", item.Id)
+	}
+}
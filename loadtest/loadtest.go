@@ -0,0 +1,270 @@
+// Package loadtest drives configurable read/write mixes against a dsc Manager and reports throughput and latency percentiles.
+package loadtest
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/viant/dsc"
+)
+
+// Operation represents a weighted unit of work executed against a manager
+type Operation struct {
+	Name string
+	//Weight relative frequency of the operation in the mix
+	Weight int
+	Run    func(ctx context.Context, manager dsc.Manager, random *rand.Rand) error
+}
+
+// NewReadOperation returns operation reading all rows of passed in query, parameters may be nil or produce per call parameters
+func NewReadOperation(name string, weight int, SQL string, parameters func(random *rand.Rand) []interface{}) *Operation {
+	return &Operation{
+		Name:   name,
+		Weight: weight,
+		Run: func(ctx context.Context, manager dsc.Manager, random *rand.Rand) error {
+			var sqlParameters []interface{}
+			if parameters != nil {
+				sqlParameters = parameters(random)
+			}
+			return manager.ReadAllWithHandler(SQL, sqlParameters, func(scanner dsc.Scanner) (bool, error) {
+				return true, nil
+			})
+		},
+	}
+}
+
+// NewWriteOperation returns operation executing passed in DML, parameters may be nil or produce per call parameters
+func NewWriteOperation(name string, weight int, SQL string, parameters func(random *rand.Rand) []interface{}) *Operation {
+	return &Operation{
+		Name:   name,
+		Weight: weight,
+		Run: func(ctx context.Context, manager dsc.Manager, random *rand.Rand) error {
+			var sqlParameters []interface{}
+			if parameters != nil {
+				sqlParameters = parameters(random)
+			}
+			_, err := manager.Execute(SQL, sqlParameters...)
+			return err
+		},
+	}
+}
+
+// Config represents load test settings
+type Config struct {
+	//Concurrency number of workers
+	Concurrency int
+	//RampUp duration over which workers are started evenly
+	RampUp time.Duration
+	//Duration total test duration including ramp up, zero means until Requests are executed or context is done
+	Duration time.Duration
+	//Requests max number of operations executed by all workers, zero means no limit
+	Requests int
+	//Seed seeds operations selection, each worker uses Seed + worker index
+	Seed       int64
+	Operations []*Operation
+}
+
+// Validate checks config settings
+func (c *Config) Validate() error {
+	if len(c.Operations) == 0 {
+		return fmt.Errorf("operations were empty")
+	}
+	if c.Duration <= 0 && c.Requests <= 0 {
+		return fmt.Errorf("either duration or requests has to be set")
+	}
+	for _, operation := range c.Operations {
+		if operation.Run == nil {
+			return fmt.Errorf("operation %v run was nil", operation.Name)
+		}
+		if operation.Weight < 0 {
+			return fmt.Errorf("operation %v weight was negative", operation.Name)
+		}
+	}
+	return nil
+}
+
+// Stats represents latency statistics of an operation
+type Stats struct {
+	Name   string
+	Count  int
+	Errors int
+	//LastError last error message reported by the operation
+	LastError string `json:",omitempty"`
+	Min       time.Duration
+	Max       time.Duration
+	Mean      time.Duration
+	P50       time.Duration
+	P90       time.Duration
+	P95       time.Duration
+	P99       time.Duration
+}
+
+// Report represents load test result
+type Report struct {
+	Duration time.Duration
+	Count    int
+	Errors   int
+	//Throughput operations per second
+	Throughput float64
+	Total      *Stats
+	Operations []*Stats
+}
+
+// Percentile returns nearest rank percentile of sorted latencies
+func Percentile(sorted []time.Duration, percentile float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(percentile/100*float64(len(sorted))+0.5) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	if rank >= len(sorted) {
+		rank = len(sorted) - 1
+	}
+	return sorted[rank]
+}
+
+func newStats(name string, latencies []time.Duration, errors int, lastError string) *Stats {
+	result := &Stats{Name: name, Count: len(latencies), Errors: errors, LastError: lastError}
+	if len(latencies) == 0 {
+		return result
+	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	var total time.Duration
+	for _, latency := range latencies {
+		total += latency
+	}
+	result.Min = latencies[0]
+	result.Max = latencies[len(latencies)-1]
+	result.Mean = total / time.Duration(len(latencies))
+	result.P50 = Percentile(latencies, 50)
+	result.P90 = Percentile(latencies, 90)
+	result.P95 = Percentile(latencies, 95)
+	result.P99 = Percentile(latencies, 99)
+	return result
+}
+
+type operationResult struct {
+	latencies []time.Duration
+	errors    int
+	lastError string
+}
+
+type worker struct {
+	random  *rand.Rand
+	results []*operationResult
+}
+
+func (w *worker) pick(operations []*Operation, totalWeight int) int {
+	if totalWeight == 0 {
+		return w.random.Intn(len(operations))
+	}
+	value := w.random.Intn(totalWeight)
+	for i, operation := range operations {
+		if value < operation.Weight {
+			return i
+		}
+		value -= operation.Weight
+	}
+	return len(operations) - 1
+}
+
+// Run executes load test against passed in manager and returns report, ramp up starts workers evenly over config.RampUp
+func Run(ctx context.Context, manager dsc.Manager, config *Config) (*Report, error) {
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid load test config due to %v", err)
+	}
+	concurrency := config.Concurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	if config.Duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, config.Duration)
+		defer cancel()
+	}
+	totalWeight := 0
+	for _, operation := range config.Operations {
+		totalWeight += operation.Weight
+	}
+	var remaining = int64(config.Requests)
+	var mutex = &sync.Mutex{}
+	take := func() bool {
+		if config.Requests <= 0 {
+			return true
+		}
+		mutex.Lock()
+		defer mutex.Unlock()
+		if remaining == 0 {
+			return false
+		}
+		remaining--
+		return true
+	}
+
+	workers := make([]*worker, concurrency)
+	waitGroup := &sync.WaitGroup{}
+	started := time.Now()
+	for i := range workers {
+		workers[i] = &worker{random: rand.New(rand.NewSource(config.Seed + int64(i))), results: make([]*operationResult, len(config.Operations))}
+		for j := range config.Operations {
+			workers[i].results[j] = &operationResult{}
+		}
+		delay := time.Duration(0)
+		if config.RampUp > 0 {
+			delay = config.RampUp * time.Duration(i) / time.Duration(concurrency)
+		}
+		waitGroup.Add(1)
+		go func(w *worker, delay time.Duration) {
+			defer waitGroup.Done()
+			if delay > 0 {
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(delay):
+				}
+			}
+			for ctx.Err() == nil && take() {
+				index := w.pick(config.Operations, totalWeight)
+				result := w.results[index]
+				start := time.Now()
+				err := config.Operations[index].Run(ctx, manager, w.random)
+				result.latencies = append(result.latencies, time.Since(start))
+				if err != nil {
+					result.errors++
+					result.lastError = err.Error()
+				}
+			}
+		}(workers[i], delay)
+	}
+	waitGroup.Wait()
+
+	report := &Report{Duration: time.Since(started), Operations: make([]*Stats, 0, len(config.Operations))}
+	var all = make([]time.Duration, 0)
+	for j, operation := range config.Operations {
+		var latencies = make([]time.Duration, 0)
+		var errors = 0
+		var lastError = ""
+		for _, w := range workers {
+			latencies = append(latencies, w.results[j].latencies...)
+			errors += w.results[j].errors
+			if w.results[j].lastError != "" {
+				lastError = w.results[j].lastError
+			}
+		}
+		all = append(all, latencies...)
+		report.Errors += errors
+		report.Operations = append(report.Operations, newStats(operation.Name, latencies, errors, lastError))
+	}
+	report.Count = len(all)
+	report.Total = newStats("total", all, report.Errors, "")
+	if seconds := report.Duration.Seconds(); seconds > 0 {
+		report.Throughput = float64(report.Count) / seconds
+	}
+	return report, nil
+}
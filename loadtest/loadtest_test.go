@@ -0,0 +1,64 @@
+package loadtest_test
+
+import (
+	"context"
+	"math/rand"
+	"path/filepath"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/dsc"
+	"github.com/viant/dsc/loadtest"
+)
+
+func TestRun(t *testing.T) {
+	config := dsc.NewConfig("sqlite3", "[url]", "url:"+filepath.Join(t.TempDir(), "load.db"))
+	manager, err := dsc.NewManagerFactory().Create(config)
+	if !assert.Nil(t, err) {
+		return
+	}
+	_, err = manager.Execute("CREATE TABLE events(id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT)")
+	if !assert.Nil(t, err) {
+		return
+	}
+	report, err := loadtest.Run(context.Background(), manager, &loadtest.Config{
+		Concurrency: 2,
+		RampUp:      10 * time.Millisecond,
+		Requests:    50,
+		Seed:        1,
+		Operations: []*loadtest.Operation{
+			loadtest.NewReadOperation("read", 3, "SELECT id, name FROM events WHERE id > ?", func(random *rand.Rand) []interface{} {
+				return []interface{}{random.Intn(10)}
+			}),
+			loadtest.NewWriteOperation("write", 1, "INSERT INTO events(name) VALUES(?)", func(random *rand.Rand) []interface{} {
+				return []interface{}{"event"}
+			}),
+			loadtest.NewReadOperation("broken", 0, "SELECT * FROM missing", nil),
+		},
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, 50, report.Count)
+	assert.Equal(t, 0, report.Errors, "zero weight operation should not run")
+	assert.Equal(t, "read", report.Operations[0].Name)
+	assert.True(t, report.Operations[0].Count > report.Operations[1].Count)
+	assert.True(t, report.Total.P50 <= report.Total.P99)
+	assert.True(t, report.Throughput > 0)
+
+	_, err = loadtest.Run(context.Background(), manager, &loadtest.Config{})
+	assert.NotNil(t, err)
+}
+
+func TestPercentile(t *testing.T) {
+	var latencies []time.Duration
+	for i := 1; i <= 100; i++ {
+		latencies = append(latencies, time.Duration(i))
+	}
+	assert.EqualValues(t, 50, loadtest.Percentile(latencies, 50))
+	assert.EqualValues(t, 99, loadtest.Percentile(latencies, 99))
+	assert.EqualValues(t, 100, loadtest.Percentile(latencies, 100))
+	assert.EqualValues(t, 0, loadtest.Percentile(nil, 50))
+}
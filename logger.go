@@ -1,6 +1,17 @@
 package dsc
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+const (
+	slowQueryThresholdMsKey = "slowQueryThresholdMs"
+	redactParametersKey     = "redactParameters"
+	redactedParameter       = "[redacted]"
+)
 
 //Log represent log function
 type Log func(format string, args ...interface{})
@@ -17,3 +28,85 @@ func VoidLogger(format string, args ...interface{}) {
 func StdoutLogger(format string, args ...interface{}) {
 	fmt.Print(fmt.Sprintf(format, args...) + "\n")
 }
+
+//QueryEvent represents executed statement details passed to QueryLogger
+type QueryEvent struct {
+	SQL        string
+	Parameters []interface{}
+	Duration   time.Duration
+	//RowsAffected affected rows for DML or fetched rows for queries, -1 if unknown
+	RowsAffected int64
+	//Slow is set when duration reached slowQueryThresholdMs config parameter
+	Slow  bool
+	Error error
+}
+
+//QueryLogger represents structured query logger called by manager for each executed statement
+type QueryLogger interface {
+	LogQuery(event *QueryEvent)
+}
+
+//QueryLoggerFunc represents function adapter for QueryLogger
+type QueryLoggerFunc func(event *QueryEvent)
+
+//LogQuery calls the function
+func (f QueryLoggerFunc) LogQuery(event *QueryEvent) {
+	f(event)
+}
+
+type slogQueryLogger struct {
+	logger *slog.Logger
+}
+
+//LogQuery logs failed statements at error, slow statements at warn and other statements at debug level
+func (l *slogQueryLogger) LogQuery(event *QueryEvent) {
+	level := slog.LevelDebug
+	message := "query"
+	if event.Slow {
+		level = slog.LevelWarn
+		message = "slow query"
+	}
+	if event.Error != nil {
+		level = slog.LevelError
+		message = "query failed"
+	}
+	attributes := []slog.Attr{
+		slog.String("sql", event.SQL),
+		slog.Any("parameters", event.Parameters),
+		slog.Duration("duration", event.Duration),
+		slog.Int64("rows", event.RowsAffected),
+	}
+	if event.Error != nil {
+		attributes = append(attributes, slog.String("error", event.Error.Error()))
+	}
+	l.logger.LogAttrs(context.Background(), level, message, attributes...)
+}
+
+//NewSlogQueryLogger returns QueryLogger writing to passed in slog logger, slog.Default() is used when logger is nil
+func NewSlogQueryLogger(logger *slog.Logger) QueryLogger {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &slogQueryLogger{logger: logger}
+}
+
+//logQuery passes statement details to config query logger, when slowQueryThresholdMs is set only slow or failed statements are logged
+func logQuery(config *Config, SQL string, parameters []interface{}, started time.Time, rows int64, err error) {
+	if config.QueryLogger == nil {
+		return
+	}
+	event := &QueryEvent{SQL: SQL, Parameters: parameters, Duration: time.Since(started), RowsAffected: rows, Error: err}
+	if threshold := config.GetDuration(slowQueryThresholdMsKey, time.Millisecond, 0); threshold > 0 {
+		event.Slow = event.Duration >= threshold
+		if !event.Slow && err == nil {
+			return
+		}
+	}
+	if config.GetBoolean(redactParametersKey, false) {
+		event.Parameters = make([]interface{}, len(parameters))
+		for i := range parameters {
+			event.Parameters[i] = redactedParameter
+		}
+	}
+	config.QueryLogger.LogQuery(event)
+}
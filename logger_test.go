@@ -0,0 +1,62 @@
+package dsc_test
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/dsc"
+)
+
+func TestQueryLogger(t *testing.T) {
+	var events = make([]*dsc.QueryEvent, 0)
+	config := dsc.NewConfig("sqlite3", "[url]", "url:./test/logger.db,redactParameters:true")
+	config.QueryLogger = dsc.QueryLoggerFunc(func(event *dsc.QueryEvent) {
+		events = append(events, event)
+	})
+	manager, err := dsc.NewManagerFactory().Create(config)
+	if !assert.Nil(t, err) {
+		return
+	}
+	events = events[:0]
+	_, err = manager.Execute("DROP TABLE IF EXISTS logged")
+	assert.Nil(t, err)
+	_, err = manager.Execute("CREATE TABLE logged(id INTEGER, name TEXT)")
+	assert.Nil(t, err)
+	_, err = manager.Execute("INSERT INTO logged(id, name) VALUES(?, ?)", 1, "secret")
+	assert.Nil(t, err)
+	var records = make([]map[string]interface{}, 0)
+	err = manager.ReadAll(&records, "SELECT id, name FROM logged WHERE id = ?", []interface{}{1}, nil)
+	assert.Nil(t, err)
+	_, err = manager.Execute("INSERT INTO missing(id) VALUES(1)")
+	assert.NotNil(t, err)
+
+	if !assert.Equal(t, 5, len(events)) {
+		return
+	}
+	assert.EqualValues(t, 1, events[2].RowsAffected)
+	assert.Equal(t, []interface{}{"[redacted]", "[redacted]"}, events[2].Parameters)
+	assert.EqualValues(t, 1, events[3].RowsAffected, "read should report fetched rows")
+	assert.True(t, strings.HasPrefix(events[3].SQL, "SELECT"))
+	assert.NotNil(t, events[4].Error)
+	assert.EqualValues(t, -1, events[4].RowsAffected)
+
+	events = events[:0]
+	config.Parameters["slowQueryThresholdMs"] = 60000
+	_, err = manager.Execute("SELECT 1")
+	assert.Nil(t, err)
+	_, _ = manager.Execute("SELECT * FROM missing")
+	if assert.Equal(t, 1, len(events), "only slow or failed statements should be logged") {
+		assert.NotNil(t, events[0].Error)
+	}
+
+	buffer := new(bytes.Buffer)
+	config.QueryLogger = dsc.NewSlogQueryLogger(slog.New(slog.NewTextHandler(buffer, nil)))
+	config.Parameters["slowQueryThresholdMs"] = 0
+	_, _ = manager.Execute("SELECT * FROM missing")
+	assert.Contains(t, buffer.String(), "level=ERROR")
+	assert.Contains(t, buffer.String(), "query failed")
+}
@@ -1,6 +1,7 @@
 package dsc
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
@@ -25,10 +26,14 @@ type AbstractManager struct {
 	connectionProvider      ConnectionProvider
 	tableDescriptorRegistry TableDescriptorRegistry
 	limiter                 *Limiter
+	stats                   *managerStats
 }
 
-// Config returns a config.
+// Config returns a config, it is connection provider config that is replaced as a whole on reload
 func (m *AbstractManager) Config() *Config {
+	if m.connectionProvider != nil {
+		return m.connectionProvider.Config()
+	}
 	return m.config
 }
 
@@ -37,6 +42,20 @@ func (m *AbstractManager) ConnectionProvider() ConnectionProvider {
 	return m.connectionProvider
 }
 
+// Close shuts down connection provider, in-flight operations are awaited up to shutdownTimeoutMs (30 sec by default),
+// provider not implementing ShutdownConnectionProvider is closed right away.
+func (m *AbstractManager) Close() error {
+	provider := m.Manager.ConnectionProvider()
+	shutdownProvider, ok := provider.(ShutdownConnectionProvider)
+	if !ok {
+		return provider.Close()
+	}
+	timeout := m.Config().GetDuration(shutdownTimeoutMsKey, time.Millisecond, defaultShutdownTimeoutMs*time.Millisecond)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return shutdownProvider.Shutdown(ctx)
+}
+
 // Execute executes passed in sql with parameters.  It returns sql result, or an error.
 func (m *AbstractManager) Execute(sql string, sqlParameters ...interface{}) (result sql.Result, err error) {
 	var connection Connection
@@ -58,9 +77,69 @@ func (m *AbstractManager) ExecuteAll(sqls []string) ([]sql.Result, error) {
 	return m.Manager.ExecuteAllOnConnection(connection, sqls)
 }
 
+// ExecuteAllWithOptions executes passed in SQL in one transaction or in batches committed separately, result reports rows affected by each statement,
+// index of the first failing statement and number of committed statements, statement failure is returned as *StatementError
+func (m *AbstractManager) ExecuteAllWithOptions(sqls []string, options *ExecuteOptions) (*ExecuteAllResult, error) {
+	var executeOptions = ExecuteOptions{}
+	if options != nil {
+		executeOptions = *options
+	}
+	batchSize := len(sqls)
+	if !executeOptions.Transactional {
+		if batchSize = executeOptions.BatchSize; batchSize <= 0 {
+			batchSize = m.Config().GetInt(BatchSizeKey, defaultBatchSize)
+		}
+	}
+	var result = &ExecuteAllResult{RowsAffected: make([]int64, 0, len(sqls)), FailedIndex: -1}
+	connection, err := m.Manager.ConnectionProvider().Get()
+	if err != nil {
+		return result, err
+	}
+	defer connection.Close()
+	for offset := 0; offset < len(sqls); offset += batchSize {
+		limit := offset + batchSize
+		if limit > len(sqls) {
+			limit = len(sqls)
+		}
+		if err = m.executeBatch(connection, sqls, offset, limit, result); err != nil {
+			return result, err
+		}
+		result.Committed = limit
+	}
+	return result, nil
+}
+
+// executeBatch executes statements from offset to limit in one transaction
+func (m *AbstractManager) executeBatch(connection Connection, sqls []string, offset, limit int, result *ExecuteAllResult) error {
+	if err := connection.Begin(); err != nil {
+		return fmt.Errorf("failed to start transaction on %v due to %v", m.Config().Descriptor, err)
+	}
+	for i := offset; i < limit; i++ {
+		sqlResult, err := m.Manager.ExecuteOnConnection(connection, sqls[i], nil)
+		if err == nil {
+			affected, affectedErr := sqlResult.RowsAffected()
+			if affectedErr != nil {
+				affected = -1
+			}
+			result.RowsAffected = append(result.RowsAffected, affected)
+			continue
+		}
+		result.FailedIndex = i
+		if rollbackErr := connection.Rollback(); rollbackErr != nil {
+			return fmt.Errorf("failed to rollback on %v due to %v, %v", m.Config().Descriptor, err, rollbackErr)
+		}
+		return &StatementError{Index: i, SQL: sqls[i], Err: err}
+	}
+	if err := connection.Commit(); err != nil {
+		result.FailedIndex = limit - 1
+		return fmt.Errorf("failed to commit on %v due to %v", m.Config().Descriptor, err)
+	}
+	return nil
+}
+
 // Acquire if max request per second is specified this function will throttle any request exceeding specified max
 func (m *AbstractManager) Acquire() {
-	if m.config.MaxRequestPerSecond == 0 {
+	if m.Config().MaxRequestPerSecond == 0 {
 		return
 	}
 
@@ -84,7 +163,6 @@ func (m *AbstractManager) ExecuteAllOnConnection(connection Connection, sqls []s
 		}
 	}()
 	for i, sql := range sqls {
-		var err error
 		result[i], err = m.Manager.ExecuteOnConnection(connection, sql, nil)
 		if err != nil {
 			return result, err
@@ -116,12 +194,18 @@ func (m AbstractManager) ReadAll(resultSlicePointer interface{}, query string, q
 
 // ReadAllOnConnection executes query with parameters on passed in connection and fetches all table rows. The row is mapped to result slice pointer with record mapper.
 func (m *AbstractManager) ReadAllOnConnection(connection Connection, resultSlicePointer interface{}, query string, queryParameters []interface{}, mapper RecordMapper) error {
+	queryParameters = withStructNullPolicies(queryParameters, reflect.TypeOf(resultSlicePointer).Elem().Elem())
+	return m.Manager.ReadAllOnWithHandlerOnConnection(connection, query, queryParameters, newSliceMappingHandler(resultSlicePointer, query, mapper))
+}
+
+// newSliceMappingHandler returns reading handler appending each mapped row to result slice pointer.
+func newSliceMappingHandler(resultSlicePointer interface{}, query interface{}, mapper RecordMapper) func(scanner Scanner) (toContinue bool, err error) {
 	toolbox.AssertPointerKind(resultSlicePointer, reflect.Slice, "resultSlicePointer")
 	slice := reflect.ValueOf(resultSlicePointer).Elem()
 	if mapper == nil {
 		mapper = NewRecordMapperIfNeeded(mapper, reflect.TypeOf(resultSlicePointer).Elem().Elem())
 	}
-	err := m.Manager.ReadAllOnWithHandlerOnConnection(connection, query, queryParameters, func(scannalbe Scanner) (toContinue bool, err error) {
+	return func(scannalbe Scanner) (toContinue bool, err error) {
 		mapped, providerError := mapper.Map(scannalbe)
 		if providerError != nil {
 			return false, fmt.Errorf("failed to map row sql: %v  due to %v", query, providerError.Error())
@@ -137,8 +221,45 @@ func (m *AbstractManager) ReadAllOnConnection(connection Connection, resultSlice
 			slice.Set(reflect.Append(slice, mappedValue))
 		}
 		return true, nil
-	})
-	return err
+	}
+}
+
+// ExecuteNative executes backend specific query document with the native client obtained with connection Unwrap. It returns sql result, or an error.
+func (m *AbstractManager) ExecuteNative(query interface{}) (sql.Result, error) {
+	connection, err := m.Manager.ConnectionProvider().Get()
+	if err != nil {
+		return nil, err
+	}
+	defer connection.Close()
+	return m.nativeManager().ExecuteNativeOnConnection(connection, query)
+}
+
+// nativeManager returns manager implementing native queries, or abstract manager if it does not implement NativeManager
+func (m *AbstractManager) nativeManager() NativeManager {
+	if native, ok := m.Manager.(NativeManager); ok {
+		return native
+	}
+	return m
+}
+
+// ExecuteNativeOnConnection returns unsupported operation error, datastore specific manager needs to implement it to support native queries.
+func (m *AbstractManager) ExecuteNativeOnConnection(connection Connection, query interface{}) (sql.Result, error) {
+	return nil, fmt.Errorf("failed to execute native query %T due to %v", query, errUnsupportedOperation)
+}
+
+// ReadAllNative executes backend specific query document and fetches all records. Each record is mapped to result slice pointer with record mapper.
+func (m *AbstractManager) ReadAllNative(resultSlicePointer interface{}, query interface{}, mapper RecordMapper) error {
+	connection, err := m.Manager.ConnectionProvider().Get()
+	if err != nil {
+		return err
+	}
+	defer connection.Close()
+	return m.nativeManager().ReadAllNativeWithHandlerOnConnection(connection, query, newSliceMappingHandler(resultSlicePointer, query, mapper))
+}
+
+// ReadAllNativeWithHandlerOnConnection returns unsupported operation error, datastore specific manager needs to implement it to support native queries.
+func (m *AbstractManager) ReadAllNativeWithHandlerOnConnection(connection Connection, query interface{}, readingHandler func(scanner Scanner) (toContinue bool, err error)) error {
+	return fmt.Errorf("failed to read native query %T due to %v", query, errUnsupportedOperation)
 }
 
 // ReadSingle executes query with parameters and reads on connection single table row. The row is mapped to result pointer with record mapper.
@@ -153,14 +274,20 @@ func (m *AbstractManager) ReadSingle(resultPointer interface{}, query string, qu
 
 // ReadSingleOnConnection executes query with parameters on passed in connection and reads single table row. The row is mapped to result pointer with record mapper.
 func (m *AbstractManager) ReadSingleOnConnection(connection Connection, resultPointer interface{}, query string, queryParameters []interface{}, mapper RecordMapper) (success bool, err error) {
+	queryParameters = withStructNullPolicies(queryParameters, reflect.TypeOf(resultPointer).Elem())
+	err = m.Manager.ReadAllOnWithHandlerOnConnection(connection, query, queryParameters, newSingleMappingHandler(resultPointer, query, mapper, &success))
+	return success, err
+}
+
+// newSingleMappingHandler returns reading handler mapping first row to result pointer, success is set once the row was mapped.
+func newSingleMappingHandler(resultPointer interface{}, query string, mapper RecordMapper, success *bool) func(scanner Scanner) (toContinue bool, err error) {
 	toolbox.AssertKind(resultPointer, reflect.Ptr, "resultStruct")
 	if mapper == nil {
 		mapper = NewRecordMapperIfNeeded(mapper, reflect.TypeOf(resultPointer).Elem())
 	}
-	var mapped interface{}
 	var elementType = reflect.TypeOf(resultPointer).Elem()
-	err = m.Manager.ReadAllOnWithHandlerOnConnection(connection, query, queryParameters, func(scanner Scanner) (toContinue bool, err error) {
-		mapped, err = mapper.Map(scanner)
+	return func(scanner Scanner) (toContinue bool, err error) {
+		mapped, err := mapper.Map(scanner)
 		if err != nil {
 			return false, fmt.Errorf("failed to map record: %v with %T due to %v", query, mapper, err)
 		}
@@ -190,12 +317,11 @@ func (m *AbstractManager) ReadSingleOnConnection(connection Connection, resultPo
 				}
 				reflect.ValueOf(resultPointer).Elem().Set(reflect.ValueOf(mapped))
 			}
-			success = true
+			*success = true
 		}
 
 		return false, nil
-	})
-	return success, err
+	}
 }
 
 // PersistAll persists all table rows, dmlProvider is used to generate insert or update statement. It returns number of inserted, updated or error.
@@ -209,18 +335,18 @@ func (m *AbstractManager) PersistAll(dataPointer interface{}, table string, prov
 
 	err = connection.Begin()
 	if err != nil {
-		return 0, 0, fmt.Errorf("failed to start transaction on %v due to %v", m.config.Descriptor, err)
+		return 0, 0, fmt.Errorf("failed to start transaction on %v due to %v", m.Config().Descriptor, err)
 	}
 	inserted, updated, err := m.Manager.PersistAllOnConnection(connection, dataPointer, table, provider)
 	if err == nil {
 		commitErr := connection.Commit()
 		if commitErr != nil {
-			return 0, 0, fmt.Errorf("failed to commit on %v due to %v", m.config.Descriptor, commitErr)
+			return 0, 0, fmt.Errorf("failed to commit on %v due to %v", m.Config().Descriptor, commitErr)
 		}
 	} else {
 		rollbackErr := connection.Rollback()
 		if rollbackErr != nil {
-			return 0, 0, fmt.Errorf("failed to rollback on %v due to %v, %v", m.config.Descriptor, err, rollbackErr)
+			return 0, 0, fmt.Errorf("failed to rollback on %v due to %v, %v", m.Config().Descriptor, err, rollbackErr)
 		}
 	}
 	return inserted, updated, err
@@ -244,7 +370,9 @@ func (m *AbstractManager) RegisterDescriptorIfNeeded(table string, instance inte
 
 // PersistAllOnConnection persists on connection all table rows, dmlProvider is used to generate insert or update statement. It returns number of inserted, updated or error.
 func (m *AbstractManager) PersistAllOnConnection(connection Connection, dataPointer interface{}, table string, provider DmlProvider) (inserted int, updated int, err error) {
-
+	defer func(started time.Time) {
+		m.recordOperation("persist", started, err)
+	}(time.Now())
 	if ranger, isRanger := dataPointer.(toolbox.Ranger); isRanger {
 		collection := toolbox.AsSlice(ranger)
 		dataPointer = &collection
@@ -255,14 +383,23 @@ func (m *AbstractManager) PersistAllOnConnection(connection Connection, dataPoin
 
 	toolbox.AssertPointerKind(dataPointer, reflect.Slice, "resultSlicePointer")
 	structType := reflect.TypeOf(dataPointer).Elem().Elem()
-	provider, err = NewDmlProviderIfNeeded(provider, table, structType)
+	var isManagerProvider = provider == nil
+	provider, err = newDmlProviderIfNeeded(provider, table, structType, identifierQuoter(m.Config()))
 	if err != nil {
 		return 0, 0, err
 	}
+	if isManagerProvider {
+		if err = applyZeroValuePolicy(m.Config(), provider); err != nil {
+			return 0, 0, err
+		}
+	}
 	descriptor, err := m.RegisterDescriptorIfNeeded(table, dataPointer)
 	if err != nil {
 		return 0, 0, err
 	}
+	if err = generateKeys(m.Manager, descriptor, dataPointer); err != nil {
+		return 0, 0, fmt.Errorf("failed to generate %v keys due to %v", table, err)
+	}
 	insertables, updatables, err := m.Manager.ClassifyDataAsInsertableOrUpdatable(connection, dataPointer, table, provider)
 	if err != nil {
 		return 0, 0, err
@@ -292,8 +429,9 @@ func (m *AbstractManager) PersistAllOnConnection(connection Connection, dataPoin
 		})
 	}
 
+	getSQL := m.parametrizedSQLProvider(provider)
 	inserted, insertErr := m.Manager.PersistData(connection, insertables, table, provider, func(item interface{}) *ParametrizedSQL {
-		return provider.Get(SQLTypeInsert, item)
+		return getSQL(SQLTypeInsert, item)
 	})
 
 	if insertErr != nil {
@@ -307,7 +445,7 @@ func (m *AbstractManager) PersistAllOnConnection(connection Connection, dataPoin
 	}
 
 	updated, updateErr := m.Manager.PersistData(connection, updatables, table, provider, func(item interface{}) *ParametrizedSQL {
-		return provider.Get(SQLTypeUpdate, item)
+		return getSQL(SQLTypeUpdate, item)
 	})
 
 	if updateErr != nil {
@@ -317,6 +455,18 @@ func (m *AbstractManager) PersistAllOnConnection(connection Connection, dataPoin
 	return inserted, updated, nil
 }
 
+// parametrizedSQLProvider returns provider SQL function, audited providers are passed actor of config actor provider
+func (m *AbstractManager) parametrizedSQLProvider(provider DmlProvider) func(sqlType int, item interface{}) *ParametrizedSQL {
+	audited, ok := provider.(AuditedDmlProvider)
+	if !ok || m.Config().ActorProvider == nil {
+		return provider.Get
+	}
+	actor := m.Config().ActorProvider.Actor()
+	return func(sqlType int, item interface{}) *ParametrizedSQL {
+		return audited.GetAudited(sqlType, item, actor)
+	}
+}
+
 // PersistSingle persists single table row, dmlProvider is used to generate insert or update statement. It returns number of inserted, updated or error.
 func (m *AbstractManager) PersistSingle(dataPointer interface{}, table string, provider DmlProvider) (inserted int, updated int, err error) {
 	slice := convertToTypesSlice(dataPointer)
@@ -424,6 +574,7 @@ func (m *AbstractManager) fetchExistingData(connection Connection, table string,
 	if len(pkValues) > 0 {
 		descriptor := TableDescriptor{Table: table, PkColumns: descriptor.PkColumns}
 		sqlBuilder := NewQueryBuilder(&descriptor, "")
+		sqlBuilder.quote = identifierQuoter(m.Config())
 		sqlWithArguments := sqlBuilder.BuildBatchedQueryOnPk(descriptor.PkColumns, pkValues, defaultBatchSize)
 
 		var mapper = NewColumnarRecordMapper(false, reflect.TypeOf(rows))
@@ -456,7 +607,7 @@ func (m *AbstractManager) ClassifyDataAsInsertableOrUpdatable(connection Connect
 			}
 		}
 		candidates = append(candidates, row)
-		key := toolbox.JoinAsString(pkValueForThisRow, "")
+		key := keyLiteral(pkValueForThisRow)
 		pkValues = append(pkValues, pkValueForThisRow)
 		rowsByKey[key] = row
 		return true
@@ -470,7 +621,7 @@ func (m *AbstractManager) ClassifyDataAsInsertableOrUpdatable(connection Connect
 		}
 		//process existing rows and add mapped entires as updatables
 		for _, row := range rows {
-			key := toolbox.JoinAsString(row, "")
+			key := keyLiteral(row)
 			if instance, ok := rowsByKey[key]; ok {
 				updatables = append(updatables, instance)
 				delete(rowsByKey, key)
@@ -481,7 +632,7 @@ func (m *AbstractManager) ClassifyDataAsInsertableOrUpdatable(connection Connect
 	//go over all candidates and if no key or entries still found in rows by key then classify as insertable
 	for _, candidate := range candidates {
 		var values = provider.Key(candidate)
-		key := toolbox.JoinAsString(values, "")
+		key := keyLiteral(values)
 		if _, ok := rowsByKey[key]; ok {
 			insertables = append(insertables, candidate)
 		}
@@ -498,18 +649,18 @@ func (m *AbstractManager) DeleteAll(dataPointer interface{}, table string, keyPr
 	defer connection.Close()
 	err = connection.Begin()
 	if err != nil {
-		return 0, fmt.Errorf("failed to start transaction on %v due to %v", m.config.Descriptor, err)
+		return 0, fmt.Errorf("failed to start transaction on %v due to %v", m.Config().Descriptor, err)
 	}
 	deleted, err = m.DeleteAllOnConnection(connection, dataPointer, table, keyProvider)
 	if err == nil {
 		commitErr := connection.Commit()
 		if commitErr != nil {
-			return 0, fmt.Errorf("failed to commit on %v due to %v", m.config.Descriptor, commitErr)
+			return 0, fmt.Errorf("failed to commit on %v due to %v", m.Config().Descriptor, commitErr)
 		}
 	} else {
 		rollbackErr := connection.Rollback()
 		if rollbackErr != nil {
-			return 0, fmt.Errorf("failed to rollback on %v due to %v, %v", m.config.Descriptor, err, rollbackErr)
+			return 0, fmt.Errorf("failed to rollback on %v due to %v, %v", m.Config().Descriptor, err, rollbackErr)
 		}
 	}
 	return deleted, err
@@ -518,7 +669,14 @@ func (m *AbstractManager) DeleteAll(dataPointer interface{}, table string, keyPr
 // DeleteAllOnConnection deletes all rows on connection from table, key provider is used to extract primary keys. It returns number of deleted rows or error.
 // If driver allows this operation is executed in one transaction.
 func (m *AbstractManager) DeleteAllOnConnection(connection Connection, dataPointer interface{}, table string, keyProvider KeyGetter) (deleted int, err error) {
+	return m.deleteAllOnConnection(connection, dataPointer, table, keyProvider, nil)
+}
 
+// deleteAllOnConnection deletes or, with options soft delete column, marks as deleted all rows on connection from table.
+func (m *AbstractManager) deleteAllOnConnection(connection Connection, dataPointer interface{}, table string, keyProvider KeyGetter, options *DeleteOptions) (deleted int, err error) {
+	defer func(started time.Time) {
+		m.recordOperation("delete", started, err)
+	}(time.Now())
 	deleted = 0
 	structType := toolbox.DiscoverTypeByKind(dataPointer, reflect.Struct)
 	keyProvider, err = NewKeyGetterIfNeeded(keyProvider, table, structType)
@@ -528,22 +686,22 @@ func (m *AbstractManager) DeleteAllOnConnection(connection Connection, dataPoint
 	m.RegisterDescriptorIfNeeded(table, dataPointer)
 
 	descriptor := m.tableDescriptorRegistry.Get(table)
+	quote := identifierQuoter(m.Config())
 	toolbox.ProcessSlice(dataPointer, func(item interface{}) bool {
 		if err != nil {
 			return false
 		}
 
 		where := m.buildPKWhere(descriptor)
-		if len(descriptor.PkColumns) > 1 {
-			values := strings.Repeat("?,", len(descriptor.PkColumns))
-			values = values[0 : len(values)-1]
-			where = where + " IN (" + values + ")"
-		} else {
-			where = where + " = ?"
+		quotedTable := quoteIdentifier(quote, table)
+		dml := fmt.Sprintf(deleteSQLTemplate, quotedTable, where)
+		parameters := keyProvider.Key(item)
+		if options != nil && options.SoftDeleteColumn != "" {
+			dml = fmt.Sprintf(updateSQLTemplate, quotedTable, quoteIdentifier(quote, options.SoftDeleteColumn)+" = ?", where)
+			parameters = append([]interface{}{options.softDeleteValue()}, parameters...)
 		}
-		dml := fmt.Sprintf(deleteSQLTemplate, table, where)
 		var result sql.Result
-		result, err = m.Manager.ExecuteOnConnection(connection, dml, keyProvider.Key(item))
+		result, err = m.Manager.ExecuteOnConnection(connection, dml, parameters)
 		if err != nil {
 			return false
 		}
@@ -560,11 +718,57 @@ func (m *AbstractManager) DeleteAllOnConnection(connection Connection, dataPoint
 	return deleted, nil
 }
 
+// DeleteAllWithOptions deletes (or soft deletes) all rows from table in one transaction, key provider is used to extract primary keys.
+// When options ExpectedCount is set and the number of affected rows differs, the transaction is rolled back and *DeleteCountMismatchError is returned.
+func (m *AbstractManager) DeleteAllWithOptions(dataPointer interface{}, table string, options *DeleteOptions) (deleted int, err error) {
+	if options == nil {
+		options = &DeleteOptions{}
+	}
+	connection, err := m.Manager.ConnectionProvider().Get()
+	if err != nil {
+		return 0, err
+	}
+	defer connection.Close()
+	err = connection.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to start transaction on %v due to %v", m.Config().Descriptor, err)
+	}
+	deleted, err = m.deleteAllOnConnection(connection, dataPointer, table, options.KeyProvider, options)
+	if err == nil && options.ExpectedCount > 0 && deleted != options.ExpectedCount {
+		err = &DeleteCountMismatchError{Table: table, Expected: options.ExpectedCount, Actual: deleted}
+	}
+	if err == nil {
+		commitErr := connection.Commit()
+		if commitErr != nil {
+			return 0, fmt.Errorf("failed to commit on %v due to %v", m.Config().Descriptor, commitErr)
+		}
+		return deleted, nil
+	}
+	rollbackErr := connection.Rollback()
+	if rollbackErr != nil {
+		return 0, fmt.Errorf("failed to rollback on %v due to %v, %v", m.Config().Descriptor, err, rollbackErr)
+	}
+	return 0, err
+}
+
+// buildPKWhere returns primary key criteria, composite key columns are joined with AND
 func (m *AbstractManager) buildPKWhere(descriptor *TableDescriptor) string {
-	var pk = descriptor.PkColumns
+	var pk = append([]string{}, descriptor.PkColumns...)
 	updateReserved(pk)
-	where := strings.Join(pk, ",")
-	return where
+	var criteria = make([]string, len(pk))
+	for i, column := range quoteIdentifiers(identifierQuoter(m.Config()), pk) {
+		criteria[i] = column + " = ?"
+	}
+	return strings.Join(criteria, " AND ")
+}
+
+// keyLiteral returns primary key values literal, composite key parts are separated so that (1, 23) and (12, 3) keys do not collide
+func keyLiteral(values []interface{}) string {
+	var parts = make([]string, len(values))
+	for i, value := range values {
+		parts[i] = toolbox.AsString(value)
+	}
+	return strings.Join(parts, "\x00")
 }
 
 // DeleteSingle deletes single row from table on for passed in data pointer, key provider is used to extract primary keys. It returns boolean if successful, or error.
@@ -576,18 +780,18 @@ func (m *AbstractManager) DeleteSingle(dataPointer interface{}, table string, ke
 	defer connection.Close()
 	err = connection.Begin()
 	if err != nil {
-		return false, fmt.Errorf("failed to start transaction on %v due to %v", m.config.Descriptor, err)
+		return false, fmt.Errorf("failed to start transaction on %v due to %v", m.Config().Descriptor, err)
 	}
 	suceess, err := m.DeleteSingleOnConnection(connection, dataPointer, table, keyProvider)
 	if err == nil {
 		commitErr := connection.Commit()
 		if commitErr != nil {
-			return false, fmt.Errorf("failed to commit on %v due to %v", m.config.Descriptor, commitErr)
+			return false, fmt.Errorf("failed to commit on %v due to %v", m.Config().Descriptor, commitErr)
 		}
 	} else {
 		rollbackErr := connection.Rollback()
 		if rollbackErr != nil {
-			return false, fmt.Errorf("failed to rollback on %v due to %v, %v", m.config.Descriptor, err, rollbackErr)
+			return false, fmt.Errorf("failed to rollback on %v due to %v, %v", m.Config().Descriptor, err, rollbackErr)
 		}
 	}
 	return suceess, err
@@ -638,7 +842,7 @@ func (m *AbstractManager) TableDescriptorRegistry() TableDescriptorRegistry {
 // NewAbstractManager create a new abstract manager, it takes config, conneciton provider, and target (sub class) manager
 func NewAbstractManager(config *Config, connectionProvider ConnectionProvider, self Manager) *AbstractManager {
 	var descriptorRegistry = newTableDescriptorRegistry()
-	var result = &AbstractManager{config: config, connectionProvider: connectionProvider, Manager: self, tableDescriptorRegistry: descriptorRegistry}
+	var result = &AbstractManager{config: config, connectionProvider: connectionProvider, Manager: self, tableDescriptorRegistry: descriptorRegistry, stats: newManagerStats()}
 	descriptorRegistry.manager = result
 	if config.MaxRequestPerSecond > 0 {
 		result.limiter = NewLimiter(time.Second, config.MaxRequestPerSecond)
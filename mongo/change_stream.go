@@ -0,0 +1,146 @@
+// Package mongo provides dsc change data capture stream over mongo change streams:
+//
+//	stream, err := mongo.NewChangeStream(client.Database("app"), &dsc.ChangeStreamOptions{Tables: []string{"users"}})
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/viant/dsc"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	mongodb "go.mongodb.org/mongo-driver/v2/mongo"
+	mongooptions "go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// changeDocument represents mongo change stream event document
+type changeDocument struct {
+	ID            bson.Raw `bson:"_id"`
+	OperationType string   `bson:"operationType"`
+	Namespace     struct {
+		Collection string `bson:"coll"`
+	} `bson:"ns"`
+	DocumentKey       bson.M `bson:"documentKey"`
+	FullDocument      bson.M `bson:"fullDocument"`
+	UpdateDescription struct {
+		UpdatedFields bson.M `bson:"updatedFields"`
+	} `bson:"updateDescription"`
+}
+
+// token returns resume token _data value
+func (d *changeDocument) token() (string, error) {
+	value, err := d.ID.LookupErr("_data")
+	if err != nil {
+		return "", fmt.Errorf("failed to read resume token due to %v", err)
+	}
+	token, ok := value.StringValueOK()
+	if !ok {
+		return "", fmt.Errorf("failed to read resume token: unsupported _data type %v", value.Type)
+	}
+	return token, nil
+}
+
+// newChangeEvent converts change document into change event, it returns nil for non DML operations (drop, rename, invalidate)
+func newChangeEvent(document *changeDocument) (*dsc.ChangeEvent, error) {
+	var event = &dsc.ChangeEvent{Table: document.Namespace.Collection, Key: map[string]interface{}(document.DocumentKey), Record: make(map[string]interface{})}
+	switch document.OperationType {
+	case "insert":
+		event.Type = dsc.ChangeTypeInsert
+	case "update", "replace":
+		event.Type = dsc.ChangeTypeUpdate
+	case "delete":
+		event.Type = dsc.ChangeTypeDelete
+	default:
+		return nil, nil
+	}
+	var err error
+	if event.Token, err = document.token(); err != nil {
+		return nil, err
+	}
+	for key, value := range document.DocumentKey {
+		event.Record[key] = value
+	}
+	if document.FullDocument != nil {
+		for key, value := range document.FullDocument {
+			event.Record[key] = value
+		}
+	} else {
+		//document was deleted before update lookup, only updated fields are known
+		for key, value := range document.UpdateDescription.UpdatedFields {
+			event.Record[key] = value
+		}
+	}
+	return event, nil
+}
+
+// changeStream represents mongo database change stream, consumer position is not kept server side, Commit is a no-op
+type changeStream struct {
+	stream  *mongodb.ChangeStream
+	context context.Context
+	cancel  context.CancelFunc
+	once    *sync.Once
+}
+
+func (s *changeStream) Read(handler func(event *dsc.ChangeEvent) (toContinue bool, err error)) error {
+	for s.stream.Next(s.context) {
+		var document = &changeDocument{}
+		if err := s.stream.Decode(document); err != nil {
+			return fmt.Errorf("failed to decode change event due to %v", err)
+		}
+		event, err := newChangeEvent(document)
+		if err != nil {
+			return err
+		}
+		if event == nil {
+			continue
+		}
+		toContinue, err := handler(event)
+		if err != nil || !toContinue {
+			return err
+		}
+	}
+	if s.context.Err() != nil {
+		return nil
+	}
+	if err := s.stream.Err(); err != nil {
+		return fmt.Errorf("failed to read change stream due to %v", err)
+	}
+	return nil
+}
+
+func (s *changeStream) Commit(token string) error {
+	return nil
+}
+
+func (s *changeStream) Close() error {
+	var err error
+	s.once.Do(func() {
+		s.cancel()
+		err = s.stream.Close(context.Background())
+	})
+	return err
+}
+
+// NewChangeStream opens change stream on database, options.Tables limits captured collections, options.ResumeToken resumes after the event with that token,
+// updates are delivered with looked up full document
+func NewChangeStream(database *mongodb.Database, options *dsc.ChangeStreamOptions) (dsc.ChangeStream, error) {
+	if options == nil {
+		options = &dsc.ChangeStreamOptions{}
+	}
+	var pipeline = mongodb.Pipeline{}
+	if len(options.Tables) > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$match", Value: bson.M{"ns.coll": bson.M{"$in": options.Tables}}}})
+	}
+	streamOptions := mongooptions.ChangeStream().SetFullDocument(mongooptions.UpdateLookup)
+	if options.ResumeToken != "" {
+		streamOptions.SetResumeAfter(bson.M{"_data": options.ResumeToken})
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := database.Watch(ctx, pipeline, streamOptions)
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("failed to open %v change stream due to %v", database.Name(), err)
+	}
+	return &changeStream{stream: stream, context: ctx, cancel: cancel, once: &sync.Once{}}, nil
+}
@@ -0,0 +1,81 @@
+package mongo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/dsc"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestNewChangeEvent(t *testing.T) {
+	type User struct {
+		ID       int    `column:"_id"`
+		Username string `column:"username"`
+	}
+	token, err := bson.Marshal(bson.M{"_data": "82663A1B2C000000012B"})
+	if !assert.Nil(t, err) {
+		return
+	}
+	var useCases = []struct {
+		description   string
+		operationType string
+		fullDocument  bson.M
+		updatedFields bson.M
+		expectType    string
+		expectUser    *User
+	}{
+		{
+			description:   "insert",
+			operationType: "insert",
+			fullDocument:  bson.M{"_id": 1, "username": "Bob"},
+			expectType:    dsc.ChangeTypeInsert,
+			expectUser:    &User{ID: 1, Username: "Bob"},
+		},
+		{
+			description:   "replace",
+			operationType: "replace",
+			fullDocument:  bson.M{"_id": 1, "username": "Ann"},
+			expectType:    dsc.ChangeTypeUpdate,
+			expectUser:    &User{ID: 1, Username: "Ann"},
+		},
+		{
+			description:   "update without looked up document",
+			operationType: "update",
+			updatedFields: bson.M{"username": "Tom"},
+			expectType:    dsc.ChangeTypeUpdate,
+			expectUser:    &User{ID: 1, Username: "Tom"},
+		},
+		{
+			description:   "delete",
+			operationType: "delete",
+			expectType:    dsc.ChangeTypeDelete,
+			expectUser:    &User{ID: 1},
+		},
+		{
+			description:   "invalidate",
+			operationType: "invalidate",
+		},
+	}
+	for _, useCase := range useCases {
+		var document = &changeDocument{ID: token, OperationType: useCase.operationType, DocumentKey: bson.M{"_id": 1}, FullDocument: useCase.fullDocument}
+		document.Namespace.Collection = "users"
+		document.UpdateDescription.UpdatedFields = useCase.updatedFields
+		event, err := newChangeEvent(document)
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		if useCase.expectType == "" {
+			assert.Nil(t, event, useCase.description)
+			continue
+		}
+		assert.Equal(t, useCase.expectType, event.Type, useCase.description)
+		assert.Equal(t, "users", event.Table, useCase.description)
+		assert.Equal(t, "82663A1B2C000000012B", event.Token, useCase.description)
+		assert.EqualValues(t, map[string]interface{}{"_id": 1}, event.Key, useCase.description)
+		var user = User{}
+		if assert.Nil(t, event.Map(&user, nil), useCase.description) {
+			assert.EqualValues(t, useCase.expectUser, &user, useCase.description)
+		}
+	}
+}
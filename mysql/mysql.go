@@ -0,0 +1,32 @@
+// Package mysql registers github.com/go-sql-driver/mysql specific dsc hooks: error numbers used by error classification,
+// invalid connection error reported by dsc.IsConnectionError and tls.Config registration used with Config.TLS.
+// It also registers mysql database/sql driver:
+//
+//	import _ "github.com/viant/dsc/mysql"
+package mysql
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/viant/dsc"
+)
+
+// DriverName represents mysql driver name
+const DriverName = "mysql"
+
+// errorCode returns mysql error number
+func errorCode(err error) (string, bool) {
+	var mysqlError *mysql.MySQLError
+	if !errors.As(err, &mysqlError) {
+		return "", false
+	}
+	return strconv.Itoa(int(mysqlError.Number)), true
+}
+
+func init() {
+	dsc.RegisterErrorCodeExtractor(DriverName, errorCode)
+	dsc.RegisterConnectionError(mysql.ErrInvalidConn)
+	dsc.RegisterTLSConfigRegistrar(DriverName, mysql.RegisterTLSConfig)
+}
@@ -0,0 +1,27 @@
+package mysql
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/dsc"
+)
+
+func TestErrorCode(t *testing.T) {
+	code, ok := errorCode(fmt.Errorf("failed to insert due to %w", &mysql.MySQLError{Number: 1062, Message: "Duplicate entry"}))
+	assert.True(t, ok)
+	assert.EqualValues(t, "1062", code)
+	_, ok = errorCode(fmt.Errorf("failed"))
+	assert.False(t, ok)
+	assert.True(t, dsc.IsConnectionError(fmt.Errorf("failed to read due to %w", mysql.ErrInvalidConn)))
+}
+
+func TestApplyTLS(t *testing.T) {
+	dsn, err := dsc.GetDatastoreDialect(DriverName).(dsc.TLSDialect).ApplyTLS("root@tcp(127.0.0.1:3306)/db", &dsc.TLSConfig{InsecureSkipVerify: true})
+	if assert.Nil(t, err) {
+		assert.True(t, strings.HasPrefix(dsn, "root@tcp(127.0.0.1:3306)/db?tls=dsc_"), dsn)
+	}
+}
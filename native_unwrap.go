@@ -0,0 +1,76 @@
+package dsc
+
+import (
+	"reflect"
+	"sync"
+)
+
+// NativeUnwrapper returns driver native handle for passed in target type pointer, i.e. (*pgx.Conn)(nil), release function is called once connection is returned to the pool or closed,
+// nil native with nil error indicates unsupported target
+type NativeUnwrapper func(connection Connection, target interface{}) (native interface{}, release func(), err error)
+
+var nativeUnwrappers = make(map[string]NativeUnwrapper)
+var nativeUnwrappersMutex = &sync.RWMutex{}
+
+// RegisterNativeUnwrapper registers native unwrapper for a driver, it extends sql connection Unwrap beyond *sql.DB and *sql.Tx targets
+func RegisterNativeUnwrapper(driver string, unwrapper NativeUnwrapper) {
+	nativeUnwrappersMutex.Lock()
+	defer nativeUnwrappersMutex.Unlock()
+	nativeUnwrappers[driver] = unwrapper
+}
+
+func getNativeUnwrapper(driver string) NativeUnwrapper {
+	nativeUnwrappersMutex.RLock()
+	defer nativeUnwrappersMutex.RUnlock()
+	return nativeUnwrappers[driver]
+}
+
+// nativeHandles represents native handles unwrapped for a connection, cached by target type until released
+type nativeHandles struct {
+	mutex    sync.Mutex
+	handles  map[reflect.Type]interface{}
+	releases []func()
+}
+
+// unwrap returns cached or newly unwrapped native handle for the target
+func (h *nativeHandles) unwrap(connection Connection, unwrapper NativeUnwrapper, target interface{}) (interface{}, error) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	targetType := reflect.TypeOf(target)
+	if native, ok := h.handles[targetType]; ok {
+		return native, nil
+	}
+	native, release, err := unwrapper(connection, target)
+	if err != nil || native == nil {
+		return nil, err
+	}
+	if h.handles == nil {
+		h.handles = make(map[reflect.Type]interface{})
+	}
+	h.handles[targetType] = native
+	if release != nil {
+		h.releases = append(h.releases, release)
+	}
+	return native, nil
+}
+
+// release releases all unwrapped native handles
+func (h *nativeHandles) release() {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	for _, release := range h.releases {
+		release()
+	}
+	h.handles = nil
+	h.releases = nil
+}
+
+// nativeReleaser represents connection holding native handles that have to be released when connection is returned to the pool
+type nativeReleaser interface {
+	releaseNative()
+}
+
+// releaseNative releases native handles unwrapped with registered NativeUnwrapper
+func (c *sqlConnection) releaseNative() {
+	c.natives.release()
+}
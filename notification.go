@@ -0,0 +1,233 @@
+package dsc
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	subscriptionMinReconnectDelay = 100 * time.Millisecond
+	subscriptionMaxReconnectDelay = 10 * time.Second
+)
+
+// subscriptionRecycleCheck represents interval of checking whether manager connection pool was recycled
+var subscriptionRecycleCheck = time.Second
+
+// Notification represents datastore push notification
+type Notification struct {
+	Channel string
+	Payload string
+	//PID notifying backend process id if reported by datastore
+	PID int
+}
+
+// NotificationListener represents dedicated datastore connection receiving push notifications
+type NotificationListener interface {
+	//Listen subscribes to notification channel
+	Listen(channel string) error
+	//Notifications returns received notifications, the channel is closed once the listener connection is lost or closed
+	Notifications() <-chan *Notification
+	//Close closes listener connection
+	Close() error
+}
+
+// notificationDialect represents dialect supporting push notifications
+type notificationDialect interface {
+	newNotificationListener(manager Manager) (NotificationListener, error)
+}
+
+// NotificationListenerFactory opens notification listener connection with passed in DSN
+type NotificationListenerFactory func(dsn string) (NotificationListener, error)
+
+var notificationListenerFactories = make(map[string]NotificationListenerFactory)
+var notificationListenerFactoriesMutex = &sync.RWMutex{}
+
+// RegisterNotificationListenerFactory registers notification listener factory for a driver, postgres factory is used by all postgres dialect drivers without their own one
+func RegisterNotificationListenerFactory(driver string, factory NotificationListenerFactory) {
+	notificationListenerFactoriesMutex.Lock()
+	defer notificationListenerFactoriesMutex.Unlock()
+	notificationListenerFactories[driver] = factory
+}
+
+func getNotificationListenerFactory(driver string) NotificationListenerFactory {
+	notificationListenerFactoriesMutex.RLock()
+	defer notificationListenerFactoriesMutex.RUnlock()
+	if factory, ok := notificationListenerFactories[driver]; ok {
+		return factory
+	}
+	return notificationListenerFactories["postgres"]
+}
+
+// newNotificationListener opens postgres listener connection with manager DSN and registered listener factory (see github.com/viant/dsc/pq)
+func (d pgDialect) newNotificationListener(manager Manager) (NotificationListener, error) {
+	config := manager.Config()
+	factory := getNotificationListenerFactory(config.DriverName)
+	if factory == nil {
+		return nil, fmt.Errorf("failed to open listener connection: postgres notification listener factory was not registered, import github.com/viant/dsc/pq")
+	}
+	dsn, err := config.DsnDescriptor()
+	if err != nil {
+		return nil, err
+	}
+	if config.TLS != nil {
+		if dsn, err = d.ApplyTLS(dsn, config.TLS); err != nil {
+			return nil, fmt.Errorf("failed to apply TLS config on %v due to %v", config.DriverName, err)
+		}
+	}
+	result, err := factory(dsn)
+	if err != nil {
+		return nil, &Error{Kinds: []error{ErrConnection}, Err: fmt.Errorf("failed to open listener connection on %v due to %w", config.Descriptor, err)}
+	}
+	return result, nil
+}
+
+// Subscription represents push notification subscription, it reconnects and resubscribes all channels when listener connection is lost
+// or manager connection pool was recycled (i.e. with config or credentials reload). Notifications sent while reconnecting are lost.
+type Subscription struct {
+	manager  Manager
+	dialect  notificationDialect
+	mutex    *sync.Mutex
+	channels []string
+	listener NotificationListener
+	events   chan *Notification
+	closed   chan bool
+	done     chan bool
+	once     *sync.Once
+}
+
+// Events returns received notifications, the channel is closed once subscription is closed
+func (s *Subscription) Events() <-chan *Notification {
+	return s.events
+}
+
+// Listen subscribes to additional notification channel
+func (s *Subscription) Listen(channel string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, candidate := range s.channels {
+		if candidate == channel {
+			return nil
+		}
+	}
+	s.channels = append(s.channels, channel)
+	if s.listener != nil {
+		return s.listener.Listen(channel)
+	}
+	return nil
+}
+
+// Close closes subscription and its listener connection
+func (s *Subscription) Close() error {
+	s.once.Do(func() {
+		close(s.closed)
+	})
+	<-s.done
+	return nil
+}
+
+// connect opens listener connection and subscribes all channels
+func (s *Subscription) connect() (NotificationListener, error) {
+	listener, err := s.dialect.newNotificationListener(s.manager)
+	if err != nil {
+		return nil, err
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, channel := range s.channels {
+		if err = listener.Listen(channel); err != nil {
+			_ = listener.Close()
+			return nil, err
+		}
+	}
+	s.listener = listener
+	return listener, nil
+}
+
+func (s *Subscription) disconnect(listener NotificationListener) {
+	s.mutex.Lock()
+	s.listener = nil
+	s.mutex.Unlock()
+	if err := listener.Close(); err != nil {
+		Logf("failed to close notification listener %v", err)
+	}
+}
+
+// receive delivers notifications until listener connection is lost, pool is recycled or subscription is closed, it returns false once closed
+func (s *Subscription) receive(listener NotificationListener) bool {
+	pool := s.manager.ConnectionProvider().ConnectionPool()
+	ticker := time.NewTicker(subscriptionRecycleCheck)
+	defer ticker.Stop()
+	defer s.disconnect(listener)
+	for {
+		select {
+		case <-s.closed:
+			return false
+		case notification, ok := <-listener.Notifications():
+			if !ok {
+				Logf("notification listener connection on %v was lost, reconnecting", s.manager.Config().Descriptor)
+				return true
+			}
+			select {
+			case s.events <- notification:
+			case <-s.closed:
+				return false
+			}
+		case <-ticker.C:
+			if s.manager.ConnectionProvider().ConnectionPool() != pool {
+				return true
+			}
+		}
+	}
+}
+
+func (s *Subscription) run(listener NotificationListener) {
+	defer close(s.done)
+	defer close(s.events)
+	delay := subscriptionMinReconnectDelay
+	for {
+		if listener != nil {
+			if !s.receive(listener) {
+				return
+			}
+			delay = subscriptionMinReconnectDelay
+		}
+		var err error
+		if listener, err = s.connect(); err == nil {
+			continue
+		}
+		Logf("failed to reconnect notification listener on %v due to %v, retrying in %v", s.manager.Config().Descriptor, err, delay)
+		select {
+		case <-s.closed:
+			return
+		case <-time.After(delay):
+		}
+		if delay *= 2; delay > subscriptionMaxReconnectDelay {
+			delay = subscriptionMaxReconnectDelay
+		}
+	}
+}
+
+// Subscribe subscribes to datastore push notifications channels (postgres LISTEN/NOTIFY), notifications are delivered with Subscription.Events
+func (m *AbstractManager) Subscribe(channels ...string) (*Subscription, error) {
+	dialect, ok := GetDatastoreDialect(m.Manager.Config().DriverName).(notificationDialect)
+	if !ok {
+		return nil, fmt.Errorf("failed to subscribe: push notifications are not supported by %v", m.Manager.Config().DriverName)
+	}
+	subscription := &Subscription{
+		manager:  m.Manager,
+		dialect:  dialect,
+		mutex:    &sync.Mutex{},
+		channels: append([]string{}, channels...),
+		events:   make(chan *Notification, 32),
+		closed:   make(chan bool),
+		done:     make(chan bool),
+		once:     &sync.Once{},
+	}
+	listener, err := subscription.connect()
+	if err != nil {
+		return nil, fmt.Errorf("failed to subscribe %v due to %w", channels, err)
+	}
+	go subscription.run(listener)
+	return subscription, nil
+}
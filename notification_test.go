@@ -0,0 +1,109 @@
+package dsc
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+)
+
+type testNotificationListener struct {
+	mutex         *sync.Mutex
+	channels      []string
+	notifications chan *Notification
+	once          *sync.Once
+}
+
+func (l *testNotificationListener) Listen(channel string) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.channels = append(l.channels, channel)
+	return nil
+}
+
+func (l *testNotificationListener) listening() []string {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return append([]string{}, l.channels...)
+}
+
+func (l *testNotificationListener) Notifications() <-chan *Notification {
+	return l.notifications
+}
+
+func (l *testNotificationListener) Close() error {
+	l.once.Do(func() {
+		close(l.notifications)
+	})
+	return nil
+}
+
+type testNotificationDialect struct {
+	DatastoreDialect
+	listeners chan *testNotificationListener
+}
+
+func (d testNotificationDialect) newNotificationListener(manager Manager) (NotificationListener, error) {
+	listener := &testNotificationListener{mutex: &sync.Mutex{}, notifications: make(chan *Notification, 1), once: &sync.Once{}}
+	d.listeners <- listener
+	return listener, nil
+}
+
+func TestManager_Subscribe(t *testing.T) {
+	manager, err := NewManagerFactory().Create(NewConfig("sqlite3", "[url]", "url:./test/notify.db"))
+	if !assert.Nil(t, err) {
+		return
+	}
+	_, err = manager.(Subscriber).Subscribe("events")
+	assert.NotNil(t, err, "sqlite does not support notifications")
+
+	dialect := GetDatastoreDialect("sqlite3")
+	listeners := make(chan *testNotificationListener, 3)
+	RegisterDatastoreDialect("sqlite3", testNotificationDialect{DatastoreDialect: dialect, listeners: listeners})
+	defer RegisterDatastoreDialect("sqlite3", dialect)
+	recycleCheck := subscriptionRecycleCheck
+	subscriptionRecycleCheck = 10 * time.Millisecond
+	defer func() { subscriptionRecycleCheck = recycleCheck }()
+
+	subscription, err := manager.(Subscriber).Subscribe("events")
+	if !assert.Nil(t, err) {
+		return
+	}
+	next := func() *testNotificationListener {
+		select {
+		case listener := <-listeners:
+			return listener
+		case <-time.After(2 * time.Second):
+			assert.Fail(t, "listener was not reconnected")
+			return nil
+		}
+	}
+	listener := next()
+	assert.EqualValues(t, []string{"events"}, listener.listening())
+	listener.notifications <- &Notification{Channel: "events", Payload: "1"}
+	assert.Equal(t, "1", (<-subscription.Events()).Payload)
+
+	//lost connection
+	_ = listener.Close()
+	listener = next()
+	if !assert.NotNil(t, listener) {
+		return
+	}
+	assert.EqualValues(t, []string{"events"}, listener.listening())
+	assert.Nil(t, subscription.Listen("jobs"))
+	assert.EqualValues(t, []string{"events", "jobs"}, listener.listening())
+
+	//recycled pool
+	assert.Nil(t, manager.ConnectionProvider().(ReloadableConnectionProvider).Reload(nil))
+	listener = next()
+	if !assert.NotNil(t, listener) {
+		return
+	}
+	assert.EqualValues(t, []string{"events", "jobs"}, listener.listening())
+
+	assert.Nil(t, subscription.Close())
+	_, ok := <-subscription.Events()
+	assert.False(t, ok)
+}
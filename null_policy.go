@@ -0,0 +1,333 @@
+package dsc
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/viant/toolbox"
+)
+
+const (
+	// NullPolicyKey represents config parameter with manager NULL mapping policy: native (default), zero or error, column "nullPolicy" tag takes precedence
+	NullPolicyKey = "nullPolicy"
+	// ZeroValuePolicyKey represents config parameter with manager zero value writing policy: value (default), null or omit, column "zeroValue" tag takes precedence
+	ZeroValuePolicyKey = "zeroValuePolicy"
+)
+
+const (
+	//NullPolicyNative maps NULL with database/sql semantics: pointer fields are set to nil, sql.Null* fields are invalid, other fields fail to scan
+	NullPolicyNative = "native"
+	//NullPolicyZero maps NULL to field zero value
+	NullPolicyZero = "zero"
+	//NullPolicyError fails on NULL, including pointer and sql.Null* fields
+	NullPolicyError = "error"
+)
+
+const (
+	//ZeroValueAsValue writes zero valued field as is
+	ZeroValueAsValue = "value"
+	//ZeroValueAsNull writes zero valued field as NULL
+	ZeroValueAsNull = "null"
+	//ZeroValueOmit omits zero valued field from INSERT and UPDATE, so that column default or current value is kept
+	ZeroValueOmit = "omit"
+)
+
+func validateNullPolicy(policy string) error {
+	switch policy {
+	case "", NullPolicyNative, NullPolicyZero, NullPolicyError:
+		return nil
+	}
+	return fmt.Errorf("invalid null policy: %v, supported: %v, %v, %v", policy, NullPolicyNative, NullPolicyZero, NullPolicyError)
+}
+
+func validateZeroValuePolicy(policy string) error {
+	switch policy {
+	case "", ZeroValueAsValue, ZeroValueAsNull, ZeroValueOmit:
+		return nil
+	}
+	return fmt.Errorf("invalid zero value policy: %v, supported: %v, %v, %v", policy, ZeroValueAsValue, ZeroValueAsNull, ZeroValueOmit)
+}
+
+// newColumnNullPolicies returns column NULL mapping and zero value writing policies for fields with `nullPolicy` and `zeroValue` tags
+func newColumnNullPolicies(targetType reflect.Type) (nullPolicies map[string]string, zeroValuePolicies map[string]string, err error) {
+	mapping := toolbox.BuildTagMapping(targetType, "column", "transient", true, true, []string{"column", "nullPolicy", "zeroValue"})
+	for _, fieldMapping := range mapping {
+		column, ok := fieldMapping["column"]
+		if !ok {
+			column = fieldMapping["fieldName"]
+		}
+		if policy, ok := fieldMapping["nullPolicy"]; ok {
+			if err = validateNullPolicy(policy); err != nil {
+				return nil, nil, fmt.Errorf("column %v %v", column, err)
+			}
+			if nullPolicies == nil {
+				nullPolicies = make(map[string]string)
+			}
+			nullPolicies[column] = policy
+		}
+		if policy, ok := fieldMapping["zeroValue"]; ok {
+			if err = validateZeroValuePolicy(policy); err != nil {
+				return nil, nil, fmt.Errorf("column %v %v", column, err)
+			}
+			if zeroValuePolicies == nil {
+				zeroValuePolicies = make(map[string]string)
+			}
+			zeroValuePolicies[column] = policy
+		}
+	}
+	return nullPolicies, zeroValuePolicies, nil
+}
+
+// columnNullPolicies returns NULL mapping policies of registered table descriptors by lower case column name, query option policies take precedence
+func columnNullPolicies(registry TableDescriptorRegistry, optionPolicies map[string]string) map[string]string {
+	var result = make(map[string]string)
+	if registry != nil {
+		for _, table := range registry.Tables() {
+			for column, policy := range registry.Get(table).NullPolicies {
+				result[strings.ToLower(column)] = policy
+			}
+		}
+	}
+	for column, policy := range optionPolicies {
+		result[column] = policy
+	}
+	return result
+}
+
+// WithNullPolicy returns option overriding NULL mapping policy (native, zero or error) of passed in columns for a single read, or of all columns if none were passed
+func WithNullPolicy(policy string, columns ...string) QueryOption {
+	return func(options *QueryOptions) {
+		if options.NullPolicies == nil {
+			options.NullPolicies = make(map[string]string)
+		}
+		if len(columns) == 0 {
+			options.NullPolicies[allColumns] = policy
+		}
+		for _, column := range columns {
+			options.NullPolicies[strings.ToLower(column)] = policy
+		}
+	}
+}
+
+// allColumns represents NullPolicies key of policy applied to all columns
+const allColumns = "*"
+
+var structNullPolicies = &sync.Map{}
+
+// withStructNullPolicies returns parameters with query option carrying NULL mapping policies of target struct `nullPolicy` tags, options passed by caller take precedence
+func withStructNullPolicies(parameters []interface{}, targetType reflect.Type) []interface{} {
+	if targetType.Kind() == reflect.Ptr {
+		targetType = targetType.Elem()
+	}
+	if targetType.Kind() != reflect.Struct {
+		return parameters
+	}
+	cached, ok := structNullPolicies.Load(targetType)
+	if !ok {
+		policies, _, err := newColumnNullPolicies(targetType)
+		if err != nil {
+			policies = nil
+		}
+		cached, _ = structNullPolicies.LoadOrStore(targetType, policies)
+	}
+	policies := cached.(map[string]string)
+	if len(policies) == 0 {
+		return parameters
+	}
+	var option QueryOption = func(options *QueryOptions) {
+		if options.NullPolicies == nil {
+			options.NullPolicies = make(map[string]string)
+		}
+		for column, policy := range policies {
+			options.NullPolicies[strings.ToLower(column)] = policy
+		}
+	}
+	return append([]interface{}{option}, parameters...)
+}
+
+// nullGuard represents sql.Scanner destination failing on NULL
+type nullGuard struct {
+	sql.Scanner
+	column string
+}
+
+// Scan delegates non NULL value to guarded scanner
+func (g *nullGuard) Scan(src interface{}) error {
+	if src == nil {
+		return fmt.Errorf("failed to scan %v: unexpected NULL", g.column)
+	}
+	return g.Scanner.Scan(src)
+}
+
+// nullPolicyScanner represents scanner applying NULL mapping policy, destinations are scanned through an extra pointer, so that NULL can be detected
+type nullPolicyScanner struct {
+	Scanner
+	policies []string
+}
+
+// Scan scans row applying column NULL policies
+func (s *nullPolicyScanner) Scan(destinations ...interface{}) error {
+	var scanned = make([]interface{}, len(destinations))
+	columns, _ := s.Columns()
+	for i, destination := range destinations {
+		scanned[i] = destination
+		if i >= len(s.policies) || s.policies[i] == NullPolicyNative || destination == nil {
+			continue
+		}
+		if scanner, ok := destination.(sql.Scanner); ok {
+			if s.policies[i] == NullPolicyError && i < len(columns) {
+				scanned[i] = &nullGuard{Scanner: scanner, column: columns[i]}
+			}
+			continue
+		}
+		if value := reflect.ValueOf(destination); value.Kind() == reflect.Ptr {
+			scanned[i] = reflect.New(value.Type()).Interface()
+		}
+	}
+	if err := s.Scanner.Scan(scanned...); err != nil {
+		return err
+	}
+	for i, destination := range destinations {
+		if _, ok := scanned[i].(*nullGuard); ok || scanned[i] == destination {
+			continue
+		}
+		nullable := reflect.ValueOf(scanned[i]).Elem()
+		target := reflect.ValueOf(destination).Elem()
+		if !nullable.IsNil() {
+			target.Set(nullable.Elem())
+			continue
+		}
+		if s.policies[i] == NullPolicyError {
+			column := ""
+			if i < len(columns) {
+				column = columns[i]
+			}
+			return fmt.Errorf("failed to scan %v: unexpected NULL", column)
+		}
+		target.Set(reflect.Zero(target.Type()))
+	}
+	return nil
+}
+
+// newNullPolicyScanner returns NULL policy scanner for columns or nil if all columns use native policy, column policies take precedence over config policy
+func newNullPolicyScanner(config *Config, columns []string, policiesByColumn map[string]string) (*nullPolicyScanner, error) {
+	defaultPolicy := config.GetString(NullPolicyKey, NullPolicyNative)
+	if policy, ok := policiesByColumn[allColumns]; ok {
+		defaultPolicy = policy
+	}
+	if err := validateNullPolicy(defaultPolicy); err != nil {
+		return nil, err
+	}
+	var result = &nullPolicyScanner{policies: make([]string, len(columns))}
+	var applied = false
+	for i, column := range columns {
+		policy, ok := policiesByColumn[strings.ToLower(column)]
+		if !ok || policy == "" {
+			policy = defaultPolicy
+		}
+		if err := validateNullPolicy(policy); err != nil {
+			return nil, err
+		}
+		result.policies[i] = policy
+		applied = applied || policy != NullPolicyNative
+	}
+	if !applied {
+		return nil, nil
+	}
+	return result, nil
+}
+
+// zeroValuePolicy returns column zero value policy, column policy takes precedence over table policy
+func (t *TableDescriptor) zeroValuePolicy(column string) string {
+	if policy, ok := t.ZeroValuePolicies[column]; ok && policy != "" {
+		return policy
+	}
+	return t.ZeroValuePolicy
+}
+
+// hasZeroValuePolicy returns true if any column zero value is written as NULL or omitted
+func (t *TableDescriptor) hasZeroValuePolicy() bool {
+	if t.ZeroValuePolicy != "" && t.ZeroValuePolicy != ZeroValueAsValue {
+		return true
+	}
+	for _, policy := range t.ZeroValuePolicies {
+		if policy != "" && policy != ZeroValueAsValue {
+			return true
+		}
+	}
+	return false
+}
+
+// zeroValueProvider returns value provider writing zero values of ZeroValueAsNull columns as NULL, and set of zero valued ZeroValueOmit columns,
+// primary key and audit columns are always written
+func zeroValueProvider(descriptor *TableDescriptor, columns []string, valueProvider func(column string) interface{}) (func(column string) interface{}, map[string]bool) {
+	var nulls, omitted map[string]bool
+	for _, column := range columns {
+		policy := descriptor.zeroValuePolicy(column)
+		if policy == "" || policy == ZeroValueAsValue || toolbox.HasSliceAnyElements(descriptor.PkColumns, column) {
+			continue
+		}
+		if descriptor.Audit != nil && toolbox.HasSliceAnyElements(descriptor.Audit.columns(), column) {
+			continue
+		}
+		if value := valueProvider(column); value != nil && !isZeroValue(reflect.ValueOf(value)) {
+			continue
+		}
+		if policy == ZeroValueOmit {
+			if omitted == nil {
+				omitted = make(map[string]bool)
+			}
+			omitted[column] = true
+			continue
+		}
+		if nulls == nil {
+			nulls = make(map[string]bool)
+		}
+		nulls[column] = true
+	}
+	if len(nulls) == 0 {
+		return valueProvider, omitted
+	}
+	return func(column string) interface{} {
+		if nulls[column] {
+			return nil
+		}
+		return valueProvider(column)
+	}, omitted
+}
+
+// withoutColumns returns columns excluding omitted ones
+func withoutColumns(columns []string, omitted map[string]bool) []string {
+	var result = make([]string, 0, len(columns))
+	for _, column := range columns {
+		if !omitted[column] {
+			result = append(result, column)
+		}
+	}
+	return result
+}
+
+// applyZeroValuePolicy sets config zero value policy on provider created by manager, table descriptor policy takes precedence
+func applyZeroValuePolicy(config *Config, provider DmlProvider) error {
+	policy := config.GetString(ZeroValuePolicyKey, "")
+	if policy == "" {
+		return nil
+	}
+	if err := validateZeroValuePolicy(policy); err != nil {
+		return err
+	}
+	var descriptor *TableDescriptor
+	switch actual := provider.(type) {
+	case *metaDmlProvider:
+		descriptor = actual.dmlBuilder.TableDescriptor
+	case *mapDmlProvider:
+		descriptor = actual.tableDescriptor
+	}
+	if descriptor != nil && descriptor.ZeroValuePolicy == "" {
+		descriptor.ZeroValuePolicy = policy
+	}
+	return nil
+}
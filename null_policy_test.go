@@ -0,0 +1,136 @@
+package dsc_test
+
+import (
+	"database/sql"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/dsc"
+)
+
+type nullableItem struct {
+	Id   int    `primaryKey:"true"`
+	Name string `column:"name"`
+	Qty  int    `column:"qty"`
+}
+
+type zeroNullableItem struct {
+	Id   int    `primaryKey:"true"`
+	Name string `column:"name" nullPolicy:"zero"`
+	Qty  int    `column:"qty" nullPolicy:"zero"`
+}
+
+type writtenItem struct {
+	Id   int    `primaryKey:"true"`
+	Name string `column:"name" zeroValue:"null"`
+	Qty  int    `column:"qty" zeroValue:"omit"`
+}
+
+type readItem struct {
+	Id   int            `primaryKey:"true"`
+	Name sql.NullString `column:"name"`
+	Qty  *int           `column:"qty"`
+}
+
+func newNullPolicyManager(t *testing.T, parameters string) dsc.Manager {
+	config := dsc.NewConfig("sqlite3", "[url]", "url:./test/null_policy.db"+parameters)
+	manager, err := dsc.NewManagerFactory().Create(config)
+	if !assert.Nil(t, err) {
+		return nil
+	}
+	for _, SQL := range []string{
+		"DROP TABLE IF EXISTS items",
+		"CREATE TABLE items(id INTEGER PRIMARY KEY, name TEXT, qty INTEGER DEFAULT 7)",
+		"INSERT INTO items(id, name, qty) VALUES(1, NULL, NULL)",
+	} {
+		_, err = manager.Execute(SQL)
+		assert.Nil(t, err)
+	}
+	return manager
+}
+
+func TestNullPolicy_Read(t *testing.T) {
+	manager := newNullPolicyManager(t, "")
+	if manager == nil {
+		return
+	}
+	var items = make([]*nullableItem, 0)
+	assert.NotNil(t, manager.ReadAll(&items, "SELECT id, name, qty FROM items", nil, nil), "native policy should fail on NULL into string")
+
+	var zeroItems = make([]*zeroNullableItem, 0)
+	if assert.Nil(t, manager.ReadAll(&zeroItems, "SELECT id, name, qty FROM items", nil, nil)) && assert.Equal(t, 1, len(zeroItems)) {
+		assert.EqualValues(t, &zeroNullableItem{Id: 1}, zeroItems[0])
+	}
+	items = make([]*nullableItem, 0)
+	assert.Nil(t, manager.ReadAll(&items, "SELECT id, name, qty FROM items", []interface{}{dsc.WithNullPolicy(dsc.NullPolicyZero)}, nil))
+	assert.Equal(t, 1, len(items))
+
+	var read = readItem{}
+	success, err := manager.ReadSingle(&read, "SELECT id, name, qty FROM items", nil, nil)
+	assert.Nil(t, err)
+	assert.True(t, success)
+	assert.False(t, read.Name.Valid)
+	assert.Nil(t, read.Qty)
+
+	_, err = manager.ReadSingle(&read, "SELECT id, name, qty FROM items", []interface{}{dsc.WithNullPolicy(dsc.NullPolicyError, "name")}, nil)
+	assert.NotNil(t, err, "error policy should fail on sql.NullString")
+	_, err = manager.ReadSingle(&read, "SELECT id, name, qty FROM items", []interface{}{dsc.WithNullPolicy(dsc.NullPolicyError, "qty")}, nil)
+	assert.NotNil(t, err, "error policy should fail on pointer")
+	_, err = manager.ReadSingle(&read, "SELECT id, name, qty FROM items", []interface{}{dsc.WithNullPolicy("skip")}, nil)
+	assert.NotNil(t, err)
+
+	manager = newNullPolicyManager(t, ",nullPolicy:zero")
+	if manager == nil {
+		return
+	}
+	items = make([]*nullableItem, 0)
+	if assert.Nil(t, manager.ReadAll(&items, "SELECT id, name, qty FROM items", nil, nil)) && assert.Equal(t, 1, len(items)) {
+		assert.EqualValues(t, &nullableItem{Id: 1}, items[0])
+	}
+	var record = make(map[string]interface{})
+	success, err = manager.ReadSingle(&record, "SELECT id, name FROM items", nil, nil)
+	assert.Nil(t, err)
+	assert.True(t, success)
+	assert.Nil(t, record["name"])
+}
+
+func TestNullPolicy_Write(t *testing.T) {
+	manager := newNullPolicyManager(t, ",batchSize:10")
+	if manager == nil {
+		return
+	}
+	inserted, _, err := manager.PersistAll(&[]*writtenItem{{Id: 2, Name: "", Qty: 0}, {Id: 3, Name: "c", Qty: 3}, {Id: 4}}, "items", nil)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, 3, inserted)
+	var items = make([]*readItem, 0)
+	if assert.Nil(t, manager.ReadAll(&items, "SELECT id, name, qty FROM items WHERE id > 1 ORDER BY id", nil, nil)) && assert.Equal(t, 3, len(items)) {
+		assert.False(t, items[0].Name.Valid, "zero value should be written as NULL")
+		assert.EqualValues(t, 7, *items[0].Qty, "omitted zero value should keep column default")
+		assert.EqualValues(t, "c", items[1].Name.String)
+		assert.EqualValues(t, 3, *items[1].Qty)
+		assert.EqualValues(t, 7, *items[2].Qty)
+	}
+	_, updated, err := manager.PersistAll(&[]*writtenItem{{Id: 3, Name: "updated"}}, "items", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, updated)
+	var item = readItem{}
+	_, err = manager.ReadSingle(&item, "SELECT id, name, qty FROM items WHERE id = ?", []interface{}{3}, nil)
+	assert.Nil(t, err)
+	assert.EqualValues(t, "updated", item.Name.String)
+	assert.EqualValues(t, 3, *item.Qty, "omitted zero value should keep current value")
+
+	manager = newNullPolicyManager(t, ",zeroValuePolicy:null")
+	if manager == nil {
+		return
+	}
+	_, _, err = manager.PersistSingle(&nullableItem{Id: 5}, "items", nil)
+	assert.Nil(t, err)
+	item = readItem{}
+	_, err = manager.ReadSingle(&item, "SELECT id, name, qty FROM items WHERE id = ?", []interface{}{5}, nil)
+	assert.Nil(t, err)
+	assert.False(t, item.Name.Valid)
+	assert.Nil(t, item.Qty)
+}
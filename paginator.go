@@ -0,0 +1,249 @@
+package dsc
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/viant/toolbox"
+)
+
+// pageCursor represents decoded cursor token, it carries last key values and key columns hash,
+// key columns are never taken from the token, so that a token can not alter paginator SQL
+type pageCursor struct {
+	Hash   string        `json:"h"`
+	Values []interface{} `json:"v"`
+}
+
+// keyColumnsHash returns hash of key columns, it rejects token created for other key columns
+func keyColumnsHash(columns []string) string {
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(strings.Join(columns, ",")))
+	return strconv.FormatUint(uint64(hash.Sum32()), 36)
+}
+
+func encodeCursor(columns []string, values []interface{}) (string, error) {
+	encoded, err := json.Marshal(&pageCursor{Hash: keyColumnsHash(columns), Values: values})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode cursor due to %v", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(encoded), nil
+}
+
+func decodeCursor(token string, columns []string) (*pageCursor, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor %q due to %v", token, err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(decoded))
+	decoder.UseNumber()
+	var result = &pageCursor{}
+	if err = decoder.Decode(result); err != nil {
+		return nil, fmt.Errorf("invalid cursor %q due to %v", token, err)
+	}
+	if result.Hash != keyColumnsHash(columns) || len(columns) != len(result.Values) {
+		return nil, fmt.Errorf("invalid cursor %q: key columns mismatch", token)
+	}
+	for i, value := range result.Values {
+		if number, ok := value.(json.Number); ok {
+			if intValue, err := number.Int64(); err == nil {
+				result.Values[i] = intValue
+			} else if floatValue, err := number.Float64(); err == nil {
+				result.Values[i] = floatValue
+			}
+		}
+	}
+	return result, nil
+}
+
+// Paginator walks a table page by page with keyset (seek) pagination: each page is selected with key columns greater than
+// the last key of the previous page, ordered by the key columns, so that deep pages do not pay OFFSET scan cost
+type Paginator struct {
+	manager Manager
+	table   string
+	//PageSize maximum number of records in a page
+	PageSize int
+	//KeyColumns ordering and seek columns, if empty table descriptor, struct tags or dialect key is used
+	KeyColumns []string
+	//Descending walks table in descending key order
+	Descending bool
+	//Criteria optional additional where criteria, i.e. "status = ?"
+	Criteria string
+	//Parameters criteria parameters
+	Parameters []interface{}
+}
+
+// keyColumns returns paginator key columns for passed in record type
+func (p *Paginator) keyColumns(recordType reflect.Type) ([]string, error) {
+	if len(p.KeyColumns) > 0 {
+		return p.KeyColumns, nil
+	}
+	registry := p.manager.TableDescriptorRegistry()
+	if registry.Has(p.table) {
+		if descriptor := registry.Get(p.table); len(descriptor.PkColumns) > 0 {
+			return descriptor.PkColumns, nil
+		}
+	}
+	if recordType != nil && recordType.Kind() == reflect.Struct {
+		if descriptor, err := NewTableDescriptor(p.table, recordType); err == nil && len(descriptor.PkColumns) > 0 {
+			return descriptor.PkColumns, nil
+		}
+	}
+	if dialect := GetDatastoreDialect(p.manager.Config().DriverName); dialect != nil {
+		if datastore, err := dialect.GetCurrentDatastore(p.manager); err == nil {
+			var result = make([]string, 0)
+			for _, column := range strings.Split(dialect.GetKeyName(p.manager, datastore, p.table), ",") {
+				if column = strings.TrimSpace(column); column != "" {
+					result = append(result, column)
+				}
+			}
+			if len(result) > 0 {
+				return result, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("failed to paginate %v: unable to resolve key columns", p.table)
+}
+
+// limit returns dialect specific top and limit clauses
+func (p *Paginator) limit() (string, string) {
+	switch p.manager.Config().DriverName {
+	case "ora", "oci8":
+		return "", fmt.Sprintf(" FETCH FIRST %d ROWS ONLY", p.PageSize)
+	case "sqlserver":
+		return fmt.Sprintf("TOP %d ", p.PageSize), ""
+	}
+	return "", fmt.Sprintf(" LIMIT %d", p.PageSize)
+}
+
+func (p *Paginator) query(keyColumns []string, cursor *pageCursor) *ParametrizedSQL {
+	var projection, from = "*", p.table
+	registry := p.manager.TableDescriptorRegistry()
+	if registry.Has(p.table) {
+		descriptor := registry.Get(p.table)
+		from = descriptor.From()
+		if len(descriptor.Columns) > 0 {
+			projection = strings.Join(descriptor.Columns, ", ")
+		}
+	}
+	var operator, direction = ">", ""
+	if p.Descending {
+		operator, direction = "<", " DESC"
+	}
+	var criteria = make([]string, 0)
+	var parameters = make([]interface{}, 0)
+	if p.Criteria != "" {
+		criteria = append(criteria, "("+p.Criteria+")")
+		parameters = append(parameters, p.Parameters...)
+	}
+	if cursor != nil {
+		//(k1 > ?) OR (k1 = ? AND k2 > ?) ..., expanded form, as not every dialect supports row value comparison
+		var seek = make([]string, 0)
+		for i := range keyColumns {
+			var terms = make([]string, 0)
+			for j := 0; j < i; j++ {
+				terms = append(terms, keyColumns[j]+" = ?")
+				parameters = append(parameters, cursor.Values[j])
+			}
+			terms = append(terms, keyColumns[i]+" "+operator+" ?")
+			parameters = append(parameters, cursor.Values[i])
+			seek = append(seek, "("+strings.Join(terms, " AND ")+")")
+		}
+		criteria = append(criteria, "("+strings.Join(seek, " OR ")+")")
+	}
+	var order = make([]string, 0)
+	for _, column := range keyColumns {
+		order = append(order, column+direction)
+	}
+	top, limit := p.limit()
+	var where string
+	if len(criteria) > 0 {
+		where = " WHERE " + strings.Join(criteria, " AND ")
+	}
+	return &ParametrizedSQL{
+		SQL:    fmt.Sprintf("SELECT %v%v FROM %v%v ORDER BY %v%v", top, projection, from, where, strings.Join(order, ", "), limit),
+		Values: parameters,
+	}
+}
+
+// SQL returns page SQL for passed in cursor token, empty cursor selects the first page
+func (p *Paginator) SQL(cursor string) (*ParametrizedSQL, error) {
+	keyColumns, err := p.keyColumns(nil)
+	if err != nil {
+		return nil, err
+	}
+	var decoded *pageCursor
+	if cursor != "" {
+		if decoded, err = decodeCursor(cursor, keyColumns); err != nil {
+			return nil, err
+		}
+	}
+	return p.query(keyColumns, decoded), nil
+}
+
+// recordKey returns key column values of passed in struct or map record
+func (p *Paginator) recordKey(record interface{}, keyColumns []string) ([]interface{}, error) {
+	if toolbox.IsMap(record) {
+		var aMap = toolbox.AsMap(record)
+		var result = make([]interface{}, len(keyColumns))
+		for i, column := range keyColumns {
+			value, ok := aMap[column]
+			if !ok {
+				return nil, fmt.Errorf("failed to paginate %v: key column %v was not selected", p.table, column)
+			}
+			result[i] = value
+		}
+		return result, nil
+	}
+	provider, err := newMetaDmlProvider(p.table, toolbox.DiscoverTypeByKind(record, reflect.Struct), nil)
+	if err != nil {
+		return nil, err
+	}
+	return provider.(*metaDmlProvider).readValues(record, keyColumns), nil
+}
+
+// Page reads a page following passed in cursor token (empty for the first page) into result slice pointer,
+// it returns the next page cursor token or empty string if there are no more pages
+func (p *Paginator) Page(resultSlicePointer interface{}, cursor string) (string, error) {
+	if p.PageSize <= 0 {
+		return "", fmt.Errorf("failed to paginate %v: invalid page size %v", p.table, p.PageSize)
+	}
+	toolbox.AssertPointerKind(resultSlicePointer, reflect.Slice, "resultSlicePointer")
+	recordType := reflect.TypeOf(resultSlicePointer).Elem().Elem()
+	if recordType.Kind() == reflect.Ptr {
+		recordType = recordType.Elem()
+	}
+	keyColumns, err := p.keyColumns(recordType)
+	if err != nil {
+		return "", err
+	}
+	var decoded *pageCursor
+	if cursor != "" {
+		if decoded, err = decodeCursor(cursor, keyColumns); err != nil {
+			return "", err
+		}
+	}
+	query := p.query(keyColumns, decoded)
+	if err = p.manager.ReadAll(resultSlicePointer, query.SQL, query.Values, nil); err != nil {
+		return "", err
+	}
+	slice := reflect.ValueOf(resultSlicePointer).Elem()
+	if slice.Len() < p.PageSize {
+		return "", nil
+	}
+	key, err := p.recordKey(slice.Index(slice.Len()-1).Interface(), keyColumns)
+	if err != nil {
+		return "", err
+	}
+	return encodeCursor(keyColumns, key)
+}
+
+// Paginator returns keyset paginator for passed in table
+func (m *AbstractManager) Paginator(table string, pageSize int) *Paginator {
+	return &Paginator{manager: m.Manager, table: table, PageSize: pageSize}
+}
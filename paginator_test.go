@@ -0,0 +1,92 @@
+package dsc_test
+
+import (
+	"encoding/base64"
+	"fmt"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/dsc"
+)
+
+type pageRecord struct {
+	Region string `column:"region" primaryKey:"true"`
+	ID     int    `column:"id" primaryKey:"true"`
+	Name   string `column:"name"`
+}
+
+func TestPaginator(t *testing.T) {
+	manager, err := dsc.NewManagerFactory().Create(dsc.NewConfig("sqlite3", "[url]", "url:./test/pages.db"))
+	if !assert.Nil(t, err) {
+		return
+	}
+	for _, SQL := range []string{"DROP TABLE IF EXISTS pages", "CREATE TABLE pages(region TEXT, id INTEGER, name TEXT, PRIMARY KEY(region, id))"} {
+		_, err = manager.Execute(SQL)
+		assert.Nil(t, err)
+	}
+	for i := 1; i <= 7; i++ {
+		for _, region := range []string{"eu", "us"} {
+			_, err = manager.Execute("INSERT INTO pages(region, id, name) VALUES(?, ?, ?)", region, i, fmt.Sprintf("%v-%v", region, i))
+			assert.Nil(t, err)
+		}
+	}
+
+	paginator := manager.(dsc.PaginatorProvider).Paginator("pages", 4)
+	paginator.KeyColumns = []string{"region", "id"}
+	var names = make([]string, 0)
+	var pages, cursor = 0, ""
+	for {
+		var records = make([]*pageRecord, 0)
+		cursor, err = paginator.Page(&records, cursor)
+		if !assert.Nil(t, err) {
+			return
+		}
+		pages++
+		for _, record := range records {
+			names = append(names, record.Name)
+		}
+		if cursor == "" {
+			break
+		}
+	}
+	assert.Equal(t, 4, pages)
+	assert.EqualValues(t, []string{"eu-1", "eu-2", "eu-3", "eu-4", "eu-5", "eu-6", "eu-7", "us-1", "us-2", "us-3", "us-4", "us-5", "us-6", "us-7"}, names)
+
+	{ //map records, descending order and criteria
+		paginator := manager.(dsc.PaginatorProvider).Paginator("pages", 2)
+		paginator.KeyColumns = []string{"id"}
+		paginator.Descending = true
+		paginator.Criteria = "region = ?"
+		paginator.Parameters = []interface{}{"us"}
+		var records = make([]map[string]interface{}, 0)
+		cursor, err := paginator.Page(&records, "")
+		if assert.Nil(t, err) && assert.Equal(t, 2, len(records)) {
+			assert.EqualValues(t, 7, records[0]["id"])
+			records = make([]map[string]interface{}, 0)
+			_, err = paginator.Page(&records, cursor)
+			assert.Nil(t, err)
+			assert.EqualValues(t, 5, records[0]["id"])
+		}
+		query, err := paginator.SQL(cursor)
+		if assert.Nil(t, err) {
+			assert.Equal(t, "SELECT * FROM pages WHERE (region = ?) AND ((id < ?)) ORDER BY id DESC LIMIT 2", query.SQL)
+		}
+	}
+
+	_, err = paginator.Page(&[]*pageRecord{}, "not a cursor")
+	assert.NotNil(t, err)
+
+	{ //token crafted with other key columns is rejected
+		forged := base64.RawURLEncoding.EncodeToString([]byte(`{"c":["1=1 OR id"],"h":"x","v":[1,2]}`))
+		_, err = paginator.Page(&[]*pageRecord{}, forged)
+		assert.NotNil(t, err)
+		other := manager.(dsc.PaginatorProvider).Paginator("pages", 4)
+		other.KeyColumns = []string{"id"}
+		var records = make([]*pageRecord, 0)
+		cursor, err := other.Page(&records, "")
+		assert.Nil(t, err)
+		_, err = paginator.Page(&records, cursor)
+		assert.NotNil(t, err, "token created for other key columns should be rejected")
+	}
+}
@@ -0,0 +1,241 @@
+package dsc
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/viant/toolbox"
+)
+
+const defaultPartitions = 4
+
+// PartitionOptions represents parallel read partitioning, partition criteria are applied to the query result, so partition column has to be selected by the query
+type PartitionOptions struct {
+	//Partitions number of partitions read concurrently on separate connections, default 4
+	Partitions int
+	//Column numeric partition column, rows are split into min/max key ranges
+	Column string
+	//Modulo splits rows by column modulo instead of key ranges
+	Modulo bool
+	//Table partitioned table, if dialect provides partition metadata (mysql RANGE/LIST partitions) partitions follow table partitions
+	Table string
+	//Criteria explicit partition criteria, one partition per criterion, i.e. "region = 'eu'"
+	Criteria []string
+}
+
+// partitionDialect represents dialect providing table partition metadata, it returns one criterion per table partition
+type partitionDialect interface {
+	partitionCriteria(manager Manager, table string) ([]string, error)
+}
+
+var partitionIdentifier = regexp.MustCompile("^[A-Za-z_][A-Za-z0-9_]*$")
+
+// partitionCriteria returns mysql RANGE and LIST partitions criteria, other methods (HASH, KEY) or expressions return no criteria
+func (d mySQLDialect) partitionCriteria(manager Manager, table string) ([]string, error) {
+	datastore, err := d.GetCurrentDatastore(manager)
+	if err != nil {
+		return nil, err
+	}
+	var records = make([]map[string]interface{}, 0)
+	SQL := "SELECT PARTITION_METHOD AS method, PARTITION_EXPRESSION AS expression, PARTITION_DESCRIPTION AS description FROM information_schema.PARTITIONS " +
+		"WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? AND PARTITION_NAME IS NOT NULL ORDER BY PARTITION_ORDINAL_POSITION"
+	if err = manager.ReadAll(&records, SQL, []interface{}{datastore, table}, nil); err != nil {
+		return nil, err
+	}
+	var result = make([]string, 0)
+	var lowerBound string
+	for _, record := range records {
+		method := strings.ToUpper(toolbox.AsString(record["method"]))
+		column := strings.Trim(toolbox.AsString(record["expression"]), "`")
+		description := toolbox.AsString(record["description"])
+		if !partitionIdentifier.MatchString(column) {
+			return nil, nil
+		}
+		switch method {
+		case "RANGE", "RANGE COLUMNS":
+			var terms = make([]string, 0)
+			if lowerBound != "" {
+				terms = append(terms, fmt.Sprintf("%v >= %v", column, lowerBound))
+			}
+			if description != "MAXVALUE" {
+				terms = append(terms, fmt.Sprintf("%v < %v", column, description))
+			}
+			if len(terms) == 0 {
+				terms = append(terms, "1 = 1")
+			}
+			lowerBound = description
+			result = append(result, strings.Join(terms, " AND "))
+		case "LIST", "LIST COLUMNS":
+			result = append(result, fmt.Sprintf("%v IN (%v)", column, description))
+		default:
+			return nil, nil
+		}
+	}
+	return result, nil
+}
+
+// partitionSQL represents partition query with its parameters
+type partitionSQL struct {
+	SQL        string
+	Parameters []interface{}
+}
+
+// partitionQueries splits query into partition queries
+func (m *AbstractManager) partitionQueries(query string, parameters []interface{}, options *PartitionOptions) ([]*partitionSQL, error) {
+	if options == nil {
+		return nil, fmt.Errorf("failed to partition %v: partition options were empty", query)
+	}
+	criteria := options.Criteria
+	if len(criteria) == 0 && options.Table != "" {
+		if dialect, ok := GetDatastoreDialect(m.Manager.Config().DriverName).(partitionDialect); ok {
+			var err error
+			if criteria, err = dialect.partitionCriteria(m.Manager, options.Table); err != nil {
+				return nil, fmt.Errorf("failed to read %v partitions due to %w", options.Table, err)
+			}
+		}
+	}
+	if len(criteria) == 0 {
+		var err error
+		if criteria, err = m.columnPartitionCriteria(query, parameters, options); err != nil {
+			return nil, err
+		}
+	}
+	var result = make([]*partitionSQL, 0, len(criteria))
+	for _, criterion := range criteria {
+		result = append(result, &partitionSQL{
+			SQL:        fmt.Sprintf("SELECT * FROM (%v) t WHERE %v", query, criterion),
+			Parameters: parameters,
+		})
+	}
+	return result, nil
+}
+
+// columnPartitionCriteria returns modulo or key range criteria for partition column
+func (m *AbstractManager) columnPartitionCriteria(query string, parameters []interface{}, options *PartitionOptions) ([]string, error) {
+	if options.Column == "" {
+		return nil, fmt.Errorf("failed to partition %v: partition column, criteria or partitioned table metadata are required", query)
+	}
+	partitions := options.Partitions
+	if partitions <= 0 {
+		partitions = defaultPartitions
+	}
+	column := options.Column
+	var result = make([]string, 0, partitions)
+	if options.Modulo {
+		modulo := "ABS(%v %% %d) = %d"
+		switch m.Manager.Config().DriverName {
+		case "ora", "oci8":
+			modulo = "ABS(MOD(%v, %d)) = %d"
+		}
+		for i := 0; i < partitions; i++ {
+			criterion := fmt.Sprintf(modulo, column, partitions, i)
+			if i == 0 {
+				criterion = fmt.Sprintf("(%v OR %v IS NULL)", criterion, column)
+			}
+			result = append(result, criterion)
+		}
+		return result, nil
+	}
+	var bounds = make([]interface{}, 0)
+	if _, err := m.Manager.ReadSingle(&bounds, fmt.Sprintf("SELECT MIN(%v), MAX(%v) FROM (%v) t", column, column, query), parameters, nil); err != nil {
+		return nil, fmt.Errorf("failed to read %v partition bounds due to %w", column, err)
+	}
+	if len(bounds) != 2 || bounds[0] == nil || bounds[1] == nil {
+		return []string{"1 = 1"}, nil
+	}
+	min, err := toolbox.ToInt(bounds[0])
+	if err != nil {
+		return nil, fmt.Errorf("failed to partition by %v: non numeric bound %v", column, bounds[0])
+	}
+	max, err := toolbox.ToInt(bounds[1])
+	if err != nil {
+		return nil, fmt.Errorf("failed to partition by %v: non numeric bound %v", column, bounds[1])
+	}
+	step := (max - min + partitions) / partitions
+	for lower := min; lower <= max; lower += step {
+		upper := lower + step
+		if upper > max {
+			result = append(result, fmt.Sprintf("(%v >= %d OR %v IS NULL)", column, lower, column))
+			break
+		}
+		result = append(result, fmt.Sprintf("%v >= %d AND %v < %d", column, lower, column, upper))
+	}
+	return result, nil
+}
+
+// ReadAllParallel splits query into partitions read concurrently on separate connections, partition results are appended to result slice pointer in partition order
+func (m *AbstractManager) ReadAllParallel(resultSlicePointer interface{}, query string, parameters []interface{}, options *PartitionOptions, mapper RecordMapper) error {
+	toolbox.AssertPointerKind(resultSlicePointer, reflect.Slice, "resultSlicePointer")
+	queries, err := m.partitionQueries(query, parameters, options)
+	if err != nil {
+		return err
+	}
+	sliceType := reflect.TypeOf(resultSlicePointer).Elem()
+	var results = make([]reflect.Value, len(queries))
+	var errors = make([]error, len(queries))
+	waitGroup := &sync.WaitGroup{}
+	for i, partition := range queries {
+		waitGroup.Add(1)
+		go func(i int, partition *partitionSQL) {
+			defer waitGroup.Done()
+			results[i] = reflect.New(sliceType)
+			results[i].Elem().Set(reflect.MakeSlice(sliceType, 0, 0))
+			errors[i] = m.Manager.ReadAll(results[i].Interface(), partition.SQL, partition.Parameters, mapper)
+		}(i, partition)
+	}
+	waitGroup.Wait()
+	target := reflect.ValueOf(resultSlicePointer).Elem()
+	for i := range queries {
+		if errors[i] != nil {
+			return fmt.Errorf("failed to read partition %v due to %w", i, errors[i])
+		}
+		target.Set(reflect.AppendSlice(target, results[i].Elem()))
+	}
+	return nil
+}
+
+// ReadAllParallelWithHandler splits query into partitions read concurrently on separate connections, handler calls are serialized,
+// returning false or error from handler stops reading all partitions
+func (m *AbstractManager) ReadAllParallelWithHandler(query string, parameters []interface{}, options *PartitionOptions, readingHandler func(scanner Scanner) (toContinue bool, err error)) error {
+	queries, err := m.partitionQueries(query, parameters, options)
+	if err != nil {
+		return err
+	}
+	var mutex = &sync.Mutex{}
+	var stopped bool
+	var errors = make([]error, len(queries))
+	waitGroup := &sync.WaitGroup{}
+	for i, partition := range queries {
+		waitGroup.Add(1)
+		go func(i int, partition *partitionSQL) {
+			defer waitGroup.Done()
+			errors[i] = m.Manager.ReadAllWithHandler(partition.SQL, partition.Parameters, func(scanner Scanner) (bool, error) {
+				mutex.Lock()
+				defer mutex.Unlock()
+				if stopped {
+					return false, nil
+				}
+				toContinue, err := readingHandler(scanner)
+				if err != nil || !toContinue {
+					stopped = true
+				}
+				return toContinue, err
+			})
+			if errors[i] != nil {
+				mutex.Lock()
+				stopped = true
+				mutex.Unlock()
+			}
+		}(i, partition)
+	}
+	waitGroup.Wait()
+	for i := range queries {
+		if errors[i] != nil {
+			return fmt.Errorf("failed to read partition %v due to %w", i, errors[i])
+		}
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package dsc_test
+
+import (
+	"sort"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/dsc"
+)
+
+func TestManager_ReadAllParallel(t *testing.T) {
+	manager, err := dsc.NewManagerFactory().Create(dsc.NewConfig("sqlite3", "[url]", "url:./test/parallel.db"))
+	if !assert.Nil(t, err) {
+		return
+	}
+	for _, SQL := range []string{"DROP TABLE IF EXISTS events", "CREATE TABLE events(id INTEGER PRIMARY KEY, kind TEXT)"} {
+		_, err = manager.Execute(SQL)
+		assert.Nil(t, err)
+	}
+	for i := 1; i <= 25; i++ {
+		kind := "a"
+		if i%2 == 0 {
+			kind = "b"
+		}
+		_, err = manager.Execute("INSERT INTO events(id, kind) VALUES(?, ?)", i, kind)
+		assert.Nil(t, err)
+	}
+	type event struct {
+		ID   int    `column:"id"`
+		Kind string `column:"kind"`
+	}
+	ids := func(events []*event) []int {
+		var result = make([]int, 0)
+		for _, event := range events {
+			result = append(result, event.ID)
+		}
+		sort.Ints(result)
+		return result
+	}
+
+	var useCases = []struct {
+		description string
+		options     *dsc.PartitionOptions
+	}{
+		{"key range", &dsc.PartitionOptions{Column: "id", Partitions: 4}},
+		{"modulo", &dsc.PartitionOptions{Column: "id", Partitions: 3, Modulo: true}},
+		{"criteria", &dsc.PartitionOptions{Criteria: []string{"id <= 10", "id > 10"}}},
+	}
+	for _, useCase := range useCases {
+		var events = make([]*event, 0)
+		err = manager.(dsc.ParallelReader).ReadAllParallel(&events, "SELECT id, kind FROM events WHERE kind = ?", []interface{}{"a"}, useCase.options, nil)
+		if assert.Nil(t, err, useCase.description) {
+			assert.EqualValues(t, []int{1, 3, 5, 7, 9, 11, 13, 15, 17, 19, 21, 23, 25}, ids(events), useCase.description)
+		}
+	}
+
+	count := 0
+	err = manager.(dsc.ParallelReader).ReadAllParallelWithHandler("SELECT id FROM events", nil, &dsc.PartitionOptions{Column: "id", Partitions: 5}, func(scanner dsc.Scanner) (bool, error) {
+		count++
+		return count < 7, nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 7, count)
+
+	err = manager.(dsc.ParallelReader).ReadAllParallel(&[]*event{}, "SELECT id FROM events", nil, &dsc.PartitionOptions{}, nil)
+	assert.NotNil(t, err)
+}
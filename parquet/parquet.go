@@ -0,0 +1,163 @@
+// Package parquet registers parquet export format, import it for side effects:
+//
+//	import _ "github.com/viant/dsc/parquet"
+package parquet
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"time"
+
+	pq "github.com/parquet-go/parquet-go"
+	"github.com/viant/dsc"
+	"github.com/viant/toolbox"
+)
+
+const (
+	kindString = iota
+	kindInt
+	kindDouble
+	kindBoolean
+	kindTimestamp
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// columnKind returns parquet column kind for driver scan type
+func columnKind(column *dsc.ExportColumn) int {
+	valueType := column.ValueType()
+	if valueType == nil {
+		return kindString
+	}
+	if valueType == timeType {
+		return kindTimestamp
+	}
+	switch valueType.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return kindInt
+	case reflect.Float32, reflect.Float64:
+		return kindDouble
+	case reflect.Bool:
+		return kindBoolean
+	}
+	return kindString
+}
+
+// writer represents parquet export writer, all columns are optional to preserve NULL values
+type writer struct {
+	writer  *pq.Writer
+	columns []*dsc.ExportColumn
+	kinds   []int
+	indexes []int
+	rows    []pq.Row
+}
+
+// value converts formatted value into parquet column value
+func (w *writer) value(i int, value interface{}) (pq.Value, error) {
+	var err error
+	var result pq.Value
+	switch w.kinds[i] {
+	case kindInt:
+		var intValue int
+		if intValue, err = toolbox.ToInt(value); err == nil {
+			result = pq.Int64Value(int64(intValue))
+		}
+	case kindDouble:
+		var floatValue float64
+		if floatValue, err = toolbox.ToFloat(value); err == nil {
+			result = pq.DoubleValue(floatValue)
+		}
+	case kindBoolean:
+		result = pq.BooleanValue(toolbox.AsBoolean(value))
+	case kindTimestamp:
+		var timeValue *time.Time
+		if timeValue, err = toolbox.ToTime(value, ""); err == nil {
+			result = pq.Int64Value(timeValue.UnixNano())
+		}
+	default:
+		if bytes, ok := value.([]byte); ok {
+			result = pq.ByteArrayValue(bytes)
+		} else {
+			result = pq.ByteArrayValue([]byte(toolbox.AsString(value)))
+		}
+	}
+	if err != nil {
+		return result, fmt.Errorf("failed to convert %v value %v due to %v", w.columns[i].Name, value, err)
+	}
+	return result, nil
+}
+
+func (w *writer) Write(values []interface{}) error {
+	var row = make(pq.Row, len(values))
+	for i, value := range values {
+		if value == nil {
+			row[w.indexes[i]] = pq.NullValue().Level(0, 0, w.indexes[i])
+			continue
+		}
+		columnValue, err := w.value(i, value)
+		if err != nil {
+			return err
+		}
+		row[w.indexes[i]] = columnValue.Level(0, 1, w.indexes[i])
+	}
+	w.rows = append(w.rows, row)
+	if len(w.rows) >= 1024 {
+		return w.flush()
+	}
+	return nil
+}
+
+func (w *writer) flush() error {
+	if len(w.rows) == 0 {
+		return nil
+	}
+	_, err := w.writer.WriteRows(w.rows)
+	w.rows = w.rows[:0]
+	return err
+}
+
+func (w *writer) Close() error {
+	if err := w.flush(); err != nil {
+		return err
+	}
+	return w.writer.Close()
+}
+
+// newWriter creates parquet export writer, column types are derived from driver scan types, unknown types are written as strings
+func newWriter(output io.Writer, columns []*dsc.ExportColumn, options *dsc.ExportOptions) (dsc.ExportWriter, error) {
+	var group = pq.Group{}
+	var result = &writer{columns: columns, kinds: make([]int, len(columns)), indexes: make([]int, len(columns))}
+	for i, column := range columns {
+		if _, ok := group[column.Name]; ok {
+			return nil, fmt.Errorf("failed to create parquet schema: duplicate column %v", column.Name)
+		}
+		result.kinds[i] = columnKind(column)
+		var node pq.Node
+		switch result.kinds[i] {
+		case kindInt:
+			node = pq.Int(64)
+		case kindDouble:
+			node = pq.Leaf(pq.DoubleType)
+		case kindBoolean:
+			node = pq.Leaf(pq.BooleanType)
+		case kindTimestamp:
+			node = pq.Timestamp(pq.Nanosecond)
+		default:
+			node = pq.String()
+		}
+		group[column.Name] = pq.Optional(node)
+	}
+	schema := pq.NewSchema("export", group)
+	for i, column := range columns {
+		leaf, _ := schema.Lookup(column.Name)
+		result.indexes[i] = leaf.ColumnIndex
+	}
+	result.writer = pq.NewWriter(output, schema)
+	return result, nil
+}
+
+func init() {
+	dsc.RegisterExportFormat(dsc.ExportFormatParquet, newWriter)
+}
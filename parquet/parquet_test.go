@@ -0,0 +1,49 @@
+package parquet_test
+
+import (
+	"bytes"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	pq "github.com/parquet-go/parquet-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/dsc"
+	_ "github.com/viant/dsc/parquet"
+)
+
+func TestExport(t *testing.T) {
+	manager, err := dsc.NewManagerFactory().Create(dsc.NewConfig("sqlite3", "[url]", "url:../test/parquet.db"))
+	if !assert.Nil(t, err) {
+		return
+	}
+	for _, SQL := range []string{"DROP TABLE IF EXISTS accounts", "CREATE TABLE accounts(id INTEGER PRIMARY KEY, name TEXT, balance REAL)",
+		"INSERT INTO accounts(id, name, balance) VALUES(1, 'Bob', 10.5)", "INSERT INTO accounts(id, name) VALUES(2, 'Ann')"} {
+		_, err = manager.Execute(SQL)
+		assert.Nil(t, err)
+	}
+	buffer := new(bytes.Buffer)
+	count, err := manager.(dsc.Exporter).Export(buffer, "SELECT id, name, balance FROM accounts ORDER BY id", nil, &dsc.ExportOptions{Format: dsc.ExportFormatParquet})
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, 2, count)
+	file, err := pq.OpenFile(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.EqualValues(t, 2, file.NumRows())
+	type account struct {
+		ID      *int64   `parquet:"id,optional"`
+		Name    *string  `parquet:"name,optional"`
+		Balance *float64 `parquet:"balance,optional"`
+	}
+	reader := pq.NewGenericReader[account](bytes.NewReader(buffer.Bytes()))
+	var accounts = make([]account, 2)
+	read, _ := reader.Read(accounts)
+	if assert.Equal(t, 2, read) {
+		assert.EqualValues(t, 1, *accounts[0].ID)
+		assert.Equal(t, "Bob", *accounts[0].Name)
+		assert.EqualValues(t, 10.5, *accounts[0].Balance)
+		assert.Nil(t, accounts[1].Balance)
+	}
+}
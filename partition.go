@@ -0,0 +1,318 @@
+package dsc
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/viant/toolbox"
+)
+
+// Partition methods
+const (
+	PartitionMethodRange = "RANGE"
+	PartitionMethodList  = "LIST"
+)
+
+const partitionMaxValue = "MAXVALUE"
+
+// TablePartition represents table partition, bounds and values are SQL literals, i.e. '2024-01-01', 100 or MAXVALUE
+type TablePartition struct {
+	Name string
+	//Method partitioning method: RANGE, LIST (HASH and KEY partitions are listed, but can not be created)
+	Method string
+	//Expression partition key expression
+	Expression string `json:",omitempty"`
+	//From range partition inclusive lower bound, mysql partition starts at upper bound of previous partition
+	From string `json:",omitempty"`
+	//To range partition exclusive upper bound, MAXVALUE for unbounded
+	To string `json:",omitempty"`
+	//Values list partition values
+	Values []string `json:",omitempty"`
+	//Default postgres default partition
+	Default bool `json:",omitempty"`
+}
+
+// GetPartitions returns an error, default dialect does not support partitions
+func (d DefaultDialect) GetPartitions(manager Manager, table string) ([]*TablePartition, error) {
+	return nil, fmt.Errorf("failed to get %v partitions due to %v", table, errUnsupportedOperation)
+}
+
+// CreatePartition returns an error, default dialect does not support partitions
+func (d DefaultDialect) CreatePartition(manager Manager, table string, partition *TablePartition) error {
+	return fmt.Errorf("failed to create %v partition due to %v", table, errUnsupportedOperation)
+}
+
+// DropPartition returns an error, default dialect does not support partitions
+func (d DefaultDialect) DropPartition(manager Manager, table, partition string) error {
+	return fmt.Errorf("failed to drop %v partition %v due to %v", table, partition, errUnsupportedOperation)
+}
+
+// GetPartitions returns an error, dialect does not know how to manage partitions
+func (d sqlDatastoreDialect) GetPartitions(manager Manager, table string) ([]*TablePartition, error) {
+	return nil, fmt.Errorf("failed to get %v partitions due to %v", table, errUnsupportedOperation)
+}
+
+// CreatePartition returns an error, dialect does not know how to manage partitions
+func (d sqlDatastoreDialect) CreatePartition(manager Manager, table string, partition *TablePartition) error {
+	return fmt.Errorf("failed to create %v partition due to %v", table, errUnsupportedOperation)
+}
+
+// DropPartition returns an error, dialect does not know how to manage partitions
+func (d sqlDatastoreDialect) DropPartition(manager Manager, table, partition string) error {
+	return fmt.Errorf("failed to drop %v partition %v due to %v", table, partition, errUnsupportedOperation)
+}
+
+// GetPartitions returns mysql table partitions, range partition lower bound is taken from the previous partition
+func (d mySQLDialect) GetPartitions(manager Manager, table string) ([]*TablePartition, error) {
+	datastore, err := d.GetCurrentDatastore(manager)
+	if err != nil {
+		return nil, err
+	}
+	var records = make([]map[string]interface{}, 0)
+	SQL := "SELECT PARTITION_NAME AS name, PARTITION_METHOD AS method, PARTITION_EXPRESSION AS expression, PARTITION_DESCRIPTION AS description FROM information_schema.PARTITIONS " +
+		"WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? AND PARTITION_NAME IS NOT NULL ORDER BY PARTITION_ORDINAL_POSITION"
+	if err = manager.ReadAll(&records, SQL, []interface{}{datastore, table}, nil); err != nil {
+		return nil, fmt.Errorf("failed to get %v partitions due to %v", table, err)
+	}
+	var result = make([]*TablePartition, 0)
+	var lowerBound string
+	for _, record := range records {
+		partition := &TablePartition{
+			Name:       toolbox.AsString(record["name"]),
+			Method:     strings.TrimSuffix(strings.ToUpper(toolbox.AsString(record["method"])), " COLUMNS"),
+			Expression: toolbox.AsString(record["expression"]),
+		}
+		description := toolbox.AsString(record["description"])
+		switch partition.Method {
+		case PartitionMethodRange:
+			partition.From, partition.To = lowerBound, description
+			lowerBound = description
+		case PartitionMethodList:
+			partition.Values = splitPartitionValues(description)
+		}
+		result = append(result, partition)
+	}
+	return result, nil
+}
+
+// CreatePartition adds RANGE (VALUES LESS THAN) or LIST partition to mysql partitioned table, range partition has to be added after the last one
+func (d mySQLDialect) CreatePartition(manager Manager, table string, partition *TablePartition) error {
+	definition, err := partitionDefinition(partition)
+	if err != nil {
+		return fmt.Errorf("failed to create %v partition due to %v", table, err)
+	}
+	switch partition.Method {
+	case PartitionMethodRange:
+		definition = fmt.Sprintf("VALUES LESS THAN (%v)", partition.To)
+	case PartitionMethodList:
+		definition = fmt.Sprintf("VALUES IN (%v)", strings.Join(partition.Values, ", "))
+	}
+	SQL := fmt.Sprintf("ALTER TABLE %v ADD PARTITION (PARTITION %v %v)", table, partition.Name, definition)
+	if _, err = manager.Execute(SQL); err != nil {
+		return fmt.Errorf("failed to create %v partition %v due to %v", table, partition.Name, err)
+	}
+	return nil
+}
+
+// DropPartition drops mysql table partition with its rows
+func (d mySQLDialect) DropPartition(manager Manager, table, partition string) error {
+	if _, err := manager.Execute(fmt.Sprintf("ALTER TABLE %v DROP PARTITION %v", table, partition)); err != nil {
+		return fmt.Errorf("failed to drop %v partition %v due to %v", table, partition, err)
+	}
+	return nil
+}
+
+var pgPartitionKey = regexp.MustCompile(`(?i)^\s*(\w+)\s*\((.*)\)\s*$`)
+var pgPartitionRange = regexp.MustCompile(`(?is)^\s*FOR VALUES FROM \((.*)\) TO \((.*)\)\s*$`)
+var pgPartitionList = regexp.MustCompile(`(?is)^\s*FOR VALUES IN \((.*)\)\s*$`)
+
+// GetPartitions returns postgres declarative partitions of the table
+func (d pgDialect) GetPartitions(manager Manager, table string) ([]*TablePartition, error) {
+	var records = make([]map[string]interface{}, 0)
+	SQL := "SELECT c.relname AS name, pg_get_expr(c.relpartbound, c.oid) AS bound, pg_get_partkeydef(i.inhparent) AS partition_key " +
+		"FROM pg_inherits i JOIN pg_class c ON c.oid = i.inhrelid WHERE i.inhparent = CAST(? AS regclass) ORDER BY c.relname"
+	if err := manager.ReadAll(&records, SQL, []interface{}{table}, nil); err != nil {
+		return nil, fmt.Errorf("failed to get %v partitions due to %v", table, err)
+	}
+	var result = make([]*TablePartition, 0)
+	for _, record := range records {
+		partition := &TablePartition{Name: toolbox.AsString(record["name"])}
+		if key := pgPartitionKey.FindStringSubmatch(toolbox.AsString(record["partition_key"])); len(key) > 0 {
+			partition.Method, partition.Expression = strings.ToUpper(key[1]), key[2]
+		}
+		parsePgPartitionBound(toolbox.AsString(record["bound"]), partition)
+		result = append(result, partition)
+	}
+	return result, nil
+}
+
+// parsePgPartitionBound sets partition bounds from pg_get_expr(relpartbound) output, i.e. FOR VALUES FROM ('2024-01-01') TO ('2024-02-01')
+func parsePgPartitionBound(bound string, partition *TablePartition) {
+	if strings.EqualFold(strings.TrimSpace(bound), "DEFAULT") {
+		partition.Default = true
+		return
+	}
+	if matched := pgPartitionRange.FindStringSubmatch(bound); len(matched) > 0 {
+		partition.From, partition.To = matched[1], matched[2]
+		return
+	}
+	if matched := pgPartitionList.FindStringSubmatch(bound); len(matched) > 0 {
+		partition.Values = splitPartitionValues(matched[1])
+	}
+}
+
+// CreatePartition creates postgres partition of the table (CREATE TABLE ... PARTITION OF), partition without bounds is created as the default one
+func (d pgDialect) CreatePartition(manager Manager, table string, partition *TablePartition) error {
+	var definition = "DEFAULT"
+	if !partition.Default {
+		var err error
+		if definition, err = partitionDefinition(partition); err != nil {
+			return fmt.Errorf("failed to create %v partition due to %v", table, err)
+		}
+	}
+	SQL := fmt.Sprintf("CREATE TABLE %v PARTITION OF %v %v", partition.Name, table, definition)
+	if _, err := manager.Execute(SQL); err != nil {
+		return fmt.Errorf("failed to create %v partition %v due to %v", table, partition.Name, err)
+	}
+	return nil
+}
+
+// DropPartition drops postgres partition table with its rows, it returns an error if partition is not a partition of the table
+func (d pgDialect) DropPartition(manager Manager, table, partition string) error {
+	var records = make([]map[string]interface{}, 0)
+	SQL := "SELECT i.inhrelid FROM pg_inherits i WHERE i.inhrelid = CAST(? AS regclass) AND i.inhparent = CAST(? AS regclass)"
+	if err := manager.ReadAll(&records, SQL, []interface{}{partition, table}, nil); err != nil {
+		return fmt.Errorf("failed to drop %v partition %v due to %v", table, partition, err)
+	}
+	if len(records) == 0 {
+		return fmt.Errorf("failed to drop %v partition %v: table is not a partition of %v", table, partition, table)
+	}
+	if _, err := manager.Execute(fmt.Sprintf("DROP TABLE %v", partition)); err != nil {
+		return fmt.Errorf("failed to drop %v partition %v due to %v", table, partition, err)
+	}
+	return nil
+}
+
+// partitionDefinition validates partition and returns postgres style bound definition
+func partitionDefinition(partition *TablePartition) (string, error) {
+	if partition == nil || partition.Name == "" {
+		return "", fmt.Errorf("partition name was empty")
+	}
+	switch partition.Method {
+	case PartitionMethodRange:
+		if partition.To == "" {
+			return "", fmt.Errorf("range partition %v upper bound was empty", partition.Name)
+		}
+		from := partition.From
+		if from == "" {
+			from = "MINVALUE"
+		}
+		return fmt.Sprintf("FOR VALUES FROM (%v) TO (%v)", from, partition.To), nil
+	case PartitionMethodList:
+		if len(partition.Values) == 0 {
+			return "", fmt.Errorf("list partition %v values were empty", partition.Name)
+		}
+		return fmt.Sprintf("FOR VALUES IN (%v)", strings.Join(partition.Values, ", ")), nil
+	}
+	return "", fmt.Errorf("unsupported partition %v method: %v", partition.Name, partition.Method)
+}
+
+// splitPartitionValues splits comma separated SQL literals, commas within quotes or parentheses are preserved
+func splitPartitionValues(text string) []string {
+	var result = make([]string, 0)
+	var quoted bool
+	var depth, start int
+	for i, char := range text {
+		switch {
+		case char == '\'':
+			quoted = !quoted
+		case quoted:
+		case char == '(':
+			depth++
+		case char == ')':
+			depth--
+		case char == ',' && depth == 0:
+			result = append(result, strings.TrimSpace(text[start:i]))
+			start = i + 1
+		}
+	}
+	if value := strings.TrimSpace(text[start:]); value != "" {
+		result = append(result, value)
+	}
+	return result
+}
+
+var partitionTimeLayouts = []string{"2006-01-02 15:04:05-07", "2006-01-02 15:04:05", "2006-01-02", time.RFC3339}
+
+// partitionExpired returns true if range upper bound literal is less than or equal to passed in value, MAXVALUE bound never expires,
+// bound and value are compared as times, numbers if both parse as numbers, or strings, it returns error if bound type does not match value type
+func partitionExpired(bound string, before interface{}) (bool, error) {
+	bound = strings.TrimSpace(bound)
+	if bound == "" || strings.EqualFold(bound, partitionMaxValue) {
+		return false, nil
+	}
+	literal := bound
+	if strings.HasPrefix(bound, "'") && strings.HasSuffix(bound, "'") && len(bound) > 1 {
+		bound = strings.Replace(bound[1:len(bound)-1], "''", "'", -1)
+	}
+	switch value := before.(type) {
+	case time.Time:
+		for _, layout := range partitionTimeLayouts {
+			if boundTime, err := time.Parse(layout, bound); err == nil {
+				return !boundTime.After(value), nil
+			}
+		}
+		return false, fmt.Errorf("partition bound %v is not a time", literal)
+	case *time.Time:
+		if value == nil {
+			return false, fmt.Errorf("partition cutoff was nil")
+		}
+		return partitionExpired(literal, *value)
+	}
+	boundValue, boundErr := toolbox.ToFloat(bound)
+	beforeValue, beforeErr := toolbox.ToFloat(before)
+	if boundErr == nil && beforeErr == nil {
+		return boundValue <= beforeValue, nil
+	}
+	if value, ok := before.(string); ok && boundErr != nil && beforeErr != nil {
+		return bound <= value, nil
+	}
+	return false, fmt.Errorf("partition bound %v type does not match cutoff %v (%T)", literal, before, before)
+}
+
+// DropExpiredPartitions drops range partitions with upper bound less than or equal to before (time, number or string), it returns names of dropped partitions,
+// no partition is dropped if any range bound type does not match before type,
+// i.e. DropExpiredPartitions(manager, "events", time.Now().AddDate(0, -3, 0)) implements 3 months retention of monthly partitioned table
+func DropExpiredPartitions(manager Manager, table string, before interface{}) ([]string, error) {
+	dialect, ok := GetDatastoreDialect(manager.Config().DriverName).(PartitionDialect)
+	if !ok {
+		return nil, fmt.Errorf("failed to drop expired %v partitions due to %v", table, errUnsupportedOperation)
+	}
+	partitions, err := dialect.GetPartitions(manager, table)
+	if err != nil {
+		return nil, err
+	}
+	var expired = make([]string, 0)
+	for _, partition := range partitions {
+		if partition.Method != PartitionMethodRange || partition.Default {
+			continue
+		}
+		isExpired, err := partitionExpired(partition.To, before)
+		if err != nil {
+			return nil, fmt.Errorf("failed to drop expired %v partitions due to %v", table, err)
+		}
+		if isExpired {
+			expired = append(expired, partition.Name)
+		}
+	}
+	var result = make([]string, 0)
+	for _, name := range expired {
+		if err = dialect.DropPartition(manager, table, name); err != nil {
+			return result, err
+		}
+		result = append(result, name)
+	}
+	return result, nil
+}
@@ -0,0 +1,28 @@
+package dsc_test
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/dsc"
+)
+
+func TestPgDialect_DropPartition(t *testing.T) {
+	manager, mock, closer := newPostgresMock(t, "dsc_drop_partition")
+	if manager == nil {
+		return
+	}
+	defer closer()
+	dialect := dsc.GetDatastoreDialect("postgres").(dsc.PartitionDialect)
+
+	mock.ExpectPrepare("SELECT i.inhrelid FROM pg_inherits").ExpectQuery().WithArgs("users", "events").
+		WillReturnRows(sqlmock.NewRows([]string{"inhrelid"}))
+	assert.NotNil(t, dialect.DropPartition(manager, "events", "users"), "table which is not a partition should not be dropped")
+
+	mock.ExpectPrepare("SELECT i.inhrelid FROM pg_inherits").ExpectQuery().WithArgs("events_2024_01", "events").
+		WillReturnRows(sqlmock.NewRows([]string{"inhrelid"}).AddRow(1))
+	mock.ExpectExec("DROP TABLE events_2024_01").WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.Nil(t, dialect.DropPartition(manager, "events", "events_2024_01"))
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
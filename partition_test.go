@@ -0,0 +1,72 @@
+package dsc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePgPartitionBound(t *testing.T) {
+	partition := &TablePartition{}
+	parsePgPartitionBound("FOR VALUES FROM ('2024-01-01 00:00:00') TO ('2024-02-01 00:00:00')", partition)
+	assert.Equal(t, "'2024-01-01 00:00:00'", partition.From)
+	assert.Equal(t, "'2024-02-01 00:00:00'", partition.To)
+
+	partition = &TablePartition{}
+	parsePgPartitionBound("FOR VALUES IN ('eu', 'a,b', 'o''neil')", partition)
+	assert.Equal(t, []string{"'eu'", "'a,b'", "'o''neil'"}, partition.Values)
+
+	partition = &TablePartition{}
+	parsePgPartitionBound("DEFAULT", partition)
+	assert.True(t, partition.Default)
+}
+
+func TestPartitionExpired(t *testing.T) {
+	cutoff := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	var useCases = []struct {
+		description string
+		bound       string
+		before      interface{}
+		expect      bool
+		hasError    bool
+	}{
+		{description: "date bound", bound: "'2024-02-01'", before: cutoff, expect: true},
+		{description: "timestamp bound", bound: "'2024-03-01 00:00:00+00'", before: cutoff, expect: true},
+		{description: "future date bound", bound: "'2024-04-01'", before: cutoff},
+		{description: "max value", bound: "MAXVALUE", before: cutoff},
+		{description: "number bound", bound: "738000", before: 738001, expect: true},
+		{description: "future number bound", bound: "738002", before: 738001},
+		{description: "string bound", bound: "'a'", before: "b", expect: true},
+		{description: "numeric strings", bound: "'900'", before: "1000", expect: true},
+		{description: "numeric string cutoff", bound: "1000", before: "900"},
+		{description: "number bound with time cutoff", bound: "738000", before: cutoff, hasError: true},
+		{description: "date bound with number cutoff", bound: "'2024-02-01'", before: 738001, hasError: true},
+		{description: "text bound with numeric cutoff", bound: "'b'", before: "100", hasError: true},
+	}
+	for _, useCase := range useCases {
+		expired, err := partitionExpired(useCase.bound, useCase.before)
+		if useCase.hasError {
+			assert.NotNil(t, err, useCase.description)
+			continue
+		}
+		assert.Nil(t, err, useCase.description)
+		assert.Equal(t, useCase.expect, expired, useCase.description)
+	}
+}
+
+func TestPartitionDefinition(t *testing.T) {
+	definition, err := partitionDefinition(&TablePartition{Name: "events_2024_01", Method: PartitionMethodRange, From: "'2024-01-01'", To: "'2024-02-01'"})
+	assert.Nil(t, err)
+	assert.Equal(t, "FOR VALUES FROM ('2024-01-01') TO ('2024-02-01')", definition)
+	definition, err = partitionDefinition(&TablePartition{Name: "events_eu", Method: PartitionMethodList, Values: []string{"'eu'", "'uk'"}})
+	assert.Nil(t, err)
+	assert.Equal(t, "FOR VALUES IN ('eu', 'uk')", definition)
+	_, err = partitionDefinition(&TablePartition{Name: "events_hash", Method: "HASH"})
+	assert.NotNil(t, err)
+	_, err = partitionDefinition(&TablePartition{Name: "events", Method: PartitionMethodRange})
+	assert.NotNil(t, err)
+
+	_, err = GetDatastoreDialect("sqlite3").(PartitionDialect).GetPartitions(nil, "events")
+	assert.NotNil(t, err, "sqlite does not support partitions")
+}
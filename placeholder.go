@@ -0,0 +1,87 @@
+package dsc
+
+import (
+	"strconv"
+	"strings"
+)
+
+// placeholder returns bind parameter placeholder for 1 based parameter position
+func (s PlaceholderStyle) placeholder(position int) string {
+	if s == "" || s == PlaceholderQuestion {
+		return "?"
+	}
+	return string(s) + strconv.Itoa(position)
+}
+
+// skipQuoted returns index following quoted text starting at index, quote is escaped by doubling it
+func skipQuoted(SQL string, index int, closing byte) int {
+	for i := index + 1; i < len(SQL); i++ {
+		if SQL[i] == closing {
+			return i + 1
+		}
+	}
+	return len(SQL)
+}
+
+// skipComment returns index following comment starting at index, or index itself if there is no comment
+func skipComment(SQL string, index int) int {
+	if index+1 >= len(SQL) {
+		return index
+	}
+	switch SQL[index : index+2] {
+	case "--":
+		if end := strings.IndexByte(SQL[index:], '\n'); end != -1 {
+			return index + end + 1
+		}
+		return len(SQL)
+	case "/*":
+		if end := strings.Index(SQL[index+2:], "*/"); end != -1 {
+			return index + 2 + end + 2
+		}
+		return len(SQL)
+	}
+	return index
+}
+
+// RewritePlaceholders rewrites positional ? placeholders of SQL into passed in style, i.e. $1, $2 for postgres,
+// question marks within string literals, quoted identifiers and comments are left unchanged,
+// SQL already using target style has no ? placeholders and is returned as is
+func RewritePlaceholders(SQL string, style PlaceholderStyle) string {
+	if style == "" || style == PlaceholderQuestion || !strings.Contains(SQL, "?") {
+		return SQL
+	}
+	var result = strings.Builder{}
+	result.Grow(len(SQL) + 8)
+	position := 1
+	for i := 0; i < len(SQL); {
+		switch aChar := SQL[i]; aChar {
+		case '\'', '"', '`':
+			end := skipQuoted(SQL, i, aChar)
+			result.WriteString(SQL[i:end])
+			i = end
+		case '[':
+			//sqlserver bracket quoted identifier, other datastores use brackets for array subscript
+			end := i + 1
+			if style == PlaceholderAt {
+				end = skipQuoted(SQL, i, ']')
+			}
+			result.WriteString(SQL[i:end])
+			i = end
+		case '-', '/':
+			end := skipComment(SQL, i)
+			if end == i {
+				end = i + 1
+			}
+			result.WriteString(SQL[i:end])
+			i = end
+		case '?':
+			result.WriteString(style.placeholder(position))
+			position++
+			i++
+		default:
+			result.WriteByte(aChar)
+			i++
+		}
+	}
+	return result.String()
+}
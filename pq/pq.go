@@ -0,0 +1,70 @@
+// Package pq registers github.com/lib/pq based postgres notification listener used by dsc Subscriber.Subscribe (LISTEN/NOTIFY).
+// It also registers postgres database/sql driver:
+//
+//	import _ "github.com/viant/dsc/pq"
+package pq
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/lib/pq"
+	"github.com/viant/dsc"
+)
+
+// DriverName represents lib/pq driver name
+const DriverName = "postgres"
+
+// listener represents postgres LISTEN connection
+type listener struct {
+	connection    *pq.ListenerConn
+	notifications chan *dsc.Notification
+	closed        chan bool
+	once          *sync.Once
+}
+
+// Listen subscribes to notification channel
+func (l *listener) Listen(channel string) error {
+	if _, err := l.connection.Listen(channel); err != nil {
+		return fmt.Errorf("failed to listen %v due to %v", channel, err)
+	}
+	return nil
+}
+
+// Notifications returns received notifications
+func (l *listener) Notifications() <-chan *dsc.Notification {
+	return l.notifications
+}
+
+// Close closes listener connection
+func (l *listener) Close() error {
+	l.once.Do(func() {
+		close(l.closed)
+	})
+	return l.connection.Close()
+}
+
+// newListener opens postgres listener connection
+func newListener(dsn string) (dsc.NotificationListener, error) {
+	received := make(chan *pq.Notification, 32)
+	connection, err := pq.NewListenerConn(dsn, received)
+	if err != nil {
+		return nil, err
+	}
+	result := &listener{connection: connection, notifications: make(chan *dsc.Notification, 32), closed: make(chan bool), once: &sync.Once{}}
+	go func() {
+		defer close(result.notifications)
+		for notification := range received {
+			select {
+			case result.notifications <- &dsc.Notification{Channel: notification.Channel, Payload: notification.Extra, PID: notification.BePid}:
+			case <-result.closed:
+				return
+			}
+		}
+	}()
+	return result, nil
+}
+
+func init() {
+	dsc.RegisterNotificationListenerFactory(DriverName, newListener)
+}
@@ -13,12 +13,21 @@ var queryAllSQLTemplate = "SELECT %v FROM %v"
 type QueryBuilder struct {
 	QueryHint       string
 	TableDescriptor *TableDescriptor
+	quote           func(identifier string) string
+}
+
+//table returns query table or FROM query
+func (qb *QueryBuilder) table() string {
+	if qb.TableDescriptor.FromQuery != "" {
+		return qb.TableDescriptor.From()
+	}
+	return quoteIdentifier(qb.quote, qb.TableDescriptor.Table)
 }
 
 //BuildQueryAll builds query all data without where clause
 func (qb *QueryBuilder) BuildQueryAll(columns []string) *ParametrizedSQL {
-	var columnsLiteral = qb.QueryHint + " " + strings.Join(columns, ",")
-	table := qb.TableDescriptor.From()
+	var columnsLiteral = qb.QueryHint + " " + strings.Join(quoteIdentifiers(qb.quote, columns), ",")
+	table := qb.table()
 	return &ParametrizedSQL{
 		SQL:    fmt.Sprintf(queryAllSQLTemplate, columnsLiteral, table),
 		Values: make([]interface{}, 0),
@@ -35,9 +44,9 @@ func (qb *QueryBuilder) BuildQueryOnPk(columns []string, pkRowValues [][]interfa
 func (qb *QueryBuilder) BuildQueryWithInColumns(columns []string, inCriteriaColumns []string, pkRowValues [][]interface{}) *ParametrizedSQL {
 	columns = append([]string{}, columns...)
 	updateReserved(columns)
-	var columnsLiteral = qb.QueryHint + " " + strings.Join(columns, ",")
+	var columnsLiteral = qb.QueryHint + " " + strings.Join(quoteIdentifiers(qb.quote, columns), ",")
 	updateReserved(inCriteriaColumns)
-	var inColumns = strings.Join(inCriteriaColumns, ",")
+	var inColumns = strings.Join(quoteIdentifiers(qb.quote, inCriteriaColumns), ",")
 	var sqlArguments = make([]interface{}, 0)
 	var criteria = ""
 	var multiValuePk = false
@@ -63,7 +72,7 @@ func (qb *QueryBuilder) BuildQueryWithInColumns(columns []string, inCriteriaColu
 	if multiValuePk {
 		whereCriteria = "(" + inColumns + ") IN (" + criteria + ")"
 	}
-	table := qb.TableDescriptor.From()
+	table := qb.table()
 	return &ParametrizedSQL{
 		SQL:    fmt.Sprintf(querySQLTemplate, columnsLiteral, table, whereCriteria),
 		Values: sqlArguments,
@@ -0,0 +1,126 @@
+package dsc
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// QueryTimeoutMsKey represents config parameter with default statement timeout in milliseconds
+const QueryTimeoutMsKey = "queryTimeoutMs"
+
+// QueryOptions represents per call statement options
+type QueryOptions struct {
+	//Timeout statement deadline, zero means no timeout
+	Timeout time.Duration
+	//Lock row lock acquired by read within active transaction, see WithForUpdate
+	Lock LockMode
+	//LockWait locking read behaviour on rows locked by other transactions, see WithSkipLocked
+	LockWait LockWait
+	//NullPolicies column NULL mapping policies by lower case column name, see WithNullPolicy
+	NullPolicies map[string]string
+}
+
+// QueryOption represents per call statement option passed along with statement parameters, i.e. manager.Execute(SQL, id, dsc.WithQueryTimeout(time.Second))
+type QueryOption func(options *QueryOptions)
+
+// WithQueryTimeout returns option overriding queryTimeoutMs config parameter for a single call, zero disables timeout
+func WithQueryTimeout(timeout time.Duration) QueryOption {
+	return func(options *QueryOptions) {
+		options.Timeout = timeout
+	}
+}
+
+// splitQueryOptions returns parameters without query options and resolved options, queryTimeoutMs config parameter is used as default timeout
+func splitQueryOptions(config *Config, parameters []interface{}) ([]interface{}, *QueryOptions) {
+	options := &QueryOptions{Timeout: config.GetDuration(QueryTimeoutMsKey, time.Millisecond, 0)}
+	var result []interface{}
+	for i, parameter := range parameters {
+		option, ok := parameter.(QueryOption)
+		if !ok {
+			if result != nil {
+				result = append(result, parameter)
+			}
+			continue
+		}
+		if result == nil {
+			result = append(make([]interface{}, 0, len(parameters)), parameters[:i]...)
+		}
+		if option != nil {
+			option(options)
+		}
+	}
+	if result == nil {
+		return parameters, options
+	}
+	return result, options
+}
+
+// context returns statement context with options deadline
+func (o *QueryOptions) context() (context.Context, context.CancelFunc) {
+	if o.Timeout <= 0 {
+		return context.WithCancel(context.Background())
+	}
+	return context.WithTimeout(context.Background(), o.Timeout)
+}
+
+// statementTimeoutDialect represents dialect able to also enforce statement timeout on the server side, restore (if not nil) is called once statement completes
+type statementTimeoutDialect interface {
+	applyStatementTimeout(SQL string, timeout time.Duration, tx *sql.Tx) (string, func() error, error)
+}
+
+// applyStatementTimeout adds MAX_EXECUTION_TIME optimizer hint to SELECT statements
+func (d mySQLDialect) applyStatementTimeout(SQL string, timeout time.Duration, tx *sql.Tx) (string, func() error, error) {
+	trimmed := strings.TrimSpace(SQL)
+	if len(trimmed) < 6 || !strings.EqualFold(trimmed[:6], "SELECT") || strings.Contains(strings.ToUpper(trimmed), "MAX_EXECUTION_TIME") {
+		return SQL, nil, nil
+	}
+	return fmt.Sprintf("SELECT /*+ MAX_EXECUTION_TIME(%d) */%v", timeout.Milliseconds(), trimmed[6:]), nil, nil
+}
+
+// applyStatementTimeout sets transaction scoped statement_timeout for a single statement, previous value is restored once statement completes,
+// so that following statements of the transaction are not affected; outside transaction only context deadline is used
+func (d pgDialect) applyStatementTimeout(SQL string, timeout time.Duration, tx *sql.Tx) (string, func() error, error) {
+	if tx == nil {
+		return SQL, nil, nil
+	}
+	var previous string
+	if err := tx.QueryRow("SHOW statement_timeout").Scan(&previous); err != nil {
+		return "", nil, fmt.Errorf("failed to read statement_timeout due to %v", err)
+	}
+	if _, err := tx.Exec(fmt.Sprintf("SET LOCAL statement_timeout = %d", timeout.Milliseconds())); err != nil {
+		return "", nil, fmt.Errorf("failed to set statement_timeout due to %v", err)
+	}
+	return SQL, func() error {
+		_, err := tx.Exec(fmt.Sprintf("SET LOCAL statement_timeout = '%v'", strings.Replace(previous, "'", "''", -1)))
+		return err
+	}, nil
+}
+
+// prepareStatementTimeout applies dialect server side timeout if supported and returns statement context, cancel also restores server side timeout
+func prepareStatementTimeout(dialect DatastoreDialect, options *QueryOptions, SQL string, tx *sql.Tx) (string, context.Context, context.CancelFunc, error) {
+	ctx, cancel := options.context()
+	if options.Timeout <= 0 {
+		return SQL, ctx, cancel, nil
+	}
+	timeoutDialect, ok := dialect.(statementTimeoutDialect)
+	if !ok {
+		return SQL, ctx, cancel, nil
+	}
+	SQL, restore, err := timeoutDialect.applyStatementTimeout(SQL, options.Timeout, tx)
+	if err != nil {
+		cancel()
+		return "", nil, nil, err
+	}
+	if restore == nil {
+		return SQL, ctx, cancel, nil
+	}
+	return SQL, ctx, func() {
+		cancel()
+		if err := restore(); err != nil { //statement failure aborts postgres transaction, restore error is only logged
+			Logf("failed to restore statement timeout due to %v", err)
+		}
+	}, nil
+}
@@ -0,0 +1,88 @@
+package dsc_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/dsc"
+)
+
+const endlessSQL = "WITH RECURSIVE c(x) AS (SELECT 1 UNION ALL SELECT x + 1 FROM c) SELECT COUNT(*) FROM c WHERE x > ?"
+
+func TestQueryTimeout(t *testing.T) {
+	config := dsc.NewConfig("sqlite3", "[url]", "url:./test/timeout.db")
+	manager, err := dsc.NewManagerFactory().Create(config)
+	if !assert.Nil(t, err) {
+		return
+	}
+	var record = make([]interface{}, 0)
+	started := time.Now()
+	_, err = manager.ReadSingle(&record, endlessSQL, []interface{}{0, dsc.WithQueryTimeout(50 * time.Millisecond)}, nil)
+	assert.NotNil(t, err, "per call timeout should cancel query")
+	assert.True(t, time.Since(started) < 5*time.Second)
+
+	_, err = manager.Execute("SELECT ? + ?", 1, dsc.WithQueryTimeout(time.Second), 2)
+	assert.Nil(t, err, "options should be removed from statement parameters")
+
+	config.Parameters[dsc.QueryTimeoutMsKey] = 50
+	started = time.Now()
+	_, err = manager.Execute(endlessSQL, 0)
+	assert.NotNil(t, err, "config timeout should cancel statement")
+	assert.True(t, time.Since(started) < 5*time.Second)
+
+	var records = make([]map[string]interface{}, 0)
+	err = manager.ReadAll(&records, "SELECT 1 AS id WHERE 1 = ?", []interface{}{1, dsc.WithQueryTimeout(0)}, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(records))
+}
+
+func TestRowLockOptions(t *testing.T) {
+	config := dsc.NewConfig("sqlite3", "[url]", "url:./test/timeout.db")
+	manager, err := dsc.NewManagerFactory().Create(config)
+	if !assert.Nil(t, err) {
+		return
+	}
+	var record = make([]interface{}, 0)
+	_, err = manager.ReadSingle(&record, "SELECT ? AS id", []interface{}{1, dsc.WithForUpdate()}, nil)
+	assert.NotNil(t, err, "locking read should require active transaction")
+
+	connection, err := manager.ConnectionProvider().Get()
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer connection.Close()
+	if !assert.Nil(t, connection.Begin()) {
+		return
+	}
+	success, err := manager.ReadSingleOnConnection(connection, &record, "SELECT ? AS id", []interface{}{1, dsc.WithForUpdate(), dsc.WithSkipLocked()}, nil)
+	assert.Nil(t, err)
+	assert.True(t, success)
+	assert.Nil(t, connection.Commit())
+}
+
+func TestQueryTimeout_PostgresTransaction(t *testing.T) {
+	manager, mock, closer := newPostgresMock(t, "dsc_statement_timeout")
+	if manager == nil {
+		return
+	}
+	defer closer()
+	mock.ExpectBegin()
+	mock.ExpectQuery("SHOW statement_timeout").WillReturnRows(sqlmock.NewRows([]string{"statement_timeout"}).AddRow("30s"))
+	mock.ExpectExec("SET LOCAL statement_timeout = 100").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("UPDATE orders SET status = 'done'").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("SET LOCAL statement_timeout = '30s'").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("DELETE FROM orders").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	err := manager.(dsc.TxRunner).RunInTx(func(connection dsc.Connection) error {
+		if _, err := manager.ExecuteOnConnection(connection, "UPDATE orders SET status = 'done'", []interface{}{dsc.WithQueryTimeout(100 * time.Millisecond)}); err != nil {
+			return err
+		}
+		_, err := manager.ExecuteOnConnection(connection, "DELETE FROM orders", nil)
+		return err
+	}, nil)
+	assert.Nil(t, err)
+	assert.Nil(t, mock.ExpectationsWereMet(), "statement timeout should be restored before the next transaction statement")
+}
@@ -115,9 +115,24 @@ func (rm *metaRecordMapper) applyFieldMapValuesIfNeeded(fieldsValueMap map[strin
 func (rm *metaRecordMapper) scanData(scanner Scanner) (result interface{}, err error) {
 	structType := toolbox.DiscoverTypeByKind(rm.structType, reflect.Struct)
 	structPointer := reflect.New(structType)
+	if _, err = rm.scanInto(scanner, structPointer, nil); err != nil {
+		return nil, err
+	}
+	if !rm.usePointer {
+		result = structPointer.Elem().Interface()
+		return result, err
+	}
+	return structPointer.Interface(), err
+}
+
+//scanInto scans row into passed in struct pointer, field value pointers buffer is reused if large enough, returned buffer can be passed to the next call
+func (rm *metaRecordMapper) scanInto(scanner Scanner, structPointer reflect.Value, fieldValuePointers []interface{}) ([]interface{}, error) {
 	resultStruct := structPointer.Elem()
 	columns, _ := scanner.Columns()
-	var fieldValuePointers = make([]interface{}, len(columns))
+	if cap(fieldValuePointers) < len(columns) {
+		fieldValuePointers = make([]interface{}, len(columns))
+	}
+	fieldValuePointers = fieldValuePointers[:len(columns)]
 	var fieldsValueMap map[string]interface{}
 
 	hasFieldValueMap := rm.getValueMappingCount(columns) > 0
@@ -144,7 +159,7 @@ func (rm *metaRecordMapper) scanData(scanner Scanner) (result interface{}, err e
 			return nil, fmt.Errorf("unable to map column %v to %v, avaialble: %v", key, rm.columnToFieldMap[key], rm.columnToFieldMap)
 		}
 	}
-	err = scanner.Scan(fieldValuePointers...)
+	err := scanner.Scan(fieldValuePointers...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to scan data: %v\n", err)
 	}
@@ -155,12 +170,7 @@ func (rm *metaRecordMapper) scanData(scanner Scanner) (result interface{}, err e
 			return nil, err
 		}
 	}
-
-	if !rm.usePointer {
-		result = structPointer.Elem().Interface()
-		return result, err
-	}
-	return structPointer.Interface(), err
+	return fieldValuePointers, nil
 }
 
 func (rm *metaRecordMapper) mapFromValues(vaues []interface{}) (result interface{}, err error) {
@@ -0,0 +1,91 @@
+package dsc
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+// pooledReader hydrates rows into struct pointers borrowed from the caller pool
+type pooledReader struct {
+	pool               *sync.Pool
+	recordType         reflect.Type
+	mapper             *metaRecordMapper
+	fieldValuePointers []interface{}
+}
+
+func newPooledReader(pool *sync.Pool) (*pooledReader, error) {
+	if pool == nil || pool.New == nil {
+		return nil, fmt.Errorf("failed to read into pool: pool New function was nil")
+	}
+	sample := pool.Get()
+	recordType := reflect.TypeOf(sample)
+	if recordType == nil || recordType.Kind() != reflect.Ptr || recordType.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("failed to read into pool: expected pool of struct pointers, but had %T", sample)
+	}
+	pool.Put(sample)
+	result := &pooledReader{
+		pool:       pool,
+		recordType: recordType,
+		mapper:     NewMetaRecordMapped(recordType, true).(*metaRecordMapper),
+	}
+	return result, nil
+}
+
+// read resets borrowed record, scans row into it and passes it to the handler with its release callback
+func (r *pooledReader) read(scanner Scanner, handler func(record interface{}, release func()) (bool, error)) (bool, error) {
+	record := r.pool.Get()
+	if reflect.TypeOf(record) != r.recordType {
+		return false, fmt.Errorf("failed to read into pool: expected %v, but had %T", r.recordType, record)
+	}
+	value := reflect.ValueOf(record)
+	value.Elem().Set(reflect.Zero(r.recordType.Elem()))
+	var err error
+	if r.fieldValuePointers, err = r.mapper.scanInto(scanner, value, r.fieldValuePointers); err != nil {
+		r.pool.Put(record)
+		return false, err
+	}
+	return handler(record, r.release(record))
+}
+
+// release returns callback putting borrowed record back to the pool once, release is allocated per borrow,
+// so that release retained from earlier row can not return record borrowed by later row
+func (r *pooledReader) release(record interface{}) func() {
+	var released int32
+	return func() {
+		if atomic.CompareAndSwapInt32(&released, 0, 1) {
+			r.pool.Put(record)
+		}
+	}
+}
+
+// ReadAllPooled executes query with parameters and hydrates each row into struct pointer taken from the pool (pool.New has to return struct pointer).
+// The handler receives the record with its release callback returning the record to the pool, the record must not be used after release.
+func (m *AbstractManager) ReadAllPooled(pool *sync.Pool, query string, queryParameters []interface{}, handler func(record interface{}, release func()) (toContinue bool, err error)) error {
+	connection, err := m.Manager.ConnectionProvider().Get()
+	if err != nil {
+		return err
+	}
+	defer connection.Close()
+	return m.poolReader().ReadAllPooledOnConnection(connection, pool, query, queryParameters, handler)
+}
+
+// poolReader returns manager implementing pooled reads, or abstract manager if it does not implement PoolReader
+func (m *AbstractManager) poolReader() PoolReader {
+	if reader, ok := m.Manager.(PoolReader); ok {
+		return reader
+	}
+	return m
+}
+
+// ReadAllPooledOnConnection executes query with parameters on passed in connection and hydrates each row into struct pointer taken from the pool.
+func (m *AbstractManager) ReadAllPooledOnConnection(connection Connection, pool *sync.Pool, query string, queryParameters []interface{}, handler func(record interface{}, release func()) (toContinue bool, err error)) error {
+	reader, err := newPooledReader(pool)
+	if err != nil {
+		return err
+	}
+	return m.Manager.ReadAllOnWithHandlerOnConnection(connection, query, queryParameters, func(scanner Scanner) (bool, error) {
+		return reader.read(scanner, handler)
+	})
+}
@@ -29,6 +29,42 @@ var reservedKeyword = map[string]bool{
 	"order":      true,
 	"is":         true,
 	"database":   true,
+	"where":      true,
+	"group":      true,
+	"having":     true,
+	"limit":      true,
+	"offset":     true,
+	"values":     true,
+	"default":    true,
+	"check":      true,
+	"unique":     true,
+	"references": true,
+	"user":       true,
+	"to":         true,
+	"on":         true,
+	"not":        true,
+	"null":       true,
+	"join":       true,
+	"union":      true,
+	"case":       true,
+	"when":       true,
+	"then":       true,
+	"else":       true,
+	"end":        true,
+	"create":     true,
+	"drop":       true,
+	"alter":      true,
+	"insert":     true,
+	"update":     true,
+	"delete":     true,
+	"set":        true,
+	"into":       true,
+	"distinct":   true,
+	"grant":      true,
+	"like":       true,
+	"exists":     true,
+	"with":       true,
+	"range":      true,
 }
 
 func updateReserved(pk []string) {
@@ -0,0 +1,476 @@
+package dsc
+
+import (
+	"container/list"
+	"database/sql"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultCacheMaxEntries = 1000
+	resultCacheStatsName   = "results"
+)
+
+var (
+	sqlTokenExpr    = regexp.MustCompile("[^\\s(),;']+|'(?:[^']|'')*'|[(),;]")
+	writeTablesExpr = regexp.MustCompile("(?i)\\b(?:INSERT\\s+(?:IGNORE\\s+)?INTO|REPLACE\\s+INTO|UPDATE|DELETE\\s+FROM|MERGE\\s+INTO|TRUNCATE\\s+(?:TABLE\\s+)?|DROP\\s+TABLE\\s+(?:IF\\s+EXISTS\\s+)?|ALTER\\s+TABLE)\\s+([^\\s(),;]+)")
+)
+
+// CacheConfig represents read-through result cache options used by NewCachedManager
+type CacheConfig struct {
+	//TTLMs default cached result time to live, zero disables caching of tables without TableTTLMs
+	TTLMs int
+	//TableTTLMs per table cached result time to live, query result uses the shortest TTL of its tables, non positive value disables table caching
+	TableTTLMs map[string]int
+	//MaxEntries maximum number of cached results, least recently used results are evicted, default 1000
+	MaxEntries int
+}
+
+// ttl returns time to live for query tables
+func (c *CacheConfig) ttl(tables []string) time.Duration {
+	if c == nil {
+		return 0
+	}
+	var result = c.TTLMs
+	for i, table := range tables {
+		ttl, ok := c.TableTTLMs[table]
+		if !ok {
+			ttl = c.TTLMs
+		}
+		if i == 0 || ttl < result {
+			result = ttl
+		}
+	}
+	if result <= 0 {
+		return 0
+	}
+	return time.Duration(result) * time.Millisecond
+}
+
+// normalizeTable returns unquoted lower case table name without schema
+func normalizeTable(table string) string {
+	table = strings.Trim(table, "`\"[]")
+	if index := strings.LastIndex(table, "."); index != -1 {
+		table = strings.Trim(table[index+1:], "`\"[]")
+	}
+	return strings.ToLower(table)
+}
+
+// matchTables returns normalized distinct tables matched by expression
+func matchTables(expr *regexp.Regexp, SQL string) []string {
+	var result = make([]string, 0)
+	var unique = make(map[string]bool)
+	for _, match := range expr.FindAllStringSubmatch(SQL, -1) {
+		result = appendTable(result, unique, match[1])
+	}
+	return result
+}
+
+func appendTable(tables []string, unique map[string]bool, table string) []string {
+	table = normalizeTable(table)
+	if table == "" || unique[table] {
+		return tables
+	}
+	unique[table] = true
+	return append(tables, table)
+}
+
+// readTables returns normalized distinct tables following FROM (including comma separated list) and JOIN keywords
+func readTables(SQL string) []string {
+	var result = make([]string, 0)
+	var unique = make(map[string]bool)
+	tokens := sqlTokenExpr.FindAllString(SQL, -1)
+	for i := 0; i < len(tokens); i++ {
+		keyword := strings.ToUpper(tokens[i])
+		if keyword != "FROM" && keyword != "JOIN" {
+			continue
+		}
+		for i+1 < len(tokens) && tokens[i+1] != "(" {
+			i++
+			result = appendTable(result, unique, tokens[i])
+			if keyword == "JOIN" {
+				break
+			}
+			//skip optional alias
+			if i+1 < len(tokens) && strings.ToUpper(tokens[i+1]) == "AS" {
+				i++
+			}
+			if i+1 < len(tokens) && isSQLAlias(tokens[i+1]) {
+				i++
+			}
+			if i+1 >= len(tokens) || tokens[i+1] != "," {
+				break
+			}
+			i++
+		}
+	}
+	return result
+}
+
+// isSQLAlias returns true if token can be table alias
+func isSQLAlias(token string) bool {
+	switch strings.ToUpper(token) {
+	case "WHERE", "JOIN", "INNER", "LEFT", "RIGHT", "FULL", "CROSS", "OUTER", "NATURAL", "ON", "USING", "GROUP", "ORDER", "HAVING",
+		"LIMIT", "OFFSET", "UNION", "EXCEPT", "INTERSECT", "WINDOW", "FOR", "FETCH", "SET", "VALUES", ",", "(", ")", ";":
+		return false
+	}
+	return !strings.HasPrefix(token, "'")
+}
+
+// cachedResult represents cached query rows
+type cachedResult struct {
+	key         string
+	tables      []string
+	columns     []string
+	columnTypes []ColumnType
+	rows        [][]interface{}
+	expiry      time.Time
+}
+
+// replay passes cached rows to reading handler
+func (r *cachedResult) replay(config *Config, readingHandler func(scanner Scanner) (toContinue bool, err error)) error {
+	for _, values := range r.rows {
+		scanner := NewFileScanner(config, r.columns, r.columnTypes)
+		scanner.Values = make(map[string]interface{}, len(r.columns))
+		for i, column := range r.columns {
+			scanner.Values[column] = values[i]
+		}
+		toContinue, err := readingHandler(scanner)
+		if err != nil || !toContinue {
+			return err
+		}
+	}
+	return nil
+}
+
+// resultCache represents LRU query result cache
+type resultCache struct {
+	config     *CacheConfig
+	mutex      *sync.Mutex
+	entries    map[string]*list.Element
+	lru        *list.List
+	generation uint64
+	hits       int64
+	misses     int64
+}
+
+func (c *resultCache) get(key string) *cachedResult {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	element, ok := c.entries[key]
+	if !ok {
+		return nil
+	}
+	result := element.Value.(*cachedResult)
+	if time.Now().After(result.expiry) {
+		c.lru.Remove(element)
+		delete(c.entries, key)
+		return nil
+	}
+	c.lru.MoveToFront(element)
+	return result
+}
+
+// put stores result unless cache was invalidated after generation was taken
+func (c *resultCache) put(generation uint64, result *cachedResult) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if generation != c.generation {
+		return
+	}
+	if element, ok := c.entries[result.key]; ok {
+		c.lru.Remove(element)
+	}
+	c.entries[result.key] = c.lru.PushFront(result)
+	maxEntries := c.config.MaxEntries
+	if maxEntries <= 0 {
+		maxEntries = defaultCacheMaxEntries
+	}
+	for c.lru.Len() > maxEntries {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cachedResult).key)
+	}
+}
+
+func (c *resultCache) currentGeneration() uint64 {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.generation
+}
+
+// invalidate removes results of passed in tables, all results if no table was passed
+func (c *resultCache) invalidate(tables ...string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.generation++
+	if len(tables) == 0 {
+		c.entries = make(map[string]*list.Element)
+		c.lru.Init()
+		return
+	}
+	var invalidated = make(map[string]bool)
+	for _, table := range tables {
+		invalidated[normalizeTable(table)] = true
+	}
+	for key, element := range c.entries {
+		for _, table := range element.Value.(*cachedResult).tables {
+			if invalidated[table] {
+				c.lru.Remove(element)
+				delete(c.entries, key)
+				break
+			}
+		}
+	}
+}
+
+func (c *resultCache) stats() CacheStats {
+	var result = CacheStats{Hits: atomic.LoadInt64(&c.hits), Misses: atomic.LoadInt64(&c.misses)}
+	if total := result.Hits + result.Misses; total > 0 {
+		result.HitRate = float64(result.Hits) / float64(total)
+	}
+	return result
+}
+
+// CachedManager represents read-through result cache manager decorator, ReadAll, ReadAllWithHandler and ReadSingle results are cached with
+// Config.Cache TTLs, reads on explicit connection are not cached. Execute, Persist and Delete operations through this manager invalidate affected tables,
+// statements with undetected tables and native operations invalidate whole cache, changes made by other processes are visible once TTL expires.
+// Operations on connection with active transaction invalidate affected tables again once the transaction commits.
+type CachedManager struct {
+	Manager
+	cache *resultCache
+}
+
+// cacheKey returns query and parameters cache key
+func (m *CachedManager) cacheKey(query string, parameters []interface{}) string {
+	var result = strings.Builder{}
+	result.WriteString(query)
+	for _, parameter := range parameters {
+		result.WriteString(fmt.Sprintf("\x00%T:%v", parameter, parameter))
+	}
+	return result.String()
+}
+
+// Invalidate removes cached results of passed in tables, all results if no table was passed
+func (m *CachedManager) Invalidate(tables ...string) {
+	m.cache.invalidate(tables...)
+}
+
+// Stats returns decorated manager runtime snapshot, or empty snapshot if decorated manager does not implement StatsProvider
+func (m *CachedManager) Stats() Stats {
+	if provider, ok := m.Manager.(StatsProvider); ok {
+		return provider.Stats()
+	}
+	return Stats{}
+}
+
+// Close closes decorated manager if it implements io.Closer
+func (m *CachedManager) Close() error {
+	if closer, ok := m.Manager.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// invalidateSQL invalidates tables modified by statement
+func (m *CachedManager) invalidateSQL(SQL string) {
+	tables := matchTables(writeTablesExpr, SQL)
+	if len(tables) == 0 {
+		m.cache.invalidate()
+		return
+	}
+	m.cache.invalidate(tables...)
+}
+
+// invalidateOnConnection invalidates tables now and again after connection transaction commits, since reads on other connections
+// can cache rows committed before the transaction
+func (m *CachedManager) invalidateOnConnection(connection Connection, invalidate func()) {
+	invalidate()
+	if connection, ok := connection.(commitHookConnection); ok {
+		connection.afterCommit(invalidate)
+	}
+}
+
+// ReadAllWithHandler reads cached query rows, on cache miss all rows are read and cached before being passed to reading handler
+func (m *CachedManager) ReadAllWithHandler(query string, parameters []interface{}, readingHandler func(scanner Scanner) (toContinue bool, err error)) error {
+	tables := readTables(query)
+	ttl := m.cache.config.ttl(tables)
+	if ttl == 0 {
+		return m.Manager.ReadAllWithHandler(query, parameters, readingHandler)
+	}
+	key := m.cacheKey(query, parameters)
+	if result := m.cache.get(key); result != nil {
+		atomic.AddInt64(&m.cache.hits, 1)
+		return result.replay(m.Manager.Config(), readingHandler)
+	}
+	atomic.AddInt64(&m.cache.misses, 1)
+	generation := m.cache.currentGeneration()
+	var result = &cachedResult{key: key, tables: tables, rows: make([][]interface{}, 0)}
+	err := m.Manager.ReadAllWithHandler(query, parameters, func(scanner Scanner) (bool, error) {
+		values, columns, err := ScanRow(scanner)
+		if err != nil {
+			return false, err
+		}
+		if result.columns == nil {
+			result.columns = columns
+			result.columnTypes, _ = scanner.ColumnTypes()
+		}
+		result.rows = append(result.rows, values)
+		return true, nil
+	})
+	if err != nil {
+		return err
+	}
+	result.expiry = time.Now().Add(ttl)
+	m.cache.put(generation, result)
+	return result.replay(m.Manager.Config(), readingHandler)
+}
+
+// ReadAll reads all cached query rows mapped to result slice pointer with record mapper
+func (m *CachedManager) ReadAll(resultSlicePointer interface{}, query string, parameters []interface{}, mapper RecordMapper) error {
+	return m.ReadAllWithHandler(query, parameters, newSliceMappingHandler(resultSlicePointer, query, mapper))
+}
+
+// ReadSingle reads single cached query row mapped to result pointer with record mapper
+func (m *CachedManager) ReadSingle(resultPointer interface{}, query string, parameters []interface{}, mapper RecordMapper) (success bool, err error) {
+	err = m.ReadAllWithHandler(query, parameters, newSingleMappingHandler(resultPointer, query, mapper, &success))
+	return success, err
+}
+
+// Execute executes statement and invalidates modified tables
+func (m *CachedManager) Execute(SQL string, parameters ...interface{}) (sql.Result, error) {
+	defer m.invalidateSQL(SQL)
+	return m.Manager.Execute(SQL, parameters...)
+}
+
+// ExecuteOnConnection executes statement on connection and invalidates modified tables
+func (m *CachedManager) ExecuteOnConnection(connection Connection, SQL string, parameters []interface{}) (sql.Result, error) {
+	defer m.invalidateOnConnection(connection, func() { m.invalidateSQL(SQL) })
+	return m.Manager.ExecuteOnConnection(connection, SQL, parameters)
+}
+
+// ExecuteAll executes statements and invalidates modified tables
+func (m *CachedManager) ExecuteAll(SQLs []string) ([]sql.Result, error) {
+	defer m.invalidateAll(SQLs)
+	return m.Manager.ExecuteAll(SQLs)
+}
+
+// ExecuteAllOnConnection executes statements on connection and invalidates modified tables
+func (m *CachedManager) ExecuteAllOnConnection(connection Connection, SQLs []string) ([]sql.Result, error) {
+	defer m.invalidateOnConnection(connection, func() { m.invalidateAll(SQLs) })
+	return m.Manager.ExecuteAllOnConnection(connection, SQLs)
+}
+
+func (m *CachedManager) invalidateAll(SQLs []string) {
+	for _, SQL := range SQLs {
+		m.invalidateSQL(SQL)
+	}
+}
+
+// ExecuteNative executes native query and invalidates whole cache
+func (m *CachedManager) ExecuteNative(query interface{}) (sql.Result, error) {
+	native, ok := m.Manager.(NativeManager)
+	if !ok {
+		return nil, fmt.Errorf("failed to execute native query %T due to %v", query, errUnsupportedOperation)
+	}
+	defer m.cache.invalidate()
+	return native.ExecuteNative(query)
+}
+
+// ExecuteNativeOnConnection executes native query on connection and invalidates whole cache
+func (m *CachedManager) ExecuteNativeOnConnection(connection Connection, query interface{}) (sql.Result, error) {
+	native, ok := m.Manager.(NativeManager)
+	if !ok {
+		return nil, fmt.Errorf("failed to execute native query %T due to %v", query, errUnsupportedOperation)
+	}
+	defer m.invalidateOnConnection(connection, func() { m.cache.invalidate() })
+	return native.ExecuteNativeOnConnection(connection, query)
+}
+
+// PersistAll persists data and invalidates table
+func (m *CachedManager) PersistAll(slicePointer interface{}, table string, provider DmlProvider) (int, int, error) {
+	defer m.cache.invalidate(table)
+	return m.Manager.PersistAll(slicePointer, table, provider)
+}
+
+// PersistAllOnConnection persists data on connection and invalidates table
+func (m *CachedManager) PersistAllOnConnection(connection Connection, dataPointer interface{}, table string, provider DmlProvider) (int, int, error) {
+	defer m.invalidateOnConnection(connection, func() { m.cache.invalidate(table) })
+	return m.Manager.PersistAllOnConnection(connection, dataPointer, table, provider)
+}
+
+// PersistSingle persists single row and invalidates table
+func (m *CachedManager) PersistSingle(dataPointer interface{}, table string, provider DmlProvider) (int, int, error) {
+	defer m.cache.invalidate(table)
+	return m.Manager.PersistSingle(dataPointer, table, provider)
+}
+
+// PersistSingleOnConnection persists single row on connection and invalidates table
+func (m *CachedManager) PersistSingleOnConnection(connection Connection, dataPointer interface{}, table string, provider DmlProvider) (int, int, error) {
+	defer m.invalidateOnConnection(connection, func() { m.cache.invalidate(table) })
+	return m.Manager.PersistSingleOnConnection(connection, dataPointer, table, provider)
+}
+
+// PersistData persists data with sql provider and invalidates table
+func (m *CachedManager) PersistData(connection Connection, data interface{}, table string, keySetter KeySetter, sqlProvider func(item interface{}) *ParametrizedSQL) (int, error) {
+	defer m.invalidateOnConnection(connection, func() { m.cache.invalidate(table) })
+	return m.Manager.PersistData(connection, data, table, keySetter, sqlProvider)
+}
+
+// DeleteAll deletes records and invalidates table
+func (m *CachedManager) DeleteAll(slicePointer interface{}, table string, keyProvider KeyGetter) (int, error) {
+	defer m.cache.invalidate(table)
+	return m.Manager.DeleteAll(slicePointer, table, keyProvider)
+}
+
+// DeleteAllWithOptions deletes or soft deletes records and invalidates table
+func (m *CachedManager) DeleteAllWithOptions(slicePointer interface{}, table string, options *DeleteOptions) (int, error) {
+	deleter, ok := m.Manager.(OptionsDeleter)
+	if !ok {
+		return 0, fmt.Errorf("failed to delete from %v due to %v", table, errUnsupportedOperation)
+	}
+	defer m.cache.invalidate(table)
+	return deleter.DeleteAllWithOptions(slicePointer, table, options)
+}
+
+// DeleteAllOnConnection deletes records on connection and invalidates table
+func (m *CachedManager) DeleteAllOnConnection(connection Connection, resultPointer interface{}, table string, keyProvider KeyGetter) (int, error) {
+	defer m.invalidateOnConnection(connection, func() { m.cache.invalidate(table) })
+	return m.Manager.DeleteAllOnConnection(connection, resultPointer, table, keyProvider)
+}
+
+// DeleteSingle deletes single record and invalidates table
+func (m *CachedManager) DeleteSingle(resultPointer interface{}, table string, keyProvider KeyGetter) (bool, error) {
+	defer m.cache.invalidate(table)
+	return m.Manager.DeleteSingle(resultPointer, table, keyProvider)
+}
+
+// DeleteSingleOnConnection deletes single record on connection and invalidates table
+func (m *CachedManager) DeleteSingleOnConnection(connection Connection, resultPointer interface{}, table string, keyProvider KeyGetter) (bool, error) {
+	defer m.invalidateOnConnection(connection, func() { m.cache.invalidate(table) })
+	return m.Manager.DeleteSingleOnConnection(connection, resultPointer, table, keyProvider)
+}
+
+// NewCachedManager creates read-through result cache decorator for manager configured with Config.Cache, cache counters are reported with manager Stats
+func NewCachedManager(manager Manager) *CachedManager {
+	var config = manager.Config().Cache
+	if config == nil {
+		config = &CacheConfig{}
+	}
+	result := &CachedManager{
+		Manager: manager,
+		cache:   &resultCache{config: config, mutex: &sync.Mutex{}, entries: make(map[string]*list.Element), lru: list.New()},
+	}
+	if registry, ok := manager.(interface {
+		RegisterCacheStats(name string, provider func() CacheStats)
+	}); ok {
+		registry.RegisterCacheStats(resultCacheStatsName, result.cache.stats)
+	}
+	return result
+}
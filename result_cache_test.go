@@ -0,0 +1,108 @@
+package dsc_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/dsc"
+)
+
+func TestCachedManager_ReadAll(t *testing.T) {
+	manager := GetManager(t)
+	_, err := manager.Execute("DROP TABLE IF EXISTS user_events")
+	assert.Nil(t, err)
+	_, err = manager.Execute("CREATE TABLE user_events (id INTEGER PRIMARY KEY, user_id INTEGER, name varchar(255))")
+	assert.Nil(t, err)
+	manager.Config().Cache = &dsc.CacheConfig{TTLMs: 60000, TableTTLMs: map[string]int{"user_events": 0}}
+	cached := dsc.NewCachedManager(manager)
+
+	var users = make([]User, 0)
+	assert.Nil(t, cached.ReadAll(&users, "SELECT id, username FROM users WHERE id > ?", []interface{}{0}, nil))
+	assert.Equal(t, 1, len(users))
+
+	//changes made bypassing cached manager are not visible until TTL expires
+	_, err = manager.Execute("INSERT INTO users(username) VALUES('Bob')")
+	assert.Nil(t, err)
+	users = make([]User, 0)
+	assert.Nil(t, cached.ReadAll(&users, "SELECT id, username FROM users WHERE id > ?", []interface{}{0}, nil))
+	assert.Equal(t, 1, len(users))
+	users = make([]User, 0)
+	assert.Nil(t, cached.ReadAll(&users, "SELECT id, username FROM users WHERE id > ?", []interface{}{1}, nil), "different parameters")
+	assert.Equal(t, 1, len(users))
+	assert.Equal(t, "Bob", users[0].Username)
+
+	//execute invalidates modified table
+	_, err = cached.Execute("INSERT INTO users(username) VALUES('Ann')")
+	assert.Nil(t, err)
+	users = make([]User, 0)
+	assert.Nil(t, cached.ReadAll(&users, "SELECT id, username FROM users WHERE id > ?", []interface{}{0}, nil))
+	assert.Equal(t, 3, len(users))
+
+	var user = User{}
+	success, err := cached.ReadSingle(&user, "SELECT id, username FROM users WHERE id = ?", []interface{}{3}, nil)
+	assert.Nil(t, err)
+	assert.True(t, success)
+	assert.Equal(t, "Ann", user.Username)
+
+	//persist invalidates table
+	user.Username = "Anna"
+	_, updated, err := cached.PersistSingle(&user, "users", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, updated)
+	user = User{}
+	_, err = cached.ReadSingle(&user, "SELECT id, username FROM users WHERE id = ?", []interface{}{3}, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "Anna", user.Username)
+
+	//queries with table with disabled caching are not cached
+	_, err = manager.Execute("INSERT INTO user_events(id, user_id, name) VALUES(1, 1, 'login')")
+	assert.Nil(t, err)
+	var events = make([]map[string]interface{}, 0)
+	query := "SELECT u.username, e.name FROM users u JOIN user_events e ON e.user_id = u.id"
+	assert.Nil(t, cached.ReadAll(&events, query, nil, nil))
+	assert.Equal(t, 1, len(events))
+	_, err = manager.Execute("INSERT INTO user_events(id, user_id, name) VALUES(2, 1, 'logout')")
+	assert.Nil(t, err)
+	events = make([]map[string]interface{}, 0)
+	assert.Nil(t, cached.ReadAll(&events, query, nil, nil))
+	assert.Equal(t, 2, len(events))
+
+	//explicit invalidation
+	_, err = manager.Execute("DELETE FROM users WHERE id > 1")
+	assert.Nil(t, err)
+	cached.Invalidate("users")
+	users = make([]User, 0)
+	assert.Nil(t, cached.ReadAll(&users, "SELECT id, username FROM users WHERE id > ?", []interface{}{0}, nil))
+	assert.Equal(t, 1, len(users))
+
+	stats := cached.Stats().Caches["results"]
+	assert.EqualValues(t, 1, stats.Hits)
+	assert.True(t, stats.Misses >= 4)
+}
+
+func TestCachedManager_InvalidateOnCommit(t *testing.T) {
+	manager := GetManager(t)
+	manager.Config().Cache = &dsc.CacheConfig{TTLMs: 60000}
+	cached := dsc.NewCachedManager(manager)
+	read := func() string {
+		var user = User{}
+		_, err := cached.ReadSingle(&user, "SELECT id, username FROM users WHERE id = ?", []interface{}{1}, nil)
+		assert.Nil(t, err)
+		return user.Username
+	}
+	assert.Equal(t, "Edi", read())
+
+	connection, err := manager.ConnectionProvider().Get()
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer connection.Close()
+	if !assert.Nil(t, connection.Begin()) {
+		return
+	}
+	_, err = cached.ExecuteOnConnection(connection, "UPDATE users SET username = ? WHERE id = ?", []interface{}{"Eddie", 1})
+	assert.Nil(t, err)
+	assert.Equal(t, "Edi", read(), "uncommitted change should not be visible to other connections")
+	assert.Nil(t, connection.Commit())
+	assert.Equal(t, "Eddie", read(), "commit should invalidate rows cached during transaction")
+}
@@ -0,0 +1,186 @@
+package dsc
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+type managerContextKey struct{}
+
+// ContextWithManager returns context carrying passed in manager, use ManagerFromContext in nested code to pick up scoped manager
+func ContextWithManager(ctx context.Context, manager Manager) context.Context {
+	return context.WithValue(ctx, managerContextKey{}, manager)
+}
+
+// ManagerFromContext returns manager carried by context or passed in default manager
+func ManagerFromContext(ctx context.Context, defaultManager Manager) Manager {
+	if ctx != nil {
+		if manager, ok := ctx.Value(managerContextKey{}).(Manager); ok {
+			return manager
+		}
+	}
+	return defaultManager
+}
+
+// connectionProviderBinder represents a manager able to create its copy using passed in connection provider
+type connectionProviderBinder interface {
+	withConnectionProvider(provider ConnectionProvider) Manager
+}
+
+// pinnedConnection represents a connection shared by all operations of a rollback scope, nested transactions are mapped to savepoints
+type pinnedConnection struct {
+	Connection
+	savepoints []string
+}
+
+// Close keeps connection pinned to the scope
+func (c *pinnedConnection) Close() error {
+	return nil
+}
+
+// CloseNow keeps connection pinned to the scope
+func (c *pinnedConnection) CloseNow() error {
+	return nil
+}
+
+// afterCommit registers hook on scope connection, savepoint release does not commit
+func (c *pinnedConnection) afterCommit(hook func()) bool {
+	if connection, ok := c.Connection.(commitHookConnection); ok {
+		return connection.afterCommit(hook)
+	}
+	return false
+}
+
+func (c *pinnedConnection) execute(SQL string) error {
+	tx, err := asSQLTx(c.Connection.Unwrap(sqlTxtPointer))
+	if err != nil {
+		return err
+	}
+	if tx == nil {
+		return fmt.Errorf("failed to execute %v: no active transaction", SQL)
+	}
+	_, err = tx.Exec(SQL)
+	return err
+}
+
+// Begin creates a savepoint
+func (c *pinnedConnection) Begin() error {
+	name := fmt.Sprintf("dsc_sp_%v", len(c.savepoints)+1)
+	SQL := "SAVEPOINT " + name
+	if c.Config().DriverName == "sqlserver" {
+		SQL = "SAVE TRANSACTION " + name
+	}
+	if err := c.execute(SQL); err != nil {
+		return err
+	}
+	c.savepoints = append(c.savepoints, name)
+	return nil
+}
+
+func (c *pinnedConnection) popSavepoint() (string, error) {
+	if len(c.savepoints) == 0 {
+		return "", fmt.Errorf("no active transaction")
+	}
+	name := c.savepoints[len(c.savepoints)-1]
+	c.savepoints = c.savepoints[:len(c.savepoints)-1]
+	return name, nil
+}
+
+// Commit releases the last savepoint
+func (c *pinnedConnection) Commit() error {
+	name, err := c.popSavepoint()
+	if err != nil {
+		return err
+	}
+	switch c.Config().DriverName {
+	case "sqlserver", "ora", "oci8":
+		return nil
+	}
+	return c.execute("RELEASE SAVEPOINT " + name)
+}
+
+// Rollback rolls back to the last savepoint
+func (c *pinnedConnection) Rollback() error {
+	name, err := c.popSavepoint()
+	if err != nil {
+		return err
+	}
+	if c.Config().DriverName == "sqlserver" {
+		return c.execute("ROLLBACK TRANSACTION " + name)
+	}
+	return c.execute("ROLLBACK TO SAVEPOINT " + name)
+}
+
+// pinnedConnectionProvider represents a connection provider always returning scope pinned connection
+type pinnedConnectionProvider struct {
+	ConnectionProvider
+	connection *pinnedConnection
+}
+
+// Get returns pinned connection
+func (p *pinnedConnectionProvider) Get() (Connection, error) {
+	return p.connection, nil
+}
+
+// Close keeps underlying provider open, it is owned by the scope parent manager
+func (p *pinnedConnectionProvider) Close() error {
+	return nil
+}
+
+// Shutdown keeps underlying provider open, it is owned by the scope parent manager
+func (p *pinnedConnectionProvider) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+// Stats returns underlying provider pool snapshot
+func (p *pinnedConnectionProvider) Stats() PoolStats {
+	if provider, ok := p.ConnectionProvider.(PoolStatsProvider); ok {
+		return provider.Stats()
+	}
+	return PoolStats{}
+}
+
+// RunInRollbackScope runs passed in function inside a transaction that is always rolled back, so that any changes made by the function are discarded.
+// The function receives a manager bound to the scope connection and context carrying it (see ManagerFromContext);
+// transactions started within the scope are mapped to savepoints, a nested scope (detected via context) uses a savepoint on the parent scope connection.
+func RunInRollbackScope(ctx context.Context, manager Manager, fn func(ctx context.Context, manager Manager) error) (err error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	manager = ManagerFromContext(ctx, manager)
+	if pinned, ok := manager.ConnectionProvider().(*pinnedConnectionProvider); ok {
+		if err = pinned.connection.Begin(); err != nil {
+			return err
+		}
+		defer func() {
+			if rollbackErr := pinned.connection.Rollback(); err == nil {
+				err = rollbackErr
+			}
+		}()
+		return fn(ctx, manager)
+	}
+	binder, ok := manager.(connectionProviderBinder)
+	if !ok {
+		return fmt.Errorf("failed to run in rollback scope: %T %v", manager, errUnsupportedOperation)
+	}
+	if !GetDatastoreDialect(manager.Config().DriverName).CanHandleTransaction() {
+		return fmt.Errorf("failed to run in rollback scope: %v does not handle transactions", manager.Config().DriverName)
+	}
+	connection, err := manager.ConnectionProvider().Get()
+	if err != nil {
+		return err
+	}
+	defer connection.Close()
+	if err = connection.Begin(); err != nil {
+		return err
+	}
+	defer func() {
+		if rollbackErr := connection.Rollback(); err == nil && rollbackErr != sql.ErrTxDone {
+			err = rollbackErr
+		}
+	}()
+	provider := &pinnedConnectionProvider{ConnectionProvider: manager.ConnectionProvider(), connection: &pinnedConnection{Connection: connection}}
+	scoped := binder.withConnectionProvider(provider)
+	return fn(ContextWithManager(ctx, scoped), scoped)
+}
@@ -0,0 +1,62 @@
+package dsc_test
+
+import (
+	"context"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/dsc"
+)
+
+type scopedEvent struct {
+	Id   int `primaryKey:"true"`
+	Name string
+}
+
+func countEvents(t *testing.T, ctx context.Context, manager dsc.Manager) int {
+	var record = make([]interface{}, 0)
+	_, err := dsc.ManagerFromContext(ctx, manager).ReadSingle(&record, "SELECT COUNT(*) FROM events", nil, nil)
+	assert.Nil(t, err)
+	if len(record) == 0 {
+		return -1
+	}
+	return int(record[0].(int64))
+}
+
+func TestRunInRollbackScope(t *testing.T) {
+	config := dsc.NewConfig("sqlite3", "[url]", "url:./test/scope.db")
+	manager, err := dsc.NewManagerFactory().Create(config)
+	if !assert.Nil(t, err) {
+		return
+	}
+	for _, SQL := range []string{
+		"DROP TABLE IF EXISTS events",
+		"CREATE TABLE events(id INTEGER PRIMARY KEY, name TEXT)",
+	} {
+		_, err = manager.Execute(SQL)
+		if !assert.Nil(t, err) {
+			return
+		}
+	}
+	err = dsc.RunInRollbackScope(context.Background(), manager, func(ctx context.Context, scoped dsc.Manager) error {
+		_, err := scoped.Execute("INSERT INTO events(id, name) VALUES(1, 'created')")
+		assert.Nil(t, err)
+		inserted, _, err := scoped.PersistAll(&[]*scopedEvent{{Id: 2, Name: "persisted"}}, "events", nil)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, inserted)
+		assert.Equal(t, 2, countEvents(t, ctx, manager))
+
+		err = dsc.RunInRollbackScope(ctx, manager, func(ctx context.Context, nested dsc.Manager) error {
+			_, err := nested.Execute("INSERT INTO events(id, name) VALUES(3, 'nested')")
+			assert.Nil(t, err)
+			assert.Equal(t, 3, countEvents(t, ctx, manager))
+			return nil
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, 2, countEvents(t, ctx, manager), "nested scope should be rolled back to savepoint")
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 0, countEvents(t, context.Background(), manager), "scope should be rolled back")
+}
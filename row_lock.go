@@ -0,0 +1,156 @@
+package dsc
+
+import (
+	"fmt"
+	"strings"
+)
+
+// LockMode represents row lock acquired by a read
+type LockMode string
+
+const (
+	//LockForUpdate exclusive row lock (SELECT ... FOR UPDATE)
+	LockForUpdate LockMode = "UPDATE"
+	//LockForShare shared row lock (SELECT ... FOR SHARE)
+	LockForShare LockMode = "SHARE"
+)
+
+// LockWait represents behaviour of a locking read when rows are locked by other transactions
+type LockWait string
+
+const (
+	//LockNoWait fails read instead of waiting for locked rows
+	LockNoWait LockWait = "NOWAIT"
+	//LockSkipLocked skips rows locked by other transactions
+	LockSkipLocked LockWait = "SKIP LOCKED"
+)
+
+// WithForUpdate returns read option locking selected rows exclusively until the end of active transaction
+func WithForUpdate() QueryOption {
+	return func(options *QueryOptions) {
+		options.Lock = LockForUpdate
+	}
+}
+
+// WithForShare returns read option locking selected rows in shared mode until the end of active transaction
+func WithForShare() QueryOption {
+	return func(options *QueryOptions) {
+		options.Lock = LockForShare
+	}
+}
+
+// WithNoWait returns read option failing locking read when rows are locked by other transactions, FOR UPDATE is used if no lock mode was set
+func WithNoWait() QueryOption {
+	return func(options *QueryOptions) {
+		options.LockWait = LockNoWait
+	}
+}
+
+// WithSkipLocked returns read option skipping rows locked by other transactions, FOR UPDATE is used if no lock mode was set
+func WithSkipLocked() QueryOption {
+	return func(options *QueryOptions) {
+		options.LockWait = LockSkipLocked
+	}
+}
+
+// lockMode returns requested lock mode, wait option alone implies FOR UPDATE
+func (o *QueryOptions) lockMode() LockMode {
+	if o.Lock == "" && o.LockWait != "" {
+		return LockForUpdate
+	}
+	return o.Lock
+}
+
+// rowLockDialect represents dialect able to add row lock to SELECT statement
+type rowLockDialect interface {
+	applyRowLock(SQL string, mode LockMode, wait LockWait) (string, error)
+}
+
+// lockClause returns trailing lock clause, i.e. FOR UPDATE SKIP LOCKED
+func lockClause(mode LockMode, wait LockWait) string {
+	result := " FOR " + string(mode)
+	if wait != "" {
+		result += " " + string(wait)
+	}
+	return result
+}
+
+func (d pgDialect) applyRowLock(SQL string, mode LockMode, wait LockWait) (string, error) {
+	return SQL + lockClause(mode, wait), nil
+}
+
+// applyRowLock adds mysql 8 lock clause
+func (d mySQLDialect) applyRowLock(SQL string, mode LockMode, wait LockWait) (string, error) {
+	return SQL + lockClause(mode, wait), nil
+}
+
+// applyRowLock adds oracle lock clause, oracle has no shared row lock
+func (d oraDialect) applyRowLock(SQL string, mode LockMode, wait LockWait) (string, error) {
+	if mode == LockForShare {
+		return "", fmt.Errorf("FOR SHARE is not supported by oracle")
+	}
+	return SQL + lockClause(mode, wait), nil
+}
+
+// applyRowLock leaves statement unchanged, sqlite locks the whole database and has no row locks
+func (d sqlLiteDialect) applyRowLock(SQL string, mode LockMode, wait LockWait) (string, error) {
+	return SQL, nil
+}
+
+// fromClauseTerminators represents keywords ending table reference of the first FROM clause
+var fromClauseTerminators = map[string]bool{
+	"WHERE": true, "ORDER": true, "GROUP": true, "HAVING": true, "JOIN": true, "INNER": true, "LEFT": true,
+	"RIGHT": true, "FULL": true, "CROSS": true, "OUTER": true, "UNION": true, "OPTION": true, "WITH": true,
+}
+
+// applyRowLock adds table hint to the first table of FROM clause, i.e. FROM jobs j WITH (UPDLOCK, ROWLOCK, READPAST)
+func (d msSQLDialect) applyRowLock(SQL string, mode LockMode, wait LockWait) (string, error) {
+	var hints = []string{"UPDLOCK", "ROWLOCK"}
+	if mode == LockForShare {
+		hints = []string{"HOLDLOCK", "ROWLOCK"}
+	}
+	switch wait {
+	case LockSkipLocked:
+		hints = append(hints, "READPAST")
+	case LockNoWait:
+		hints = append(hints, "NOWAIT")
+	}
+	fromIndex := strings.Index(strings.ToUpper(SQL), " FROM ")
+	if fromIndex == -1 {
+		return "", fmt.Errorf("failed to lock rows: FROM clause was not found in %v", SQL)
+	}
+	position := fromIndex + len(" FROM ")
+	tokens := strings.Fields(SQL[position:])
+	if len(tokens) == 0 {
+		return "", fmt.Errorf("failed to lock rows: table was not found in %v", SQL)
+	}
+	var reference = 1
+	if len(tokens) > 2 && strings.EqualFold(tokens[1], "AS") {
+		reference = 3
+	} else if len(tokens) > 1 && !fromClauseTerminators[strings.ToUpper(tokens[1])] {
+		reference = 2
+	}
+	for i := 0; i < reference; i++ {
+		for position < len(SQL) && (SQL[position] == ' ' || SQL[position] == '\t' || SQL[position] == '\n' || SQL[position] == '\r') {
+			position++
+		}
+		position += len(tokens[i])
+	}
+	return SQL[:position] + " WITH (" + strings.Join(hints, ", ") + ")" + SQL[position:], nil
+}
+
+// prepareRowLock adds dialect row lock to SELECT statement when requested, locking read requires active transaction
+func prepareRowLock(config *Config, dialect DatastoreDialect, options *QueryOptions, SQL string, inTransaction bool) (string, error) {
+	mode := options.lockMode()
+	if mode == "" {
+		return SQL, nil
+	}
+	if !inTransaction {
+		return "", fmt.Errorf("failed to lock rows: FOR %v requires active transaction", mode)
+	}
+	lockDialect, ok := dialect.(rowLockDialect)
+	if !ok {
+		return "", fmt.Errorf("failed to lock rows: row locks are not supported by %v", config.DriverName)
+	}
+	return lockDialect.applyRowLock(strings.TrimRight(strings.TrimSpace(SQL), ";"), mode, options.LockWait)
+}
@@ -0,0 +1,96 @@
+package dsc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrepareRowLock(t *testing.T) {
+	var useCases = []struct {
+		description string
+		driver      string
+		options     []QueryOption
+		SQL         string
+		expect      string
+		hasError    bool
+	}{
+		{
+			description: "pg skip locked",
+			driver:      "pg",
+			options:     []QueryOption{WithForUpdate(), WithSkipLocked()},
+			SQL:         "SELECT id FROM jobs WHERE status = $1 LIMIT 1",
+			expect:      "SELECT id FROM jobs WHERE status = $1 LIMIT 1 FOR UPDATE SKIP LOCKED",
+		},
+		{
+			description: "mysql for share",
+			driver:      "mysql",
+			options:     []QueryOption{WithForShare()},
+			SQL:         "SELECT id FROM jobs;",
+			expect:      "SELECT id FROM jobs FOR SHARE",
+		},
+		{
+			description: "nowait implies for update",
+			driver:      "ora",
+			options:     []QueryOption{WithNoWait()},
+			SQL:         "SELECT id FROM jobs",
+			expect:      "SELECT id FROM jobs FOR UPDATE NOWAIT",
+		},
+		{
+			description: "oracle has no shared lock",
+			driver:      "ora",
+			options:     []QueryOption{WithForShare()},
+			SQL:         "SELECT id FROM jobs",
+			hasError:    true,
+		},
+		{
+			description: "sqlserver table hint",
+			driver:      "sqlserver",
+			options:     []QueryOption{WithForUpdate(), WithSkipLocked()},
+			SQL:         "SELECT TOP 1 id FROM jobs WHERE status = ? ORDER BY id",
+			expect:      "SELECT TOP 1 id FROM jobs WITH (UPDLOCK, ROWLOCK, READPAST) WHERE status = ? ORDER BY id",
+		},
+		{
+			description: "sqlserver table hint after alias",
+			driver:      "sqlserver",
+			options:     []QueryOption{WithForShare(), WithNoWait()},
+			SQL:         "SELECT j.id FROM jobs AS j JOIN queues q ON q.id = j.queue_id",
+			expect:      "SELECT j.id FROM jobs AS j WITH (HOLDLOCK, ROWLOCK, NOWAIT) JOIN queues q ON q.id = j.queue_id",
+		},
+		{
+			description: "sqlite has no row locks",
+			driver:      "sqlite3",
+			options:     []QueryOption{WithForUpdate()},
+			SQL:         "SELECT id FROM jobs",
+			expect:      "SELECT id FROM jobs",
+		},
+		{
+			description: "unsupported dialect",
+			driver:      "cql",
+			options:     []QueryOption{WithForUpdate()},
+			SQL:         "SELECT id FROM jobs",
+			hasError:    true,
+		},
+	}
+	for _, useCase := range useCases {
+		options := &QueryOptions{}
+		for _, option := range useCase.options {
+			option(options)
+		}
+		config := &Config{DriverName: useCase.driver}
+		actual, err := prepareRowLock(config, GetDatastoreDialect(useCase.driver), options, useCase.SQL, true)
+		if useCase.hasError {
+			assert.NotNil(t, err, useCase.description)
+			continue
+		}
+		if assert.Nil(t, err, useCase.description) {
+			assert.Equal(t, useCase.expect, actual, useCase.description)
+		}
+	}
+
+	_, err := prepareRowLock(&Config{DriverName: "pg"}, GetDatastoreDialect("pg"), &QueryOptions{Lock: LockForUpdate}, "SELECT 1", false)
+	assert.NotNil(t, err, "locking read requires transaction")
+	SQL, err := prepareRowLock(&Config{DriverName: "pg"}, GetDatastoreDialect("pg"), &QueryOptions{}, "SELECT 1", false)
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT 1", SQL)
+}
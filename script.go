@@ -0,0 +1,343 @@
+package dsc
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/viant/toolbox/url"
+)
+
+const defaultScriptDelimiter = ";"
+
+var dollarQuoteTag = regexp.MustCompile(`^\$([A-Za-z_][A-Za-z0-9_]*)?\$`)
+var oraBlockStatement = regexp.MustCompile(`(?is)^(BEGIN|DECLARE|CREATE\s+(OR\s+REPLACE\s+)?((EDITIONABLE|NONEDITIONABLE)\s+)?(FUNCTION|PROCEDURE|PACKAGE|TRIGGER|TYPE))\b`)
+var msSQLBlockStatement = regexp.MustCompile(`(?is)^(BEGIN|DECLARE|(CREATE|ALTER)\s+(OR\s+ALTER\s+)?(FUNCTION|PROCEDURE|PROC|TRIGGER))\b`)
+
+// ScriptStatement represents SQL script statement
+type ScriptStatement struct {
+	SQL string
+	//Line script line where statement starts
+	Line int
+}
+
+// ScriptError represents SQL script parsing or statement execution error
+type ScriptError struct {
+	//Line script line of failed statement or parsing error
+	Line int
+	//SQL failed statement, empty for parsing errors
+	SQL string
+	Err error
+}
+
+// Error returns error message with script line
+func (e *ScriptError) Error() string {
+	if e.SQL == "" {
+		return fmt.Sprintf("failed to parse script at line %v due to %v", e.Line, e.Err)
+	}
+	return fmt.Sprintf("failed to execute script statement at line %v: %v due to %v", e.Line, e.SQL, e.Err)
+}
+
+// Unwrap returns underlying error
+func (e *ScriptError) Unwrap() error {
+	return e.Err
+}
+
+// scriptSyntax represents dialect specific script lexical rules
+type scriptSyntax struct {
+	//backslashEscapes allows backslash escaped quotes in string literals
+	backslashEscapes bool
+	//hashComments allows # line comments
+	hashComments bool
+	//dollarQuotes allows $tag$ quoted bodies
+	dollarQuotes bool
+	//lineDelimiter terminates statement when it is the only text in a line (i.e. / for oracle PL/SQL blocks, GO for sqlserver batches)
+	lineDelimiter string
+	//block matches statement that is terminated only with lineDelimiter, semicolons within its body are kept
+	block *regexp.Regexp
+}
+
+// scriptDialect represents dialect with specific script lexical rules
+type scriptDialect interface {
+	scriptSyntax() *scriptSyntax
+}
+
+func (d mySQLDialect) scriptSyntax() *scriptSyntax {
+	return &scriptSyntax{backslashEscapes: true, hashComments: true}
+}
+
+func (d pgDialect) scriptSyntax() *scriptSyntax {
+	return &scriptSyntax{dollarQuotes: true}
+}
+
+func (d oraDialect) scriptSyntax() *scriptSyntax {
+	return &scriptSyntax{lineDelimiter: "/", block: oraBlockStatement}
+}
+
+func (d msSQLDialect) scriptSyntax() *scriptSyntax {
+	return &scriptSyntax{lineDelimiter: "GO", block: msSQLBlockStatement}
+}
+
+// scriptParser represents SQL script parser
+type scriptParser struct {
+	syntax     *scriptSyntax
+	script     string
+	delimiter  string
+	index      int
+	line       int
+	statement  strings.Builder
+	start      int
+	statements []*ScriptStatement
+}
+
+func (p *scriptParser) error(line int, format string, args ...interface{}) error {
+	return &ScriptError{Line: line, Err: fmt.Errorf(format, args...)}
+}
+
+// flush adds pending statement
+func (p *scriptParser) flush() {
+	if SQL := strings.TrimSpace(p.statement.String()); SQL != "" {
+		p.statements = append(p.statements, &ScriptStatement{SQL: SQL, Line: p.start})
+	}
+	p.statement.Reset()
+	p.start = 0
+}
+
+// append appends text to pending statement, counting lines
+func (p *scriptParser) append(text string) {
+	if p.start == 0 {
+		p.start = p.line
+	}
+	p.statement.WriteString(text)
+	p.line += strings.Count(text, "\n")
+}
+
+// skip skips text outside statement, counting lines
+func (p *scriptParser) skip(text string) {
+	if p.start != 0 {
+		p.statement.WriteString(text)
+	}
+	p.line += strings.Count(text, "\n")
+}
+
+// directive handles DELIMITER and line delimiter lines, it returns true if line was consumed
+func (p *scriptParser) directive() bool {
+	end := strings.Index(p.script[p.index:], "\n")
+	if end == -1 {
+		end = len(p.script)
+	} else {
+		end += p.index
+	}
+	line := strings.TrimSpace(p.script[p.index:end])
+	fields := strings.Fields(line)
+	if p.start == 0 && len(fields) == 2 && strings.EqualFold(fields[0], "DELIMITER") {
+		p.delimiter = fields[1]
+	} else if p.syntax.lineDelimiter != "" && strings.EqualFold(line, p.syntax.lineDelimiter) {
+		p.flush()
+	} else {
+		return false
+	}
+	if end < len(p.script) {
+		end++
+		p.line++
+	}
+	p.index = end
+	return true
+}
+
+// quoted returns quoted literal or identifier length
+func (p *scriptParser) quoted(quote byte) (int, error) {
+	for i := p.index + 1; i < len(p.script); i++ {
+		switch p.script[i] {
+		case '\\':
+			if p.syntax.backslashEscapes && quote != '`' {
+				i++
+			}
+		case quote:
+			if i+1 < len(p.script) && p.script[i+1] == quote {
+				i++
+				continue
+			}
+			return i + 1 - p.index, nil
+		}
+	}
+	return 0, p.error(p.line, "unterminated %c quoted text", quote)
+}
+
+// dollarQuoted returns dollar quoted body length, or zero if text is not dollar quoted
+func (p *scriptParser) dollarQuoted() (int, error) {
+	if p.index > 0 {
+		if previous := p.script[p.index-1]; previous == '_' || previous == '$' || isAlphanumeric(previous) {
+			return 0, nil
+		}
+	}
+	tag := dollarQuoteTag.FindString(p.script[p.index:])
+	if tag == "" {
+		return 0, nil
+	}
+	end := strings.Index(p.script[p.index+len(tag):], tag)
+	if end == -1 {
+		return 0, p.error(p.line, "unterminated %v quoted body", tag)
+	}
+	return len(tag) + end + len(tag), nil
+}
+
+func isAlphanumeric(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// isBlock returns true if pending statement is delimited only with line delimiter
+func (p *scriptParser) isBlock() bool {
+	if p.syntax.block == nil || p.delimiter != defaultScriptDelimiter || p.start == 0 {
+		return false
+	}
+	return p.syntax.block.MatchString(p.statement.String())
+}
+
+// isLineComment returns true if line comment starts at current index
+func (p *scriptParser) isLineComment() bool {
+	text := p.script[p.index:]
+	if p.syntax.hashComments && strings.HasPrefix(text, "#") {
+		return true
+	}
+	if !strings.HasPrefix(text, "--") {
+		return false
+	}
+	//mysql requires whitespace after double dash
+	return !p.syntax.hashComments || len(text) == 2 || strings.ContainsAny(text[2:3], " \t\r\n")
+}
+
+func (p *scriptParser) parse() ([]*ScriptStatement, error) {
+	lineStart := true
+	for p.index < len(p.script) {
+		if lineStart {
+			lineStart = false
+			if p.directive() {
+				lineStart = true
+				continue
+			}
+		}
+		c := p.script[p.index]
+		text := p.script[p.index:]
+		switch {
+		case c == '\n':
+			p.skip("\n")
+			p.index++
+			lineStart = true
+		case strings.HasPrefix(text, p.delimiter) && !p.isBlock():
+			p.flush()
+			p.index += len(p.delimiter)
+		case c == ' ' || c == '\t' || c == '\r':
+			p.skip(text[:1])
+			p.index++
+		case p.isLineComment():
+			end := strings.Index(text, "\n")
+			if end == -1 {
+				end = len(text)
+			}
+			p.skip(text[:end])
+			p.index += end
+		case strings.HasPrefix(text, "/*"):
+			end := strings.Index(text[2:], "*/")
+			if end == -1 {
+				return nil, p.error(p.line, "unterminated comment")
+			}
+			comment := text[:end+4]
+			if strings.HasPrefix(comment, "/*!") || strings.HasPrefix(comment, "/*+") {
+				p.append(comment)
+			} else {
+				p.skip(comment)
+			}
+			p.index += len(comment)
+		case c == '\'' || c == '"' || c == '`':
+			length, err := p.quoted(c)
+			if err != nil {
+				return nil, err
+			}
+			p.append(text[:length])
+			p.index += length
+		case c == '$' && p.syntax.dollarQuotes:
+			length, err := p.dollarQuoted()
+			if err != nil {
+				return nil, err
+			}
+			if length == 0 {
+				length = 1
+			}
+			p.append(text[:length])
+			p.index += length
+		default:
+			p.append(text[:1])
+			p.index++
+		}
+	}
+	p.flush()
+	return p.statements, nil
+}
+
+// ParseScript splits SQL script into statements, it handles comments, quoted text, DELIMITER directive (i.e. DELIMITER // for mysql procedures),
+// postgres dollar quoted bodies, oracle / and sqlserver GO lines terminating PL/SQL and T-SQL blocks
+func ParseScript(driverName string, script string) ([]*ScriptStatement, error) {
+	var syntax = &scriptSyntax{}
+	if dialect, ok := GetDatastoreDialect(driverName).(scriptDialect); ok {
+		syntax = dialect.scriptSyntax()
+	}
+	parser := &scriptParser{syntax: syntax, script: script, delimiter: defaultScriptDelimiter, line: 1}
+	return parser.parse()
+}
+
+// executeScript executes script statements with executor, failed statement error reports its line
+func executeScript(driverName string, script string, executor func(SQL string) (sql.Result, error)) ([]sql.Result, error) {
+	statements, err := ParseScript(driverName, script)
+	if err != nil {
+		return nil, err
+	}
+	var result = make([]sql.Result, 0, len(statements))
+	for _, statement := range statements {
+		executed, err := executor(statement.SQL)
+		if err != nil {
+			return result, &ScriptError{Line: statement.Line, SQL: statement.SQL, Err: err}
+		}
+		result = append(result, executed)
+	}
+	return result, nil
+}
+
+// ExecuteScript executes SQL script statements in order, it stops on the first failed statement returning ScriptError with its line
+func (m *AbstractManager) ExecuteScript(script string) ([]sql.Result, error) {
+	connection, err := m.Manager.ConnectionProvider().Get()
+	if err != nil {
+		return nil, err
+	}
+	defer connection.Close()
+	return m.scriptExecutor().ExecuteScriptOnConnection(connection, script)
+}
+
+// scriptExecutor returns manager implementing script execution, or abstract manager if it does not implement ScriptExecutor
+func (m *AbstractManager) scriptExecutor() ScriptExecutor {
+	if executor, ok := m.Manager.(ScriptExecutor); ok {
+		return executor
+	}
+	return m
+}
+
+// ExecuteScriptOnConnection executes SQL script statements in order on passed in connection, it stops on the first failed statement
+func (m *AbstractManager) ExecuteScriptOnConnection(connection Connection, script string) ([]sql.Result, error) {
+	return executeScript(m.Manager.Config().DriverName, script, func(SQL string) (sql.Result, error) {
+		return m.Manager.ExecuteOnConnection(connection, SQL, nil)
+	})
+}
+
+// ExecuteScriptFromURL executes SQL script (i.e. migration file) from URL
+func (m *AbstractManager) ExecuteScriptFromURL(URL string) ([]sql.Result, error) {
+	script, err := url.NewResource(URL).DownloadText()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load script %v due to %v", URL, err)
+	}
+	results, err := m.scriptExecutor().ExecuteScript(script)
+	if err != nil {
+		return results, fmt.Errorf("%v: %w", URL, err)
+	}
+	return results, nil
+}
@@ -0,0 +1,146 @@
+package dsc_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/dsc"
+)
+
+func TestParseScript(t *testing.T) {
+	var useCases = []struct {
+		description string
+		driverName  string
+		script      string
+		expectSQL   []string
+		expectLines []int
+	}{
+		{
+			description: "multi statements with comments",
+			driverName:  "sqlite3",
+			script: `-- schema
+CREATE TABLE a(id INT); /* block
+comment */
+INSERT INTO a VALUES(1);INSERT INTO a VALUES(2) -- trailing
+;
+;`,
+			expectSQL:   []string{"CREATE TABLE a(id INT)", "INSERT INTO a VALUES(1)", "INSERT INTO a VALUES(2) -- trailing"},
+			expectLines: []int{2, 4, 4},
+		},
+		{
+			description: "quoted delimiter",
+			driverName:  "sqlite3",
+			script:      "INSERT INTO a VALUES('a;''b', \"c;\");\nSELECT 1",
+			expectSQL:   []string{"INSERT INTO a VALUES('a;''b', \"c;\")", "SELECT 1"},
+			expectLines: []int{1, 2},
+		},
+		{
+			description: "mysql procedure with custom delimiter",
+			driverName:  "mysql",
+			script: `DROP PROCEDURE IF EXISTS p;
+DELIMITER //
+# procedure
+CREATE PROCEDURE p()
+BEGIN
+  SELECT 'it\'s;';
+END//
+DELIMITER ;
+CALL p();`,
+			expectSQL:   []string{"DROP PROCEDURE IF EXISTS p", "CREATE PROCEDURE p()\nBEGIN\n  SELECT 'it\\'s;';\nEND", "CALL p()"},
+			expectLines: []int{1, 4, 9},
+		},
+		{
+			description: "postgres dollar quoted body",
+			driverName:  "postgres",
+			script: `CREATE FUNCTION f() RETURNS INT AS $body$
+BEGIN
+  RETURN 1; -- $$ inside
+END;
+$body$ LANGUAGE plpgsql;
+SELECT $1, a$b FROM t;
+DO $$ BEGIN PERFORM 1; END $$;`,
+			expectSQL:   []string{"CREATE FUNCTION f() RETURNS INT AS $body$\nBEGIN\n  RETURN 1; -- $$ inside\nEND;\n$body$ LANGUAGE plpgsql", "SELECT $1, a$b FROM t", "DO $$ BEGIN PERFORM 1; END $$"},
+			expectLines: []int{1, 6, 7},
+		},
+		{
+			description: "oracle block terminated with slash",
+			driverName:  "ora",
+			script: `BEGIN
+  NULL;
+END;
+/
+SELECT 1 FROM dual;`,
+			expectSQL:   []string{"BEGIN\n  NULL;\nEND;", "SELECT 1 FROM dual"},
+			expectLines: []int{1, 5},
+		},
+		{
+			description: "sqlserver GO batches",
+			driverName:  "sqlserver",
+			script:      "CREATE PROCEDURE p AS\nBEGIN\n  SELECT 1;\nEND\nGO\nSELECT 1; SELECT 2\ngo\n",
+			expectSQL:   []string{"CREATE PROCEDURE p AS\nBEGIN\n  SELECT 1;\nEND", "SELECT 1", "SELECT 2"},
+			expectLines: []int{1, 6, 6},
+		},
+	}
+
+	for _, useCase := range useCases {
+		statements, err := dsc.ParseScript(useCase.driverName, useCase.script)
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		var actualSQL = make([]string, 0)
+		var actualLines = make([]int, 0)
+		for _, statement := range statements {
+			actualSQL = append(actualSQL, statement.SQL)
+			actualLines = append(actualLines, statement.Line)
+		}
+		assert.EqualValues(t, useCase.expectSQL, actualSQL, useCase.description)
+		assert.EqualValues(t, useCase.expectLines, actualLines, useCase.description)
+	}
+
+	_, err := dsc.ParseScript("sqlite3", "SELECT 1;\nSELECT 'abc")
+	if assert.NotNil(t, err) {
+		scriptError, ok := err.(*dsc.ScriptError)
+		if assert.True(t, ok) {
+			assert.Equal(t, 2, scriptError.Line)
+		}
+	}
+	_, err = dsc.ParseScript("pg", "SELECT $x$ abc")
+	assert.NotNil(t, err)
+}
+
+func TestManager_ExecuteScript(t *testing.T) {
+	manager := GetManager(t)
+	results, err := manager.(dsc.ScriptExecutor).ExecuteScript(`DROP TABLE IF EXISTS script_items;
+CREATE TABLE script_items(id INT PRIMARY KEY, name VARCHAR(255));
+/* seed */
+INSERT INTO script_items VALUES(1, 'a;b');
+INSERT INTO script_items VALUES(2, 'c');`)
+	assert.Nil(t, err)
+	assert.Equal(t, 4, len(results))
+
+	var records = make([]map[string]interface{}, 0)
+	assert.Nil(t, manager.ReadAll(&records, "SELECT id, name FROM script_items ORDER BY id", nil, nil))
+	if assert.Equal(t, 2, len(records)) {
+		assert.EqualValues(t, "a;b", records[0]["name"])
+	}
+
+	results, err = manager.(dsc.ScriptExecutor).ExecuteScript("INSERT INTO script_items VALUES(3, 'd');\n\nINSERT INTO script_items VALUES(1, 'duplicate');")
+	assert.Equal(t, 1, len(results))
+	if assert.NotNil(t, err) {
+		var scriptError *dsc.ScriptError
+		if assert.True(t, errors.As(err, &scriptError)) {
+			assert.Equal(t, 3, scriptError.Line)
+			assert.Equal(t, "INSERT INTO script_items VALUES(1, 'duplicate')", scriptError.SQL)
+		}
+	}
+
+	results, err = manager.(dsc.ScriptExecutor).ExecuteScriptFromURL("test/script.sql")
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(results))
+	records = make([]map[string]interface{}, 0)
+	assert.Nil(t, manager.ReadAll(&records, "SELECT id, name FROM script_items ORDER BY id", nil, nil))
+	if assert.Equal(t, 2, len(records)) {
+		assert.EqualValues(t, "x", records[0]["name"])
+	}
+}
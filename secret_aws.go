@@ -0,0 +1,153 @@
+package dsc
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/viant/toolbox"
+)
+
+const awsSecretsManagerService = "secretsmanager"
+
+// AwsSecretProvider resolves secrets from AWS Secrets Manager.
+// Key uses secret id with optional JSON field: prod/db#password, when field is omitted the whole secret string is returned.
+type AwsSecretProvider struct {
+	//Region defaults to AWS_REGION or AWS_DEFAULT_REGION
+	Region string
+	//Endpoint defaults to https://secretsmanager.<region>.amazonaws.com
+	Endpoint string
+	//AccessKeyID defaults to AWS_ACCESS_KEY_ID
+	AccessKeyID string
+	//SecretAccessKey defaults to AWS_SECRET_ACCESS_KEY
+	SecretAccessKey string
+	//SessionToken defaults to AWS_SESSION_TOKEN
+	SessionToken string
+	Client       *http.Client
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// Secret returns secret string or its JSON field value
+func (p *AwsSecretProvider) Secret(ctx context.Context, key string) (string, error) {
+	secretID, field := splitSecretField(key)
+	region := firstNonEmpty(p.Region, os.Getenv("AWS_REGION"), os.Getenv("AWS_DEFAULT_REGION"))
+	if region == "" {
+		return "", fmt.Errorf("aws region was empty, set AWS_REGION")
+	}
+	endpoint := firstNonEmpty(p.Endpoint, fmt.Sprintf("https://%v.%v.amazonaws.com", awsSecretsManagerService, region))
+	payload, err := json.Marshal(map[string]string{"SecretId": secretID})
+	if err != nil {
+		return "", err
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return "", err
+	}
+	request.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	request.Header.Set("X-Amz-Target", "secretsmanager.GetSecretValue")
+	if err = p.sign(request, payload, region); err != nil {
+		return "", err
+	}
+	client := p.Client
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return "", err
+	}
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("secrets manager returned %v for %v", response.Status, secretID)
+	}
+	var secret = struct {
+		SecretString string
+	}{}
+	if err = json.Unmarshal(body, &secret); err != nil {
+		return "", fmt.Errorf("failed to decode secrets manager response for %v due to %v", secretID, err)
+	}
+	if field == "" {
+		return secret.SecretString, nil
+	}
+	var fields = make(map[string]interface{})
+	if err = json.Unmarshal([]byte(secret.SecretString), &fields); err != nil {
+		return "", fmt.Errorf("failed to decode secret %v as JSON due to %v", secretID, err)
+	}
+	value, ok := fields[field]
+	if !ok {
+		return "", fmt.Errorf("field %v was not found in secret %v", field, secretID)
+	}
+	return toolbox.AsString(value), nil
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	hash := hmac.New(sha256.New, key)
+	hash.Write([]byte(data))
+	return hash.Sum(nil)
+}
+
+func sha256Hex(data []byte) string {
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
+
+// sign signs request with AWS signature version 4
+func (p *AwsSecretProvider) sign(request *http.Request, payload []byte, region string) error {
+	accessKeyID := firstNonEmpty(p.AccessKeyID, os.Getenv("AWS_ACCESS_KEY_ID"))
+	secretAccessKey := firstNonEmpty(p.SecretAccessKey, os.Getenv("AWS_SECRET_ACCESS_KEY"))
+	if accessKeyID == "" || secretAccessKey == "" {
+		return fmt.Errorf("aws credentials were empty, set AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY")
+	}
+	sessionToken := firstNonEmpty(p.SessionToken, os.Getenv("AWS_SESSION_TOKEN"))
+	timestamp := time.Now().UTC()
+	amzDate := timestamp.Format("20060102T150405Z")
+	date := timestamp.Format("20060102")
+	endpointURL := request.URL
+	request.Header.Set("X-Amz-Date", amzDate)
+	signedHeaders := "content-type;host;x-amz-date;x-amz-target"
+	canonicalHeaders := "content-type:" + request.Header.Get("Content-Type") + "\n" +
+		"host:" + endpointURL.Host + "\n" +
+		"x-amz-date:" + amzDate + "\n" +
+		"x-amz-target:" + request.Header.Get("X-Amz-Target") + "\n"
+	if sessionToken != "" {
+		request.Header.Set("X-Amz-Security-Token", sessionToken)
+		signedHeaders += ";x-amz-security-token"
+		canonicalHeaders += "x-amz-security-token:" + sessionToken + "\n"
+	}
+	path := endpointURL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	canonicalRequest := request.Method + "\n" + path + "\n" + endpointURL.RawQuery + "\n" + canonicalHeaders + "\n" + signedHeaders + "\n" + sha256Hex(payload)
+	scope := date + "/" + region + "/" + awsSecretsManagerService + "/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+	signingKey := hmacSHA256(hmacSHA256(hmacSHA256(hmacSHA256([]byte("AWS4"+secretAccessKey), date), region), awsSecretsManagerService), "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+	request.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%v/%v, SignedHeaders=%v, Signature=%v", accessKeyID, scope, signedHeaders, signature))
+	return nil
+}
+
+// NewAwsSecretProvider returns AWS Secrets Manager secret provider configured with AWS_* environment variables
+func NewAwsSecretProvider() *AwsSecretProvider {
+	return &AwsSecretProvider{}
+}
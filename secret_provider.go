@@ -0,0 +1,157 @@
+package dsc
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	secretMacroPrefix = "[secret:"
+	//secretProviderKey represents config parameter with the name of the provider used by references without explicit provider
+	secretProviderKey     = "secretProvider"
+	defaultSecretProvider = "env"
+	//secretRefreshMsKey represents config parameter controlling how long resolved secret is cached before it is resolved again
+	secretRefreshMsKey     = "secretRefreshMs"
+	defaultSecretRefreshMs = 60000
+)
+
+// SecretProvider represents a secret resolver for references used in Config descriptor i.e. [secret:prod/db/password] or [secret:vault:prod/db#password]
+type SecretProvider interface {
+	// Secret returns secret value for passed in key
+	Secret(ctx context.Context, key string) (string, error)
+}
+
+var secretProviderRegistry = make(map[string]SecretProvider)
+var secretProviderMutex = &sync.RWMutex{}
+
+// RegisterSecretProvider registers a secret provider with passed in name
+func RegisterSecretProvider(name string, provider SecretProvider) {
+	secretProviderMutex.Lock()
+	defer secretProviderMutex.Unlock()
+	secretProviderRegistry[name] = provider
+}
+
+// GetSecretProvider returns secret provider registered with passed in name or error
+func GetSecretProvider(name string) (SecretProvider, error) {
+	secretProviderMutex.RLock()
+	defer secretProviderMutex.RUnlock()
+	if result, ok := secretProviderRegistry[name]; ok {
+		return result, nil
+	}
+	return nil, fmt.Errorf("failed to lookup secret provider: %v", name)
+}
+
+type envSecretProvider struct{}
+
+// Secret returns environment variable value, key is matched as is, then as upper case name with non alphanumeric characters replaced by '_' (prod/db/password -> PROD_DB_PASSWORD)
+func (p *envSecretProvider) Secret(ctx context.Context, key string) (string, error) {
+	if value, ok := os.LookupEnv(key); ok {
+		return value, nil
+	}
+	name := strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, strings.ToUpper(key))
+	if value, ok := os.LookupEnv(name); ok {
+		return value, nil
+	}
+	return "", fmt.Errorf("environment variable %v was not defined", name)
+}
+
+// NewEnvSecretProvider returns a secret provider resolving secrets from environment variables
+func NewEnvSecretProvider() SecretProvider {
+	return &envSecretProvider{}
+}
+
+// splitSecretField splits key into secret path and optional JSON field (path#field)
+func splitSecretField(key string) (string, string) {
+	if index := strings.LastIndex(key, "#"); index != -1 {
+		return key[:index], key[index+1:]
+	}
+	return key, ""
+}
+
+type cachedSecret struct {
+	value   string
+	expired time.Time
+}
+
+// secretCache expands descriptor secret references, resolved values are cached for the configured refresh period to pick up rotated secrets
+type secretCache struct {
+	mutex  *sync.Mutex
+	values map[string]*cachedSecret
+}
+
+func (c *secretCache) reset() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.values = make(map[string]*cachedSecret)
+}
+
+func (c *secretCache) resolve(ctx context.Context, config *Config, reference string) (string, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if cached, ok := c.values[reference]; ok && time.Now().Before(cached.expired) {
+		return cached.value, nil
+	}
+	providerName := config.GetString(secretProviderKey, defaultSecretProvider)
+	key := reference
+	if index := strings.Index(reference, ":"); index != -1 {
+		if _, err := GetSecretProvider(reference[:index]); err == nil {
+			providerName, key = reference[:index], reference[index+1:]
+		}
+	}
+	provider, err := GetSecretProvider(providerName)
+	if err != nil {
+		return "", err
+	}
+	value, err := provider.Secret(ctx, key)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve secret %v with %v provider due to %v", key, providerName, err)
+	}
+	refresh := config.GetDuration(secretRefreshMsKey, time.Millisecond, defaultSecretRefreshMs*time.Millisecond)
+	c.values[reference] = &cachedSecret{value: value, expired: time.Now().Add(refresh)}
+	return value, nil
+}
+
+// expand replaces all [secret:reference] macros in passed in text
+func (c *secretCache) expand(ctx context.Context, config *Config, text string) (string, error) {
+	var offset = 0
+	for {
+		start := strings.Index(text[offset:], secretMacroPrefix)
+		if start == -1 {
+			return text, nil
+		}
+		start += offset
+		end := strings.Index(text[start:], "]")
+		if end == -1 {
+			return "", fmt.Errorf("unterminated secret reference in descriptor")
+		}
+		end += start
+		value, err := c.resolve(ctx, config, text[start+len(secretMacroPrefix):end])
+		if err != nil {
+			return "", err
+		}
+		text = text[:start] + value + text[end+1:]
+		offset = start + len(value)
+	}
+}
+
+func newSecretCache() *secretCache {
+	return &secretCache{
+		mutex:  &sync.Mutex{},
+		values: make(map[string]*cachedSecret),
+	}
+}
+
+func init() {
+	RegisterSecretProvider("env", NewEnvSecretProvider())
+	RegisterSecretProvider("vault", NewVaultSecretProvider())
+	RegisterSecretProvider("aws", NewAwsSecretProvider())
+}
@@ -0,0 +1,100 @@
+package dsc_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/dsc"
+)
+
+func TestConfig_DsnDescriptorWithSecret(t *testing.T) {
+	os.Setenv("DSC_TEST_DB_PASSWORD", "pass1")
+	defer os.Unsetenv("DSC_TEST_DB_PASSWORD")
+	config := dsc.NewConfig("mysql", "root:[secret:dsc_test/db/password]@tcp(127.0.0.1:3306)/[dbname]", "dbname:mydb")
+	dsn, err := config.DsnDescriptor()
+	assert.Nil(t, err)
+	assert.EqualValues(t, "root:pass1@tcp(127.0.0.1:3306)/mydb", dsn)
+
+	os.Setenv("DSC_TEST_DB_PASSWORD", "pass2")
+	dsn, _ = config.DsnDescriptor()
+	assert.EqualValues(t, "root:pass1@tcp(127.0.0.1:3306)/mydb", dsn, "secret should be cached")
+	config.RefreshSecrets()
+	dsn, _ = config.DsnDescriptor()
+	assert.EqualValues(t, "root:pass2@tcp(127.0.0.1:3306)/mydb", dsn, "rotated secret should be resolved")
+
+	config = dsc.NewConfig("mysql", "root:[secret:env:DSC_TEST_UNDEFINED]@tcp(127.0.0.1:3306)/mydb", "")
+	_, err = config.DsnDescriptor()
+	assert.NotNil(t, err)
+}
+
+func TestConfig_SecretsConcurrentAccess(t *testing.T) {
+	os.Setenv("DSC_TEST_DB_PASSWORD", "pass1")
+	defer os.Unsetenv("DSC_TEST_DB_PASSWORD")
+	config := dsc.NewConfig("mysql", "root:[secret:dsc_test/db/password]@tcp(127.0.0.1:3306)/mydb", "")
+	var group sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		group.Add(1)
+		go func() {
+			defer group.Done()
+			dsn, err := config.DsnDescriptor()
+			assert.Nil(t, err)
+			assert.EqualValues(t, "root:pass1@tcp(127.0.0.1:3306)/mydb", dsn)
+			config.RefreshSecrets()
+		}()
+	}
+	group.Wait()
+}
+
+func TestVaultSecretProvider_Secret(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if request.URL.Path != "/v1/secret/data/prod/db" || request.Header.Get("X-Vault-Token") != "token1" {
+			writer.WriteHeader(http.StatusForbidden)
+			return
+		}
+		_, _ = writer.Write([]byte(`{"data":{"data":{"password":"vault-pass"}}}`))
+	}))
+	defer server.Close()
+	provider := &dsc.VaultSecretProvider{Address: server.URL, Token: "token1"}
+	for _, key := range []string{"prod/db/password", "prod/db#password"} {
+		secret, err := provider.Secret(context.Background(), key)
+		assert.Nil(t, err, key)
+		assert.EqualValues(t, "vault-pass", secret, key)
+	}
+	_, err := provider.Secret(context.Background(), "prod/db#user")
+	assert.NotNil(t, err)
+}
+
+func TestAwsSecretProvider_Secret(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		authorization := request.Header.Get("Authorization")
+		if !strings.HasPrefix(authorization, "AWS4-HMAC-SHA256 Credential=AKID/") || request.Header.Get("X-Amz-Target") != "secretsmanager.GetSecretValue" {
+			writer.WriteHeader(http.StatusForbidden)
+			return
+		}
+		body, _ := io.ReadAll(request.Body)
+		var input = map[string]string{}
+		_ = json.Unmarshal(body, &input)
+		if input["SecretId"] != "prod/db" {
+			writer.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = writer.Write([]byte(`{"Name":"prod/db","SecretString":"{\"password\":\"aws-pass\"}"}`))
+	}))
+	defer server.Close()
+	provider := &dsc.AwsSecretProvider{Region: "us-east-1", Endpoint: server.URL, AccessKeyID: "AKID", SecretAccessKey: "secret"}
+	secret, err := provider.Secret(context.Background(), "prod/db#password")
+	assert.Nil(t, err)
+	assert.EqualValues(t, "aws-pass", secret)
+
+	secret, err = provider.Secret(context.Background(), "prod/db")
+	assert.Nil(t, err)
+	assert.EqualValues(t, `{"password":"aws-pass"}`, secret)
+}
@@ -0,0 +1,119 @@
+package dsc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/viant/toolbox"
+)
+
+const defaultVaultMount = "secret"
+
+// VaultSecretProvider resolves secrets from HashiCorp Vault KV secret engine.
+// Key uses mount relative path with optional field: prod/db#password, when field is omitted the last path element is used as field (prod/db/password).
+type VaultSecretProvider struct {
+	//Address vault address, defaults to VAULT_ADDR
+	Address string
+	//Token vault token, defaults to VAULT_TOKEN
+	Token string
+	//Namespace vault enterprise namespace, defaults to VAULT_NAMESPACE
+	Namespace string
+	//Mount KV engine mount, defaults to secret
+	Mount string
+	//KVVersion KV engine version, defaults to 2
+	KVVersion int
+	Client    *http.Client
+}
+
+func (p *VaultSecretProvider) secretURL(path string) (string, error) {
+	address := p.Address
+	if address == "" {
+		address = os.Getenv("VAULT_ADDR")
+	}
+	if address == "" {
+		return "", fmt.Errorf("vault address was empty, set VAULT_ADDR")
+	}
+	mount := p.Mount
+	if mount == "" {
+		mount = defaultVaultMount
+	}
+	address = strings.TrimRight(address, "/") + "/v1/" + strings.Trim(mount, "/")
+	if p.KVVersion == 1 {
+		return address + "/" + strings.Trim(path, "/"), nil
+	}
+	return address + "/data/" + strings.Trim(path, "/"), nil
+}
+
+// Secret returns vault secret field value
+func (p *VaultSecretProvider) Secret(ctx context.Context, key string) (string, error) {
+	path, field := splitSecretField(key)
+	if field == "" {
+		index := strings.LastIndex(path, "/")
+		if index == -1 {
+			return "", fmt.Errorf("invalid vault secret key: %v, expected path#field", key)
+		}
+		path, field = path[:index], path[index+1:]
+	}
+	URL, err := p.secretURL(path)
+	if err != nil {
+		return "", err
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, URL, nil)
+	if err != nil {
+		return "", err
+	}
+	token := p.Token
+	if token == "" {
+		token = os.Getenv("VAULT_TOKEN")
+	}
+	request.Header.Set("X-Vault-Token", token)
+	namespace := p.Namespace
+	if namespace == "" {
+		namespace = os.Getenv("VAULT_NAMESPACE")
+	}
+	if namespace != "" {
+		request.Header.Set("X-Vault-Namespace", namespace)
+	}
+	client := p.Client
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return "", err
+	}
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("vault returned %v for %v", response.Status, path)
+	}
+	var secret = struct {
+		Data map[string]interface{} `json:"data"`
+	}{}
+	if err = json.Unmarshal(body, &secret); err != nil {
+		return "", fmt.Errorf("failed to decode vault response for %v due to %v", path, err)
+	}
+	data := secret.Data
+	if p.KVVersion != 1 {
+		data, _ = data["data"].(map[string]interface{})
+	}
+	value, ok := data[field]
+	if !ok {
+		return "", fmt.Errorf("field %v was not found in vault secret %v", field, path)
+	}
+	return toolbox.AsString(value), nil
+}
+
+// NewVaultSecretProvider returns vault secret provider configured with VAULT_* environment variables
+func NewVaultSecretProvider() *VaultSecretProvider {
+	return &VaultSecretProvider{}
+}
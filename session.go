@@ -0,0 +1,168 @@
+package dsc
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"sync"
+)
+
+// sqlDatabase represents sql.DB or session pinned sql.Conn
+type sqlDatabase interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// sessionConnection represents sql connection with a single pinned datastore connection
+type sessionConnection struct {
+	*sqlConnection
+	conn *sql.Conn
+}
+
+// Close keeps connection pinned to the session
+func (c *sessionConnection) Close() error {
+	return nil
+}
+
+// CloseNow keeps connection pinned to the session
+func (c *sessionConnection) CloseNow() error {
+	return nil
+}
+
+// Begin starts transaction on pinned connection
+func (c *sessionConnection) Begin() error {
+	if !c.canHandleTransaction {
+		return nil
+	}
+	tx, err := c.conn.BeginTx(context.Background(), nil)
+	if err != nil {
+		return err
+	}
+	c.tx = tx
+	return nil
+}
+
+// sessionConnectionProvider represents a connection provider always returning session connection
+type sessionConnectionProvider struct {
+	ConnectionProvider
+	connection *sessionConnection
+}
+
+// Get returns session connection
+func (p *sessionConnectionProvider) Get() (Connection, error) {
+	return p.connection, nil
+}
+
+// Close keeps underlying provider open, it is owned by the session parent manager
+func (p *sessionConnectionProvider) Close() error {
+	return nil
+}
+
+// Shutdown keeps underlying provider open, it is owned by the session parent manager
+func (p *sessionConnectionProvider) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+// Stats returns underlying provider pool snapshot
+func (p *sessionConnectionProvider) Stats() PoolStats {
+	if provider, ok := p.ConnectionProvider.(PoolStatsProvider); ok {
+		return provider.Stats()
+	}
+	return PoolStats{}
+}
+
+// Session represents a manager with all operations running on a single pinned datastore connection,
+// so that session scoped state (temporary tables, SET variables, advisory locks) survives between calls.
+// Session is not safe for concurrent use, it has to be released with Release or Discard.
+type Session struct {
+	Manager
+	connection *sessionConnection
+	mutex      sync.Mutex
+	released   bool
+}
+
+// release rolls back pending transaction and returns pinned connection to the pool, discard closes pinned connection instead
+func (s *Session) release(discard bool) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.released {
+		return nil
+	}
+	s.released = true
+	var err error
+	if s.connection.tx != nil {
+		if err = s.connection.sqlConnection.Rollback(); err != nil {
+			err = fmt.Errorf("failed to rollback session transaction due to %v", err)
+		}
+	}
+	if discard { //driver.ErrBadConn makes database/sql close the connection
+		_ = s.connection.conn.Raw(func(driverConn interface{}) error {
+			return driver.ErrBadConn
+		})
+	} else if closeErr := s.connection.conn.Close(); closeErr != nil && err == nil {
+		err = closeErr
+	}
+	if closeErr := s.connection.sqlConnection.Close(); closeErr != nil && err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// Release returns pinned connection back to the pool, pending transaction is rolled back, session state stays with the pooled connection
+func (s *Session) Release() error {
+	return s.release(false)
+}
+
+// Discard releases session closing pinned datastore connection, so that session state is not shared with other pool users
+func (s *Session) Discard() error {
+	return s.release(true)
+}
+
+// Close releases session
+func (s *Session) Close() error {
+	return s.Release()
+}
+
+// RunInTx executes function in a transaction on pinned session connection
+func (s *Session) RunInTx(fn func(connection Connection) error, options *TxOptions) error {
+	runner, ok := s.Manager.(TxRunner)
+	if !ok {
+		return fmt.Errorf("failed to run session transaction: %T %v", s.Manager, errUnsupportedOperation)
+	}
+	return runner.RunInTx(fn, options)
+}
+
+// Session checks out and pins a single datastore connection, returned session manager runs all operations on it until released
+func (m *AbstractManager) Session(ctx context.Context) (*Session, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	binder, ok := m.Manager.(connectionProviderBinder)
+	if !ok {
+		return nil, fmt.Errorf("failed to create session: %T %v", m.Manager, errUnsupportedOperation)
+	}
+	connection, err := m.Manager.ConnectionProvider().Get()
+	if err != nil {
+		return nil, err
+	}
+	sqlConnection, ok := connection.(*sqlConnection)
+	if !ok {
+		_ = connection.Close()
+		return nil, fmt.Errorf("failed to create session: %T %v", connection, errUnsupportedOperation)
+	}
+	db, err := asSQLDb(sqlConnection.db)
+	if err != nil {
+		_ = connection.Close()
+		return nil, err
+	}
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		_ = connection.Close()
+		return nil, &Error{Kinds: []error{ErrConnection}, Err: fmt.Errorf("failed to pin session connection on %v due to %w", m.Config().DriverName, err)}
+	}
+	session := &Session{connection: &sessionConnection{sqlConnection: sqlConnection, conn: conn}}
+	session.Manager = binder.withConnectionProvider(&sessionConnectionProvider{ConnectionProvider: m.Manager.ConnectionProvider(), connection: session.connection})
+	return session, nil
+}
@@ -0,0 +1,86 @@
+package dsc
+
+import (
+	"fmt"
+	"github.com/viant/toolbox"
+	"sort"
+	"strings"
+)
+
+const mysqlSessionSettingSQL = "SET SESSION %v = %v"
+const pgSessionSettingSQL = "SET %v = %v"
+const oraSessionSettingSQL = "ALTER SESSION SET %v = %v"
+const msSessionSettingSQL = "SET %v %v"
+const sqlLiteSessionSettingSQL = "PRAGMA %v = %v"
+
+var mysqlSessionSettings = newSessionSettingAllowlist("sql_mode", "time_zone", "wait_timeout", "interactive_timeout", "max_execution_time", "transaction_isolation", "tx_isolation",
+	"autocommit", "character_set_client", "character_set_results", "collation_connection", "group_concat_max_len", "lock_wait_timeout", "innodb_lock_wait_timeout",
+	"sql_safe_updates", "foreign_key_checks", "unique_checks")
+
+var pgSessionSettings = newSessionSettingAllowlist("statement_timeout", "lock_timeout", "idle_in_transaction_session_timeout", "search_path", "timezone", "application_name",
+	"work_mem", "datestyle", "intervalstyle", "client_encoding", "default_transaction_isolation", "default_transaction_read_only", "synchronous_commit", "extra_float_digits")
+
+var oraSessionSettings = newSessionSettingAllowlist("nls_date_format", "nls_timestamp_format", "nls_timestamp_tz_format", "nls_language", "nls_territory", "nls_numeric_characters",
+	"nls_sort", "nls_comp", "nls_date_language", "nls_currency", "time_zone", "optimizer_mode", "ddl_lock_timeout")
+
+var msSessionSettings = newSessionSettingAllowlist("lock_timeout", "language", "datefirst", "dateformat", "textsize", "deadlock_priority", "arithabort", "ansi_nulls",
+	"ansi_warnings", "quoted_identifier", "nocount", "xact_abort", "concat_null_yields_null")
+
+var sqlLiteSessionSettings = newSessionSettingAllowlist("foreign_keys", "busy_timeout", "journal_mode", "synchronous", "cache_size", "temp_store", "case_sensitive_like", "recursive_triggers")
+
+// sessionSettingKeywords represents values that are passed to the datastore without quotes
+var sessionSettingKeywords = newSessionSettingAllowlist("on", "off", "true", "false", "default")
+
+func newSessionSettingAllowlist(names ...string) map[string]bool {
+	var result = make(map[string]bool)
+	for _, name := range names {
+		result[name] = true
+	}
+	return result
+}
+
+// SessionSettingSQL returns SQL applying session setting or error if setting is not on the dialect allowlist
+func (d sqlDatastoreDialect) SessionSettingSQL(name string, value interface{}) (string, error) {
+	if d.sessionSettingSQL == "" || !d.sessionSettings[strings.ToLower(name)] {
+		return "", fmt.Errorf("unsupported session setting: %v", name)
+	}
+	return fmt.Sprintf(d.sessionSettingSQL, name, sessionSettingValue(value)), nil
+}
+
+// sessionSettingValue returns value literal, numeric values and keyword are not quoted
+func sessionSettingValue(value interface{}) string {
+	if toolbox.IsInt(value) || toolbox.IsFloat(value) || toolbox.IsBool(value) {
+		return toolbox.AsString(value)
+	}
+	textValue := toolbox.AsString(value)
+	if sessionSettingKeywords[strings.ToLower(textValue)] {
+		return textValue
+	}
+	return "'" + strings.Replace(textValue, "'", "''", -1) + "'"
+}
+
+// applySessionSettings executes config session settings with passed in executor
+func applySessionSettings(dialect DatastoreDialect, settings map[string]interface{}, execute func(SQL string) error) error {
+	if len(settings) == 0 {
+		return nil
+	}
+	settingsDialect, ok := dialect.(SessionSettingsDialect)
+	if !ok {
+		return fmt.Errorf("failed to apply session settings due to %v", errUnsupportedOperation)
+	}
+	var names = make([]string, 0, len(settings))
+	for name := range settings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		SQL, err := settingsDialect.SessionSettingSQL(name, settings[name])
+		if err != nil {
+			return err
+		}
+		if err = execute(SQL); err != nil {
+			return fmt.Errorf("failed to apply session setting %v due to %v", name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package dsc_test
+
+import (
+	"context"
+	"database/sql"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/dsc"
+	"testing"
+)
+
+func TestSessionSettingSQL(t *testing.T) {
+	var useCases = []struct {
+		description string
+		driver      string
+		name        string
+		value       interface{}
+		expect      string
+		hasError    bool
+	}{
+		{description: "pg statement timeout", driver: "pg", name: "statement_timeout", value: 5000, expect: "SET statement_timeout = 5000"},
+		{description: "mysql sql mode", driver: "mysql", name: "sql_mode", value: "STRICT_ALL_TABLES", expect: "SET SESSION sql_mode = 'STRICT_ALL_TABLES'"},
+		{description: "oracle NLS setting", driver: "ora", name: "NLS_DATE_FORMAT", value: "YYYY-MM-DD", expect: "ALTER SESSION SET NLS_DATE_FORMAT = 'YYYY-MM-DD'"},
+		{description: "mssql keyword value", driver: "sqlserver", name: "XACT_ABORT", value: "ON", expect: "SET XACT_ABORT ON"},
+		{description: "quote escaping", driver: "pg", name: "application_name", value: "it's me", expect: "SET application_name = 'it''s me'"},
+		{description: "setting not on allowlist", driver: "mysql", name: "password", value: "abc", hasError: true},
+		{description: "dialect without session settings", driver: "ndjson", name: "foreign_keys", value: "ON", hasError: true},
+	}
+	for _, useCase := range useCases {
+		dialect := dsc.GetDatastoreDialect(useCase.driver)
+		actual, err := dialect.(dsc.SessionSettingsDialect).SessionSettingSQL(useCase.name, useCase.value)
+		if useCase.hasError {
+			assert.NotNil(t, err, useCase.description)
+			continue
+		}
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		assert.EqualValues(t, useCase.expect, actual, useCase.description)
+	}
+}
+
+func TestConfig_SessionSettings(t *testing.T) {
+	{
+		config := dsc.NewConfig("sqlite3", "[url]", "url:./test/foo.db")
+		config.SessionSettings = map[string]interface{}{
+			"busy_timeout": 1234,
+		}
+		manager, err := dsc.NewManagerFactory().Create(config)
+		if !assert.Nil(t, err) {
+			return
+		}
+		var record = make([]interface{}, 0)
+		success, err := manager.ReadSingle(&record, "PRAGMA busy_timeout", nil, nil)
+		assert.Nil(t, err)
+		assert.True(t, success)
+		if assert.Equal(t, 1, len(record)) {
+			assert.EqualValues(t, 1234, record[0])
+		}
+	}
+	{
+		config := dsc.NewConfig("sqlite3", "[url]", "url:./test/foo.db")
+		config.SessionSettings = map[string]interface{}{
+			"secure_delete": 1,
+		}
+		manager, err := dsc.NewManagerFactory().Create(config)
+		if !assert.Nil(t, err) {
+			return
+		}
+		_, err = manager.Execute("SELECT 1")
+		assert.NotNil(t, err)
+	}
+}
+
+func TestConfig_SessionSettingsOnEveryConnection(t *testing.T) {
+	config := dsc.NewConfig("sqlite3", "[url]", "url:./test/foo.db")
+	config.SessionSettings = map[string]interface{}{
+		"busy_timeout": 4321,
+	}
+	config.InitSQL = []string{"CREATE TEMP TABLE init_marker(id INTEGER)"}
+	manager, err := dsc.NewManagerFactory().Create(config)
+	if !assert.Nil(t, err) {
+		return
+	}
+	connection, err := manager.ConnectionProvider().Get()
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer connection.Close()
+	db := connection.Unwrap((*sql.DB)(nil)).(*sql.DB)
+	//both physical connections are checked out at the same time
+	var conns = make([]*sql.Conn, 2)
+	for i := range conns {
+		if conns[i], err = db.Conn(context.Background()); !assert.Nil(t, err) {
+			return
+		}
+		defer conns[i].Close()
+	}
+	for _, conn := range conns {
+		var timeout int
+		assert.Nil(t, conn.QueryRowContext(context.Background(), "PRAGMA busy_timeout").Scan(&timeout))
+		assert.EqualValues(t, 4321, timeout)
+		var count int
+		assert.Nil(t, conn.QueryRowContext(context.Background(), "SELECT COUNT(*) FROM init_marker").Scan(&count), "init SQL should run on every connection")
+	}
+}
@@ -0,0 +1,58 @@
+package dsc_test
+
+import (
+	"context"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/dsc"
+)
+
+type sessionEvent struct {
+	Id   int `primaryKey:"true"`
+	Name string
+}
+
+func TestManager_Session(t *testing.T) {
+	config := dsc.NewConfig("sqlite3", "[url]", "url:./test/session.db")
+	manager, err := dsc.NewManagerFactory().Create(config)
+	if !assert.Nil(t, err) {
+		return
+	}
+	session, err := manager.(dsc.SessionProvider).Session(context.Background())
+	if !assert.Nil(t, err) {
+		return
+	}
+	_, err = session.Execute("CREATE TEMP TABLE session_events(id INTEGER PRIMARY KEY, name TEXT)")
+	if !assert.Nil(t, err) {
+		return
+	}
+	inserted, _, err := session.PersistAll(&[]*sessionEvent{{Id: 1, Name: "a"}, {Id: 2, Name: "b"}}, "session_events", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, inserted)
+
+	err = session.RunInTx(func(connection dsc.Connection) error {
+		_, err := session.ExecuteOnConnection(connection, "INSERT INTO session_events(id, name) VALUES(3, 'c')", nil)
+		return err
+	}, nil)
+	assert.Nil(t, err)
+
+	var events = make([]*sessionEvent, 0)
+	assert.Nil(t, session.ReadAll(&events, "SELECT id, name FROM session_events ORDER BY id", nil, nil))
+	assert.Equal(t, 3, len(events), "temporary table should survive between session calls")
+
+	assert.Nil(t, session.Discard())
+	assert.Nil(t, session.Release(), "release should be idempotent")
+
+	session, err = manager.(dsc.SessionProvider).Session(context.Background())
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer session.Release()
+	_, err = session.Execute("SELECT COUNT(*) FROM session_events")
+	assert.NotNil(t, err, "discarded session state should not be visible to new session")
+
+	_, ok := interface{}(session).(dsc.SessionProvider)
+	assert.False(t, ok, "session should not create nested session")
+}
@@ -0,0 +1,601 @@
+package dsc
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/viant/toolbox"
+	"github.com/viant/toolbox/url"
+)
+
+// ShardKeyExtractor returns shard key of passed in struct or map record
+type ShardKeyExtractor func(record interface{}) (interface{}, error)
+
+// ShardMap represents shard routing config
+type ShardMap struct {
+	//URL shard map source, see NewShardMapFromURL and ShardedManager.Watch
+	URL string
+	//Shards datastore configs keyed by shard name
+	Shards map[string]*Config
+	//Routes explicit shard key to shard name routes, keys without route are hashed over shard names sorted by name
+	Routes map[string]string
+}
+
+// validate checks that shard map defines shards and routes to defined shards only
+func (m *ShardMap) validate() error {
+	if len(m.Shards) == 0 {
+		return fmt.Errorf("invalid shard map %v: shards were empty", m.URL)
+	}
+	for key, shard := range m.Routes {
+		if _, ok := m.Shards[shard]; !ok {
+			return fmt.Errorf("invalid shard map %v: key %v routes to undefined shard %v", m.URL, key, shard)
+		}
+	}
+	return nil
+}
+
+// NewShardMapFromURL returns shard map from JSON or YAML url, shard configs are initialised as with NewConfigFromURL
+func NewShardMapFromURL(URL string) (*ShardMap, error) {
+	document, err := loadConfigDocument(URL, make(map[string]bool), nil)
+	if err != nil {
+		return nil, err
+	}
+	encoded, err := json.Marshal(document)
+	if err != nil {
+		return nil, err
+	}
+	result := &ShardMap{}
+	if err = json.Unmarshal(encoded, result); err != nil {
+		return nil, fmt.Errorf("failed to decode shard map %v due to %v", URL, err)
+	}
+	result.URL = URL
+	for name, config := range result.Shards {
+		if config == nil {
+			return nil, fmt.Errorf("invalid shard map %v: shard %v config was empty", URL, name)
+		}
+		config.initLock()
+		if err = config.Init(); err != nil {
+			return nil, fmt.Errorf("failed to init shard %v config due to %v", name, err)
+		}
+		config.applyDefaults()
+		if err = config.validate(); err != nil {
+			return nil, err
+		}
+	}
+	return result, result.validate()
+}
+
+// shard represents shard manager with config fingerprint used to detect shard config changes on reload,
+// shard removed from routing is closed once in-flight operations release it
+type shard struct {
+	name        string
+	manager     Manager
+	fingerprint string
+	mutex       sync.Mutex
+	refs        int
+	retired     bool
+	closed      bool
+}
+
+// acquire marks shard as used by in-flight operation, it has to be called while holding ShardedManager read lock
+func (s *shard) acquire() {
+	s.mutex.Lock()
+	s.refs++
+	s.mutex.Unlock()
+}
+
+// release ends in-flight operation, retired shard is closed with the last release
+func (s *shard) release() {
+	s.mutex.Lock()
+	s.refs--
+	toClose := s.retired && s.refs == 0 && !s.closed
+	s.closed = s.closed || toClose
+	s.mutex.Unlock()
+	if toClose {
+		if err := s.close(); err != nil {
+			Logf("%v", err)
+		}
+	}
+}
+
+// retire marks shard as removed from routing, it is closed right away when not in use, or with the last release otherwise
+func (s *shard) retire() error {
+	s.mutex.Lock()
+	if s.retired {
+		s.mutex.Unlock()
+		return nil
+	}
+	s.retired = true
+	toClose := s.refs == 0
+	s.closed = toClose
+	s.mutex.Unlock()
+	if !toClose {
+		return nil
+	}
+	return s.close()
+}
+
+func (s *shard) close() error {
+	closer, ok := s.manager.(io.Closer)
+	if !ok {
+		return nil
+	}
+	if err := closer.Close(); err != nil {
+		return fmt.Errorf("failed to close shard %v due to %v", s.name, err)
+	}
+	return nil
+}
+
+// shardRouting represents immutable routing state, reload replaces it as a whole
+type shardRouting struct {
+	shardMap *ShardMap
+	names    []string
+	shards   map[string]*shard
+}
+
+// shardFor returns shard name for passed in shard key
+func (r *shardRouting) shardFor(key interface{}) string {
+	textKey := toolbox.AsString(key)
+	if name, ok := r.shardMap.Routes[textKey]; ok {
+		return name
+	}
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(textKey))
+	return r.names[int(hash.Sum32()%uint32(len(r.names)))]
+}
+
+// ShardedManager represents manager routing operations to shard managers, records are routed by shard key declared with registered table descriptor
+// ShardColumn or ShardKey, reads without shard key are scattered across all shards and gathered in shard name order.
+// Hash routing depends on number of shards, use ShardMap.Routes to pin keys while adding shards.
+type ShardedManager struct {
+	mutex       *sync.RWMutex
+	routing     *shardRouting
+	descriptors map[string]*TableDescriptor
+	factory     ManagerFactory
+}
+
+// configFingerprint returns shard config identity, shard manager is recreated when it changes
+func configFingerprint(config *Config) (string, error) {
+	encoded, err := json.Marshal(config)
+	if err != nil {
+		return "", err
+	}
+	return config.DriverName + "\x00" + config.Descriptor + "\x00" + string(encoded), nil
+}
+
+// newRouting creates routing for shard map, shard managers with unchanged config are taken from previous routing
+func (m *ShardedManager) newRouting(shardMap *ShardMap, previous *shardRouting) (*shardRouting, error) {
+	if err := shardMap.validate(); err != nil {
+		return nil, err
+	}
+	result := &shardRouting{shardMap: shardMap, names: make([]string, 0, len(shardMap.Shards)), shards: make(map[string]*shard)}
+	for name, config := range shardMap.Shards {
+		result.names = append(result.names, name)
+		fingerprint, err := configFingerprint(config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create shard %v due to %v", name, err)
+		}
+		if previous != nil {
+			if existing, ok := previous.shards[name]; ok && existing.fingerprint == fingerprint {
+				result.shards[name] = existing
+				continue
+			}
+		}
+		manager, err := m.factory.Create(config)
+		if err != nil {
+			_ = closeShards(result, previous)
+			return nil, fmt.Errorf("failed to create shard %v due to %v", name, err)
+		}
+		result.shards[name] = &shard{name: name, manager: manager, fingerprint: fingerprint}
+	}
+	sort.Strings(result.names)
+	return result, nil
+}
+
+// closeShards retires shard managers of routing that are not used by retained routing,
+// shards used by in-flight operations are closed once the operations complete
+func closeShards(routing, retained *shardRouting) error {
+	var err error
+	for name, candidate := range routing.shards {
+		if retained != nil {
+			if kept, ok := retained.shards[name]; ok && kept == candidate {
+				continue
+			}
+		}
+		if closeErr := candidate.retire(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+func (m *ShardedManager) currentRouting() *shardRouting {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.routing
+}
+
+// acquireRouting returns current routing with all its shards acquired, so that reload does not close them until returned function is called
+func (m *ShardedManager) acquireRouting() (*shardRouting, func()) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	routing := m.routing
+	for _, shard := range routing.shards {
+		shard.acquire()
+	}
+	return routing, func() {
+		for _, shard := range routing.shards {
+			shard.release()
+		}
+	}
+}
+
+// Register registers table descriptor declaring shard key with ShardColumn or ShardKey, descriptor is used for routing only
+func (m *ShardedManager) Register(descriptor *TableDescriptor) error {
+	if descriptor.Table == "" {
+		return fmt.Errorf("table name was not set %v", descriptor)
+	}
+	if descriptor.ShardKey == nil && descriptor.ShardColumn == "" {
+		return fmt.Errorf("failed to register %v: shard key was not declared", descriptor.Table)
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.descriptors[descriptor.Table] = descriptor
+	return nil
+}
+
+// Shards returns shard names sorted by name
+func (m *ShardedManager) Shards() []string {
+	return append([]string{}, m.currentRouting().names...)
+}
+
+// Shard returns shard manager for passed in shard name, the manager is closed once the shard is removed or changed by Reload,
+// use AcquireShard to keep using the manager across reloads
+func (m *ShardedManager) Shard(name string) (Manager, error) {
+	shard, ok := m.currentRouting().shards[name]
+	if !ok {
+		return nil, fmt.Errorf("failed to lookup shard %v", name)
+	}
+	return shard.manager, nil
+}
+
+// AcquireShard returns shard manager for passed in shard name with release function, Reload does not close the manager before it is released
+func (m *ShardedManager) AcquireShard(name string) (Manager, func(), error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	shard, ok := m.routing.shards[name]
+	if !ok {
+		return nil, nil, fmt.Errorf("failed to lookup shard %v", name)
+	}
+	shard.acquire()
+	return shard.manager, shard.release, nil
+}
+
+// ShardFor returns shard name for passed in shard key
+func (m *ShardedManager) ShardFor(key interface{}) string {
+	return m.currentRouting().shardFor(key)
+}
+
+// ManagerFor returns shard manager for passed in shard key, see Shard for the manager lifecycle
+func (m *ShardedManager) ManagerFor(key interface{}) Manager {
+	routing := m.currentRouting()
+	return routing.shards[routing.shardFor(key)].manager
+}
+
+// shardKey returns record shard key extracted with table descriptor ShardKey or ShardColumn
+func (m *ShardedManager) shardKey(table string, record interface{}) (interface{}, error) {
+	m.mutex.RLock()
+	descriptor, ok := m.descriptors[table]
+	m.mutex.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("failed to route %v: table descriptor with shard key was not registered", table)
+	}
+	if descriptor.ShardKey != nil {
+		return descriptor.ShardKey(record)
+	}
+	if toolbox.IsMap(record) {
+		value, ok := toolbox.AsMap(record)[descriptor.ShardColumn]
+		if !ok {
+			return nil, fmt.Errorf("failed to route %v: shard column %v was missing", table, descriptor.ShardColumn)
+		}
+		return value, nil
+	}
+	provider, err := newMetaDmlProvider(table, toolbox.DiscoverTypeByKind(record, reflect.Struct), nil)
+	if err != nil {
+		return nil, err
+	}
+	return provider.(*metaDmlProvider).readValues(record, []string{descriptor.ShardColumn})[0], nil
+}
+
+// ManagerForRecord returns shard manager for passed in table record, see Shard for the manager lifecycle
+func (m *ShardedManager) ManagerForRecord(table string, record interface{}) (Manager, error) {
+	return m.managerForRecord(m.currentRouting(), table, record)
+}
+
+func (m *ShardedManager) managerForRecord(routing *shardRouting, table string, record interface{}) (Manager, error) {
+	key, err := m.shardKey(table, record)
+	if err != nil {
+		return nil, err
+	}
+	return routing.shards[routing.shardFor(key)].manager, nil
+}
+
+// shardSlice represents records of a slice routed to one shard with their source slice indexes
+type shardSlice struct {
+	manager Manager
+	slice   reflect.Value
+	indexes []int
+}
+
+// splitSlice groups slice pointer records by shard, groups are returned in shard name order
+func (m *ShardedManager) splitSlice(routing *shardRouting, slicePointer interface{}, table string) (reflect.Value, []*shardSlice, error) {
+	source := reflect.ValueOf(slicePointer)
+	if source.Kind() != reflect.Ptr || source.Elem().Kind() != reflect.Slice {
+		return source, nil, fmt.Errorf("failed to route %v: expected slice pointer but had %T", table, slicePointer)
+	}
+	source = source.Elem()
+	var byShard = make(map[string]*shardSlice)
+	for i := 0; i < source.Len(); i++ {
+		key, err := m.shardKey(table, source.Index(i).Interface())
+		if err != nil {
+			return source, nil, err
+		}
+		name := routing.shardFor(key)
+		group, ok := byShard[name]
+		if !ok {
+			group = &shardSlice{manager: routing.shards[name].manager, slice: reflect.MakeSlice(source.Type(), 0, 1)}
+			byShard[name] = group
+		}
+		group.slice = reflect.Append(group.slice, source.Index(i))
+		group.indexes = append(group.indexes, i)
+	}
+	var result = make([]*shardSlice, 0, len(byShard))
+	for _, name := range routing.names {
+		if group, ok := byShard[name]; ok {
+			result = append(result, group)
+		}
+	}
+	return source, result, nil
+}
+
+// PersistAll persists records on their shards, generated keys of value slices are copied back into passed in slice
+func (m *ShardedManager) PersistAll(slicePointer interface{}, table string, provider DmlProvider) (inserted int, updated int, err error) {
+	routing, release := m.acquireRouting()
+	defer release()
+	source, groups, err := m.splitSlice(routing, slicePointer, table)
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, group := range groups {
+		groupPointer := reflect.New(group.slice.Type())
+		groupPointer.Elem().Set(group.slice)
+		shardInserted, shardUpdated, err := group.manager.PersistAll(groupPointer.Interface(), table, provider)
+		inserted += shardInserted
+		updated += shardUpdated
+		if err != nil {
+			return inserted, updated, err
+		}
+		for i, index := range group.indexes {
+			source.Index(index).Set(groupPointer.Elem().Index(i))
+		}
+	}
+	return inserted, updated, nil
+}
+
+// PersistSingle persists record on its shard
+func (m *ShardedManager) PersistSingle(dataPointer interface{}, table string, provider DmlProvider) (inserted int, updated int, err error) {
+	routing, release := m.acquireRouting()
+	defer release()
+	manager, err := m.managerForRecord(routing, table, dataPointer)
+	if err != nil {
+		return 0, 0, err
+	}
+	return manager.PersistSingle(dataPointer, table, provider)
+}
+
+// DeleteAll deletes records from their shards
+func (m *ShardedManager) DeleteAll(slicePointer interface{}, table string, keyProvider KeyGetter) (deleted int, err error) {
+	routing, release := m.acquireRouting()
+	defer release()
+	_, groups, err := m.splitSlice(routing, slicePointer, table)
+	if err != nil {
+		return 0, err
+	}
+	for _, group := range groups {
+		groupPointer := reflect.New(group.slice.Type())
+		groupPointer.Elem().Set(group.slice)
+		shardDeleted, err := group.manager.DeleteAll(groupPointer.Interface(), table, keyProvider)
+		deleted += shardDeleted
+		if err != nil {
+			return deleted, err
+		}
+	}
+	return deleted, nil
+}
+
+// DeleteSingle deletes record from its shard
+func (m *ShardedManager) DeleteSingle(resultPointer interface{}, table string, keyProvider KeyGetter) (success bool, err error) {
+	routing, release := m.acquireRouting()
+	defer release()
+	manager, err := m.managerForRecord(routing, table, resultPointer)
+	if err != nil {
+		return false, err
+	}
+	return manager.DeleteSingle(resultPointer, table, keyProvider)
+}
+
+// scatter runs function concurrently on all routing shards, it returns the first error in shard name order
+func (m *ShardedManager) scatter(routing *shardRouting, fn func(index int, name string, manager Manager) error) error {
+	var errs = make([]error, len(routing.names))
+	var group = &sync.WaitGroup{}
+	for i, name := range routing.names {
+		group.Add(1)
+		go func(index int, name string, manager Manager) {
+			defer group.Done()
+			if err := fn(index, name, manager); err != nil {
+				errs[index] = fmt.Errorf("failed to read shard %v due to %v", name, err)
+			}
+		}(i, name, routing.shards[name].manager)
+	}
+	group.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadAll reads query concurrently from all shards, results are appended to result slice in shard name order
+func (m *ShardedManager) ReadAll(resultSlicePointer interface{}, query string, parameters []interface{}, mapper RecordMapper) error {
+	target := reflect.ValueOf(resultSlicePointer)
+	if target.Kind() != reflect.Ptr || target.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("failed to read shards: expected slice pointer but had %T", resultSlicePointer)
+	}
+	routing, release := m.acquireRouting()
+	defer release()
+	var results = make([]reflect.Value, len(routing.names))
+	err := m.scatter(routing, func(index int, name string, manager Manager) error {
+		results[index] = reflect.New(target.Elem().Type())
+		return manager.ReadAll(results[index].Interface(), query, parameters, mapper)
+	})
+	if err != nil {
+		return err
+	}
+	for _, result := range results {
+		if result.IsValid() {
+			target.Elem().Set(reflect.AppendSlice(target.Elem(), result.Elem()))
+		}
+	}
+	return nil
+}
+
+// ReadAllWithHandler reads query concurrently from all shards, handler calls are serialized, reading stops on all shards once handler returns false
+func (m *ShardedManager) ReadAllWithHandler(query string, parameters []interface{}, readingHandler func(scanner Scanner) (toContinue bool, err error)) error {
+	routing, release := m.acquireRouting()
+	defer release()
+	var mutex = &sync.Mutex{}
+	var done bool
+	return m.scatter(routing, func(index int, name string, manager Manager) error {
+		return manager.ReadAllWithHandler(query, parameters, func(scanner Scanner) (bool, error) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			if done {
+				return false, nil
+			}
+			toContinue, err := readingHandler(scanner)
+			if !toContinue || err != nil {
+				done = true
+			}
+			return toContinue, err
+		})
+	})
+}
+
+// Reload applies passed in shard map (or shard map reloaded from its URL when nil) and rebuilds routing,
+// shard managers with unchanged config are kept, removed or changed shard managers are closed once in-flight operations complete
+func (m *ShardedManager) Reload(shardMap *ShardMap) error {
+	previous := m.currentRouting()
+	if shardMap == nil {
+		if previous.shardMap.URL == "" {
+			return fmt.Errorf("failed to reload shard map: URL was empty")
+		}
+		var err error
+		if shardMap, err = NewShardMapFromURL(previous.shardMap.URL); err != nil {
+			return fmt.Errorf("failed to reload shard map due to %v", err)
+		}
+	}
+	m.mutex.Lock()
+	previous = m.routing
+	routing, err := m.newRouting(shardMap, previous)
+	if err != nil {
+		m.mutex.Unlock()
+		return fmt.Errorf("failed to reload shard map due to %v", err)
+	}
+	m.routing = routing
+	m.mutex.Unlock()
+	return closeShards(previous, routing)
+}
+
+// shardMapFingerprint returns hash of shard map source content
+func shardMapFingerprint(URL string) (string, error) {
+	content, err := url.NewResource(URL).DownloadText()
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(content))), nil
+}
+
+// Watch checks shard map source (ShardMap.URL) every interval in background and reloads routing when it changes, it stops when context is done.
+func (m *ShardedManager) Watch(ctx context.Context, interval time.Duration) error {
+	URL := m.currentRouting().shardMap.URL
+	if URL == "" {
+		return fmt.Errorf("failed to watch shard map: URL was empty")
+	}
+	fingerprint, err := shardMapFingerprint(URL)
+	if err != nil {
+		return err
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			current, err := shardMapFingerprint(URL)
+			if err != nil {
+				Logf("failed to check shard map changes %v", err)
+				continue
+			}
+			if current == fingerprint {
+				continue
+			}
+			if err = m.Reload(nil); err != nil {
+				Logf("%v", err)
+				continue
+			}
+			fingerprint = current
+		}
+	}()
+	return nil
+}
+
+// Close closes all shard managers, shards used by in-flight operations are closed once the operations complete
+func (m *ShardedManager) Close() error {
+	return closeShards(m.currentRouting(), nil)
+}
+
+// NewShardedManager creates manager routing operations to shard managers created for shard map configs
+func NewShardedManager(shardMap *ShardMap) (*ShardedManager, error) {
+	result := &ShardedManager{
+		mutex:       &sync.RWMutex{},
+		descriptors: make(map[string]*TableDescriptor),
+		factory:     NewManagerFactory(),
+	}
+	routing, err := result.newRouting(shardMap, nil)
+	if err != nil {
+		return nil, err
+	}
+	result.routing = routing
+	return result, nil
+}
+
+// NewShardedManagerFromURL creates sharded manager for shard map JSON or YAML url, see ShardedManager.Watch
+func NewShardedManagerFromURL(URL string) (*ShardedManager, error) {
+	shardMap, err := NewShardMapFromURL(URL)
+	if err != nil {
+		return nil, err
+	}
+	return NewShardedManager(shardMap)
+}
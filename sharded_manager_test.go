@@ -0,0 +1,107 @@
+package dsc_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/dsc"
+)
+
+type tenantOrder struct {
+	Id     int    `column:"id" primaryKey:"true"`
+	Tenant string `column:"tenant"`
+	Amount int    `column:"amount"`
+}
+
+func writeShardMap(t *testing.T, URL string, dir string, shards ...string) {
+	var shardConfigs = ""
+	for i, shard := range shards {
+		if i > 0 {
+			shardConfigs += ","
+		}
+		shardConfigs += fmt.Sprintf(`"%v":{"Driver":"sqlite3","DSN":"[url]","Parameters":{"url":"%v"}}`, shard, filepath.Join(dir, shard+".db"))
+	}
+	content := fmt.Sprintf(`{"Shards":{%v},"Routes":{"acme":"s1","globex":"s2"}}`, shardConfigs)
+	assert.Nil(t, os.WriteFile(URL, []byte(content), 0644))
+}
+
+func TestShardedManager(t *testing.T) {
+	dir := t.TempDir()
+	URL := filepath.Join(dir, "shards.json")
+	writeShardMap(t, URL, dir, "s1", "s2")
+	manager, err := dsc.NewShardedManagerFromURL(URL)
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer manager.Close()
+	assert.EqualValues(t, []string{"s1", "s2"}, manager.Shards())
+	for _, name := range manager.Shards() {
+		shard, err := manager.Shard(name)
+		if !assert.Nil(t, err) {
+			return
+		}
+		_, err = shard.Execute("CREATE TABLE orders(id INTEGER PRIMARY KEY, tenant TEXT, amount INTEGER)")
+		assert.Nil(t, err)
+	}
+	assert.NotNil(t, manager.Register(&dsc.TableDescriptor{Table: "orders"}))
+	assert.Nil(t, manager.Register(&dsc.TableDescriptor{Table: "orders", ShardColumn: "tenant"}))
+
+	orders := []tenantOrder{{Id: 1, Tenant: "acme", Amount: 10}, {Id: 2, Tenant: "globex", Amount: 20}, {Id: 3, Tenant: "acme", Amount: 30}}
+	inserted, _, err := manager.PersistAll(&orders, "orders", nil)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, 3, inserted)
+
+	s1, _ := manager.Shard("s1")
+	var s1Orders = make([]tenantOrder, 0)
+	assert.Nil(t, s1.ReadAll(&s1Orders, "SELECT id, tenant, amount FROM orders ORDER BY id", nil, nil))
+	assert.Equal(t, 2, len(s1Orders))
+	assert.Equal(t, "s2", manager.ShardFor("globex"))
+
+	var all = make([]tenantOrder, 0)
+	assert.Nil(t, manager.ReadAll(&all, "SELECT id, tenant, amount FROM orders ORDER BY id", nil, nil))
+	if assert.Equal(t, 3, len(all)) {
+		assert.EqualValues(t, []int{1, 3, 2}, []int{all[0].Id, all[1].Id, all[2].Id})
+	}
+	var count = 0
+	assert.Nil(t, manager.ReadAllWithHandler("SELECT id FROM orders", nil, func(scanner dsc.Scanner) (bool, error) {
+		count++
+		return true, nil
+	}))
+	assert.Equal(t, 3, count)
+
+	deleted, err := manager.DeleteSingle(&orders[1], "orders", nil)
+	assert.Nil(t, err)
+	assert.True(t, deleted)
+
+	writeShardMap(t, URL, dir, "s1", "s2", "s3")
+	if assert.Nil(t, manager.Reload(nil)) {
+		assert.EqualValues(t, []string{"s1", "s2", "s3"}, manager.Shards())
+		retained, _ := manager.Shard("s1")
+		assert.True(t, retained == s1)
+	}
+	_, _, err = manager.PersistSingle(&tenantOrder{Id: 4}, "customers", nil)
+	assert.NotNil(t, err)
+
+	//removed shard is closed once released
+	s3, release, err := manager.AcquireShard("s3")
+	if !assert.Nil(t, err) {
+		return
+	}
+	writeShardMap(t, URL, dir, "s1", "s2")
+	if assert.Nil(t, manager.Reload(nil)) {
+		assert.EqualValues(t, []string{"s1", "s2"}, manager.Shards())
+		_, err = s3.Execute("CREATE TABLE orders(id INTEGER PRIMARY KEY, tenant TEXT, amount INTEGER)")
+		assert.Nil(t, err, "acquired shard should not be closed by reload")
+		release()
+		_, err = s3.Execute("SELECT 1")
+		assert.NotNil(t, err, "removed shard should be closed with the last release")
+	}
+	_, _, err = manager.AcquireShard("s3")
+	assert.NotNil(t, err)
+}
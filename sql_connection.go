@@ -10,17 +10,38 @@ const (
 	connMaxLifetimeMsKey     = "connMaxLifetimeMs"
 	defaultConnMaxLifetimeMs = 1000
 	maxIdleConnsKey          = "maxIdleConns"
+	defaultMaxIdleConns      = 2 //database/sql default
+	maxOpenConnsKey          = "maxOpenConns"
+	connMaxIdleTimeMsKey     = "connMaxIdleTimeMs"
+	defaultConnMaxIdleTimeMs = 0 //no limit
 )
 
 type sqlConnection struct {
 	canHandleTransaction bool
 	*AbstractConnection
-	db   *sql.DB
-	tx   *sql.Tx
-	init bool
+	db          *sql.DB
+	tx          *sql.Tx
+	init        bool
+	natives     nativeHandles
+	commitHooks []func()
+}
+
+// commitHookConnection represents connection running registered hooks after its active transaction commits
+type commitHookConnection interface {
+	//afterCommit registers hook run after active transaction commit, it returns false if there is no active transaction
+	afterCommit(hook func()) bool
+}
+
+func (c *sqlConnection) afterCommit(hook func()) bool {
+	if c.tx == nil {
+		return false
+	}
+	c.commitHooks = append(c.commitHooks, hook)
+	return true
 }
 
 func (c *sqlConnection) CloseNow() error {
+	c.releaseNative()
 	db, err := asSQLDb(c.db)
 	if err != nil {
 		return err
@@ -51,6 +72,16 @@ func (c *sqlConnection) Unwrap(target interface{}) interface{} {
 	} else if target == sqlTxtPointer {
 		return c.tx
 	}
+	if unwrapper := getNativeUnwrapper(c.config.DriverName); unwrapper != nil {
+		native, err := c.natives.unwrap(c, unwrapper, target)
+		if err != nil {
+			Logf("failed to unwrap %T on %v due to %v", target, c.config.DriverName, err)
+			return nil
+		}
+		if native != nil {
+			return native
+		}
+	}
 	panic(fmt.Sprintf("unsupported target type %v", target))
 }
 
@@ -63,6 +94,13 @@ func (c *sqlConnection) Commit() error {
 	}
 	err := c.tx.Commit()
 	c.tx = nil
+	hooks := c.commitHooks
+	c.commitHooks = nil
+	if err == nil {
+		for _, hook := range hooks {
+			hook()
+		}
+	}
 	return err
 }
 
@@ -75,6 +113,7 @@ func (c *sqlConnection) Rollback() error {
 	}
 	err := c.tx.Rollback()
 	c.tx = nil
+	c.commitHooks = nil
 	return err
 }
 
@@ -88,18 +127,31 @@ func (c *sqlConnectionProvider) NewConnection() (Connection, error) {
 	if err != nil {
 		return nil, err
 	}
-	db, err := sql.Open(config.DriverName, dsn)
+	dialect := GetDatastoreDialect(config.DriverName)
+	if config.TLS != nil {
+		tlsDialect, ok := dialect.(TLSDialect)
+		if !ok {
+			return nil, fmt.Errorf("failed to apply TLS config on %v due to %v", config.DriverName, errUnsupportedOperation)
+		}
+		if dsn, err = tlsDialect.ApplyTLS(dsn, config.TLS); err != nil {
+			return nil, fmt.Errorf("failed to apply TLS config on %v due to %v", config.DriverName, err)
+		}
+	}
+	statements, err := initStatements(config, dialect)
+	if err != nil {
+		return nil, err
+	}
+	db, err := openDB(config.DriverName, dsn, statements)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open connection to %v on %v due to %v", config.DriverName, config.Descriptor, err)
+		return nil, &Error{Kinds: []error{ErrConnection}, Err: fmt.Errorf("failed to open connection to %v on %v due to %w", config.DriverName, config.Descriptor, err)}
 	}
-	if len(config.InitSQL) > 0 {
-		for _, SQL := range config.InitSQL {
-			if _, err = db.Exec(SQL); err != nil {
-				return nil, fmt.Errorf("failed to execute init SQL %v on %v due to %v", SQL, config.Descriptor, err)
-			}
+	applyPoolSettings(db, config)
+	if len(statements) > 0 { //init SQL and session settings run by connector on every physical connection, the first one reports errors early
+		if err = db.Ping(); err != nil {
+			_ = db.Close()
+			return nil, fmt.Errorf("failed to initialise connection on %v due to %v", config.Descriptor, err)
 		}
 	}
-	dialect := GetDatastoreDialect(config.DriverName)
 	var sqlConnection = &sqlConnection{db: db, canHandleTransaction: dialect.CanHandleTransaction()}
 	var connection Connection = sqlConnection
 	var super = NewAbstractConnection(config, c.ConnectionProvider.ConnectionPool(), connection)
@@ -113,34 +165,39 @@ func (c *sqlConnectionProvider) Get() (Connection, error) {
 	if err != nil {
 		return nil, err
 	}
-	db, err := asSQLDb(result.Unwrap(sqlDbPointer))
+	validation, err := newConnectionValidation(c.ConnectionProvider.Config())
 	if err != nil {
 		return nil, err
 	}
-
-	if result.LastUsed() != nil && (time.Now().Sub(*result.LastUsed()) > 60*time.Second) {
-		err = db.Ping()
+	sqlConnection, ok := result.(*sqlConnection)
+	if !ok || !validation.isRequired(result.LastUsed()) {
+		return result, nil
 	}
-
-	if err == nil {
+	if err = sqlConnection.validate(validation.mode); err == nil {
 		return result, nil
 	}
+	return c.renew(result)
+}
 
-	if c.config.Has(connMaxLifetimeMsKey) {
-		connMaxLifetime := c.config.GetDuration(connMaxLifetimeMsKey, time.Millisecond, defaultConnMaxLifetimeMs)
+// applyPoolSettings applies database/sql pool settings (maxOpenConns, maxIdleConns, connMaxLifetimeMs, connMaxIdleTimeMs) before the first driver connection is established
+func applyPoolSettings(db *sql.DB, config *Config) {
+	if config.Has(maxOpenConnsKey) {
+		db.SetMaxOpenConns(config.GetInt(maxOpenConnsKey, 0))
+	}
+	if config.Has(maxIdleConnsKey) {
+		db.SetMaxIdleConns(config.GetInt(maxIdleConnsKey, defaultMaxIdleConns))
+	}
+	if config.Has(connMaxLifetimeMsKey) {
+		connMaxLifetime := config.GetDuration(connMaxLifetimeMsKey, time.Millisecond, defaultConnMaxLifetimeMs)
 		if connMaxLifetime != 0 {
 			db.SetConnMaxLifetime(connMaxLifetime)
 		}
 	}
-	if c.config.Has(maxIdleConnsKey) {
-		db.SetMaxIdleConns(c.config.GetInt(maxIdleConnsKey, 1))
-	}
-
-	result, err = c.NewConnection()
-	if err != nil {
-		return nil, err
+	if config.Has(connMaxIdleTimeMsKey) {
+		if connMaxIdleTime := config.GetDuration(connMaxIdleTimeMsKey, time.Millisecond, defaultConnMaxIdleTimeMs); connMaxIdleTime != 0 {
+			db.SetConnMaxIdleTime(connMaxIdleTime)
+		}
 	}
-	return result, nil
 }
 
 func newSQLConnectionProvider(config *Config) ConnectionProvider {
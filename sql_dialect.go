@@ -145,6 +145,8 @@ type sqlDatastoreDialect struct {
 	autoIncrementSQL       string
 	tableInfoSQL           string
 	schemaResultsetIndex   int
+	sessionSettingSQL      string
+	sessionSettings        map[string]bool
 	DatastoreDialect
 }
 
@@ -177,9 +179,10 @@ func (d *sqlDatastoreDialect) ShowCreateTable(manager Manager, table string) (st
 	}
 	var projection = make([]string, 0)
 	var keyColumns = make([]string, 0)
+	quote := identifierQuoter(manager.Config())
 	for _, column := range columns {
 		var dataType = column.DatabaseTypeName()
-		ddlColumn := fmt.Sprintf("%v %v", column.Name(), dataType)
+		ddlColumn := fmt.Sprintf("%v %v", quoteIdentifier(quote, column.Name()), dataType)
 		if nullable, ok := column.Nullable(); ok && !nullable {
 			ddlColumn += " NOT NULL "
 		}
@@ -191,7 +194,7 @@ func (d *sqlDatastoreDialect) ShowCreateTable(manager Manager, table string) (st
 		projection = append(projection, ddlColumn)
 	}
 	projection = append(keyColumns, projection...)
-	return fmt.Sprintf("CREATE TABLE %v(\n\t%v);", table, strings.Join(projection, ",\n\t")), nil
+	return fmt.Sprintf("CREATE TABLE %v(\n\t%v);", quoteIdentifier(quote, table), strings.Join(projection, ",\n\t")), nil
 }
 
 func (d sqlDatastoreDialect) Ping(manager Manager) error {
@@ -398,13 +401,13 @@ func (d sqlDatastoreDialect) DropDatastore(manager Manager, datastore string) er
 
 //DropTable drops a table in datastore managed by manager.
 func (d sqlDatastoreDialect) DropTable(manager Manager, datastore string, table string) error {
-	_, err := manager.Execute("DROP TABLE " + table)
+	_, err := manager.Execute("DROP TABLE " + quoteIdentifier(identifierQuoter(manager.Config()), table))
 	return err
 }
 
 //CreateTable creates table on in datastore managed by manager.
 func (d sqlDatastoreDialect) CreateTable(manager Manager, datastore string, table string, specification interface{}) error {
-	_, err := manager.Execute(fmt.Sprintf("CREATE TABLE %v(%v)", table, specification))
+	_, err := manager.Execute(fmt.Sprintf("CREATE TABLE %v(%v)", quoteIdentifier(identifierQuoter(manager.Config()), table), specification))
 	return err
 }
 
@@ -585,7 +588,7 @@ func NewSQLDatastoreDialect(tablesSQL, sequenceSQL, schemaSQL, allSchemaSQL, key
 }
 
 type mySQLDialect struct {
-	DatastoreDialect
+	*sqlDatastoreDialect
 }
 
 func (d mySQLDialect) CanPersistBatch() bool {
@@ -595,7 +598,9 @@ func (d mySQLDialect) CanPersistBatch() bool {
 func newMySQLDialect() mySQLDialect {
 	var result = mySQLDialect{}
 	sqlDialect := NewSQLDatastoreDialect(ansiTableListSQL, ansiSequenceSQL, defaultSchemaSQL, ansiSchemaListSQL, ansiPrimaryKeySQL, mysqlDisableForeignCheck, mysqlEnableForeignCheck, defaultAutoincremetSQL, ansiTableInfo, 0, result)
-	result.DatastoreDialect = sqlDialect
+	sqlDialect.sessionSettingSQL = mysqlSessionSettingSQL
+	sqlDialect.sessionSettings = mysqlSessionSettings
+	result.sqlDatastoreDialect = sqlDialect
 	sqlDialect.DatastoreDialect = result
 	return result
 }
@@ -788,7 +793,7 @@ func newCasandraDialect() *casandraSQLDialect {
 }
 
 type sqlLiteDialect struct {
-	DatastoreDialect
+	*sqlDatastoreDialect
 }
 
 //CreateDatastore create a new datastore (database/schema), it takes manager and target datastore
@@ -855,13 +860,15 @@ func (d sqlLiteDialect) GetKeyName(manager Manager, datastore, table string) str
 func newSQLLiteDialect() *sqlLiteDialect {
 	result := &sqlLiteDialect{}
 	sqlDialect := NewSQLDatastoreDialect(sqlLightTableSQL, sqlLightSequenceSQL, sqlLightSchemaSQL, sqlLightSchemaSQL, sqlLightPkSQL, "", "", "", ansiTableInfo, 2, result)
-	result.DatastoreDialect = sqlDialect
+	sqlDialect.sessionSettingSQL = sqlLiteSessionSettingSQL
+	sqlDialect.sessionSettings = sqlLiteSessionSettings
+	result.sqlDatastoreDialect = sqlDialect
 	sqlDialect.DatastoreDialect = result
 	return result
 }
 
 type pgDialect struct {
-	DatastoreDialect
+	*sqlDatastoreDialect
 }
 
 func (d pgDialect) CanPersistBatch() bool {
@@ -871,24 +878,15 @@ func (d pgDialect) CanPersistBatch() bool {
 func newPgDialect() *pgDialect {
 	result := &pgDialect{}
 	sqlDialect := NewSQLDatastoreDialect(pgTableListSQL, "", pgCurrentSchemaSQL, pgSchemaListSQL, pgPrimaryKeySQL, "", "", pgAutoincrementSQL, ansiTableInfo, 0, result)
-	result.DatastoreDialect = sqlDialect
+	sqlDialect.sessionSettingSQL = pgSessionSettingSQL
+	sqlDialect.sessionSettings = pgSessionSettings
+	result.sqlDatastoreDialect = sqlDialect
 	sqlDialect.DatastoreDialect = result
 	return result
 }
 
 func (d pgDialect) NormalizeSQL(SQL string) string {
-	count := 1
-	var normalizedSQL = ""
-	for _, r := range SQL {
-		aChar := string(r)
-		if aChar == "?" {
-			normalizedSQL += "$" + toolbox.AsString(count)
-			count++
-		} else {
-			normalizedSQL += aChar
-		}
-	}
-	return normalizedSQL
+	return RewritePlaceholders(SQL, d.Capabilities().Placeholder)
 }
 
 func (d pgDialect) IsAutoincrement(manager Manager, datastore, table string) bool {
@@ -927,7 +925,7 @@ func (d pgDialect) EnableForeignKeyCheck(manager Manager, connection Connection)
 }
 
 type oraDialect struct {
-	DatastoreDialect
+	*sqlDatastoreDialect
 }
 
 func (d oraDialect) CanPersistBatch() bool {
@@ -962,45 +960,25 @@ func (d oraDialect) DropDatastore(manager Manager, datastore string) error {
 }
 
 func (d oraDialect) NormalizeSQL(SQL string) string {
-	count := 1
-	var normalizedSQL = ""
-	for _, r := range SQL {
-		aChar := string(r)
-		if aChar == "?" {
-			normalizedSQL += ":" + toolbox.AsString(count)
-			count++
-		} else {
-			normalizedSQL += aChar
-		}
-	}
-	return normalizedSQL
+	return RewritePlaceholders(SQL, d.Capabilities().Placeholder)
 }
 
 func (d msSQLDialect) NormalizeSQL(SQL string) string {
-	count := 1
-	var normalizedSQL = ""
-	for _, r := range SQL {
-		aChar := string(r)
-		if aChar == "?" {
-			normalizedSQL += "@p" + toolbox.AsString(count)
-			count++
-		} else {
-			normalizedSQL += aChar
-		}
-	}
-	return normalizedSQL
+	return RewritePlaceholders(SQL, d.Capabilities().Placeholder)
 }
 
 func newOraDialect() *oraDialect {
 	result := &oraDialect{}
 	sqlDialect := NewSQLDatastoreDialect(oraTableSQL, "", oraSchemaSQL, oraSchemaListSQL, oraPrimaryKeySQL, "", "", "", ansiTableInfo, 0, result)
-	result.DatastoreDialect = sqlDialect
+	sqlDialect.sessionSettingSQL = oraSessionSettingSQL
+	sqlDialect.sessionSettings = oraSessionSettings
+	result.sqlDatastoreDialect = sqlDialect
 	sqlDialect.DatastoreDialect = result
 	return result
 }
 
 type verticaDialect struct {
-	DatastoreDialect
+	*sqlDatastoreDialect
 }
 
 //DropTable drops a datastore (database/schema), it takes manager and datastore to be droped
@@ -1050,13 +1028,13 @@ func (d *verticaDialect) BulkInsertType() string {
 func newVerticaDialect() *verticaDialect {
 	result := &verticaDialect{}
 	sqlDialect := NewSQLDatastoreDialect(verticaTableListSQL, "", verticaCurrentSchema, verticaSchemaSQL, "", "", "", "", verticaTableInfo, 0, result)
-	result.DatastoreDialect = sqlDialect
+	result.sqlDatastoreDialect = sqlDialect
 	sqlDialect.DatastoreDialect = result
 	return result
 }
 
 type odbcDialect struct {
-	DatastoreDialect
+	*sqlDatastoreDialect
 }
 
 //DropTable drops a datastore (database/schema), it takes manager and datastore to be droped
@@ -1104,31 +1082,33 @@ func (d *odbcDialect) BulkInsertType() string {
 func newOdbcDialect() *odbcDialect {
 	result := &odbcDialect{}
 	sqlDialect := NewSQLDatastoreDialect(verticaTableListSQL, "", verticaCurrentSchema, verticaSchemaSQL, "", "", "", "", verticaTableInfo, 0, result)
-	result.DatastoreDialect = sqlDialect
+	result.sqlDatastoreDialect = sqlDialect
 	sqlDialect.DatastoreDialect = result
 	return result
 }
 
 type ansiSQLDialect struct {
-	DatastoreDialect
+	*sqlDatastoreDialect
 }
 
 func newAnsiSQLDialect() *msSQLDialect {
 	result := &msSQLDialect{}
 	sqlDialect := NewSQLDatastoreDialect(ansiTableListSQL, ansiSequenceSQL, "", ansiSchemaListSQL, "", "", "", "", ansiTableInfo, 0, result)
-	result.DatastoreDialect = sqlDialect
+	result.sqlDatastoreDialect = sqlDialect
 	sqlDialect.DatastoreDialect = result
 	return result
 }
 
 type msSQLDialect struct {
-	DatastoreDialect
+	*sqlDatastoreDialect
 }
 
 func newMsSQLDialect() *msSQLDialect {
 	result := &msSQLDialect{}
 	sqlDialect := NewSQLDatastoreDialect(ansiTableListSQL, msSequenceSQL, msSchemaSQL, ansiSchemaListSQL, msSqlPrimaryKeySQL, "", "", "", ansiTableInfo, 0, result)
-	result.DatastoreDialect = sqlDialect
+	sqlDialect.sessionSettingSQL = msSessionSettingSQL
+	sqlDialect.sessionSettings = msSessionSettings
+	result.sqlDatastoreDialect = sqlDialect
 	sqlDialect.DatastoreDialect = result
 	return result
 }
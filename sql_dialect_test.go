@@ -69,3 +69,37 @@ func TestSqlDialect(t *testing.T) {
 		assert.True(t, mySQLDialect.CanPersistBatch())
 	}
 }
+
+func TestSqlDialect_Views(t *testing.T) {
+	config := dsc.NewConfig("sqlite3", "[url]", "url:./test/views.db")
+	manager, err := dsc.NewManagerFactory().Create(config)
+	if !assert.Nil(t, err) {
+		return
+	}
+	for _, SQL := range []string{
+		"DROP VIEW IF EXISTS active_accounts",
+		"DROP TABLE IF EXISTS accounts",
+		"CREATE TABLE accounts(id INTEGER PRIMARY KEY, name TEXT, active INTEGER)",
+		"CREATE VIEW active_accounts AS SELECT id, name FROM accounts WHERE active = 1",
+	} {
+		_, err = manager.Execute(SQL)
+		if !assert.Nil(t, err) {
+			return
+		}
+	}
+	dialect := dsc.GetDatastoreDialect("sqlite3")
+	datastore, err := dialect.GetCurrentDatastore(manager)
+	assert.Nil(t, err)
+	views, err := dialect.(dsc.ViewDialect).GetViews(manager, datastore)
+	if assert.Nil(t, err) && assert.Equal(t, 1, len(views)) {
+		assert.Equal(t, "active_accounts", views[0].Name)
+		assert.False(t, views[0].Materialized)
+	}
+	definition, err := dialect.(dsc.ViewDialect).GetViewDefinition(manager, datastore, "active_accounts")
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT id, name FROM accounts WHERE active = 1", definition)
+
+	_, err = dialect.(dsc.ViewDialect).GetViewDefinition(manager, datastore, "missing")
+	assert.NotNil(t, err)
+	assert.NotNil(t, dialect.(dsc.ViewDialect).RefreshMaterializedView(manager, "active_accounts"), "sqlite has no materialized views")
+}
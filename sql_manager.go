@@ -1,6 +1,7 @@
 package dsc
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"github.com/pkg/errors"
@@ -52,7 +53,7 @@ func (s *sqlScanner) ColumnTypes() ([]ColumnType, error) {
 }
 
 type sqlExecutor interface {
-	Exec(sql string, parameters ...interface{}) (sql.Result, error)
+	ExecContext(ctx context.Context, sql string, parameters ...interface{}) (sql.Result, error)
 }
 
 type sqlManager struct {
@@ -60,85 +61,192 @@ type sqlManager struct {
 }
 
 func (m *sqlManager) initConnectionIfNeeded(connection Connection) error {
+	if pinned, ok := connection.(*pinnedConnection); ok {
+		connection = pinned.Connection
+	}
+	if session, ok := connection.(*sessionConnection); ok {
+		connection = session.sqlConnection
+	}
 	if sqlConnection, ok := connection.(*sqlConnection); ok {
 		if sqlConnection.init {
 			return nil
 		}
 		sqlConnection.init = true
-		dialect := GetDatastoreDialect(m.config.DriverName)
+		dialect := GetDatastoreDialect(m.Config().DriverName)
 		return dialect.Init(m, connection)
 	}
 	return nil
 }
 
-func (m *sqlManager) ExecuteOnConnection(connection Connection, sql string, args []interface{}) (sql.Result, error) {
-	m.Acquire()
-	db, err := asSQLDb(connection.Unwrap(sqlDbPointer))
+// withConnectionProvider returns a copy of the manager using passed in connection provider
+func (m *sqlManager) withConnectionProvider(provider ConnectionProvider) Manager {
+	result := &sqlManager{}
+	var self Manager = result
+	result.AbstractManager = NewAbstractManager(m.Config(), provider, self)
+	return self
+}
+
+// unwrapConnection returns initialised connection sql.DB (or session pinned sql.Conn) and active transaction if any
+func (m *sqlManager) unwrapConnection(connection Connection) (sqlDatabase, *sql.Tx, error) {
+	var db sqlDatabase
+	sqlDb, err := asSQLDb(connection.Unwrap(sqlDbPointer))
+	db = sqlDb
+	if pinned, ok := connection.(*pinnedConnection); ok {
+		connection = pinned.Connection
+	}
+	if session, ok := connection.(*sessionConnection); ok {
+		db = session.conn
+	}
 	if err == nil {
 		err = m.initConnectionIfNeeded(connection)
 	}
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-
-	var executable sqlExecutor = db
 	tx, err := asSQLTx(connection.Unwrap(sqlTxtPointer))
+	if err != nil {
+		return nil, nil, err
+	}
+	return db, tx, nil
+}
+
+func (m *sqlManager) ExecuteOnConnection(connection Connection, sql string, args []interface{}) (sql.Result, error) {
+	if args == nil {
+		args = make([]interface{}, 0)
+	}
+	args, options := splitQueryOptions(m.Config(), args)
+	dialect := GetDatastoreDialect(m.Config().DriverName)
+	args = compositeParameters(dialect, args)
+	sql = dialect.NormalizeSQL(sql)
+	if result, ok := dryRun(m.Config(), sql, args); ok {
+		return result, nil
+	}
+	m.Acquire()
+	db, tx, err := m.unwrapConnection(connection)
 	if err != nil {
 		return nil, err
 	}
+	var executable sqlExecutor = db
 	if tx != nil {
 		executable = tx
 	}
-	if args == nil {
-		args = make([]interface{}, 0)
+	sql, ctx, cancel, err := prepareStatementTimeout(dialect, options, sql, tx)
+	if err != nil {
+		return nil, err
 	}
-
-	dialect := GetDatastoreDialect(m.config.DriverName)
-	sql = dialect.NormalizeSQL(sql)
-	result, err := executable.Exec(sql, args...)
+	defer cancel()
+	startTime := time.Now()
+	result, err := executable.ExecContext(ctx, sql, args...)
 	if !dialect.CanHandleTransaction() {
 		result = NewSQLResult(1, 0)
 	}
-	Logf("[%v]:%v %v", m.config.username, sql, args)
+	if m.Config().QueryLogger != nil {
+		var affected int64 = -1
+		if err == nil && result != nil {
+			if rows, rowsErr := result.RowsAffected(); rowsErr == nil {
+				affected = rows
+			}
+		}
+		logQuery(m.Config(), sql, args, startTime, affected, err)
+	}
+	m.recordOperation("execute", startTime, err)
+	Logf("[%v]:%v %v", m.Config().username, sql, args)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute %w: %v %v on %v", err.Error(), sql, args, m.Manager.Config().Parameters)
+		return nil, classifyError(dialect, fmt.Errorf("failed to execute %w: %v %v on %v", err, sql, args, m.Manager.Config().Parameters))
 	}
 	return result, err
 }
 
-func (m *sqlManager) ReadAllOnWithHandlerOnConnection(connection Connection, query string, args []interface{}, readingHandler func(scanner Scanner) (toContinue bool, err error)) error {
+func (m *sqlManager) ReadAllOnWithHandlerOnConnection(connection Connection, query string, args []interface{}, readingHandler func(scanner Scanner) (toContinue bool, err error)) (err error) {
 	m.Acquire()
 	startTime := time.Now()
-	db, err := asSQLDb(connection.Unwrap((*sql.DB)(nil)))
-	if err == nil {
-		err = m.initConnectionIfNeeded(connection)
-	}
+	var fetched int64
+	defer func() {
+		logQuery(m.Config(), query, args, startTime, fetched, err)
+		m.recordOperation("query", startTime, err)
+	}()
+	db, tx, err := m.unwrapConnection(connection)
 	if err != nil {
 		return err
 	}
 
-	dialect := GetDatastoreDialect(m.config.DriverName)
+	args, options := splitQueryOptions(m.Config(), args)
+	dialect := GetDatastoreDialect(m.Config().DriverName)
+	args = compositeParameters(dialect, args)
 	query = dialect.NormalizeSQL(query)
-	Logf("[%v]:%v", m.config.username, query)
+	if query, err = prepareRowLock(m.Config(), dialect, options, query, tx != nil); err != nil {
+		return err
+	}
+	query, ctx, cancel, err := prepareStatementTimeout(dialect, options, query, tx)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	Logf("[%v]:%v", m.Config().username, query)
 
-	sqlStatement, sqlError := db.Prepare(query)
+	var sqlStatement *sql.Stmt
+	var sqlError error
+	if tx != nil {
+		sqlStatement, sqlError = tx.PrepareContext(ctx, query)
+	} else {
+		sqlStatement, sqlError = db.PrepareContext(ctx, query)
+	}
 	if sqlError != nil {
-		return fmt.Errorf("failed to prepare sql: %v with %v due to:%v\n\t", query, args, sqlError.Error())
+		return classifyError(dialect, fmt.Errorf("failed to prepare sql: %v with %v due to:%w\n\t", query, args, sqlError))
 	}
 
-	Logf("[%v]:prepare time: %v\n", m.config.username, time.Now().Sub(startTime))
+	Logf("[%v]:prepare time: %v\n", m.Config().username, time.Now().Sub(startTime))
 
 	defer sqlStatement.Close()
-	rows, queryError := m.executeQuery(sqlStatement, query, args)
+	rows, queryError := m.executeQuery(ctx, sqlStatement, query, args)
 	if queryError != nil {
-		return fmt.Errorf(fmt.Sprintf("failed to execute sql: %v with %v due to:%v\n\t", query, args, queryError.Error()))
+		return classifyError(dialect, fmt.Errorf("failed to execute sql: %v with %v due to:%w\n\t", query, args, queryError))
 	}
-	Logf("[%v]:execute time: %v\n", m.config.username, time.Now().Sub(startTime))
+	Logf("[%v]:execute time: %v\n", m.Config().username, time.Now().Sub(startTime))
 
 	defer rows.Close()
 
+	var mapper *typeMapper
+	if len(m.Config().TypeMappings) > 0 {
+		columnTypes, err := (&sqlScanner{rows}).ColumnTypes()
+		if err != nil {
+			return fmt.Errorf("failed to get column types: %v due to %v", query, err)
+		}
+		if mapper, err = newTypeMapper(m.Config(), columnTypes); err != nil {
+			return err
+		}
+	}
+	var decrypting *decryptingScanner
+	if encryptors := columnEncryptors(m.TableDescriptorRegistry()); len(encryptors) > 0 {
+		columns, err := rows.Columns()
+		if err != nil {
+			return fmt.Errorf("failed to get columns: %v due to %v", query, err)
+		}
+		decrypting = newDecryptingScanner(m.Config(), query, columns, encryptors)
+	}
+	var nullPolicy *nullPolicyScanner
+	if columns, err := rows.Columns(); err == nil {
+		if nullPolicy, err = newNullPolicyScanner(m.Config(), columns, columnNullPolicies(m.TableDescriptorRegistry(), options.NullPolicies)); err != nil {
+			return err
+		}
+	}
+	composite := newCompositeTypesScanner()
 	for rows.Next() {
 		scanner, _ := asScanner(rows)
+		if nullPolicy != nil {
+			nullPolicy.Scanner = scanner
+			scanner = nullPolicy
+		}
+		composite.Scanner = scanner
+		scanner = composite
+		if decrypting != nil {
+			decrypting.Scanner = scanner
+			scanner = decrypting
+		}
+		if mapper != nil {
+			scanner = &typeMappingScanner{Scanner: scanner, mapper: mapper}
+		}
+		fetched++
 
 		toContinue, err := readingHandler(NewScanner(scanner))
 		if err != nil {
@@ -148,17 +256,97 @@ func (m *sqlManager) ReadAllOnWithHandlerOnConnection(connection Connection, que
 			break
 		}
 	}
-	Logf("[%v]:fetched time: %v\n", m.config.username, time.Now().Sub(startTime))
-	return rows.Err()
+	Logf("[%v]:fetched time: %v\n", m.Config().username, time.Now().Sub(startTime))
+	return classifyError(dialect, rows.Err())
 }
 
-func (m *sqlManager) executeQuery(sqlStatement *sql.Stmt, query string, args []interface{}) (rows *sql.Rows, err error) {
+func (m *sqlManager) executeQuery(ctx context.Context, sqlStatement *sql.Stmt, query string, args []interface{}) (rows *sql.Rows, err error) {
 	if args == nil {
 		args = make([]interface{}, 0)
 	}
-	rows, err = sqlStatement.Query(args...)
+	rows, err = sqlStatement.QueryContext(ctx, args...)
 	if err != nil {
 		return nil, err
 	}
 	return rows, nil
 }
+
+// asNativeSQL returns parametrized SQL for native query, native SQL is passed to the driver as is (without placeholder normalization)
+func asNativeSQL(query interface{}) (*ParametrizedSQL, error) {
+	switch actual := query.(type) {
+	case string:
+		return &ParametrizedSQL{SQL: actual}, nil
+	case *ParametrizedSQL:
+		return actual, nil
+	case ParametrizedSQL:
+		return &actual, nil
+	}
+	return nil, fmt.Errorf("unsupported native query type: %T, expected string or *ParametrizedSQL", query)
+}
+
+// ExecuteNativeOnConnection executes driver native SQL on passed in connection
+func (m *sqlManager) ExecuteNativeOnConnection(connection Connection, query interface{}) (sql.Result, error) {
+	native, err := asNativeSQL(query)
+	if err != nil {
+		return nil, err
+	}
+	if result, ok := dryRun(m.Config(), native.SQL, native.Values); ok {
+		return result, nil
+	}
+	m.Acquire()
+	db, tx, err := m.unwrapConnection(connection)
+	if err != nil {
+		return nil, err
+	}
+	var executable sqlExecutor = db
+	if tx != nil {
+		executable = tx
+	}
+	Logf("[%v]:%v %v", m.Config().username, native.SQL, native.Values)
+	startTime := time.Now()
+	result, err := executable.ExecContext(context.Background(), native.SQL, native.Values...)
+	m.recordOperation("executeNative", startTime, err)
+	if err != nil {
+		return nil, classifyError(GetDatastoreDialect(m.Config().DriverName), fmt.Errorf("failed to execute native sql: %v %v due to %w", native.SQL, native.Values, err))
+	}
+	return result, nil
+}
+
+// ReadAllNativeWithHandlerOnConnection reads data for driver native SQL on passed in connection
+func (m *sqlManager) ReadAllNativeWithHandlerOnConnection(connection Connection, query interface{}, readingHandler func(scanner Scanner) (toContinue bool, err error)) (err error) {
+	native, err := asNativeSQL(query)
+	if err != nil {
+		return err
+	}
+	m.Acquire()
+	startTime := time.Now()
+	defer func() {
+		m.recordOperation("queryNative", startTime, err)
+	}()
+	db, tx, err := m.unwrapConnection(connection)
+	if err != nil {
+		return err
+	}
+	Logf("[%v]:%v %v", m.Config().username, native.SQL, native.Values)
+	var rows *sql.Rows
+	if tx != nil {
+		rows, err = tx.Query(native.SQL, native.Values...)
+	} else {
+		rows, err = db.QueryContext(context.Background(), native.SQL, native.Values...)
+	}
+	if err != nil {
+		return classifyError(GetDatastoreDialect(m.Config().DriverName), fmt.Errorf("failed to execute native sql: %v with %v due to:%w", native.SQL, native.Values, err))
+	}
+	defer rows.Close()
+	for rows.Next() {
+		scanner, _ := asScanner(rows)
+		toContinue, err := readingHandler(NewScanner(scanner))
+		if err != nil {
+			return err
+		}
+		if !toContinue {
+			break
+		}
+	}
+	return rows.Err()
+}
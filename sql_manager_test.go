@@ -1,11 +1,15 @@
 package dsc_test
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"github.com/DATA-DOG/go-sqlmock"
 	_ "github.com/go-sql-driver/mysql"
 	_ "github.com/mattn/go-sqlite3"
 	"github.com/stretchr/testify/assert"
 	"github.com/viant/dsc"
+	"sync"
 	"testing"
 	"time"
 )
@@ -101,6 +105,71 @@ func TestConnection(t *testing.T) {
 	manager.ConnectionProvider().Close()
 }
 
+func TestExecuteAllWithOptions(t *testing.T) {
+	manager := GetManager(t)
+	setup := []string{
+		"DROP TABLE IF EXISTS statements",
+		"CREATE TABLE statements(id INTEGER PRIMARY KEY, name VARCHAR(255))",
+	}
+	_, err := manager.ExecuteAll(setup)
+	if !assert.Nil(t, err) {
+		return
+	}
+	statements := []string{
+		"INSERT INTO statements(id, name) VALUES(1, 'a')",
+		"INSERT INTO statements(id, name) VALUES(2, 'b')",
+		"UPDATE statements SET name = 'c'",
+		"INSERT INTO statements(id, name) VALUES(1, 'duplicate')",
+		"INSERT INTO statements(id, name) VALUES(3, 'd')",
+	}
+	{ //all statements are rolled back
+		result, err := manager.(dsc.OptionsExecutor).ExecuteAllWithOptions(statements, &dsc.ExecuteOptions{Transactional: true})
+		var statementErr *dsc.StatementError
+		if assert.True(t, errors.As(err, &statementErr)) {
+			assert.Equal(t, 3, statementErr.Index)
+		}
+		assert.Equal(t, 3, result.FailedIndex)
+		assert.EqualValues(t, []int64{1, 1, 2}, result.RowsAffected)
+		assert.Equal(t, 0, result.Committed)
+		assert.Equal(t, 0, countRows(t, manager, "statements"))
+	}
+	{ //batches before the failing one are committed
+		result, err := manager.(dsc.OptionsExecutor).ExecuteAllWithOptions(statements, &dsc.ExecuteOptions{BatchSize: 2})
+		assert.NotNil(t, err)
+		assert.Equal(t, 3, result.FailedIndex)
+		assert.Equal(t, 2, result.Committed)
+		assert.Equal(t, 2, countRows(t, manager, "statements"))
+	}
+	{
+		result, err := manager.(dsc.OptionsExecutor).ExecuteAllWithOptions(statements[4:], nil)
+		assert.Nil(t, err)
+		assert.Equal(t, -1, result.FailedIndex)
+		assert.Equal(t, 1, result.Committed)
+	}
+	//ExecuteAll rolls back on failure
+	_, err = manager.ExecuteAll([]string{"INSERT INTO statements(id, name) VALUES(10, 'x')", "SEL "})
+	assert.NotNil(t, err)
+	assert.Equal(t, 3, countRows(t, manager, "statements"))
+}
+
+func TestExecuteAllWithOptions_UnknownRowsAffected(t *testing.T) {
+	manager, mock, closer := newPostgresMock(t, "dsc_unknown_rows_affected")
+	if manager == nil {
+		return
+	}
+	defer closer()
+	mock.ExpectBegin()
+	mock.ExpectExec("CREATE TABLE orders").WillReturnResult(sqlmock.NewErrorResult(errors.New("rows affected not supported")))
+	mock.ExpectExec("INSERT INTO orders").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	result, err := manager.(dsc.OptionsExecutor).ExecuteAllWithOptions([]string{"CREATE TABLE orders(id INT)", "INSERT INTO orders(id) VALUES(1)"}, &dsc.ExecuteOptions{Transactional: true})
+	assert.Nil(t, err)
+	assert.EqualValues(t, []int64{-1, 1}, result.RowsAffected)
+	assert.Equal(t, -1, result.FailedIndex)
+	assert.Equal(t, 2, result.Committed)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
 func TestExecuteWithError(t *testing.T) {
 	manager := GetManager(t)
 	_, err := manager.Execute("SEL ", 1)
@@ -417,6 +486,46 @@ func TestDeleteAll(t *testing.T) {
 	assert.Equal(t, 2, deleted)
 }
 
+func TestDeleteAllWithOptions(t *testing.T) {
+	manager := GetManager(t)
+	users := []User{
+		{Id: 1, Username: "Sir Edi", Salary: 32432.3},
+		{Username: "Bogi", Active: true, Salary: 32432.3},
+		{Username: "Ann", Active: true, Salary: 1000},
+	}
+	_, _, err := manager.PersistAll(&users, "users", nil)
+	if !assert.Nil(t, err) {
+		return
+	}
+	count := func() int {
+		var record = make([]interface{}, 0)
+		_, err := manager.ReadSingle(&record, "SELECT COUNT(*) FROM users WHERE comments IS NULL OR comments <> 'deleted'", nil, nil)
+		assert.Nil(t, err)
+		return int(record[0].(int64))
+	}
+
+	deleted, err := manager.(dsc.OptionsDeleter).DeleteAllWithOptions(users[:2], "users", &dsc.DeleteOptions{ExpectedCount: 1})
+	assert.Equal(t, 0, deleted)
+	var mismatch *dsc.DeleteCountMismatchError
+	if assert.True(t, errors.As(err, &mismatch)) {
+		assert.Equal(t, 1, mismatch.Expected)
+		assert.Equal(t, 2, mismatch.Actual)
+	}
+	assert.Equal(t, 3, count(), "mismatched delete should be rolled back")
+
+	deleted, err = manager.(dsc.OptionsDeleter).DeleteAllWithOptions(users[2:], "users", &dsc.DeleteOptions{ExpectedCount: 1, SoftDeleteColumn: "comments", SoftDeleteValue: "deleted"})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, deleted)
+	assert.Equal(t, 2, count(), "soft deleted row should be marked")
+	var total = make([]interface{}, 0)
+	_, _ = manager.ReadSingle(&total, "SELECT COUNT(*) FROM users", nil, nil)
+	assert.EqualValues(t, 3, total[0], "soft deleted row should be kept")
+
+	deleted, err = manager.(dsc.OptionsDeleter).DeleteAllWithOptions(users[:2], "users", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, deleted)
+}
+
 func TestDeleteSingle(t *testing.T) {
 	manager := GetManager(t)
 	users := []User{
@@ -438,3 +547,240 @@ func TestDeleteSingle(t *testing.T) {
 	assert.Nil(t, err)
 	assert.True(t, deleted)
 }
+
+type OrderLine struct {
+	Quantity int `column:"quantity"`
+	OrderId  int `column:"order_id" primaryKey:"true"`
+	Line     int `column:"line" primaryKey:"true"`
+}
+
+func TestPersistAllWithCompositeKey(t *testing.T) {
+	manager := GetManager(t)
+	for _, SQL := range []string{
+		"DROP TABLE IF EXISTS order_lines",
+		"CREATE TABLE order_lines(order_id INTEGER, line INTEGER, quantity INTEGER, PRIMARY KEY(order_id, line))",
+		"INSERT INTO order_lines(order_id, line, quantity) VALUES(1, 23, 1)",
+	} {
+		_, err := manager.Execute(SQL)
+		if !assert.Nil(t, err) {
+			return
+		}
+	}
+	descriptor, err := dsc.NewTableDescriptor("order_lines", OrderLine{})
+	if assert.Nil(t, err) {
+		assert.EqualValues(t, []string{"order_id", "line"}, descriptor.PkColumns)
+		assert.EqualValues(t, []string{"quantity", "order_id", "line"}, descriptor.Columns)
+	}
+
+	//(12, 3) key must not be classified as updatable because of existing (1, 23) key
+	lines := []*OrderLine{{OrderId: 1, Line: 23, Quantity: 5}, {OrderId: 12, Line: 3, Quantity: 7}}
+	inserted, updated, err := manager.PersistAll(&lines, "order_lines", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, inserted)
+	assert.Equal(t, 1, updated)
+
+	var line = &OrderLine{}
+	success, err := manager.ReadSingle(line, "SELECT order_id, line, quantity FROM order_lines WHERE order_id = ? AND line = ?", []interface{}{1, 23}, nil)
+	if assert.Nil(t, err) && assert.True(t, success) {
+		assert.Equal(t, 5, line.Quantity)
+	}
+
+	deleted, err := manager.DeleteSingle(lines[1], "order_lines", nil)
+	assert.Nil(t, err)
+	assert.True(t, deleted)
+	var total = make([]interface{}, 0)
+	_, _ = manager.ReadSingle(&total, "SELECT COUNT(*) FROM order_lines", nil, nil)
+	assert.EqualValues(t, 1, total[0])
+}
+
+type OrderGroup struct {
+	Id    int    `column:"id" primaryKey:"true"`
+	Group string `column:"group"`
+}
+
+func TestPersistAllWithReservedIdentifiers(t *testing.T) {
+	manager := GetManager(t)
+	for _, SQL := range []string{
+		`DROP TABLE IF EXISTS "order"`,
+		`CREATE TABLE "order"(id INTEGER PRIMARY KEY, "group" TEXT)`,
+		`INSERT INTO "order"(id, "group") VALUES(1, 'a')`,
+	} {
+		_, err := manager.Execute(SQL)
+		if !assert.Nil(t, err) {
+			return
+		}
+	}
+	groups := []*OrderGroup{{Id: 1, Group: "b"}, {Id: 2, Group: "c"}}
+	inserted, updated, err := manager.PersistAll(&groups, "order", nil)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, 1, inserted)
+	assert.Equal(t, 1, updated)
+
+	var group = &OrderGroup{}
+	success, err := manager.ReadSingle(group, `SELECT id, "group" FROM "order" WHERE id = ?`, []interface{}{1}, nil)
+	if assert.Nil(t, err) && assert.True(t, success) {
+		assert.Equal(t, "b", group.Group)
+	}
+	deleted, err := manager.DeleteSingle(groups[1], "order", nil)
+	assert.Nil(t, err)
+	assert.True(t, deleted)
+}
+
+func TestNativeQuery(t *testing.T) {
+	manager := GetManager(t)
+	result, err := manager.(dsc.NativeManager).ExecuteNative(&dsc.ParametrizedSQL{SQL: "UPDATE users SET comments = ?1 WHERE id = ?2", Values: []interface{}{"native", 1}})
+	if !assert.Nil(t, err) {
+		return
+	}
+	affected, _ := result.RowsAffected()
+	assert.EqualValues(t, 1, affected)
+
+	var users = make([]User, 0)
+	err = manager.(dsc.NativeManager).ReadAllNative(&users, &dsc.ParametrizedSQL{SQL: "SELECT id, username, active, salary, comments, last_access_time FROM users WHERE comments = ?1", Values: []interface{}{"native"}}, nil)
+	if assert.Nil(t, err) && assert.Equal(t, 1, len(users)) {
+		assert.Equal(t, "Edi", users[0].Username)
+	}
+
+	_, err = manager.(dsc.NativeManager).ExecuteNative(map[string]interface{}{"aggregate": "users"})
+	assert.NotNil(t, err)
+}
+
+type testDocumentScanner struct {
+	dsc.Scanner
+	raw string
+}
+
+func (s *testDocumentScanner) Document() (map[string]interface{}, error) {
+	var result = make(map[string]interface{})
+	err := json.Unmarshal([]byte(s.raw), &result)
+	return result, err
+}
+
+func (s *testDocumentScanner) RawDocument() (json.RawMessage, error) {
+	return json.RawMessage(s.raw), nil
+}
+
+func TestReadAllDocuments(t *testing.T) {
+	manager := GetManager(t)
+	_, err := manager.Execute("INSERT INTO users(username, active) VALUES('Bob', 0)")
+	assert.Nil(t, err)
+	var documents = make([]map[string]interface{}, 0)
+	err = manager.(dsc.DocumentReader).ReadAllDocuments(&dsc.ParametrizedSQL{SQL: "SELECT id, username FROM users WHERE id IN (?1, ?2) ORDER BY id", Values: []interface{}{1, 2}}, func(document map[string]interface{}) (bool, error) {
+		documents = append(documents, document)
+		return true, nil
+	})
+	if assert.Nil(t, err) && assert.Equal(t, 2, len(documents)) {
+		assert.EqualValues(t, "Edi", documents[0]["username"])
+	}
+	var raw = make([]json.RawMessage, 0)
+	err = manager.(dsc.DocumentReader).ReadAllRawDocuments("SELECT id, username FROM users ORDER BY id", func(document json.RawMessage) (bool, error) {
+		raw = append(raw, document)
+		return false, nil
+	})
+	if assert.Nil(t, err) && assert.Equal(t, 1, len(raw), "handler should stop streaming") {
+		assert.Contains(t, string(raw[0]), `"username":"Edi"`)
+	}
+
+	scanner := &testDocumentScanner{raw: `{"name":{"first":"Bob"},"tags":["a"]}`}
+	//managers pass scanner wrapped with NewScanner to reading handlers
+	document, err := dsc.ScanDocument(dsc.NewScanner(scanner))
+	if assert.Nil(t, err) {
+		assert.EqualValues(t, map[string]interface{}{"first": "Bob"}, document["name"])
+	}
+	rawDocument, err := dsc.ScanRawDocument(dsc.NewScanner(scanner))
+	assert.Nil(t, err)
+	assert.Equal(t, scanner.raw, string(rawDocument))
+}
+
+func TestReadAllPooled(t *testing.T) {
+	manager := GetManager(t)
+	_, err := manager.Execute("INSERT INTO users(username, active, comments) VALUES('Bob', 0, 'new')")
+	assert.Nil(t, err)
+	allocated := 0
+	pool := &sync.Pool{New: func() interface{} {
+		allocated++
+		return &User{}
+	}}
+	read := func(SQL string) ([]string, []string, error) {
+		var names = make([]string, 0)
+		var comments = make([]string, 0)
+		err := manager.(dsc.PoolReader).ReadAllPooled(pool, SQL, nil, func(record interface{}, release func()) (bool, error) {
+			defer release()
+			user := record.(*User)
+			names = append(names, user.Username)
+			comments = append(comments, user.Comments)
+			return true, nil
+		})
+		return names, comments, err
+	}
+	names, comments, err := read("SELECT id, username, comments FROM users ORDER BY id")
+	if assert.Nil(t, err) {
+		assert.EqualValues(t, []string{"Edi", "Bob"}, names)
+		assert.EqualValues(t, []string{"no comments", "new"}, comments)
+	}
+	names, comments, err = read("SELECT id, username FROM users ORDER BY id")
+	if assert.Nil(t, err) {
+		assert.EqualValues(t, []string{"Edi", "Bob"}, names)
+		assert.EqualValues(t, []string{"", ""}, comments, "pooled record should be reset")
+	}
+	assert.True(t, allocated <= 4)
+
+	var stale func()
+	err = manager.(dsc.PoolReader).ReadAllPooled(pool, "SELECT id, username FROM users ORDER BY id", nil, func(record interface{}, release func()) (bool, error) {
+		if stale == nil {
+			stale = release
+			release()
+			return true, nil
+		}
+		defer release()
+		stale()
+		borrowed := pool.Get()
+		defer pool.Put(borrowed)
+		assert.True(t, borrowed != record, "stale release should not return record borrowed by later row")
+		return true, nil
+	})
+	assert.Nil(t, err)
+
+	err = manager.(dsc.PoolReader).ReadAllPooled(&sync.Pool{New: func() interface{} { return User{} }}, "SELECT id FROM users", nil, func(record interface{}, release func()) (bool, error) {
+		return true, nil
+	})
+	assert.NotNil(t, err)
+}
+
+func TestManager_Stats(t *testing.T) {
+	manager := GetManager(t)
+	_, err := manager.Execute("INSERT INTO users(username) VALUES('Bob')")
+	assert.Nil(t, err)
+	_, err = manager.Execute("INSERT INTO unknown_table(username) VALUES('Bob')")
+	assert.NotNil(t, err)
+	var users = make([]User, 0)
+	assert.Nil(t, manager.ReadAll(&users, "SELECT id, username FROM users", nil, nil))
+	_, _, err = manager.PersistAll(&users, "users", nil)
+	assert.Nil(t, err)
+
+	registry, ok := manager.(interface {
+		RegisterCacheStats(name string, provider func() dsc.CacheStats)
+	})
+	if assert.True(t, ok) {
+		registry.RegisterCacheStats("users", func() dsc.CacheStats {
+			return dsc.CacheStats{Hits: 3, Misses: 1}
+		})
+	}
+
+	stats := manager.(dsc.StatsProvider).Stats()
+	execute := stats.Operations["execute"]
+	assert.True(t, execute.Count >= 2)
+	assert.EqualValues(t, 1, execute.Errors)
+	assert.True(t, stats.Operations["query"].Count >= 1)
+	assert.EqualValues(t, 1, stats.Operations["persist"].Count)
+	assert.True(t, stats.Pool.Opened >= 1)
+	assert.Equal(t, 0, stats.Pool.InUse)
+	assert.EqualValues(t, 0.75, stats.Caches["users"].HitRate)
+
+	encoded, err := json.Marshal(stats)
+	if assert.Nil(t, err) {
+		assert.Contains(t, string(encoded), `"Operations"`)
+	}
+}
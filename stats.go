@@ -0,0 +1,195 @@
+package dsc
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// PoolStats represents connection pool snapshot
+type PoolStats struct {
+	//MaxPoolSize maximum number of pooled connections
+	MaxPoolSize int
+	//Open number of open connections (in use and idle)
+	Open int64
+	//InUse number of connections checked out from the provider
+	InUse int
+	//Idle number of connections waiting in the pool
+	Idle int
+	//Opened total number of created connections
+	Opened int64
+	//Closed total number of closed connections
+	Closed int64
+	//MaxOpen hard limit of open connections, 0 means unlimited
+	MaxOpen int
+	//Waiting number of Get calls currently waiting for connection
+	Waiting int
+	//Waits total number of Get calls that waited for pooled connection
+	Waits int64
+	//WaitDuration total time spent waiting for pooled connection
+	WaitDuration time.Duration
+	//Recycled total number of connections closed instead of being reused (config reload, veto, failed ping or release hook)
+	Recycled int64
+}
+
+// OperationStats represents manager operation counters
+type OperationStats struct {
+	Count    int64
+	Errors   int64
+	Duration time.Duration
+}
+
+// CacheStats represents cache counters
+type CacheStats struct {
+	Hits    int64
+	Misses  int64
+	HitRate float64
+}
+
+// Stats represents manager runtime snapshot, it can be published with expvar or encoded as JSON
+type Stats struct {
+	Timestamp  time.Time
+	Pool       PoolStats
+	Operations map[string]OperationStats
+	Caches     map[string]CacheStats `json:",omitempty"`
+}
+
+// poolCounters represents connection provider counters
+type poolCounters struct {
+	opened   int64
+	closed   int64
+	waits    int64
+	waitTime int64
+	recycled int64
+}
+
+func (c *poolCounters) wait(elapsed time.Duration) {
+	atomic.AddInt64(&c.waits, 1)
+	atomic.AddInt64(&c.waitTime, int64(elapsed))
+}
+
+// Stats returns connection pool snapshot
+func (cp *AbstractConnectionProvider) Stats() PoolStats {
+	config := cp.ConnectionProvider.Config()
+	result := PoolStats{
+		Idle:         len(cp.ConnectionProvider.ConnectionPool()),
+		InUse:        cp.inUse(),
+		Waiting:      cp.waiting(),
+		Opened:       atomic.LoadInt64(&cp.counters.opened),
+		Closed:       atomic.LoadInt64(&cp.counters.closed),
+		Waits:        atomic.LoadInt64(&cp.counters.waits),
+		WaitDuration: time.Duration(atomic.LoadInt64(&cp.counters.waitTime)),
+		Recycled:     atomic.LoadInt64(&cp.counters.recycled),
+	}
+	if config != nil {
+		result.MaxPoolSize = config.MaxPoolSize
+	}
+	result.MaxOpen = cp.maxOpen()
+	result.Open = result.Opened - result.Closed
+	return result
+}
+
+// countRecycled counts connection closed instead of being reused
+func (cp *AbstractConnectionProvider) countRecycled() {
+	atomic.AddInt64(&cp.counters.recycled, 1)
+}
+
+// newConnection creates a connection with provider in a new pool slot, it returns errPoolFull once poolMaxOpen limit was reached
+func (cp *AbstractConnectionProvider) newConnection() (Connection, error) {
+	if reserved, _ := cp.reserveSlot(false); !reserved {
+		return nil, errPoolFull
+	}
+	result, _, err := cp.openReserved()
+	return result, err
+}
+
+// createConnection creates a connection with provider and counts it
+func (cp *AbstractConnectionProvider) createConnection() (Connection, error) {
+	connection, err := cp.ConnectionProvider.NewConnection()
+	if err == nil {
+		atomic.AddInt64(&cp.counters.opened, 1)
+	}
+	return connection, err
+}
+
+// operationCounter represents atomic operation counters
+type operationCounter struct {
+	count    int64
+	errors   int64
+	duration int64
+}
+
+// managerStats represents manager operation and cache counters
+type managerStats struct {
+	mutex      *sync.RWMutex
+	operations map[string]*operationCounter
+	caches     map[string]func() CacheStats
+}
+
+func newManagerStats() *managerStats {
+	return &managerStats{mutex: &sync.RWMutex{}, operations: make(map[string]*operationCounter), caches: make(map[string]func() CacheStats)}
+}
+
+func (s *managerStats) counter(name string) *operationCounter {
+	s.mutex.RLock()
+	counter, ok := s.operations[name]
+	s.mutex.RUnlock()
+	if ok {
+		return counter
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if counter, ok = s.operations[name]; !ok {
+		counter = &operationCounter{}
+		s.operations[name] = counter
+	}
+	return counter
+}
+
+// recordOperation counts operation with its duration and error
+func (m *AbstractManager) recordOperation(name string, started time.Time, err error) {
+	counter := m.stats.counter(name)
+	atomic.AddInt64(&counter.count, 1)
+	atomic.AddInt64(&counter.duration, int64(time.Now().Sub(started)))
+	if err != nil {
+		atomic.AddInt64(&counter.errors, 1)
+	}
+}
+
+// RegisterCacheStats registers named cache counters provider reported with Stats
+func (m *AbstractManager) RegisterCacheStats(name string, provider func() CacheStats) {
+	m.stats.mutex.Lock()
+	defer m.stats.mutex.Unlock()
+	m.stats.caches[name] = provider
+}
+
+// Stats returns manager runtime snapshot: connection pool, per operation counters (execute, query, persist, delete) and registered cache counters
+func (m *AbstractManager) Stats() Stats {
+	result := Stats{
+		Timestamp:  time.Now(),
+		Operations: make(map[string]OperationStats),
+	}
+	if provider, ok := m.Manager.ConnectionProvider().(PoolStatsProvider); ok {
+		result.Pool = provider.Stats()
+	}
+	m.stats.mutex.RLock()
+	defer m.stats.mutex.RUnlock()
+	for name, counter := range m.stats.operations {
+		result.Operations[name] = OperationStats{
+			Count:    atomic.LoadInt64(&counter.count),
+			Errors:   atomic.LoadInt64(&counter.errors),
+			Duration: time.Duration(atomic.LoadInt64(&counter.duration)),
+		}
+	}
+	if len(m.stats.caches) > 0 {
+		result.Caches = make(map[string]CacheStats)
+		for name, provider := range m.stats.caches {
+			stats := provider()
+			if total := stats.Hits + stats.Misses; total > 0 {
+				stats.HitRate = float64(stats.Hits) / float64(total)
+			}
+			result.Caches[name] = stats
+		}
+	}
+	return result
+}
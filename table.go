@@ -15,6 +15,16 @@ type TableDescriptor struct {
 	SchemaURL      string                   //url with JSON to the TableDescriptor.Schema.
 	FromQuery      string                   //If table is query base then specify FromQuery
 	FromQueryAlias string
+	Encryptors     map[string]Encryptor `json:"-"` //Encryptors column encryptors, encrypted values are stored with key ID alongside ciphertext
+	Audit          *AuditColumns            //Audit columns populated by DmlBuilder on insert and update
+	KeyGenerator   KeyGenerator `json:"-"`   //KeyGenerator generates single column primary key for rows with zero valued key when table does not use autoincrement
+	Indexes        []*TableIndex            //Indexes table indexes discovered from datastore metadata, see TableMetadata
+	ForeignKeys    []*ForeignKey            //ForeignKeys table foreign keys discovered from datastore metadata, see TableMetadata
+	ShardColumn    string                   //ShardColumn column holding shard key used by ShardedManager routing
+	ShardKey       ShardKeyExtractor `json:"-"` //ShardKey extracts shard key from record, it takes precedence over ShardColumn
+	NullPolicies      map[string]string        //NullPolicies column NULL mapping policies (native, zero or error), they take precedence over nullPolicy config parameter
+	ZeroValuePolicy   string                   //ZeroValuePolicy table zero value writing policy (value, null or omit), it takes precedence over zeroValuePolicy config parameter
+	ZeroValuePolicies map[string]string        //ZeroValuePolicies column zero value writing policies, they take precedence over ZeroValuePolicy
 }
 
 func (t *TableDescriptor) From() string {
@@ -3,6 +3,7 @@ package dsc
 import (
 	"fmt"
 	"reflect"
+	"sort"
 	"sync"
 
 	"github.com/viant/toolbox"
@@ -100,15 +101,16 @@ func (d *TableDescriptor) HasSchema() bool {
 	return len(d.SchemaURL) > 0 || d.Schema != nil
 }
 
-//NewTableDescriptor creates a new table descriptor for passed in instance, it can use the following tags:"column", "dateLayout","dateFormat", "autoincrement", "primaryKey", "sequence", "transient"
+//NewTableDescriptor creates a new table descriptor for passed in instance, it can use the following tags:"column", "dateLayout","dateFormat", "autoincrement", "primaryKey", "sequence", "transient", "encryptor", "audit" (createdAt, createdBy, updatedAt, updatedBy), "keyGenerator", "nullPolicy" (native, zero, error), "zeroValue" (value, null, omit)
 func NewTableDescriptor(table string, instance interface{}) (*TableDescriptor, error) {
 	targetType := toolbox.DiscoverTypeByKind(instance, reflect.Struct)
 	var autoincrement bool
 	var pkColumns = make([]string, 0)
 	var columns = make([]string, 0)
 	columnToFieldMap := toolbox.NewFieldSettingByKey(targetType, "column")
+	keys := columnMappingKeys(targetType, columnToFieldMap)
 
-	for key := range columnToFieldMap {
+	for _, key := range keys {
 		mapping, _ := columnToFieldMap[key]
 		column, ok := mapping["column"]
 		if !ok {
@@ -121,7 +123,7 @@ func NewTableDescriptor(table string, instance interface{}) (*TableDescriptor, e
 		}
 	}
 
-	for key := range columnToFieldMap {
+	for _, key := range keys {
 		mapping, _ := columnToFieldMap[key]
 		column, ok := mapping["column"]
 		if !ok {
@@ -143,10 +145,81 @@ func NewTableDescriptor(table string, instance interface{}) (*TableDescriptor, e
 		}
 	}
 
+	encryptors, err := newColumnEncryptors(targetType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %v table descriptor due to %v", table, err)
+	}
+	audit, err := newAuditColumns(targetType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %v table descriptor due to %v", table, err)
+	}
+	keyGenerator, err := newKeyGenerator(targetType, pkColumns)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %v table descriptor due to %v", table, err)
+	}
+	nullPolicies, zeroValuePolicies, err := newColumnNullPolicies(targetType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %v table descriptor due to %v", table, err)
+	}
 	return &TableDescriptor{
-		Table:         table,
-		Autoincrement: autoincrement,
-		Columns:       columns,
-		PkColumns:     pkColumns,
+		Table:             table,
+		Autoincrement:     autoincrement,
+		Columns:           columns,
+		PkColumns:         pkColumns,
+		Encryptors:        encryptors,
+		Audit:             audit,
+		KeyGenerator:      keyGenerator,
+		NullPolicies:      nullPolicies,
+		ZeroValuePolicies: zeroValuePolicies,
 	}, nil
 }
+
+//columnMappingKeys returns column mapping keys in struct fields declaration order, so that composite primary key columns order is stable
+func columnMappingKeys(targetType reflect.Type, columnToFieldMap map[string](map[string]string)) []string {
+	var keys = toolbox.MapKeysToStringSlice(columnToFieldMap)
+	sort.Strings(keys)
+	var indexes = make(map[string][]int)
+	for _, key := range keys {
+		if field, ok := targetType.FieldByName(columnToFieldMap[key]["fieldName"]); ok {
+			indexes[key] = field.Index
+		}
+	}
+	sort.SliceStable(keys, func(i, j int) bool {
+		left, right := indexes[keys[i]], indexes[keys[j]]
+		if left == nil || right == nil {
+			return right == nil && left != nil
+		}
+		for k := 0; k < len(left) && k < len(right); k++ {
+			if left[k] != right[k] {
+				return left[k] < right[k]
+			}
+		}
+		return len(left) < len(right)
+	})
+	return keys
+}
+
+//newColumnEncryptors returns encryptors registered with RegisterEncryptor for fields with `encryptor` tag
+func newColumnEncryptors(targetType reflect.Type) (map[string]Encryptor, error) {
+	var result map[string]Encryptor
+	mapping := toolbox.BuildTagMapping(targetType, "column", "transient", true, true, []string{"column", "encryptor"})
+	for _, fieldMapping := range mapping {
+		name, ok := fieldMapping["encryptor"]
+		if !ok {
+			continue
+		}
+		column, ok := fieldMapping["column"]
+		if !ok {
+			column = fieldMapping["fieldName"]
+		}
+		encryptor := GetEncryptor(name)
+		if encryptor == nil {
+			return nil, fmt.Errorf("encryptor %v of column %v was not registered", name, column)
+		}
+		if result == nil {
+			result = make(map[string]Encryptor)
+		}
+		result[column] = encryptor
+	}
+	return result, nil
+}
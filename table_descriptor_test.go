@@ -36,3 +36,47 @@ func TestTableDescriptorRegistry(t *testing.T) {
 	assert.True(t, registry.Has("users"))
 	assert.Equal(t, []string{"users"}, registry.Tables())
 }
+
+func TestTableDescriptorRegistry_IndexesAndForeignKeys(t *testing.T) {
+	config := dsc.NewConfig("sqlite3", "[url]", "url:./test/metadata.db")
+	manager, err := dsc.NewManagerFactory().Create(config)
+	if !assert.Nil(t, err) {
+		return
+	}
+	for _, SQL := range []string{
+		"DROP TABLE IF EXISTS line_items",
+		"DROP TABLE IF EXISTS invoices",
+		"CREATE TABLE invoices(id INTEGER PRIMARY KEY, number TEXT, customer TEXT)",
+		"CREATE UNIQUE INDEX invoices_number ON invoices(number)",
+		"CREATE INDEX invoices_customer ON invoices(customer, number)",
+		"CREATE TABLE line_items(id INTEGER PRIMARY KEY, invoice_id INTEGER REFERENCES invoices ON DELETE CASCADE, sku TEXT)",
+	} {
+		_, err = manager.Execute(SQL)
+		if !assert.Nil(t, err) {
+			return
+		}
+	}
+	assert.Nil(t, manager.TableDescriptorRegistry().Get("invoices").Indexes, "indexes should not be read by registry")
+	descriptor, err := dsc.TableMetadata(manager, "invoices")
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.True(t, descriptor == manager.TableDescriptorRegistry().Get("invoices"), "metadata should be registered")
+	if assert.Equal(t, 2, len(descriptor.Indexes)) {
+		assert.Equal(t, &dsc.TableIndex{Name: "invoices_number", Columns: []string{"number"}, Unique: true}, descriptor.Indexes[0])
+		assert.Equal(t, &dsc.TableIndex{Name: "invoices_customer", Columns: []string{"customer", "number"}}, descriptor.Indexes[1])
+	}
+	assert.Equal(t, 0, len(descriptor.ForeignKeys))
+
+	descriptor, err = dsc.TableMetadata(manager, "line_items")
+	if !assert.Nil(t, err) {
+		return
+	}
+	if assert.Equal(t, 1, len(descriptor.ForeignKeys)) {
+		foreignKey := descriptor.ForeignKeys[0]
+		assert.Equal(t, "invoices", foreignKey.ReferencedTable)
+		assert.Equal(t, []string{"invoice_id"}, foreignKey.Columns)
+		assert.Equal(t, []string{"id"}, foreignKey.ReferencedColumns, "omitted referenced column should resolve to primary key")
+		assert.Equal(t, "CASCADE", foreignKey.OnDelete)
+	}
+}
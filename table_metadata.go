@@ -0,0 +1,248 @@
+package dsc
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/viant/toolbox"
+)
+
+// TableIndex represents table index
+type TableIndex struct {
+	Name string
+	//Columns index columns in index order
+	Columns []string
+	Unique  bool
+	//Primary true for primary key index
+	Primary bool `json:",omitempty"`
+}
+
+// ForeignKey represents table foreign key
+type ForeignKey struct {
+	Name string
+	//Columns referencing columns
+	Columns []string
+	//ReferencedTable referenced (parent) table
+	ReferencedTable string
+	//ReferencedColumns referenced columns matching Columns order
+	ReferencedColumns []string
+	//OnDelete referential action on parent row delete: NO ACTION, RESTRICT, CASCADE, SET NULL, SET DEFAULT
+	OnDelete string `json:",omitempty"`
+	//OnUpdate referential action on parent key update
+	OnUpdate string `json:",omitempty"`
+}
+
+const (
+	mySQLIndexSQL = "SELECT INDEX_NAME AS index_name, COLUMN_NAME AS column_name, NON_UNIQUE = 0 AS is_unique, INDEX_NAME = 'PRIMARY' AS is_primary " +
+		"FROM information_schema.STATISTICS WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? ORDER BY INDEX_NAME, SEQ_IN_INDEX"
+	mySQLForeignKeySQL = "SELECT k.CONSTRAINT_NAME AS name, k.COLUMN_NAME AS column_name, k.REFERENCED_TABLE_NAME AS referenced_table, k.REFERENCED_COLUMN_NAME AS referenced_column, " +
+		"r.DELETE_RULE AS on_delete, r.UPDATE_RULE AS on_update FROM information_schema.KEY_COLUMN_USAGE k " +
+		"JOIN information_schema.REFERENTIAL_CONSTRAINTS r ON r.CONSTRAINT_SCHEMA = k.CONSTRAINT_SCHEMA AND r.CONSTRAINT_NAME = k.CONSTRAINT_NAME AND r.TABLE_NAME = k.TABLE_NAME " +
+		"WHERE k.TABLE_SCHEMA = ? AND k.TABLE_NAME = ? AND k.REFERENCED_TABLE_NAME IS NOT NULL ORDER BY k.CONSTRAINT_NAME, k.ORDINAL_POSITION"
+
+	pgIndexSQL = "SELECT i.relname AS index_name, a.attname AS column_name, ix.indisunique AS is_unique, ix.indisprimary AS is_primary " +
+		"FROM pg_index ix JOIN pg_class i ON i.oid = ix.indexrelid " +
+		"JOIN LATERAL unnest(ix.indkey) WITH ORDINALITY AS k(attnum, position) ON true " +
+		"JOIN pg_attribute a ON a.attrelid = ix.indrelid AND a.attnum = k.attnum " +
+		"WHERE ix.indrelid = CAST(? AS regclass) ORDER BY i.relname, k.position"
+	pgForeignKeySQL = "SELECT c.conname AS name, a.attname AS column_name, r.relname AS referenced_table, ra.attname AS referenced_column, " +
+		"c.confdeltype AS on_delete, c.confupdtype AS on_update FROM pg_constraint c " +
+		"JOIN LATERAL unnest(c.conkey, c.confkey) WITH ORDINALITY AS k(attnum, referenced_attnum, position) ON true " +
+		"JOIN pg_attribute a ON a.attrelid = c.conrelid AND a.attnum = k.attnum " +
+		"JOIN pg_class r ON r.oid = c.confrelid " +
+		"JOIN pg_attribute ra ON ra.attrelid = c.confrelid AND ra.attnum = k.referenced_attnum " +
+		"WHERE c.contype = 'f' AND c.conrelid = CAST(? AS regclass) ORDER BY c.conname, k.position"
+
+	sqlLiteIndexListSQL      = "PRAGMA index_list(%v)"
+	sqlLiteIndexInfoSQL      = "PRAGMA index_info(%v)"
+	sqlLiteForeignKeyListSQL = "PRAGMA foreign_key_list(%v)"
+)
+
+// pgReferentialActions maps pg_constraint action codes to referential actions
+var pgReferentialActions = map[string]string{"a": "NO ACTION", "r": "RESTRICT", "c": "CASCADE", "n": "SET NULL", "d": "SET DEFAULT"}
+
+// GetIndexes returns no indexes, default dialect does not provide index metadata
+func (d DefaultDialect) GetIndexes(manager Manager, datastore, table string) ([]*TableIndex, error) {
+	return []*TableIndex{}, nil
+}
+
+// GetForeignKeys returns no foreign keys, default dialect does not provide foreign key metadata
+func (d DefaultDialect) GetForeignKeys(manager Manager, datastore, table string) ([]*ForeignKey, error) {
+	return []*ForeignKey{}, nil
+}
+
+// GetIndexes returns no indexes, dialect does not know how to read index metadata
+func (d sqlDatastoreDialect) GetIndexes(manager Manager, datastore, table string) ([]*TableIndex, error) {
+	return []*TableIndex{}, nil
+}
+
+// GetForeignKeys returns no foreign keys, dialect does not know how to read foreign key metadata
+func (d sqlDatastoreDialect) GetForeignKeys(manager Manager, datastore, table string) ([]*ForeignKey, error) {
+	return []*ForeignKey{}, nil
+}
+
+// TableMetadata returns table descriptor (see TableDescriptorRegistry.Get) with indexes and foreign keys read from datastore metadata,
+// metadata is only read by this function, loaded descriptor is registered so that the next call does not read it again
+func TableMetadata(manager Manager, table string) (*TableDescriptor, error) {
+	descriptor := manager.TableDescriptorRegistry().Get(table)
+	if descriptor.Indexes != nil && descriptor.ForeignKeys != nil {
+		return descriptor, nil
+	}
+	datastoreDialect := GetDatastoreDialect(manager.Config().DriverName)
+	dialect, ok := datastoreDialect.(IndexDialect)
+	if !ok {
+		return nil, fmt.Errorf("failed to get %v metadata due to %v", table, errUnsupportedOperation)
+	}
+	datastore, err := datastoreDialect.GetCurrentDatastore(manager)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %v metadata due to %v", table, err)
+	}
+	var result = *descriptor
+	if result.Indexes, err = dialect.GetIndexes(manager, datastore, table); err != nil {
+		return nil, err
+	}
+	if result.ForeignKeys, err = dialect.GetForeignKeys(manager, datastore, table); err != nil {
+		return nil, err
+	}
+	if err = manager.TableDescriptorRegistry().Register(&result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// readIndexes returns indexes built from index_name, column_name, is_unique, is_primary rows ordered by index and column position
+func readIndexes(manager Manager, table, SQL string, parameters ...interface{}) ([]*TableIndex, error) {
+	var records = make([]map[string]interface{}, 0)
+	if err := manager.ReadAll(&records, SQL, parameters, nil); err != nil {
+		return nil, fmt.Errorf("failed to get %v indexes due to %v", table, err)
+	}
+	var result = make([]*TableIndex, 0)
+	var index *TableIndex
+	for _, record := range records {
+		name := toolbox.AsString(record["index_name"])
+		if index == nil || index.Name != name {
+			index = &TableIndex{Name: name, Columns: []string{}, Unique: toolbox.AsBoolean(record["is_unique"]), Primary: toolbox.AsBoolean(record["is_primary"])}
+			result = append(result, index)
+		}
+		index.Columns = append(index.Columns, toolbox.AsString(record["column_name"]))
+	}
+	return result, nil
+}
+
+// readForeignKeys returns foreign keys built from name, column_name, referenced_table, referenced_column, on_delete, on_update rows ordered by constraint and column position
+func readForeignKeys(manager Manager, table, SQL string, actions map[string]string, parameters ...interface{}) ([]*ForeignKey, error) {
+	var records = make([]map[string]interface{}, 0)
+	if err := manager.ReadAll(&records, SQL, parameters, nil); err != nil {
+		return nil, fmt.Errorf("failed to get %v foreign keys due to %v", table, err)
+	}
+	var action = func(value interface{}) string {
+		text := toolbox.AsString(value)
+		if mapped, ok := actions[text]; ok {
+			return mapped
+		}
+		return strings.ToUpper(text)
+	}
+	var result = make([]*ForeignKey, 0)
+	var foreignKey *ForeignKey
+	for _, record := range records {
+		name := toolbox.AsString(record["name"])
+		if foreignKey == nil || foreignKey.Name != name {
+			foreignKey = &ForeignKey{
+				Name:              name,
+				Columns:           []string{},
+				ReferencedTable:   toolbox.AsString(record["referenced_table"]),
+				ReferencedColumns: []string{},
+				OnDelete:          action(record["on_delete"]),
+				OnUpdate:          action(record["on_update"]),
+			}
+			result = append(result, foreignKey)
+		}
+		foreignKey.Columns = append(foreignKey.Columns, toolbox.AsString(record["column_name"]))
+		foreignKey.ReferencedColumns = append(foreignKey.ReferencedColumns, toolbox.AsString(record["referenced_column"]))
+	}
+	return result, nil
+}
+
+// GetIndexes returns mysql table indexes
+func (d mySQLDialect) GetIndexes(manager Manager, datastore, table string) ([]*TableIndex, error) {
+	return readIndexes(manager, table, mySQLIndexSQL, datastore, table)
+}
+
+// GetForeignKeys returns mysql table foreign keys
+func (d mySQLDialect) GetForeignKeys(manager Manager, datastore, table string) ([]*ForeignKey, error) {
+	return readForeignKeys(manager, table, mySQLForeignKeySQL, nil, datastore, table)
+}
+
+// GetIndexes returns postgres table indexes, expression index columns are skipped
+func (d pgDialect) GetIndexes(manager Manager, datastore, table string) ([]*TableIndex, error) {
+	return readIndexes(manager, table, pgIndexSQL, table)
+}
+
+// GetForeignKeys returns postgres table foreign keys
+func (d pgDialect) GetForeignKeys(manager Manager, datastore, table string) ([]*ForeignKey, error) {
+	return readForeignKeys(manager, table, pgForeignKeySQL, pgReferentialActions, table)
+}
+
+// GetIndexes returns sqlite table indexes, INTEGER PRIMARY KEY is a rowid alias without index
+func (d sqlLiteDialect) GetIndexes(manager Manager, datastore, table string) ([]*TableIndex, error) {
+	var indexes = make([]map[string]interface{}, 0)
+	if err := manager.ReadAll(&indexes, fmt.Sprintf(sqlLiteIndexListSQL, table), []interface{}{}, nil); err != nil {
+		return nil, fmt.Errorf("failed to get %v indexes due to %v", table, err)
+	}
+	var result = make([]*TableIndex, 0)
+	for i := len(indexes) - 1; i >= 0; i-- {
+		record := indexes[i]
+		index := &TableIndex{
+			Name:    toolbox.AsString(record["name"]),
+			Columns: []string{},
+			Unique:  toolbox.AsString(record["unique"]) == "1",
+			Primary: toolbox.AsString(record["origin"]) == "pk",
+		}
+		var columns = make([]map[string]interface{}, 0)
+		if err := manager.ReadAll(&columns, fmt.Sprintf(sqlLiteIndexInfoSQL, index.Name), []interface{}{}, nil); err != nil {
+			return nil, fmt.Errorf("failed to get %v index %v columns due to %v", table, index.Name, err)
+		}
+		for _, column := range columns {
+			index.Columns = append(index.Columns, toolbox.AsString(column["name"]))
+		}
+		result = append(result, index)
+	}
+	return result, nil
+}
+
+// GetForeignKeys returns sqlite table foreign keys, sqlite foreign keys are unnamed, so name is derived from table and key id,
+// omitted referenced columns are resolved to referenced table primary key
+func (d sqlLiteDialect) GetForeignKeys(manager Manager, datastore, table string) ([]*ForeignKey, error) {
+	var records = make([]map[string]interface{}, 0)
+	if err := manager.ReadAll(&records, fmt.Sprintf(sqlLiteForeignKeyListSQL, table), []interface{}{}, nil); err != nil {
+		return nil, fmt.Errorf("failed to get %v foreign keys due to %v", table, err)
+	}
+	var result = make([]*ForeignKey, 0)
+	var foreignKey *ForeignKey
+	for _, record := range records {
+		name := fmt.Sprintf("fk_%v_%v", table, toolbox.AsString(record["id"]))
+		if foreignKey == nil || foreignKey.Name != name {
+			foreignKey = &ForeignKey{
+				Name:              name,
+				Columns:           []string{},
+				ReferencedTable:   toolbox.AsString(record["table"]),
+				ReferencedColumns: []string{},
+				OnDelete:          toolbox.AsString(record["on_delete"]),
+				OnUpdate:          toolbox.AsString(record["on_update"]),
+			}
+			result = append(result, foreignKey)
+		}
+		foreignKey.Columns = append(foreignKey.Columns, toolbox.AsString(record["from"]))
+		if record["to"] != nil {
+			foreignKey.ReferencedColumns = append(foreignKey.ReferencedColumns, toolbox.AsString(record["to"]))
+		}
+	}
+	for _, foreignKey := range result {
+		if len(foreignKey.ReferencedColumns) == 0 {
+			if key := d.GetKeyName(manager, datastore, foreignKey.ReferencedTable); key != "" {
+				foreignKey.ReferencedColumns = strings.Split(key, ",")
+			}
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,4 @@
+-- script_items seed
+DELETE FROM script_items;
+INSERT INTO script_items VALUES(10, 'x');
+INSERT INTO script_items VALUES(11, 'y');
@@ -0,0 +1,194 @@
+package dsc
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+)
+
+// TLSConfig represents datastore TLS/mTLS options, dialect translates them into driver specific DSN parameters or registered tls.Config
+type TLSConfig struct {
+	//CAFile PEM encoded certificate authority file used to verify server certificate
+	CAFile string
+	//CertFile PEM encoded client certificate file (mTLS)
+	CertFile string
+	//KeyFile PEM encoded client key file (mTLS)
+	KeyFile string
+	//ServerName overrides server name used for certificate verification
+	ServerName string
+	//InsecureSkipVerify disables server certificate verification
+	InsecureSkipVerify bool
+}
+
+// HasClientCertificate returns true if client certificate and key were specified
+func (c *TLSConfig) HasClientCertificate() bool {
+	return c.CertFile != "" && c.KeyFile != ""
+}
+
+// ClientConfig returns tls.Config for these options
+func (c *TLSConfig) ClientConfig() (*tls.Config, error) {
+	result := &tls.Config{
+		ServerName:         c.ServerName,
+		InsecureSkipVerify: c.InsecureSkipVerify,
+	}
+	if c.CAFile != "" {
+		pem, err := os.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file %v due to %v", c.CAFile, err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("failed to parse CA file %v", c.CAFile)
+		}
+		result.RootCAs = pool
+	}
+	if c.CertFile != "" || c.KeyFile != "" {
+		if !c.HasClientCertificate() {
+			return nil, fmt.Errorf("both client certificate and key files are required")
+		}
+		certificate, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate %v due to %v", c.CertFile, err)
+		}
+		result.Certificates = []tls.Certificate{certificate}
+	}
+	return result, nil
+}
+
+// key returns TLS options identity used as registered tls.Config name
+func (c *TLSConfig) key() string {
+	hash := sha256.Sum256([]byte(fmt.Sprintf("%v|%v|%v|%v|%v", c.CAFile, c.CertFile, c.KeyFile, c.ServerName, c.InsecureSkipVerify)))
+	return "dsc_" + hex.EncodeToString(hash[:8])
+}
+
+// addDsnParameters adds parameters to URL (scheme://...?k=v), or key value (k=v k=v or k=v;k=v) style DSN
+func addDsnParameters(dsn string, separator string, parameters [][2]string) string {
+	if strings.Contains(dsn, "://") {
+		for _, parameter := range parameters {
+			if strings.Contains(dsn, "?") {
+				dsn += "&"
+			} else {
+				dsn += "?"
+			}
+			dsn += parameter[0] + "=" + url.QueryEscape(parameter[1])
+		}
+		return dsn
+	}
+	for _, parameter := range parameters {
+		if dsn != "" && !strings.HasSuffix(dsn, separator) {
+			dsn += separator
+		}
+		dsn += parameter[0] + "=" + parameter[1]
+	}
+	return dsn
+}
+
+// TLSConfigRegistrar registers named tls.Config with a driver, so that DSN can refer to it by name
+type TLSConfigRegistrar func(name string, config *tls.Config) error
+
+var tlsConfigRegistrars = make(map[string]TLSConfigRegistrar)
+var tlsConfigRegistrarsMutex = &sync.RWMutex{}
+
+// RegisterTLSConfigRegistrar registers driver tls.Config registrar, i.e. mysql.RegisterTLSConfig
+func RegisterTLSConfigRegistrar(driver string, registrar TLSConfigRegistrar) {
+	tlsConfigRegistrarsMutex.Lock()
+	defer tlsConfigRegistrarsMutex.Unlock()
+	tlsConfigRegistrars[driver] = registrar
+}
+
+func getTLSConfigRegistrar(driver string) TLSConfigRegistrar {
+	tlsConfigRegistrarsMutex.RLock()
+	defer tlsConfigRegistrarsMutex.RUnlock()
+	return tlsConfigRegistrars[driver]
+}
+
+var mysqlTLSConfigs = make(map[string]bool)
+var mysqlTLSMutex = &sync.Mutex{}
+
+// ApplyTLS registers tls.Config with registered mysql registrar (see github.com/viant/dsc/mysql) and adds tls parameter to DSN
+func (d mySQLDialect) ApplyTLS(dsn string, options *TLSConfig) (string, error) {
+	if options == nil {
+		return dsn, nil
+	}
+	registrar := getTLSConfigRegistrar("mysql")
+	if registrar == nil {
+		return "", fmt.Errorf("failed to apply TLS config: mysql TLS config registrar was not registered, import github.com/viant/dsc/mysql")
+	}
+	name := options.key()
+	mysqlTLSMutex.Lock()
+	defer mysqlTLSMutex.Unlock()
+	if !mysqlTLSConfigs[name] {
+		tlsConfig, err := options.ClientConfig()
+		if err != nil {
+			return "", err
+		}
+		if err = registrar(name, tlsConfig); err != nil {
+			return "", fmt.Errorf("failed to register mysql TLS config due to %v", err)
+		}
+		mysqlTLSConfigs[name] = true
+	}
+	if strings.Contains(dsn, "?") {
+		return dsn + "&tls=" + name, nil
+	}
+	return dsn + "?tls=" + name, nil
+}
+
+// ApplyTLS adds sslmode, sslrootcert, sslcert and sslkey parameters to DSN
+func (d pgDialect) ApplyTLS(dsn string, options *TLSConfig) (string, error) {
+	if options == nil {
+		return dsn, nil
+	}
+	if options.ServerName != "" {
+		return "", fmt.Errorf("TLS server name override is not supported by %v dialect", "postgres")
+	}
+	if _, err := options.ClientConfig(); err != nil {
+		return "", err
+	}
+	mode := "verify-full"
+	if options.InsecureSkipVerify {
+		mode = "require"
+	}
+	var parameters = [][2]string{{"sslmode", mode}}
+	if options.CAFile != "" {
+		parameters = append(parameters, [2]string{"sslrootcert", options.CAFile})
+	}
+	if options.HasClientCertificate() {
+		parameters = append(parameters, [2]string{"sslcert", options.CertFile}, [2]string{"sslkey", options.KeyFile})
+	}
+	return addDsnParameters(dsn, " ", parameters), nil
+}
+
+// ApplyTLS adds encrypt, TrustServerCertificate, certificate and hostNameInCertificate parameters to DSN
+func (d msSQLDialect) ApplyTLS(dsn string, options *TLSConfig) (string, error) {
+	if options == nil {
+		return dsn, nil
+	}
+	if options.CertFile != "" || options.KeyFile != "" {
+		return "", fmt.Errorf("TLS client certificate is not supported by %v dialect", "sqlserver")
+	}
+	var parameters = [][2]string{{"encrypt", "true"}}
+	if options.InsecureSkipVerify {
+		parameters = append(parameters, [2]string{"TrustServerCertificate", "true"})
+	}
+	if options.CAFile != "" {
+		parameters = append(parameters, [2]string{"certificate", options.CAFile})
+	}
+	if options.ServerName != "" {
+		parameters = append(parameters, [2]string{"hostNameInCertificate", options.ServerName})
+	}
+	return addDsnParameters(dsn, ";", parameters), nil
+}
+
+// ApplyTLS returns error if TLS options were specified, dialect does not know how to translate them
+func (d sqlDatastoreDialect) ApplyTLS(dsn string, options *TLSConfig) (string, error) {
+	if options == nil {
+		return dsn, nil
+	}
+	return "", fmt.Errorf("failed to apply TLS config due to %v", errUnsupportedOperation)
+}
@@ -0,0 +1,96 @@
+package dsc_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/dsc"
+	_ "github.com/viant/dsc/mysql"
+)
+
+func writeTestCertificate(t *testing.T, dir string) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "dsc test"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	certificate, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyBytes, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	_ = os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificate}), 0600)
+	_ = os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyBytes}), 0600)
+	return certFile, keyFile
+}
+
+func TestTLSConfig_ClientConfig(t *testing.T) {
+	certFile, keyFile := writeTestCertificate(t, t.TempDir())
+	options := &dsc.TLSConfig{CAFile: certFile, CertFile: certFile, KeyFile: keyFile, ServerName: "db.local"}
+	config, err := options.ClientConfig()
+	if assert.Nil(t, err) {
+		assert.NotNil(t, config.RootCAs)
+		assert.Equal(t, 1, len(config.Certificates))
+		assert.EqualValues(t, "db.local", config.ServerName)
+	}
+	_, err = (&dsc.TLSConfig{CertFile: certFile}).ClientConfig()
+	assert.NotNil(t, err)
+	_, err = (&dsc.TLSConfig{CAFile: keyFile}).ClientConfig()
+	assert.NotNil(t, err)
+}
+
+func TestDialect_ApplyTLS(t *testing.T) {
+	certFile, keyFile := writeTestCertificate(t, t.TempDir())
+	mtls := &dsc.TLSConfig{CAFile: certFile, CertFile: certFile, KeyFile: keyFile}
+
+	dsn, err := dsc.GetDatastoreDialect("postgres").(dsc.TLSDialect).ApplyTLS("postgres://user@localhost/db", mtls)
+	if assert.Nil(t, err) {
+		assert.True(t, strings.HasPrefix(dsn, "postgres://user@localhost/db?sslmode=verify-full&sslrootcert="), dsn)
+		assert.True(t, strings.Contains(dsn, "&sslkey="), dsn)
+	}
+	dsn, err = dsc.GetDatastoreDialect("postgres").(dsc.TLSDialect).ApplyTLS("host=localhost dbname=db", &dsc.TLSConfig{InsecureSkipVerify: true})
+	assert.Nil(t, err)
+	assert.EqualValues(t, "host=localhost dbname=db sslmode=require", dsn)
+	_, err = dsc.GetDatastoreDialect("postgres").(dsc.TLSDialect).ApplyTLS("host=localhost", &dsc.TLSConfig{ServerName: "db"})
+	assert.NotNil(t, err)
+
+	dsn, err = dsc.GetDatastoreDialect("mysql").(dsc.TLSDialect).ApplyTLS("root@tcp(127.0.0.1:3306)/db?parseTime=true", mtls)
+	if assert.Nil(t, err) {
+		assert.True(t, strings.HasPrefix(dsn, "root@tcp(127.0.0.1:3306)/db?parseTime=true&tls=dsc_"), dsn)
+	}
+
+	dsn, err = dsc.GetDatastoreDialect("sqlserver").(dsc.TLSDialect).ApplyTLS("server=localhost;user id=sa", &dsc.TLSConfig{ServerName: "db.local", InsecureSkipVerify: true})
+	assert.Nil(t, err)
+	assert.EqualValues(t, "server=localhost;user id=sa;encrypt=true;TrustServerCertificate=true;hostNameInCertificate=db.local", dsn)
+	_, err = dsc.GetDatastoreDialect("sqlserver").(dsc.TLSDialect).ApplyTLS("server=localhost", mtls)
+	assert.NotNil(t, err)
+
+	_, err = dsc.GetDatastoreDialect("sqlite3").(dsc.TLSDialect).ApplyTLS("./test/foo.db", mtls)
+	assert.NotNil(t, err)
+	dsn, err = dsc.GetDatastoreDialect("sqlite3").(dsc.TLSDialect).ApplyTLS("./test/foo.db", nil)
+	assert.Nil(t, err)
+	assert.EqualValues(t, "./test/foo.db", dsn)
+}
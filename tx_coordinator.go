@@ -0,0 +1,245 @@
+package dsc
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// twoPhaseCommitDialect represents dialect supporting prepared (two-phase) transactions
+type twoPhaseCommitDialect interface {
+	//prepareTransaction prepares transaction active on connection, after that the transaction is detached from the connection
+	prepareTransaction(manager Manager, connection Connection, xid string) error
+	//commitPrepared commits prepared transaction
+	commitPrepared(manager Manager, xid string) error
+	//rollbackPrepared rolls back prepared transaction
+	rollbackPrepared(manager Manager, xid string) error
+}
+
+// quoteTransactionID returns transaction id as SQL string literal, embedded quotes are doubled
+func quoteTransactionID(xid string) string {
+	return "'" + strings.Replace(xid, "'", "''", -1) + "'"
+}
+
+// prepareTransaction executes PREPARE TRANSACTION, it requires max_prepared_transactions server setting greater than zero
+func (d pgDialect) prepareTransaction(manager Manager, connection Connection, xid string) error {
+	if _, err := manager.ExecuteOnConnection(connection, fmt.Sprintf("PREPARE TRANSACTION %v", quoteTransactionID(xid)), nil); err != nil {
+		return err
+	}
+	//session is no longer in transaction, database/sql transaction is only released: COMMIT would commit prepared work outside of two-phase commit
+	//or fail (lib/pq rejects it on idle session), rollback error is ignored, a driver reporting it discards the connection
+	_ = connection.Rollback()
+	return nil
+}
+
+// commitPrepared executes COMMIT PREPARED on a separate connection, prepared transaction is not bound to the preparing session
+func (d pgDialect) commitPrepared(manager Manager, xid string) error {
+	_, err := manager.Execute(fmt.Sprintf("COMMIT PREPARED %v", quoteTransactionID(xid)))
+	return err
+}
+
+// rollbackPrepared executes ROLLBACK PREPARED on a separate connection
+func (d pgDialect) rollbackPrepared(manager Manager, xid string) error {
+	_, err := manager.Execute(fmt.Sprintf("ROLLBACK PREPARED %v", quoteTransactionID(xid)))
+	return err
+}
+
+type transactionParticipant struct {
+	manager       Manager
+	connection    Connection
+	dialect       twoPhaseCommitDialect
+	xid           string
+	prepared      bool
+	committed     bool
+	compensations []func(manager Manager) error
+}
+
+func (p *transactionParticipant) rollback() error {
+	switch {
+	case p.prepared:
+		p.prepared = false
+		return p.dialect.rollbackPrepared(p.manager, p.xid)
+	case p.committed:
+		p.committed = false
+		return p.compensate()
+	case p.connection != nil:
+		return p.connection.Rollback()
+	}
+	return nil
+}
+
+// compensate runs compensations in reverse registration order
+func (p *transactionParticipant) compensate() error {
+	var errors = make([]string, 0)
+	for i := len(p.compensations) - 1; i >= 0; i-- {
+		if err := p.compensations[i](p.manager); err != nil {
+			errors = append(errors, err.Error())
+		}
+	}
+	if len(errors) > 0 {
+		return fmt.Errorf("failed to compensate %v due to %v", p.manager.Config().Descriptor, strings.Join(errors, ", "))
+	}
+	return nil
+}
+
+func (p *transactionParticipant) close() {
+	if p.connection != nil {
+		_ = p.connection.Close()
+		p.connection = nil
+	}
+}
+
+// TransactionCoordinator coordinates a transaction across multiple managers. Participants with dialects supporting
+// prepared transactions (postgres PREPARE TRANSACTION) are committed with two-phase commit, other participants are committed
+// after all prepares succeeded; if such commit fails, already committed participants are undone with registered compensations (best effort).
+// MySQL XA transactions are not supported, mysql participants are committed in the second group with compensations.
+type TransactionCoordinator struct {
+	//ID global transaction id, participant transaction ids are derived from it
+	ID           string
+	mutex        *sync.Mutex
+	participants []*transactionParticipant
+	completed    bool
+}
+
+func (c *TransactionCoordinator) participant(manager Manager) *transactionParticipant {
+	for _, candidate := range c.participants {
+		if candidate.manager == manager {
+			return candidate
+		}
+	}
+	return nil
+}
+
+// Enlist begins transaction on a manager connection and returns it, the connection should be used with *OnConnection methods; enlisting the same manager returns the same connection
+func (c *TransactionCoordinator) Enlist(manager Manager) (Connection, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.completed {
+		return nil, fmt.Errorf("failed to enlist %v: transaction %v was completed", manager.Config().Descriptor, c.ID)
+	}
+	if participant := c.participant(manager); participant != nil {
+		return participant.connection, nil
+	}
+	connection, err := manager.ConnectionProvider().Get()
+	if err != nil {
+		return nil, err
+	}
+	if err = connection.Begin(); err != nil {
+		_ = connection.Close()
+		return nil, fmt.Errorf("failed to start transaction on %v due to %v", manager.Config().Descriptor, err)
+	}
+	participant := &transactionParticipant{
+		manager:    manager,
+		connection: connection,
+		xid:        fmt.Sprintf("%v-%d", c.ID, len(c.participants)),
+	}
+	participant.dialect, _ = GetDatastoreDialect(manager.Config().DriverName).(twoPhaseCommitDialect)
+	c.participants = append(c.participants, participant)
+	return connection, nil
+}
+
+// Compensate registers compensation undoing enlisted manager changes, it is only used when the manager dialect does not support two-phase commit
+// and its transaction was committed before another participant commit failed
+func (c *TransactionCoordinator) Compensate(manager Manager, compensation func(manager Manager) error) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	participant := c.participant(manager)
+	if participant == nil {
+		return fmt.Errorf("failed to register compensation: %v was not enlisted in %v", manager.Config().Descriptor, c.ID)
+	}
+	participant.compensations = append(participant.compensations, compensation)
+	return nil
+}
+
+// rollback rolls back all participants, it returns cause combined with rollback errors
+func (c *TransactionCoordinator) rollback(cause error) error {
+	var errors = make([]string, 0)
+	for i := len(c.participants) - 1; i >= 0; i-- {
+		if err := c.participants[i].rollback(); err != nil {
+			errors = append(errors, err.Error())
+		}
+	}
+	if len(errors) == 0 {
+		return cause
+	}
+	if cause == nil {
+		return fmt.Errorf("failed to rollback %v due to %v", c.ID, strings.Join(errors, ", "))
+	}
+	return fmt.Errorf("%w, rollback errors: %v", cause, strings.Join(errors, ", "))
+}
+
+func (c *TransactionCoordinator) complete() {
+	c.completed = true
+	for _, participant := range c.participants {
+		participant.close()
+	}
+}
+
+// Commit prepares two-phase commit participants, commits the remaining ones and then commits prepared transactions.
+// Failure before the final phase rolls back (or compensates) all participants; failure in the final phase leaves in-doubt prepared transactions reported in the error.
+func (c *TransactionCoordinator) Commit() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.completed {
+		return fmt.Errorf("failed to commit: transaction %v was completed", c.ID)
+	}
+	defer c.complete()
+	for _, participant := range c.participants {
+		if participant.dialect == nil {
+			continue
+		}
+		if err := participant.dialect.prepareTransaction(participant.manager, participant.connection, participant.xid); err != nil {
+			return c.rollback(fmt.Errorf("failed to prepare %v on %v due to %w", participant.xid, participant.manager.Config().Descriptor, err))
+		}
+		participant.prepared = true
+		participant.close()
+	}
+	for _, participant := range c.participants {
+		if participant.dialect != nil {
+			continue
+		}
+		if err := participant.connection.Commit(); err != nil {
+			participant.close()
+			return c.rollback(fmt.Errorf("failed to commit on %v due to %w", participant.manager.Config().Descriptor, err))
+		}
+		participant.committed = true
+		participant.close()
+	}
+	var inDoubt = make([]string, 0)
+	var errors = make([]string, 0)
+	for _, participant := range c.participants {
+		if !participant.prepared {
+			continue
+		}
+		if err := participant.dialect.commitPrepared(participant.manager, participant.xid); err != nil {
+			inDoubt = append(inDoubt, participant.xid)
+			errors = append(errors, err.Error())
+			continue
+		}
+		participant.prepared = false
+	}
+	if len(inDoubt) > 0 {
+		return fmt.Errorf("failed to commit prepared transaction(s) %v due to %v", strings.Join(inDoubt, ","), strings.Join(errors, ", "))
+	}
+	return nil
+}
+
+// Rollback rolls back all enlisted participants
+func (c *TransactionCoordinator) Rollback() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.completed {
+		return fmt.Errorf("failed to rollback: transaction %v was completed", c.ID)
+	}
+	defer c.complete()
+	return c.rollback(nil)
+}
+
+// NewTransactionCoordinator creates a transaction coordinator, empty id generates a unique one
+func NewTransactionCoordinator(id string) *TransactionCoordinator {
+	if id == "" {
+		id = fmt.Sprintf("dsc-%v", time.Now().UnixNano())
+	}
+	return &TransactionCoordinator{ID: id, mutex: &sync.Mutex{}}
+}
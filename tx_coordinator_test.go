@@ -0,0 +1,140 @@
+package dsc_test
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/dsc"
+	"github.com/viant/toolbox"
+)
+
+func TestTransactionCoordinator(t *testing.T) {
+	orders, err := dsc.NewManagerFactory().Create(dsc.NewConfig("sqlite3", "[url]", "url:./test/orders.db"))
+	if !assert.Nil(t, err) {
+		return
+	}
+	items, err := dsc.NewManagerFactory().Create(dsc.NewConfig("sqlite3", "[url]", "url:file:./test/items.db?_foreign_keys=1"))
+	if !assert.Nil(t, err) {
+		return
+	}
+	for _, SQL := range []string{"DROP TABLE IF EXISTS orders", "CREATE TABLE orders(id INTEGER PRIMARY KEY, name TEXT)"} {
+		_, err = orders.Execute(SQL)
+		assert.Nil(t, err)
+	}
+	for _, SQL := range []string{"DROP TABLE IF EXISTS items", "DROP TABLE IF EXISTS products", "CREATE TABLE products(id INTEGER PRIMARY KEY)",
+		"CREATE TABLE items(id INTEGER PRIMARY KEY, product_id INTEGER REFERENCES products(id) DEFERRABLE INITIALLY DEFERRED)", "INSERT INTO products(id) VALUES(1)"} {
+		_, err = items.Execute(SQL)
+		assert.Nil(t, err)
+	}
+	count := func(manager dsc.Manager, table string) int {
+		var record = make([]interface{}, 0)
+		_, err := manager.ReadSingle(&record, "SELECT COUNT(*) FROM "+table, nil, nil)
+		assert.Nil(t, err)
+		return int(record[0].(int64))
+	}
+	run := func(productID int) error {
+		coordinator := dsc.NewTransactionCoordinator("")
+		orderConnection, err := coordinator.Enlist(orders)
+		if !assert.Nil(t, err) {
+			return err
+		}
+		itemConnection, err := coordinator.Enlist(items)
+		if !assert.Nil(t, err) {
+			return err
+		}
+		_, err = orders.ExecuteOnConnection(orderConnection, "INSERT INTO orders(name) VALUES(?)", []interface{}{"order"})
+		assert.Nil(t, err)
+		assert.Nil(t, coordinator.Compensate(orders, func(manager dsc.Manager) error {
+			_, err := manager.Execute("DELETE FROM orders WHERE id = (SELECT MAX(id) FROM orders)")
+			return err
+		}))
+		_, err = items.ExecuteOnConnection(itemConnection, "INSERT INTO items(product_id) VALUES(?)", []interface{}{productID})
+		assert.Nil(t, err)
+		return coordinator.Commit()
+	}
+
+	assert.Nil(t, run(1))
+	assert.Equal(t, 1, count(orders, "orders"))
+	assert.Equal(t, 1, count(items, "items"))
+
+	//deferred foreign key check fails on items commit, committed orders are compensated
+	assert.NotNil(t, run(2))
+	assert.Equal(t, 1, count(orders, "orders"))
+	assert.Equal(t, 1, count(items, "items"))
+
+	coordinator := dsc.NewTransactionCoordinator("rollback")
+	connection, err := coordinator.Enlist(orders)
+	assert.Nil(t, err)
+	_, err = orders.ExecuteOnConnection(connection, "INSERT INTO orders(name) VALUES(?)", []interface{}{"order"})
+	assert.Nil(t, err)
+	assert.Nil(t, coordinator.Rollback())
+	assert.Equal(t, 1, count(orders, "orders"))
+	_, err = coordinator.Enlist(items)
+	assert.NotNil(t, err, "completed transaction should not enlist")
+}
+
+// newPostgresMock returns manager using postgres dialect with go-sqlmock database registered as pqmock driver
+func newPostgresMock(t *testing.T, dsn string) (dsc.Manager, sqlmock.Sqlmock, func()) {
+	db, mock, err := sqlmock.NewWithDSN(dsn)
+	if !assert.Nil(t, err) {
+		return nil, nil, nil
+	}
+	if !toolbox.HasSliceAnyElements(sql.Drivers(), "pqmock") {
+		sql.Register("pqmock", db.Driver())
+	}
+	dsc.RegisterDatastoreDialect("pqmock", dsc.GetDatastoreDialect("postgres"))
+	manager, err := dsc.NewManagerFactory().Create(dsc.NewConfig("pqmock", "[dsn]", "dsn:"+dsn))
+	if !assert.Nil(t, err) {
+		_ = db.Close()
+		return nil, nil, nil
+	}
+	return manager, mock, func() { _ = db.Close() }
+}
+
+func TestTransactionCoordinator_TwoPhaseCommit(t *testing.T) {
+	manager, mock, closer := newPostgresMock(t, "dsc_two_phase_commit")
+	if manager == nil {
+		return
+	}
+	defer closer()
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO orders").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("PREPARE TRANSACTION 'tx-0'").WillReturnResult(sqlmock.NewResult(0, 0))
+	//lib/pq fails rollback (and commit) of database/sql transaction once session is idle after PREPARE TRANSACTION
+	mock.ExpectRollback().WillReturnError(errors.New("pq: unexpected transaction status idle"))
+	mock.ExpectExec("COMMIT PREPARED 'tx-0'").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	coordinator := dsc.NewTransactionCoordinator("tx")
+	connection, err := coordinator.Enlist(manager)
+	if !assert.Nil(t, err) {
+		return
+	}
+	_, err = manager.ExecuteOnConnection(connection, "INSERT INTO orders(name) VALUES('order')", nil)
+	assert.Nil(t, err)
+	assert.Nil(t, coordinator.Commit())
+	assert.Nil(t, mock.ExpectationsWereMet(), "prepared transaction should be committed with COMMIT PREPARED only")
+}
+
+func TestTransactionCoordinator_QuotedID(t *testing.T) {
+	manager, mock, closer := newPostgresMock(t, "dsc_two_phase_quoted_id")
+	if manager == nil {
+		return
+	}
+	defer closer()
+	mock.ExpectBegin()
+	mock.ExpectExec("PREPARE TRANSACTION 'tx''; DROP TABLE orders; ---0'").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+	mock.ExpectExec("COMMIT PREPARED 'tx''; DROP TABLE orders; ---0'").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	coordinator := dsc.NewTransactionCoordinator("tx'; DROP TABLE orders; --")
+	_, err := coordinator.Enlist(manager)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Nil(t, coordinator.Commit())
+	assert.Nil(t, mock.ExpectationsWereMet(), "transaction id should be quoted")
+}
@@ -0,0 +1,92 @@
+package dsc
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	txMaxRetriesKey       = "txMaxRetries"
+	txRetryBackoffMsKey   = "txRetryBackoffMs"
+	defaultTxMaxRetries   = 3
+	defaultTxRetryBackoff = 50 * time.Millisecond
+	maxTxRetryBackoff     = 5 * time.Second
+)
+
+// TxOptions represents RunInTx retry options, zero values are taken from txMaxRetries and txRetryBackoffMs config parameters
+type TxOptions struct {
+	//MaxRetries max number of closure re-executions after retryable error, negative value disables retries
+	MaxRetries int
+	//Backoff initial delay between retries, doubled with each retry
+	Backoff time.Duration
+	//Retryable optional classifier overriding dialect deadlock/serialization failure detection
+	Retryable func(err error) bool
+}
+
+func (m *AbstractManager) txOptions(options *TxOptions) *TxOptions {
+	var result = TxOptions{}
+	if options != nil {
+		result = *options
+	}
+	if result.MaxRetries == 0 {
+		result.MaxRetries = m.Config().GetInt(txMaxRetriesKey, defaultTxMaxRetries)
+	}
+	if result.Backoff == 0 {
+		result.Backoff = m.Config().GetDuration(txRetryBackoffMsKey, time.Millisecond, defaultTxRetryBackoff)
+	}
+	if result.Retryable == nil {
+		dialect := GetDatastoreDialect(m.Config().DriverName)
+		result.Retryable = func(err error) bool {
+			return IsRetryable(classifyError(dialect, err))
+		}
+	}
+	return &result
+}
+
+// runInTx executes passed in function within a single transaction, panic or error rolls back the transaction
+func (m *AbstractManager) runInTx(fn func(connection Connection) error) (err error) {
+	connection, err := m.Manager.ConnectionProvider().Get()
+	if err != nil {
+		return err
+	}
+	defer connection.Close()
+	if err = connection.Begin(); err != nil {
+		return err
+	}
+	committed := false
+	defer func() {
+		if committed {
+			return
+		}
+		if rollbackErr := connection.Rollback(); rollbackErr != nil {
+			Logf("failed to rollback transaction %v", rollbackErr)
+		}
+	}()
+	if err = fn(connection); err != nil {
+		return err
+	}
+	committed = true
+	return connection.Commit()
+}
+
+// RunInTx executes passed in function in a transaction, the function should use *OnConnection methods with the passed in connection.
+// When the function or commit fails with dialect specific retryable error (see IsRetryable, i.e. MySQL 1213, Postgres 40001/40P01),
+// the transaction is rolled back and the function re-executed with exponential backoff up to options.MaxRetries times.
+func (m *AbstractManager) RunInTx(fn func(connection Connection) error, options *TxOptions) error {
+	options = m.txOptions(options)
+	backoff := options.Backoff
+	for attempt := 0; ; attempt++ {
+		err := m.runInTx(fn)
+		if err == nil || !options.Retryable(err) {
+			return err
+		}
+		if attempt >= options.MaxRetries {
+			return fmt.Errorf("failed to run in transaction after %v attempt(s) due to %w", attempt+1, err)
+		}
+		Logf("retrying transaction after %v due to %v", backoff, err)
+		time.Sleep(backoff)
+		if backoff *= 2; backoff > maxTxRetryBackoff {
+			backoff = maxTxRetryBackoff
+		}
+	}
+}
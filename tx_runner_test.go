@@ -0,0 +1,64 @@
+package dsc_test
+
+import (
+	"errors"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/dsc"
+)
+
+func TestManager_RunInTx(t *testing.T) {
+	config := dsc.NewConfig("sqlite3", "[url]", "url:./test/tx.db,txRetryBackoffMs:1")
+	manager, err := dsc.NewManagerFactory().Create(config)
+	if !assert.Nil(t, err) {
+		return
+	}
+	for _, SQL := range []string{"DROP TABLE IF EXISTS accounts", "CREATE TABLE accounts(id INTEGER PRIMARY KEY, balance INTEGER)"} {
+		_, err = manager.Execute(SQL)
+		if !assert.Nil(t, err) {
+			return
+		}
+	}
+	count := func() int {
+		var record = make([]interface{}, 0)
+		_, err := manager.ReadSingle(&record, "SELECT COUNT(*) FROM accounts", nil, nil)
+		assert.Nil(t, err)
+		return int(record[0].(int64))
+	}
+
+	attempts := 0
+	err = manager.(dsc.TxRunner).RunInTx(func(connection dsc.Connection) error {
+		attempts++
+		if _, err := manager.ExecuteOnConnection(connection, "INSERT INTO accounts(id, balance) VALUES(?, ?)", []interface{}{attempts, 100}); err != nil {
+			return err
+		}
+		if attempts < 3 {
+			return errors.New("database is locked")
+		}
+		return nil
+	}, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, attempts)
+	assert.Equal(t, 1, count(), "failed attempts should be rolled back")
+
+	attempts = 0
+	err = manager.(dsc.TxRunner).RunInTx(func(connection dsc.Connection) error {
+		attempts++
+		_, _ = manager.ExecuteOnConnection(connection, "INSERT INTO accounts(id, balance) VALUES(10, 1)", nil)
+		return errors.New("insufficient funds")
+	}, nil)
+	assert.EqualError(t, err, "insufficient funds")
+	assert.Equal(t, 1, attempts, "non retryable error should not be retried")
+	assert.Equal(t, 1, count())
+
+	attempts = 0
+	conflict := errors.New("conflict")
+	err = manager.(dsc.TxRunner).RunInTx(func(connection dsc.Connection) error {
+		attempts++
+		return conflict
+	}, &dsc.TxOptions{MaxRetries: 2, Retryable: func(err error) bool { return errors.Is(err, conflict) }})
+	assert.True(t, errors.Is(err, conflict))
+	assert.Equal(t, 3, attempts, "retries should be bounded")
+}
@@ -0,0 +1,206 @@
+package dsc
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/viant/toolbox"
+)
+
+// typeConverters represents supported type mapping targets
+var typeConverters = map[string]func(value interface{}, dateLayout string) (interface{}, error){
+	"string": func(value interface{}, dateLayout string) (interface{}, error) {
+		switch actual := value.(type) {
+		case time.Time:
+			return actual.Format(dateLayout), nil
+		case []byte:
+			return string(actual), nil
+		}
+		return toolbox.AsString(value), nil
+	},
+	"bool": func(value interface{}, dateLayout string) (interface{}, error) {
+		if text, ok := value.([]byte); ok {
+			value = string(text)
+		}
+		return toolbox.ToBoolean(value)
+	},
+	"int":    signedConverter(func(v int64) interface{} { return int(v) }),
+	"int8":   signedConverter(func(v int64) interface{} { return int8(v) }),
+	"int16":  signedConverter(func(v int64) interface{} { return int16(v) }),
+	"int32":  signedConverter(func(v int64) interface{} { return int32(v) }),
+	"int64":  signedConverter(func(v int64) interface{} { return v }),
+	"uint":   unsignedConverter(func(v uint64) interface{} { return uint(v) }),
+	"uint8":  unsignedConverter(func(v uint64) interface{} { return uint8(v) }),
+	"uint16": unsignedConverter(func(v uint64) interface{} { return uint16(v) }),
+	"uint32": unsignedConverter(func(v uint64) interface{} { return uint32(v) }),
+	"uint64": unsignedConverter(func(v uint64) interface{} { return v }),
+	"float32": func(value interface{}, dateLayout string) (interface{}, error) {
+		result, err := toolbox.ToFloat(normalizeNumeric(value))
+		return float32(result), err
+	},
+	"float64": func(value interface{}, dateLayout string) (interface{}, error) {
+		return toolbox.ToFloat(normalizeNumeric(value))
+	},
+	"time.Time": func(value interface{}, dateLayout string) (interface{}, error) {
+		result, err := toolbox.ToTime(normalizeNumeric(value), dateLayout)
+		if err != nil || result == nil {
+			return nil, err
+		}
+		return *result, nil
+	},
+}
+
+func normalizeNumeric(value interface{}) interface{} {
+	if text, ok := value.([]byte); ok {
+		return string(text)
+	}
+	return value
+}
+
+func signedConverter(cast func(v int64) interface{}) func(value interface{}, dateLayout string) (interface{}, error) {
+	return func(value interface{}, dateLayout string) (interface{}, error) {
+		value = normalizeNumeric(value)
+		if text, ok := value.(string); ok {
+			result, err := strconv.ParseInt(strings.TrimSpace(text), 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			return cast(result), nil
+		}
+		result, err := toolbox.ToInt(value)
+		return cast(int64(result)), err
+	}
+}
+
+func unsignedConverter(cast func(v uint64) interface{}) func(value interface{}, dateLayout string) (interface{}, error) {
+	return func(value interface{}, dateLayout string) (interface{}, error) {
+		switch actual := normalizeNumeric(value).(type) {
+		case uint64:
+			return cast(actual), nil
+		case float64:
+			return cast(uint64(actual)), nil
+		case string:
+			result, err := strconv.ParseUint(strings.TrimSpace(actual), 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			return cast(result), nil
+		default:
+			result, err := toolbox.ToInt(actual)
+			if err != nil {
+				return nil, err
+			}
+			return cast(uint64(result)), nil
+		}
+	}
+}
+
+// normalizeTypeName returns upper case datastore type name with collapsed spaces, UNSIGNED modifier is always placed last
+func normalizeTypeName(name string) string {
+	fragments := strings.Fields(strings.ToUpper(name))
+	var result = make([]string, 0, len(fragments))
+	unsigned := false
+	for _, fragment := range fragments {
+		if fragment == "UNSIGNED" {
+			unsigned = true
+			continue
+		}
+		result = append(result, fragment)
+	}
+	if unsigned {
+		result = append(result, "UNSIGNED")
+	}
+	return strings.Replace(strings.Join(result, " "), " (", "(", -1)
+}
+
+// typeMapper converts scanned values according to Config.TypeMappings
+type typeMapper struct {
+	dateLayout string
+	converters []func(value interface{}, dateLayout string) (interface{}, error)
+}
+
+// newTypeMapper returns type mapper for passed in column types or nil if no mapping overrides are defined or matched
+func newTypeMapper(config *Config, columnTypes []ColumnType) (*typeMapper, error) {
+	if len(config.TypeMappings) == 0 || len(columnTypes) == 0 {
+		return nil, nil
+	}
+	var mappings = make(map[string]string)
+	for dataType, goType := range config.TypeMappings {
+		if _, ok := typeConverters[goType]; !ok {
+			return nil, fmt.Errorf("unsupported type mapping %v: %v", dataType, goType)
+		}
+		mappings[normalizeTypeName(dataType)] = goType
+	}
+	result := &typeMapper{dateLayout: config.GetDateLayout(), converters: make([]func(value interface{}, dateLayout string) (interface{}, error), len(columnTypes))}
+	matched := false
+	for i, columnType := range columnTypes {
+		if goType, ok := lookupTypeMapping(mappings, columnType); ok {
+			result.converters[i] = typeConverters[goType]
+			matched = true
+		}
+	}
+	if !matched {
+		return nil, nil
+	}
+	return result, nil
+}
+
+// lookupTypeMapping matches the most specific mapping: NAME(precision,scale), NAME(precision|length), NAME
+func lookupTypeMapping(mappings map[string]string, columnType ColumnType) (string, bool) {
+	name := normalizeTypeName(columnType.DatabaseTypeName())
+	if name == "" {
+		return "", false
+	}
+	var candidates = make([]string, 0, 4)
+	if precision, scale, ok := columnType.DecimalSize(); ok {
+		candidates = append(candidates, fmt.Sprintf("%v(%v,%v)", name, precision, scale))
+		if scale == 0 {
+			candidates = append(candidates, fmt.Sprintf("%v(%v)", name, precision))
+		}
+	}
+	if length, ok := columnType.Length(); ok {
+		candidates = append(candidates, fmt.Sprintf("%v(%v)", name, length))
+	}
+	candidates = append(candidates, name)
+	for _, candidate := range candidates {
+		if goType, ok := mappings[candidate]; ok {
+			return goType, true
+		}
+	}
+	return "", false
+}
+
+// typeMappingScanner represents a scanner converting values scanned into interface{} destinations with type mapper
+type typeMappingScanner struct {
+	Scanner
+	mapper *typeMapper
+}
+
+// Scan scans row and applies type mapping on interface{} destinations
+func (s *typeMappingScanner) Scan(destinations ...interface{}) error {
+	if err := s.Scanner.Scan(destinations...); err != nil {
+		return err
+	}
+	for i, destination := range destinations {
+		if i >= len(s.mapper.converters) || s.mapper.converters[i] == nil {
+			continue
+		}
+		pointer, ok := destination.(*interface{})
+		if !ok || *pointer == nil {
+			continue
+		}
+		converted, err := s.mapper.converters[i](*pointer, s.mapper.dateLayout)
+		if err != nil {
+			columns, _ := s.Columns()
+			column := ""
+			if i < len(columns) {
+				column = columns[i]
+			}
+			return fmt.Errorf("failed to apply type mapping on %v due to %v", column, err)
+		}
+		*pointer = converted
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package dsc_test
+
+import (
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/dsc"
+)
+
+func TestConfig_TypeMappings(t *testing.T) {
+	config := dsc.NewConfig("sqlite3", "[url]", "url:./test/mapping.db")
+	config.TypeMappings = map[string]string{
+		"timestamp":        "string",
+		"BIGINT  UNSIGNED": "uint64",
+		"NUMBER(1)":        "bool",
+	}
+	manager, err := dsc.NewManagerFactory().Create(config)
+	if !assert.Nil(t, err) {
+		return
+	}
+	for _, SQL := range []string{
+		"DROP TABLE IF EXISTS mapped",
+		"CREATE TABLE mapped(id INTEGER, active NUMBER(1), created TIMESTAMP, counter BIGINT UNSIGNED)",
+		"INSERT INTO mapped(id, active, created, counter) VALUES(1, 1, '2020-01-02 03:04:05', 42)",
+	} {
+		_, err = manager.Execute(SQL)
+		if !assert.Nil(t, err) {
+			return
+		}
+	}
+	var records = make([]map[string]interface{}, 0)
+	err = manager.ReadAll(&records, "SELECT id, active, created, counter FROM mapped", nil, nil)
+	if !assert.Nil(t, err) || !assert.Equal(t, 1, len(records)) {
+		return
+	}
+	assert.EqualValues(t, int64(1), records[0]["id"], "unmapped column should keep driver type")
+	assert.Equal(t, true, records[0]["active"])
+	assert.IsType(t, "", records[0]["created"])
+	assert.Equal(t, uint64(42), records[0]["counter"])
+
+	config.TypeMappings = map[string]string{"TIMESTAMP": "complex128"}
+	err = manager.ReadAll(&records, "SELECT created FROM mapped", nil, nil)
+	assert.NotNil(t, err, "unsupported go type should fail")
+}
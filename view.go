@@ -0,0 +1,176 @@
+package dsc
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	ansiViewListSQL       = "SELECT table_name AS name FROM information_schema.views WHERE table_schema = ?"
+	ansiViewDefinitionSQL = "SELECT view_definition AS definition FROM information_schema.views WHERE table_schema = ? AND table_name = ?"
+
+	sqlLiteViewListSQL       = "SELECT name FROM SQLITE_MASTER WHERE type = 'view' AND LENGTH(?) > 0 ORDER BY name"
+	sqlLiteViewDefinitionSQL = "SELECT sql AS definition FROM SQLITE_MASTER WHERE type = 'view' AND LENGTH(?) > 0 AND name = ?"
+
+	pgViewListSQL             = "SELECT table_name AS name FROM information_schema.views WHERE table_catalog = ? AND table_schema = 'public' ORDER BY table_name"
+	pgMaterializedViewListSQL = "SELECT matviewname AS name FROM pg_matviews WHERE schemaname = 'public' AND LENGTH(?) > 0 ORDER BY matviewname"
+	pgViewDefinitionSQL       = "SELECT pg_get_viewdef(CAST(? AS regclass), true) AS definition"
+
+	oraViewListSQL             = `SELECT view_name AS "name" FROM all_views WHERE owner = ? ORDER BY view_name`
+	oraMaterializedViewListSQL = `SELECT mview_name AS "name" FROM all_mviews WHERE owner = ? ORDER BY mview_name`
+	oraViewDefinitionSQL       = `SELECT text AS "definition" FROM all_views WHERE owner = ? AND view_name = ? UNION ALL SELECT query AS "definition" FROM all_mviews WHERE owner = ? AND mview_name = ?`
+
+	casandraViewListSQL = "SELECT view_name AS name FROM system_schema.views WHERE keyspace_name = ?"
+)
+
+// View represents datastore view
+type View struct {
+	Name string
+	//Materialized true for materialized view storing query result
+	Materialized bool `json:",omitempty"`
+}
+
+var sqlLiteCreateView = regexp.MustCompile(`(?is)^\s*CREATE\s+(?:TEMP\w*\s+)?VIEW\s+(?:IF\s+NOT\s+EXISTS\s+)?\S+(?:\s*\([^)]*\))?\s+AS\s+(.*?);?\s*$`)
+
+// GetViews returns no views, default dialect does not support views
+func (d DefaultDialect) GetViews(manager Manager, datastore string) ([]*View, error) {
+	return nil, nil
+}
+
+// GetViewDefinition returns an error, default dialect does not support views
+func (d DefaultDialect) GetViewDefinition(manager Manager, datastore, view string) (string, error) {
+	return "", fmt.Errorf("failed to get %v definition due to %v", view, errUnsupportedOperation)
+}
+
+// RefreshMaterializedView returns an error, default dialect does not support materialized views
+func (d DefaultDialect) RefreshMaterializedView(manager Manager, view string) error {
+	return fmt.Errorf("failed to refresh %v due to %v", view, errUnsupportedOperation)
+}
+
+// readViews returns views listed by SQL
+func readViews(manager Manager, SQL string, materialized bool, parameters ...interface{}) ([]*View, error) {
+	var rows = make([]nameRecord, 0)
+	if err := manager.ReadAll(&rows, SQL, parameters, nil); err != nil {
+		return nil, fmt.Errorf("failed to get views due to %v", err)
+	}
+	var result = make([]*View, 0)
+	for _, row := range rows {
+		if len(row.Name) > 0 {
+			result = append(result, &View{Name: row.Name, Materialized: materialized})
+		}
+	}
+	return result, nil
+}
+
+// readViewDefinition returns view defining SQL read with SQL
+func readViewDefinition(manager Manager, view, SQL string, parameters ...interface{}) (string, error) {
+	var record = make([]interface{}, 0)
+	success, err := manager.ReadSingle(&record, SQL, parameters, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to get %v definition due to %v", view, err)
+	}
+	if !success || len(record) == 0 {
+		return "", fmt.Errorf("failed to get %v definition: view was not found", view)
+	}
+	return strings.TrimSpace(explainValue(record[0])), nil
+}
+
+// GetViews returns views listed in information_schema.views
+func (d sqlDatastoreDialect) GetViews(manager Manager, datastore string) ([]*View, error) {
+	return readViews(manager, ansiViewListSQL, false, datastore)
+}
+
+// GetViewDefinition returns view definition from information_schema.views
+func (d sqlDatastoreDialect) GetViewDefinition(manager Manager, datastore, view string) (string, error) {
+	return readViewDefinition(manager, view, ansiViewDefinitionSQL, datastore, view)
+}
+
+// RefreshMaterializedView returns an error, dialect does not know materialized views
+func (d sqlDatastoreDialect) RefreshMaterializedView(manager Manager, view string) error {
+	return fmt.Errorf("failed to refresh %v due to %v", view, errUnsupportedOperation)
+}
+
+// GetViews returns sqlite views
+func (d sqlLiteDialect) GetViews(manager Manager, datastore string) ([]*View, error) {
+	return readViews(manager, sqlLiteViewListSQL, false, datastore)
+}
+
+// GetViewDefinition returns SELECT statement of sqlite view
+func (d sqlLiteDialect) GetViewDefinition(manager Manager, datastore, view string) (string, error) {
+	definition, err := readViewDefinition(manager, view, sqlLiteViewDefinitionSQL, datastore, view)
+	if err != nil {
+		return "", err
+	}
+	if matched := sqlLiteCreateView.FindStringSubmatch(definition); len(matched) > 0 {
+		return strings.TrimSpace(matched[1]), nil
+	}
+	return definition, nil
+}
+
+// GetViews returns postgres views and materialized views of public schema
+func (d pgDialect) GetViews(manager Manager, datastore string) ([]*View, error) {
+	result, err := readViews(manager, pgViewListSQL, false, datastore)
+	if err != nil {
+		return nil, err
+	}
+	materialized, err := readViews(manager, pgMaterializedViewListSQL, true, datastore)
+	if err != nil {
+		return nil, err
+	}
+	return append(result, materialized...), nil
+}
+
+// GetViewDefinition returns postgres view or materialized view SELECT statement
+func (d pgDialect) GetViewDefinition(manager Manager, datastore, view string) (string, error) {
+	return readViewDefinition(manager, view, pgViewDefinitionSQL, view)
+}
+
+// RefreshMaterializedView refreshes postgres materialized view
+func (d pgDialect) RefreshMaterializedView(manager Manager, view string) error {
+	if _, err := manager.Execute("REFRESH MATERIALIZED VIEW " + view); err != nil {
+		return fmt.Errorf("failed to refresh %v due to %v", view, err)
+	}
+	return nil
+}
+
+// GetViews returns oracle views and materialized views of the schema
+func (d oraDialect) GetViews(manager Manager, datastore string) ([]*View, error) {
+	result, err := readViews(manager, oraViewListSQL, false, datastore)
+	if err != nil {
+		return nil, err
+	}
+	materialized, err := readViews(manager, oraMaterializedViewListSQL, true, datastore)
+	if err != nil {
+		return nil, err
+	}
+	return append(result, materialized...), nil
+}
+
+// GetViewDefinition returns oracle view or materialized view query
+func (d oraDialect) GetViewDefinition(manager Manager, datastore, view string) (string, error) {
+	return readViewDefinition(manager, view, oraViewDefinitionSQL, datastore, view, datastore, view)
+}
+
+// RefreshMaterializedView refreshes oracle materialized view with DBMS_MVIEW
+func (d oraDialect) RefreshMaterializedView(manager Manager, view string) error {
+	if _, err := manager.Execute(fmt.Sprintf("BEGIN DBMS_MVIEW.REFRESH('%v'); END;", strings.Replace(view, "'", "''", -1))); err != nil {
+		return fmt.Errorf("failed to refresh %v due to %v", view, err)
+	}
+	return nil
+}
+
+// GetViews returns cassandra materialized views of the keyspace
+func (d casandraSQLDialect) GetViews(manager Manager, datastore string) ([]*View, error) {
+	return readViews(manager, casandraViewListSQL, true, datastore)
+}
+
+// GetViewDefinition returns an error, cassandra does not keep materialized view statement
+func (d casandraSQLDialect) GetViewDefinition(manager Manager, datastore, view string) (string, error) {
+	return "", fmt.Errorf("failed to get %v definition due to %v", view, errUnsupportedOperation)
+}
+
+// RefreshMaterializedView returns nil, cassandra materialized views are maintained on write
+func (d casandraSQLDialect) RefreshMaterializedView(manager Manager, view string) error {
+	return nil
+}